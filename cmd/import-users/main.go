@@ -0,0 +1,146 @@
+// Command import-users envía un archivo CSV o NDJSON al endpoint de importación de usuarios y guarda el informe.
+//
+//	USER_SERVICE_TOKEN=<jwt> go run ./cmd/import-users -dry-run -report informe.csv usuarios.csv
+//
+// El token debe tener el permiso Importar Usuarios. Termina con código 2 si alguna fila no es válida o falló.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importReport son los totales del informe que se muestran al terminar
+type importReport struct {
+	DryRun  bool `json:"dryRun"`
+	Applied bool `json:"applied"`
+	Total   int  `json:"total"`
+	Created int  `json:"created"`
+	Updated int  `json:"updated"`
+	Skipped int  `json:"skipped"`
+	Invalid int  `json:"invalid"`
+	Failed  int  `json:"failed"`
+}
+
+func main() {
+	baseUrl := flag.String("url", envOrDefault("USER_SERVICE_URL", "http://localhost:8080"), "URL base del servicio de usuarios")
+	token := flag.String("token", os.Getenv("USER_SERVICE_TOKEN"), "token JWT con el permiso Importar Usuarios")
+	format := flag.String("format", "", "csv o ndjson; por defecto según la extensión del archivo")
+	dryRun := flag.Bool("dry-run", false, "validar sin escribir")
+	onDuplicate := flag.String("on-duplicate", "skip", "emails ya registrados: skip, update o fail")
+	reportPath := flag.String("report", "", "archivo donde guardar el informe por fila (.csv o .json)")
+	timeout := flag.Duration("timeout", 10*time.Minute, "tiempo máximo de la petición")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Uso: %s [opciones] <archivo>\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *token == "" {
+		fail("se requiere un token: usa -token o USER_SERVICE_TOKEN")
+	}
+
+	filePath := flag.Arg(0)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		fail("no se pudo leer el archivo: %v", err)
+	}
+
+	reportFormat := "json"
+	if strings.EqualFold(filepath.Ext(*reportPath), ".csv") {
+		reportFormat = "csv"
+	}
+	query := url.Values{}
+	query.Set("dryRun", strconv.FormatBool(*dryRun))
+	query.Set("onDuplicate", *onDuplicate)
+	query.Set("report", reportFormat)
+	if *format != "" {
+		query.Set("format", *format)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		fail("no se pudo preparar la petición: %v", err)
+	}
+	part.Write(data)
+	writer.Close()
+
+	request, err := http.NewRequest(http.MethodPost, strings.TrimRight(*baseUrl, "/")+"/api/v1/users/import?"+query.Encode(), &body)
+	if err != nil {
+		fail("URL no válida: %v", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+*token)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	response, err := (&http.Client{Timeout: *timeout}).Do(request)
+	if err != nil {
+		fail("la petición falló: %v", err)
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		fail("no se pudo leer la respuesta: %v", err)
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusUnprocessableEntity {
+		fail("el servicio respondió %s: %s", response.Status, strings.TrimSpace(string(responseBody)))
+	}
+
+	if *reportPath != "" {
+		if err := os.WriteFile(*reportPath, responseBody, 0o644); err != nil {
+			fail("no se pudo guardar el informe: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Informe guardado en %s\n", *reportPath)
+	}
+
+	exitCode := 0
+	if reportFormat == "json" {
+		var report importReport
+		if err := json.Unmarshal(responseBody, &report); err != nil {
+			fail("respuesta no válida: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Filas: %d, altas: %d, actualizadas: %d, omitidas: %d, no válidas: %d, fallidas: %d\n",
+			report.Total, report.Created, report.Updated, report.Skipped, report.Invalid, report.Failed)
+		switch {
+		case report.DryRun:
+			fmt.Fprintln(os.Stderr, "Simulación: no se escribió nada")
+		case !report.Applied:
+			fmt.Fprintln(os.Stderr, "Hay filas no válidas: no se escribió nada")
+		}
+		if report.Invalid > 0 || report.Failed > 0 {
+			exitCode = 2
+		}
+	} else if response.StatusCode == http.StatusUnprocessableEntity {
+		fmt.Fprintln(os.Stderr, "Hay filas no válidas: no se escribió nada")
+		exitCode = 2
+	}
+	os.Exit(exitCode)
+}
+
+func envOrDefault(name, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -8,9 +8,39 @@ export JWT_SECRET="your_jwt_secret_here"
 # Credenciales de Firebase/GCP en formato base64
 export GCP_CREDENTIAL_JSON_BASE64="your_credential_json_base64_here"
 
+# Codificación de los permisos en los tokens (plain/compact)
+export JWT_PERMISSION_ENCODING="${JWT_PERMISSION_ENCODING:-plain}"
+
+# Service accounts de otros microservicios (servicio=clave separados por comas)
+export SERVICE_ACCOUNT_KEYS="product-service=your_product_service_key_here"
+
+# Archivo de políticas de acceso condicionadas
+export POLICY_FILE="${POLICY_FILE:-policies.json}"
+
+# Frecuencia de limpieza de roles temporales vencidos
+export ROLE_ASSIGNMENT_SWEEP_INTERVAL="${ROLE_ASSIGNMENT_SWEEP_INTERVAL:-5m}"
+
+# Retención de los usuarios eliminados antes de purgarlos y frecuencia del proceso de estados
+export USER_DELETION_RETENTION="${USER_DELETION_RETENTION:-720h}"
+export USER_STATUS_SWEEP_INTERVAL="${USER_STATUS_SWEEP_INTERVAL:-15m}"
+
+# Vigencia de los archivos de exportación de datos y frecuencia del proceso de solicitudes de datos
+export DATA_EXPORT_RETENTION="${DATA_EXPORT_RETENTION:-168h}"
+export DATA_REQUEST_WORKER_INTERVAL="${DATA_REQUEST_WORKER_INTERVAL:-1m}"
+
+# Almacenamiento de imágenes de perfil y exportaciones de datos: local (directorio servido por el servicio) o gcs
+export BLOB_STORE="${BLOB_STORE:-local}"
+export BLOB_LOCAL_DIR="${BLOB_LOCAL_DIR:-blobs}"
+# Bucket y vigencia de las URLs firmadas cuando BLOB_STORE=gcs
+export GCS_BUCKET=""
+export BLOB_URL_TTL="${BLOB_URL_TTL:-15m}"
+
 # Puerto en el que se ejecutará el servidor (por defecto 8080)
 export PORT="${PORT:-8080}"
 
+# Puerto del servidor gRPC interno (por defecto 9090)
+export GRPC_PORT="${GRPC_PORT:-9090}"
+
 # Modo de ejecución de Gin (debug/release)
 export GIN_MODE=debug
 
@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/blob"
+	"github.com/ruiborda/ecommerce-user-service/src/job"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/policy"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
 	router2 "github.com/ruiborda/ecommerce-user-service/src/route"
+	"github.com/ruiborda/ecommerce-user-service/src/rpc"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
+	serviceImpl "github.com/ruiborda/ecommerce-user-service/src/service/impl"
 	"github.com/ruiborda/go-swagger-generator/src/middleware"
 	"github.com/ruiborda/go-swagger-generator/src/openapi"
 	"github.com/ruiborda/go-swagger-generator/src/swagger"
 	"log/slog"
+	"net"
 	"os"
 	"time"
 )
@@ -38,7 +48,114 @@ func main() {
 			In("header")
 	})
 
-	router2.ApiRouter(router)
+	// Permisos registrados por otros microservicios
+	model.SetPermissionSource(impl.NewPermissionRepositoryImpl().FindAll)
+	// Permisos de productos (601-607) que antes se definían aquí, hasta que product-service registre su catálogo
+	if err := serviceImpl.NewPermissionServiceImpl().EnsureSeedPermissions(); err != nil {
+		slog.Error("Failed to seed permission catalog", "error", err)
+	}
+	// Posiciones estables de los permisos en los tokens compactos, compartidas por todas las réplicas
+	model.SetPermissionIndexStore(impl.NewPermissionIndexRepositoryImpl())
+
+	// Almacenamiento de objetos para las imágenes de perfil y las exportaciones de datos (GCS o directorio local)
+	blobStore, err := blob.NewBlobStoreFromEnv(context.Background())
+	if err != nil {
+		slog.Error("Failed to configure blob store", "error", err)
+		os.Exit(1)
+	}
+	blob.SetDefault(blobStore)
+
+	// Versiones de autorización para rechazar los tokens emitidos antes de un cambio de roles
+	security.SetAuthzVersionSource(impl.NewAuthzVersionRepositoryImpl())
+
+	// Políticas de acceso condicionadas (ABAC)
+	policyFile := os.Getenv("POLICY_FILE")
+	if policyFile == "" {
+		policyFile = "policies.json"
+	}
+	if err := policy.LoadFromFile(policyFile); err != nil {
+		slog.Error("Failed to load policies", "file", policyFile, "error", err)
+		os.Exit(1)
+	}
+
+	// Roles del sistema (USER) que no se pueden eliminar ni renombrar
+	if err := serviceImpl.NewRoleServiceImpl().EnsureSystemRoles(); err != nil {
+		slog.Error("Failed to ensure system roles", "error", err)
+	}
+
+	if err := router2.ApiRouter(router); err != nil {
+		slog.Error("Failed to register routes", "error", err)
+		os.Exit(1)
+	}
+
+	// Limpieza de asignaciones temporales de roles vencidas
+	sweepInterval := job.DefaultRoleAssignmentSweepInterval
+	if value := os.Getenv("ROLE_ASSIGNMENT_SWEEP_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			slog.Error("Invalid ROLE_ASSIGNMENT_SWEEP_INTERVAL", "value", value)
+			os.Exit(1)
+		}
+		sweepInterval = parsed
+	}
+	job.StartRoleAssignmentSweeper(sweepInterval)
+
+	// Ciclo de vida de las cuentas: fin de suspensiones y purga de usuarios eliminados
+	if value := os.Getenv("USER_DELETION_RETENTION"); value != "" {
+		retention, err := time.ParseDuration(value)
+		if err != nil || retention <= 0 {
+			slog.Error("Invalid USER_DELETION_RETENTION", "value", value)
+			os.Exit(1)
+		}
+		serviceImpl.SetUserDeletionRetention(retention)
+	}
+	statusSweepInterval := job.DefaultUserStatusSweepInterval
+	if value := os.Getenv("USER_STATUS_SWEEP_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			slog.Error("Invalid USER_STATUS_SWEEP_INTERVAL", "value", value)
+			os.Exit(1)
+		}
+		statusSweepInterval = parsed
+	}
+	job.StartUserStatusSweeper(statusSweepInterval)
+
+	// Solicitudes de exportación y supresión de datos (RGPD)
+	if value := os.Getenv("DATA_EXPORT_RETENTION"); value != "" {
+		retention, err := time.ParseDuration(value)
+		if err != nil || retention <= 0 {
+			slog.Error("Invalid DATA_EXPORT_RETENTION", "value", value)
+			os.Exit(1)
+		}
+		serviceImpl.SetDataExportRetention(retention)
+	}
+	dataRequestInterval := job.DefaultDataRequestWorkerInterval
+	if value := os.Getenv("DATA_REQUEST_WORKER_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			slog.Error("Invalid DATA_REQUEST_WORKER_INTERVAL", "value", value)
+			os.Exit(1)
+		}
+		dataRequestInterval = parsed
+	}
+	job.StartDataSubjectRequestWorker(dataRequestInterval)
+
+	// Servidor gRPC para llamadas internas entre microservicios
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	grpcListener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		slog.Error("Failed to listen for gRPC", "port", grpcPort, "error", err)
+		os.Exit(1)
+	}
+	go func() {
+		slog.Info("Starting gRPC server on port " + grpcPort)
+		if err := rpc.NewServer().Serve(grpcListener); err != nil {
+			slog.Error("gRPC server stopped", "error", err)
+		}
+	}()
 
 	port := os.Getenv("PORT")
 	if port == "" {
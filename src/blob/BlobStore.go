@@ -0,0 +1,45 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrBlobNotFound indica que no existe un objeto con esa clave
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore guarda objetos binarios, como las imágenes de perfil, identificados por una clave con "/"
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Delete no devuelve error si el objeto no existe
+	Delete(ctx context.Context, key string) error
+	// URL devuelve una URL con la que un cliente puede descargar el objeto sin credenciales
+	URL(ctx context.Context, key string) (string, error)
+}
+
+// ServingBlobStore es un BlobStore cuyos objetos sirve este mismo servicio, como el del sistema de archivos local
+type ServingBlobStore interface {
+	BlobStore
+	Open(ctx context.Context, key string) (reader io.ReadCloser, contentType string, err error)
+}
+
+var (
+	defaultStore BlobStore
+	defaultMutex sync.RWMutex
+)
+
+// SetDefault configura el almacenamiento que usan los servicios y los mappers
+func SetDefault(store BlobStore) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultStore = store
+}
+
+// Default devuelve el almacenamiento configurado, o nil si no hay ninguno
+func Default() BlobStore {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultStore
+}
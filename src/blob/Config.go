@@ -0,0 +1,59 @@
+package blob
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/api/option"
+)
+
+// LocalBlobPath es la ruta bajo la que el servicio sirve los objetos del almacenamiento local
+const LocalBlobPath = "/api/v1/blobs"
+
+// DefaultSignedUrlTTL es la vigencia por defecto de las URLs firmadas de GCS
+const DefaultSignedUrlTTL = 15 * time.Minute
+
+// NewBlobStoreFromEnv crea el almacenamiento indicado en BLOB_STORE:
+//   - "gcs": bucket GCS_BUCKET con las credenciales de GCP_CREDENTIAL_JSON_BASE64; URLs firmadas válidas durante BLOB_URL_TTL.
+//   - "local" o vacío: directorio BLOB_LOCAL_DIR (por defecto "blobs") servido bajo BLOB_BASE_URL (por defecto LocalBlobPath).
+func NewBlobStoreFromEnv(ctx context.Context) (BlobStore, error) {
+	switch kind := os.Getenv("BLOB_STORE"); kind {
+	case "gcs":
+		bucket := os.Getenv("GCS_BUCKET")
+		if bucket == "" {
+			return nil, fmt.Errorf("GCS_BUCKET is required when BLOB_STORE=gcs")
+		}
+		urlTTL := DefaultSignedUrlTTL
+		if value := os.Getenv("BLOB_URL_TTL"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid BLOB_URL_TTL %q", value)
+			}
+			urlTTL = parsed
+		}
+		var options []option.ClientOption
+		if encoded := os.Getenv("GCP_CREDENTIAL_JSON_BASE64"); encoded != "" {
+			credentialJson, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid GCP_CREDENTIAL_JSON_BASE64: %v", err)
+			}
+			options = append(options, option.WithCredentialsJSON(credentialJson))
+		}
+		return NewGcsBlobStore(ctx, bucket, urlTTL, options...)
+	case "", "local":
+		root := os.Getenv("BLOB_LOCAL_DIR")
+		if root == "" {
+			root = "blobs"
+		}
+		baseUrl := os.Getenv("BLOB_BASE_URL")
+		if baseUrl == "" {
+			baseUrl = LocalBlobPath
+		}
+		return NewLocalBlobStore(root, baseUrl)
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q, use gcs or local", kind)
+	}
+}
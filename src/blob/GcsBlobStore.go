@@ -0,0 +1,61 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+)
+
+// GcsBlobStore guarda los objetos en un bucket de Google Cloud Storage privado y entrega URLs firmadas
+type GcsBlobStore struct {
+	client *storage.Client
+	bucket string
+	urlTTL time.Duration
+}
+
+func NewGcsBlobStore(ctx context.Context, bucket string, urlTTL time.Duration, options ...option.ClientOption) (*GcsBlobStore, error) {
+	client, err := storage.NewClient(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %v", err)
+	}
+	return &GcsBlobStore{client: client, bucket: bucket, urlTTL: urlTTL}, nil
+}
+
+func (s *GcsBlobStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	writer := s.client.Bucket(s.bucket).Object(key).NewWriter(ctx)
+	writer.ContentType = contentType
+	// Las claves no se reutilizan: un objeto nuevo recibe otra clave, así que puede cachearse indefinidamente
+	writer.CacheControl = "private, max-age=31536000, immutable"
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to upload blob: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to upload blob: %v", err)
+	}
+	return nil
+}
+
+func (s *GcsBlobStore) Delete(ctx context.Context, key string) error {
+	err := s.client.Bucket(s.bucket).Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("failed to delete blob: %v", err)
+	}
+	return nil
+}
+
+func (s *GcsBlobStore) URL(ctx context.Context, key string) (string, error) {
+	signedUrl, err := s.client.Bucket(s.bucket).SignedURL(key, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  "GET",
+		Expires: time.Now().Add(s.urlTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign blob url: %v", err)
+	}
+	return signedUrl, nil
+}
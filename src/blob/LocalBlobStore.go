@@ -0,0 +1,102 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalBlobStore guarda los objetos en un directorio local y los sirve el propio servicio bajo baseUrl.
+// Pensado para desarrollo y despliegues con una sola instancia.
+type LocalBlobStore struct {
+	root    string
+	baseUrl string
+}
+
+func NewLocalBlobStore(root, baseUrl string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %v", err)
+	}
+	return &LocalBlobStore{root: root, baseUrl: strings.TrimSuffix(baseUrl, "/")}, nil
+}
+
+func (s *LocalBlobStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %v", err)
+	}
+
+	// Escribir en un archivo temporal y renombrarlo para que nunca se sirva un objeto a medias
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write blob: %v", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to write blob: %v", err)
+	}
+	if err := os.Rename(tempFile.Name(), filePath); err != nil {
+		return fmt.Errorf("failed to write blob: %v", err)
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %v", err)
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) URL(ctx context.Context, key string) (string, error) {
+	if _, err := s.filePath(key); err != nil {
+		return "", err
+	}
+	return s.baseUrl + "/" + (&url.URL{Path: key}).EscapedPath(), nil
+}
+
+func (s *LocalBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, "", ErrBlobNotFound
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, "", ErrBlobNotFound
+		}
+		return nil, "", fmt.Errorf("failed to open blob: %v", err)
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return file, contentType, nil
+}
+
+// filePath rechaza las claves que saldrían del directorio raíz
+func (s *LocalBlobStore) filePath(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
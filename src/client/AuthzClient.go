@@ -0,0 +1,244 @@
+// Package client contiene un cliente Go para que otros microservicios consulten
+// las decisiones de autorización del servicio de usuarios.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/authz"
+)
+
+const serviceAccountHeader = "X-Service-Account-Key"
+
+// maxBatchChecks es el límite de verificaciones por lote que acepta el servidor
+const maxBatchChecks = 100
+
+// DefaultMaxCachedDecisions es el número de decisiones que se cachean como máximo
+const DefaultMaxCachedDecisions = 10000
+
+// AuthzClient llama a /api/v1/authz/check y cachea localmente las decisiones durante ttl
+type AuthzClient struct {
+	baseURL    string
+	serviceKey string
+	httpClient *http.Client
+	ttl        time.Duration
+
+	mutex sync.Mutex
+	cache map[string]cachedDecision
+	// maxEntries limita la cache; al llenarse se descartan las vencidas y, si no alcanza, otras cualesquiera
+	maxEntries int
+}
+
+type cachedDecision struct {
+	response  authz.CheckResponse
+	expiresAt time.Time
+}
+
+// NewAuthzClient crea un cliente. Un ttl de cero desactiva la cache.
+func NewAuthzClient(baseURL, serviceKey string, ttl time.Duration) *AuthzClient {
+	return &AuthzClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		serviceKey: serviceKey,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		ttl:        ttl,
+		cache:      make(map[string]cachedDecision),
+		maxEntries: DefaultMaxCachedDecisions,
+	}
+}
+
+// SetMaxCachedDecisions cambia el número máximo de decisiones cacheadas
+func (c *AuthzClient) SetMaxCachedDecisions(maxEntries int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.maxEntries = maxEntries
+}
+
+// IsAllowed indica si el usuario tiene todos los permisos indicados
+func (c *AuthzClient) IsAllowed(ctx context.Context, userId string, permissionIds ...int) (bool, error) {
+	response, err := c.Check(ctx, &authz.CheckRequest{
+		Subject:       authz.Subject{UserId: userId},
+		PermissionIds: permissionIds,
+	})
+	if err != nil {
+		return false, err
+	}
+	return response.Allowed, nil
+}
+
+// Check evalúa una solicitud usando la cache local cuando es posible
+func (c *AuthzClient) Check(ctx context.Context, request *authz.CheckRequest) (*authz.CheckResponse, error) {
+	key := cacheKey(request)
+	if response, ok := c.cached(key); ok {
+		return response, nil
+	}
+
+	var response authz.CheckResponse
+	if err := c.post(ctx, "/api/v1/authz/check", request, &response); err != nil {
+		return nil, err
+	}
+
+	c.store(key, &response)
+	return &response, nil
+}
+
+// CheckBatch evalúa varias solicitudes enviando al servidor solo las que no están en cache,
+// en lotes de hasta maxBatchChecks
+func (c *AuthzClient) CheckBatch(ctx context.Context, requests []authz.CheckRequest) ([]authz.CheckResponse, error) {
+	results := make([]authz.CheckResponse, len(requests))
+	keys := make([]string, len(requests))
+
+	var pending []int
+	for i := range requests {
+		keys[i] = cacheKey(&requests[i])
+		if response, ok := c.cached(keys[i]); ok {
+			results[i] = *response
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for chunk := range slices.Chunk(pending, maxBatchChecks) {
+		batch := &authz.BatchCheckRequest{Checks: make([]authz.CheckRequest, 0, len(chunk))}
+		for _, i := range chunk {
+			batch.Checks = append(batch.Checks, requests[i])
+		}
+
+		var response authz.BatchCheckResponse
+		if err := c.post(ctx, "/api/v1/authz/check/batch", batch, &response); err != nil {
+			return nil, err
+		}
+		if len(response.Results) != len(chunk) {
+			return nil, fmt.Errorf("authz batch returned %d results for %d checks", len(response.Results), len(chunk))
+		}
+
+		for j, i := range chunk {
+			results[i] = response.Results[j]
+			c.store(keys[i], &response.Results[j])
+		}
+	}
+
+	return results, nil
+}
+
+// Invalidate elimina todas las decisiones cacheadas
+func (c *AuthzClient) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.cache = make(map[string]cachedDecision)
+}
+
+func (c *AuthzClient) post(ctx context.Context, path string, body any, target any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode authz request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create authz request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(serviceAccountHeader, c.serviceKey)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call authz service: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("authz service responded with status %d", res.StatusCode)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode authz response: %v", err)
+	}
+	return nil
+}
+
+func (c *AuthzClient) cached(key string) (*authz.CheckResponse, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.cache[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.cache, key)
+		return nil, false
+	}
+	response := entry.response
+	return &response, true
+}
+
+func (c *AuthzClient) store(key string, response *authz.CheckResponse) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if _, exists := c.cache[key]; !exists && c.maxEntries > 0 && len(c.cache) >= c.maxEntries {
+		// Las entradas vencidas solo se borraban al volver a consultarlas; se barren al llenarse la cache
+		for cachedKey, entry := range c.cache {
+			if now.After(entry.expiresAt) {
+				delete(c.cache, cachedKey)
+			}
+		}
+		for cachedKey := range c.cache {
+			if len(c.cache) < c.maxEntries {
+				break
+			}
+			delete(c.cache, cachedKey)
+		}
+	}
+	c.cache[key] = cachedDecision{response: *response, expiresAt: now.Add(c.ttl)}
+}
+
+func cacheKey(request *authz.CheckRequest) string {
+	var builder strings.Builder
+
+	if request.Subject.Token != "" {
+		// No se guardan tokens en claro como clave de cache
+		hash := sha256.Sum256([]byte(request.Subject.Token))
+		builder.WriteString("token:" + hex.EncodeToString(hash[:]))
+	} else {
+		builder.WriteString("user:" + request.Subject.UserId)
+	}
+
+	permissionIds := append([]int{}, request.PermissionIds...)
+	sort.Ints(permissionIds)
+	builder.WriteString("|permissions:")
+	for _, id := range permissionIds {
+		builder.WriteString(strconv.Itoa(id) + ",")
+	}
+
+	if request.Resource != nil {
+		builder.WriteString("|resource:" + request.Resource.Service + " " + strings.ToUpper(request.Resource.Method) + " " + request.Resource.Path)
+	}
+
+	if request.OrganizationId != "" {
+		builder.WriteString("|org:" + request.OrganizationId)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/authz"
+)
+
+// newBatchServer responde cada verificación con allowed = true y registra el tamaño de cada lote
+func newBatchServer(t *testing.T, batchSizes *[]int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request authz.BatchCheckRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("failed to decode batch: %v", err)
+		}
+		if len(request.Checks) > maxBatchChecks {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*batchSizes = append(*batchSizes, len(request.Checks))
+
+		response := authz.BatchCheckResponse{Results: make([]authz.CheckResponse, len(request.Checks))}
+		for i, check := range request.Checks {
+			response.Results[i] = authz.CheckResponse{Allowed: true, Reason: check.Subject.UserId}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func checkRequests(count int) []authz.CheckRequest {
+	requests := make([]authz.CheckRequest, count)
+	for i := range requests {
+		requests[i] = authz.CheckRequest{Subject: authz.Subject{UserId: "user-" + strconv.Itoa(i)}, PermissionIds: []int{601}}
+	}
+	return requests
+}
+
+func TestCheckBatchSplitsLargeBatches(t *testing.T) {
+	var batchSizes []int
+	server := newBatchServer(t, &batchSizes)
+	authzClient := NewAuthzClient(server.URL, "key", time.Minute)
+
+	results, err := authzClient.CheckBatch(context.Background(), checkRequests(250))
+	if err != nil {
+		t.Fatalf("CheckBatch failed: %v", err)
+	}
+	if len(batchSizes) != 3 || batchSizes[0] != 100 || batchSizes[1] != 100 || batchSizes[2] != 50 {
+		t.Fatalf("batch sizes = %v, want [100 100 50]", batchSizes)
+	}
+	for i, result := range results {
+		if want := "user-" + strconv.Itoa(i); !result.Allowed || result.Reason != want {
+			t.Fatalf("result %d = %+v, want the decision for %s", i, result, want)
+		}
+	}
+
+	// Las decisiones cacheadas no se vuelven a enviar
+	batchSizes = nil
+	if _, err := authzClient.CheckBatch(context.Background(), checkRequests(260)); err != nil {
+		t.Fatalf("CheckBatch failed: %v", err)
+	}
+	if len(batchSizes) != 1 || batchSizes[0] != 10 {
+		t.Fatalf("batch sizes = %v, want [10]", batchSizes)
+	}
+}
+
+func TestCacheIsBounded(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		entries int
+	}{
+		{name: "expired entries are swept", ttl: time.Nanosecond, entries: 50},
+		{name: "live entries are evicted", ttl: time.Hour, entries: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authzClient := NewAuthzClient("http://localhost", "key", tt.ttl)
+			authzClient.SetMaxCachedDecisions(10)
+
+			for i := 0; i < tt.entries; i++ {
+				authzClient.store("key-"+strconv.Itoa(i), &authz.CheckResponse{Allowed: true})
+			}
+
+			if size := len(authzClient.cache); size > 10 {
+				t.Fatalf("cache size = %d, want at most 10", size)
+			}
+			if _, ok := authzClient.cache["key-"+strconv.Itoa(tt.entries-1)]; !ok {
+				t.Fatal("the latest decision should be cached")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -38,6 +39,9 @@ var _ = swagger.Swagger().Path("/api/v1/login-with-google").
 				response.Description("Login response with user details and JWT token").
 					SchemaFromDTO(&auth.LoginWithAnyResponse{})
 			}).
+			Response(http.StatusForbidden, func(response openapi.Response) {
+				response.Description("The account is suspended, deactivated or deleted; code is ACCOUNT_SUSPENDED, ACCOUNT_DEACTIVATED or ACCOUNT_DELETED")
+			}).
 			Security("BearerAuth")
 	}).Doc()
 
@@ -51,7 +55,7 @@ func (authController *AuthController) LoginWithGoogle(c *gin.Context) {
 
 	response, err := authController.authService.LoginWithGoogle(loginRequest)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeLoginError(c, err)
 		return
 	}
 
@@ -74,6 +78,9 @@ var _ = swagger.Swagger().Path("/api/v1/auth/login-with-email").
 				response.Description("Login response with user details and JWT token").
 					SchemaFromDTO(&auth.LoginWithAnyResponse{})
 			}).
+			Response(http.StatusForbidden, func(response openapi.Response) {
+				response.Description("The account is suspended, deactivated or deleted; code is ACCOUNT_SUSPENDED, ACCOUNT_DEACTIVATED or ACCOUNT_DELETED")
+			}).
 			Security("BearerAuth")
 	}).Doc()
 
@@ -87,9 +94,24 @@ func (authController *AuthController) LoginWithEmail(c *gin.Context) {
 
 	response, err := authController.authService.LoginWithEmail(loginRequest)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeLoginError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+// writeLoginError answers 403 with a code such as ACCOUNT_SUSPENDED when the credentials are valid
+// but the account cannot log in, and 401 otherwise
+func writeLoginError(c *gin.Context, err error) {
+	var notActive *service.AccountNotActiveError
+	if errors.As(err, &notActive) {
+		body := gin.H{"error": notActive.Error(), "code": "ACCOUNT_" + notActive.Status}
+		if notActive.SuspendedUntil != nil {
+			body["suspendedUntil"] = notActive.SuspendedUntil
+		}
+		c.JSON(http.StatusForbidden, body)
+		return
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+}
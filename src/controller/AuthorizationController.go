@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/authz"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type AuthorizationController struct {
+	authorizationService service.AuthorizationService
+}
+
+func NewAuthorizationController() *AuthorizationController {
+	return &AuthorizationController{
+		authorizationService: impl.NewAuthorizationServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/authz/check").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Check permissions of a subject").
+			Description("Evaluates the permissions of a user (by id or token) against live role data. Intended for other services.").
+			OperationID("CheckAuthorization").
+			Tag("AuthorizationController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			HeaderParameter(middleware.ServiceAccountHeader, func(param openapi.Parameter) {
+				param.Description("Service account key").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Subject and permissions or resource to check").
+					Required(true).
+					SchemaFromDTO(&authz.CheckRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Authorization decision").
+					SchemaFromDTO(&authz.CheckResponse{})
+			})
+	}).Doc()
+
+func (a *AuthorizationController) Check(c *gin.Context) {
+	var request authz.CheckRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := a.authorizationService.Check(&request)
+	if err != nil {
+		writeAuthorizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/authz/check/batch").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Check permissions of several subjects").
+			OperationID("CheckAuthorizationBatch").
+			Tag("AuthorizationController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			HeaderParameter(middleware.ServiceAccountHeader, func(param openapi.Parameter) {
+				param.Description("Service account key").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("List of checks (at most 100)").
+					Required(true).
+					SchemaFromDTO(&authz.BatchCheckRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Authorization decisions in request order").
+					SchemaFromDTO(&authz.BatchCheckResponse{})
+			})
+	}).Doc()
+
+func (a *AuthorizationController) CheckBatch(c *gin.Context) {
+	var request authz.BatchCheckRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := a.authorizationService.CheckBatch(&request)
+	if err != nil {
+		writeAuthorizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/authz/explain").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Explain an authorization decision").
+			Description("Lists the grants and denies that apply to a user for a permission and which one decided the outcome. Denies take precedence over grants.").
+			OperationID("ExplainAuthorization").
+			Tag("AuthorizationController").
+			Produces(mime.ApplicationJSON).
+			QueryParameter("userId", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			QueryParameter("permissionId", func(param openapi.Parameter) {
+				param.Description("ID of the permission").
+					Required(true).
+					Type("integer")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Explanation of the decision").
+					SchemaFromDTO(&authz.ExplainResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (a *AuthorizationController) Explain(c *gin.Context) {
+	permissionId, err := strconv.Atoi(c.Query("permissionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid permissionId"})
+		return
+	}
+
+	response, err := a.authorizationService.Explain(c.Query("userId"), permissionId)
+	if err != nil {
+		writeAuthorizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func writeAuthorizationError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidAuthorizationRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrSubjectNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to evaluate authorization"})
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/blob"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type BlobController struct{}
+
+func NewBlobController() *BlobController {
+	return &BlobController{}
+}
+
+// GetBlob serves the objects of the local blob store, such as profile images. With GCS the clients
+// download from signed urls instead and this route always answers 404.
+func (b *BlobController) GetBlob(c *gin.Context) {
+	store, ok := blob.Default().(blob.ServingBlobStore)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blob not found"})
+		return
+	}
+
+	// Only profile images are public; other objects, such as data exports, are served by their own routes
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if !strings.HasPrefix(key, model.AvatarKeyPrefix) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blob not found"})
+		return
+	}
+
+	reader, contentType, err := store.Open(c.Request.Context(), key)
+	if errors.Is(err, blob.ErrBlobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blob not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Error opening blob: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read blob"})
+		return
+	}
+	defer reader.Close()
+
+	// Keys are never reused, a new image always gets a new key
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		log.Printf("Error serving blob: %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/datarequest"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type DataSubjectRequestController struct {
+	dataSubjectRequestService service.DataSubjectRequestService
+}
+
+func NewDataSubjectRequestController() *DataSubjectRequestController {
+	return &DataSubjectRequestController{
+		dataSubjectRequestService: impl.NewDataSubjectRequestServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me/data-export").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Request an export of the data of the authenticated user").
+			Description("The export is processed in the background. Once COMPLETED, the JSON archive with the profile, identities, roles, groups, organizations and audit entries can be downloaded until archiveExpiresAt. Requesting again while an export is open returns the open request.").
+			OperationID("RequestDataExport").
+			Tag("DataSubjectRequestController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusAccepted, func(response openapi.Response) {
+				response.Description("Export request").
+					SchemaFromDTO(&datarequest.DataSubjectRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (d *DataSubjectRequestController) RequestDataExport(c *gin.Context) {
+	response, err := d.dataSubjectRequestService.RequestExport(middleware.SubjectId(c), middleware.SubjectId(c))
+	if err != nil {
+		writeDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me/erasure-request").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Request the erasure of the data of the authenticated user").
+			Description("The erasure is processed in the background: the personal data of the user is anonymized, the user loses its roles and tokens, and a user.erased event is published so other services erase their data too. It cannot be undone.").
+			OperationID("RequestErasure").
+			Tag("DataSubjectRequestController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusAccepted, func(response openapi.Response) {
+				response.Description("Erasure request").
+					SchemaFromDTO(&datarequest.DataSubjectRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (d *DataSubjectRequestController) RequestMyErasure(c *gin.Context) {
+	response, err := d.dataSubjectRequestService.RequestErasure(middleware.SubjectId(c), middleware.SubjectId(c))
+	if err != nil {
+		writeDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me/data-requests").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("List the data requests of the authenticated user").
+			OperationID("GetMyDataRequests").
+			Tag("DataSubjectRequestController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Export and erasure requests, newest first").
+					SchemaFromDTO(&[]*datarequest.DataSubjectRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (d *DataSubjectRequestController) GetMyRequests(c *gin.Context) {
+	response, err := d.dataSubjectRequestService.GetUserRequests(middleware.SubjectId(c))
+	if err != nil {
+		writeDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me/data-requests/{id}").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get a data request of the authenticated user").
+			OperationID("GetMyDataRequest").
+			Tag("DataSubjectRequestController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the request").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Data request").
+					SchemaFromDTO(&datarequest.DataSubjectRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (d *DataSubjectRequestController) GetMyRequest(c *gin.Context) {
+	response, err := d.dataSubjectRequestService.GetUserRequest(middleware.SubjectId(c), c.Param("id"))
+	if err != nil {
+		writeDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me/data-requests/{id}/archive").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Download the archive of a completed data export").
+			Description("Returns the JSON archive, or redirects to a signed url when the blob store is GCS.").
+			OperationID("DownloadDataExport").
+			Tag("DataSubjectRequestController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the export request").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Data export archive").
+					SchemaFromDTO(&datarequest.DataExportArchive{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (d *DataSubjectRequestController) DownloadDataExport(c *gin.Context) {
+	download, err := d.dataSubjectRequestService.OpenDataExport(middleware.SubjectId(c), c.Param("id"))
+	if err != nil {
+		writeDataSubjectRequestError(c, err)
+		return
+	}
+	if download.RedirectUrl != "" {
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusFound, download.RedirectUrl)
+		return
+	}
+	defer download.Reader.Close()
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", `attachment; filename="data-export.json"`)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Type", download.ContentType)
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, download.Reader); err != nil {
+		log.Printf("Error serving data export: %v", err)
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/data-requests").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("List the data requests of all users").
+			OperationID("GetDataRequests").
+			Tag("DataSubjectRequestController").
+			Produces(mime.ApplicationJSON).
+			QueryParameter("status", func(param openapi.Parameter) {
+				param.Description("PENDING, PROCESSING, COMPLETED or FAILED").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("userId", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(false).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Export and erasure requests, newest first").
+					SchemaFromDTO(&[]*datarequest.DataSubjectRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (d *DataSubjectRequestController) GetRequests(c *gin.Context) {
+	response, err := d.dataSubjectRequestService.GetRequests(c.Query("status"), c.Query("userId"))
+	if err != nil {
+		writeDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/users/{id}/erasure-request").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Request the erasure of the data of a user").
+			Description("For requests received through other channels. Processed like the erasure requested by the user.").
+			OperationID("RequestUserErasure").
+			Tag("DataSubjectRequestController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusAccepted, func(response openapi.Response) {
+				response.Description("Erasure request").
+					SchemaFromDTO(&datarequest.DataSubjectRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (d *DataSubjectRequestController) RequestUserErasure(c *gin.Context) {
+	response, err := d.dataSubjectRequestService.RequestErasure(c.Param("id"), middleware.SubjectId(c))
+	if err != nil {
+		writeDataSubjectRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+func writeDataSubjectRequestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrDataSubjectRequestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data request not found"})
+	case errors.Is(err, service.ErrDataExportNotAvailable):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrUserErased):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process data request"})
+	}
+}
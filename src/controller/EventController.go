@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/event"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type EventController struct {
+	eventService service.EventService
+}
+
+func NewEventController() *EventController {
+	return &EventController{
+		eventService: impl.NewEventServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/events").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Read the event feed").
+			Description("Events published by this service, such as user.erased, in creation order. Consumers keep the returned nextPageToken and poll again with it; it is returned even when there are no new events. Events are delivered a few seconds after they are created.").
+			OperationID("GetEvents").
+			Tag("EventController").
+			Produces(mime.ApplicationJSON).
+			HeaderParameter(middleware.ServiceAccountHeader, func(param openapi.Parameter) {
+				param.Description("Service account key").
+					Required(true).
+					Type("string")
+			}).
+			QueryParameter("pageToken", func(param openapi.Parameter) {
+				param.Description("Token returned by the previous call; empty to read from the beginning").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("size", func(param openapi.Parameter) {
+				param.Description("Maximum number of events, up to 500").
+					Required(false).
+					Type("integer")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Events and the token to continue").
+					SchemaFromDTO(&event.OutboxEventPageResponse{})
+			})
+	}).Doc()
+
+func (e *EventController) GetEvents(c *gin.Context) {
+	size := 0
+	if value := c.Query("size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "size must be a positive integer"})
+			return
+		}
+		size = parsed
+	}
+
+	response, err := e.eventService.GetEvents(c.Query("pageToken"), size)
+	if errors.Is(err, dto.ErrInvalidPageToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type ExportController struct {
+	exportService service.ExportService
+}
+
+func NewExportController() *ExportController {
+	return &ExportController{
+		exportService: impl.NewExportServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/users/export").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Export users as NDJSON or CSV").
+			Description("Streams every user matching the same filters and sort as GET /api/v1/users/pages, reading Firestore in chunks of 500. Only the exportable fields can be selected; the password hash is never exported. If reading fails midway the connection is closed without completing the response, so a truncated export is not mistaken for a complete one.").
+			OperationID("ExportUsers").
+			Tag("ExportController").
+			Produces("application/x-ndjson", "text/csv").
+			QueryParameter("format", func(param openapi.Parameter) {
+				param.Description("ndjson (default) or csv").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("fields", func(param openapi.Parameter) {
+				param.Description("Comma-separated fields in output order: id, email, fullName, status, statusReason, suspendedUntil, purgeAt, roleIds, roleCodes, deniedPermissionIds, pictureUrl, favoriteNewsArticleIds, createdAt, updatedAt. Defaults to id, email, fullName, status, roleCodes, createdAt and updatedAt").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("query", func(param openapi.Parameter) {
+				param.Description("Text contained in the email or the full name, case-insensitive").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("emailPrefix", func(param openapi.Parameter) {
+				param.Description("Prefix of the email").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("name", func(param openapi.Parameter) {
+				param.Description("Text contained in the full name, case-insensitive").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("roleId", func(param openapi.Parameter) {
+				param.Description("ID of a role directly assigned to the user").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("status", func(param openapi.Parameter) {
+				param.Description("Comma-separated statuses: ACTIVE, SUSPENDED, DEACTIVATED, DELETED or ERASED; all but DELETED and ERASED by default").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("createdFrom", func(param openapi.Parameter) {
+				param.Description("Minimum creation date, inclusive, as RFC 3339 or YYYY-MM-DD").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("createdTo", func(param openapi.Parameter) {
+				param.Description("Maximum creation date, inclusive, as RFC 3339 or YYYY-MM-DD; a date includes the whole day").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("sort", func(param openapi.Parameter) {
+				param.Description("Sort field: createdAt (default), updatedAt, email or fullName").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("direction", func(param openapi.Parameter) {
+				param.Description("asc or desc; dates default to desc and text fields to asc").
+					Required(false).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("One line per user")
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (e *ExportController) ExportUsers(c *gin.Context) {
+	var search user.UserSearchRequest
+	var request dto.ExportRequest
+	if err := c.ShouldBindQuery(&search); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	export, err := e.exportService.ExportUsers(&search, &request)
+	if err != nil {
+		writeExportError(c, err)
+		return
+	}
+	streamExport(c, export)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/roles/export").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Export global roles as NDJSON or CSV").
+			Description("Streams the global roles ordered by code, reading Firestore in chunks of 500.").
+			OperationID("ExportRoles").
+			Tag("ExportController").
+			Produces("application/x-ndjson", "text/csv").
+			QueryParameter("format", func(param openapi.Parameter) {
+				param.Description("ndjson (default) or csv").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("fields", func(param openapi.Parameter) {
+				param.Description("Comma-separated fields in output order: id, code, privileged, system, permissionIds, permissionGroups and deniedPermissionIds, all by default").
+					Required(false).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("One line per role")
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (e *ExportController) ExportRoles(c *gin.Context) {
+	var request dto.ExportRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	export, err := e.exportService.ExportRoles(&request)
+	if err != nil {
+		writeExportError(c, err)
+		return
+	}
+	streamExport(c, export)
+}
+
+// streamExport writes the export as an attachment. The status is sent before the first chunk is read, so a
+// failure midway can only be signaled by closing the connection without the final chunk.
+func streamExport(c *gin.Context, export *service.Export) {
+	c.Header("Content-Type", export.ContentType)
+	c.Header("Content-Disposition", `attachment; filename="`+export.FileName+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+
+	if err := export.Write(c.Writer); err != nil {
+		log.Printf("Error streaming export: %v", err)
+		if conn, _, hijackErr := c.Writer.Hijack(); hijackErr == nil {
+			conn.Close()
+		}
+	}
+}
+
+func writeExportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidExport), errors.Is(err, service.ErrInvalidUserFilter):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export"})
+	}
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/favorite"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type FavoriteNewsController struct {
+	favoriteNewsService service.FavoriteNewsService
+}
+
+func NewFavoriteNewsController() *FavoriteNewsController {
+	return &FavoriteNewsController{
+		favoriteNewsService: impl.NewFavoriteNewsServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me/favorites/news").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("List the favorite news articles of the authenticated user").
+			Description("Most recently added first.").
+			OperationID("GetMyFavoriteNews").
+			Tag("FavoriteNewsController").
+			Produces(mime.ApplicationJSON).
+			QueryParameter("page", func(param openapi.Parameter) {
+				param.Description("Page number, starting at 1").
+					Required(false).
+					Type("integer")
+			}).
+			QueryParameter("size", func(param openapi.Parameter) {
+				param.Description("Number of items per page, at most 100").
+					Required(false).
+					Type("integer")
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (f *FavoriteNewsController) GetFavorites(c *gin.Context) {
+	pageable := dto.NewPageable(c.Query("page"), c.Query("size"), "")
+
+	response, err := f.favoriteNewsService.GetFavorites(c, middleware.SubjectId(c), pageable)
+	if err != nil {
+		writeFavoriteNewsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me/favorites/news/{articleId}").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Check whether a news article is a favorite of the authenticated user").
+			OperationID("GetMyFavoriteNewsArticle").
+			Tag("FavoriteNewsController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("articleId", func(param openapi.Parameter) {
+				param.Description("ID of the news article").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Favorite status").
+					SchemaFromDTO(&favorite.FavoriteNewsStatusResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Add a news article to the favorites of the authenticated user").
+			Description("Idempotent. A user can have at most 500 favorites; adding more answers 409.").
+			OperationID("AddMyFavoriteNewsArticle").
+			Tag("FavoriteNewsController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("articleId", func(param openapi.Parameter) {
+				param.Description("ID of the news article").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Favorite status").
+					SchemaFromDTO(&favorite.FavoriteNewsStatusResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Delete(func(operation openapi.Operation) {
+		operation.Summary("Remove a news article from the favorites of the authenticated user").
+			Description("Idempotent.").
+			OperationID("RemoveMyFavoriteNewsArticle").
+			Tag("FavoriteNewsController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("articleId", func(param openapi.Parameter) {
+				param.Description("ID of the news article").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Favorite status").
+					SchemaFromDTO(&favorite.FavoriteNewsStatusResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (f *FavoriteNewsController) GetFavorite(c *gin.Context) {
+	response, err := f.favoriteNewsService.GetFavorite(middleware.SubjectId(c), c.Param("articleId"))
+	if err != nil {
+		writeFavoriteNewsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (f *FavoriteNewsController) AddFavorite(c *gin.Context) {
+	response, err := f.favoriteNewsService.AddFavorite(middleware.SubjectId(c), c.Param("articleId"))
+	if err != nil {
+		writeFavoriteNewsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (f *FavoriteNewsController) RemoveFavorite(c *gin.Context) {
+	response, err := f.favoriteNewsService.RemoveFavorite(middleware.SubjectId(c), c.Param("articleId"))
+	if err != nil {
+		writeFavoriteNewsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/news/{articleId}/favorites/count").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Count the users that have a news article as favorite").
+			OperationID("CountNewsFavorites").
+			Tag("FavoriteNewsController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("articleId", func(param openapi.Parameter) {
+				param.Description("ID of the news article").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Number of users").
+					SchemaFromDTO(&favorite.NewsFavoriteCountResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (f *FavoriteNewsController) CountFavorites(c *gin.Context) {
+	response, err := f.favoriteNewsService.CountFavorites(c.Param("articleId"))
+	if err != nil {
+		writeFavoriteNewsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func writeFavoriteNewsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidNewsArticleId):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFavoriteLimitReached):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process favorites"})
+	}
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/group"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type GroupController struct {
+	groupService service.GroupService
+}
+
+func NewGroupController() *GroupController {
+	return &GroupController{
+		groupService: impl.NewGroupServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/groups").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Create a group").
+			Description("Members of the group inherit its roles. Privileged and organization roles cannot be assigned to groups.").
+			OperationID("CreateGroup").
+			Tag("GroupController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Name, roles and initial members of the group").
+					Required(true).
+					SchemaFromDTO(&group.CreateGroupRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Created group").
+					SchemaFromDTO(&group.GroupResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get all groups").
+			OperationID("GetAllGroups").
+			Tag("GroupController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("List of groups").
+					SchemaFromDTO(&[]*group.GroupResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Update a group").
+			Description("Changes to the roles apply immediately to every member. Members are managed with the members endpoints.").
+			OperationID("UpdateGroup").
+			Tag("GroupController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Group to update").
+					Required(true).
+					SchemaFromDTO(&group.UpdateGroupRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Updated group").
+					SchemaFromDTO(&group.GroupResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (g *GroupController) CreateGroup(c *gin.Context) {
+	var request group.CreateGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := g.groupService.CreateGroup(&request)
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (g *GroupController) GetAllGroups(c *gin.Context) {
+	response, err := g.groupService.GetAllGroups()
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (g *GroupController) UpdateGroup(c *gin.Context) {
+	var request group.UpdateGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := g.groupService.UpdateGroup(&request)
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/groups/{id}").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get a group").
+			OperationID("GetGroupById").
+			Tag("GroupController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the group").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Group").
+					SchemaFromDTO(&group.GroupResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Delete(func(operation openapi.Operation) {
+		operation.Summary("Delete a group").
+			Description("Members lose the inherited roles immediately.").
+			OperationID("DeleteGroupById").
+			Tag("GroupController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the group").
+					Required(true).
+					Type("string")
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (g *GroupController) GetGroupById(c *gin.Context) {
+	response, err := g.groupService.GetGroupById(c.Param("id"))
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (g *GroupController) DeleteGroupById(c *gin.Context) {
+	if err := g.groupService.DeleteGroupById(c.Param("id")); err != nil {
+		writeGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+var _ = swagger.Swagger().Path("/api/v1/groups/{id}/members/{userId}").
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Add a member to a group").
+			OperationID("AddGroupMember").
+			Tag("GroupController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the group").
+					Required(true).
+					Type("string")
+			}).
+			PathParameter("userId", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Group with the new member").
+					SchemaFromDTO(&group.GroupResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Delete(func(operation openapi.Operation) {
+		operation.Summary("Remove a member from a group").
+			Description("The user loses the inherited roles immediately in authorization checks; issued tokens keep them until they expire.").
+			OperationID("RemoveGroupMember").
+			Tag("GroupController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the group").
+					Required(true).
+					Type("string")
+			}).
+			PathParameter("userId", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Group without the member").
+					SchemaFromDTO(&group.GroupResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (g *GroupController) AddMember(c *gin.Context) {
+	response, err := g.groupService.AddMember(c.Param("id"), c.Param("userId"))
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (g *GroupController) RemoveMember(c *gin.Context) {
+	response, err := g.groupService.RemoveMember(c.Param("id"), c.Param("userId"))
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func writeGroupError(c *gin.Context, err error) {
+	if writeSodViolation(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidGroup):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrGroupNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process group"})
+	}
+}
@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type MeController struct {
+	meService service.MeService
+}
+
+func NewMeController() *MeController {
+	return &MeController{
+		meService: impl.NewMeServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get the profile of the authenticated user").
+			Description("The user is taken from the JWT subject; no permission is required.").
+			OperationID("GetMe").
+			Tag("MeController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Profile of the authenticated user").
+					SchemaFromDTO(&user.GetUserByIdResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Patch(func(operation openapi.Operation) {
+		operation.Summary("Update the profile of the authenticated user").
+			Description("Only the fields of the request can be changed; omitted fields are kept. Unknown fields such as roleIds are rejected.").
+			OperationID("UpdateMe").
+			Tag("MeController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Profile fields to change").
+					Required(true).
+					SchemaFromDTO(&user.UpdateMeRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Updated profile").
+					SchemaFromDTO(&user.GetUserByIdResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Delete(func(operation openapi.Operation) {
+		operation.Summary("Delete the account of the authenticated user").
+			OperationID("DeleteMe").
+			Tag("MeController").
+			Response(http.StatusNoContent, func(response openapi.Response) {
+				response.Description("Account deleted")
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (m *MeController) GetMe(c *gin.Context) {
+	response, err := m.meService.GetMe(middleware.SubjectId(c))
+	if err != nil {
+		writeMeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (m *MeController) UpdateMe(c *gin.Context) {
+	var request user.UpdateMeRequest
+	// Unknown fields are rejected so that a client sending roleIds learns they cannot be changed here
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := m.meService.UpdateMe(middleware.SubjectId(c), &request)
+	if err != nil {
+		writeMeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (m *MeController) DeleteMe(c *gin.Context) {
+	if err := m.meService.DeleteMe(middleware.SubjectId(c)); err != nil {
+		writeMeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me/password").
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Change the password of the authenticated user").
+			Description("Requires the current password. Tokens issued before the change are rejected with TOKEN_STALE, so the user must log in again.").
+			OperationID("ChangeMyPassword").
+			Tag("MeController").
+			Consume(mime.ApplicationJSON).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Current and new password").
+					Required(true).
+					SchemaFromDTO(&user.ChangePasswordRequest{})
+			}).
+			Response(http.StatusNoContent, func(response openapi.Response) {
+				response.Description("Password changed")
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (m *MeController) ChangePassword(c *gin.Context) {
+	var request user.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := m.meService.ChangePassword(middleware.SubjectId(c), &request); err != nil {
+		writeMeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/me/avatar").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Upload the profile image of the authenticated user").
+			Description("JPEG, PNG or GIF up to 5 MiB and 4096x4096 pixels; the format is detected from the content. The image is cropped to a square and stored in 512, 128 and 64 pixel versions, which take precedence over the Google picture.").
+			OperationID("UploadMyAvatar").
+			Tag("MeController").
+			Consume(mime.MimeType("multipart/form-data")).
+			Produces(mime.ApplicationJSON).
+			FormParameter("file", func(param openapi.Parameter) {
+				param.Description("Image file").
+					Required(true).
+					Type("file")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Profile with the new image urls").
+					SchemaFromDTO(&user.GetUserByIdResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Delete(func(operation openapi.Operation) {
+		operation.Summary("Delete the uploaded profile image of the authenticated user").
+			OperationID("DeleteMyAvatar").
+			Tag("MeController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Profile without the uploaded image").
+					SchemaFromDTO(&user.GetUserByIdResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (m *MeController) UploadAvatar(c *gin.Context) {
+	// The multipart envelope adds a few bytes on top of the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxAvatarBytes+64<<10)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			writeMeError(c, service.ErrImageTooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A multipart file field named file is required"})
+		return
+	}
+	if fileHeader.Size > service.MaxAvatarBytes {
+		writeMeError(c, service.ErrImageTooLarge)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, service.MaxAvatarBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+
+	response, err := m.meService.UploadAvatar(middleware.SubjectId(c), data)
+	if err != nil {
+		writeMeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (m *MeController) DeleteAvatar(c *gin.Context) {
+	response, err := m.meService.DeleteAvatar(middleware.SubjectId(c))
+	if err != nil {
+		writeMeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func writeMeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrImageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnsupportedImage):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidImage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidProfile), errors.Is(err, service.ErrInvalidPassword):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWrongCurrentPassword):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process profile"})
+	}
+}
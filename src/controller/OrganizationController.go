@@ -0,0 +1,410 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/organization"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/role"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type OrganizationController struct {
+	organizationService service.OrganizationService
+}
+
+func NewOrganizationController() *OrganizationController {
+	return &OrganizationController{
+		organizationService: impl.NewOrganizationServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/organizations").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Create an organization").
+			Description("Creates the organization, its ORG_ADMIN role and the membership of the owner.").
+			OperationID("CreateOrganization").
+			Tag("OrganizationController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Name and owner of the organization").
+					Required(true).
+					SchemaFromDTO(&organization.CreateOrganizationRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Created organization").
+					SchemaFromDTO(&organization.OrganizationResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get all organizations").
+			OperationID("GetAllOrganizations").
+			Tag("OrganizationController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("List of organizations").
+					SchemaFromDTO(&[]*organization.OrganizationResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (o *OrganizationController) CreateOrganization(c *gin.Context) {
+	var request organization.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := o.organizationService.CreateOrganization(&request)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (o *OrganizationController) GetAllOrganizations(c *gin.Context) {
+	response, err := o.organizationService.GetAllOrganizations()
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/organizations/{orgId}").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get an organization").
+			OperationID("GetOrganizationById").
+			Tag("OrganizationController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("orgId", func(param openapi.Parameter) {
+				param.Description("ID of the organization").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Organization").
+					SchemaFromDTO(&organization.OrganizationResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Update an organization").
+			OperationID("UpdateOrganization").
+			Tag("OrganizationController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			PathParameter("orgId", func(param openapi.Parameter) {
+				param.Description("ID of the organization").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("New data of the organization").
+					Required(true).
+					SchemaFromDTO(&organization.UpdateOrganizationRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Updated organization").
+					SchemaFromDTO(&organization.OrganizationResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (o *OrganizationController) GetOrganizationById(c *gin.Context) {
+	response, err := o.organizationService.GetOrganizationById(c.Param("orgId"))
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (o *OrganizationController) UpdateOrganization(c *gin.Context) {
+	var request organization.UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := o.organizationService.UpdateOrganization(c.Param("orgId"), &request)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/organizations/{orgId}/members").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get the members of an organization").
+			OperationID("GetOrganizationMembers").
+			Tag("OrganizationController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("orgId", func(param openapi.Parameter) {
+				param.Description("ID of the organization").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Members and their roles in the organization").
+					SchemaFromDTO(&[]*organization.OrganizationMemberResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (o *OrganizationController) GetMembers(c *gin.Context) {
+	response, err := o.organizationService.GetMembers(c.Param("orgId"))
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/organizations/{orgId}/members/{userId}").
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Add a member or replace their roles").
+			Description("Only roles of the same organization can be assigned; they apply only within it. The owner must keep the ORG_ADMIN role.").
+			OperationID("SetOrganizationMember").
+			Tag("OrganizationController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			PathParameter("orgId", func(param openapi.Parameter) {
+				param.Description("ID of the organization").
+					Required(true).
+					Type("string")
+			}).
+			PathParameter("userId", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Roles of the member in the organization").
+					Required(true).
+					SchemaFromDTO(&organization.SetOrganizationMemberRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Membership").
+					SchemaFromDTO(&organization.OrganizationMemberResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Delete(func(operation openapi.Operation) {
+		operation.Summary("Remove a member").
+			OperationID("RemoveOrganizationMember").
+			Tag("OrganizationController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("orgId", func(param openapi.Parameter) {
+				param.Description("ID of the organization").
+					Required(true).
+					Type("string")
+			}).
+			PathParameter("userId", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (o *OrganizationController) SetMember(c *gin.Context) {
+	var request organization.SetOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := o.organizationService.SetMember(c.Param("orgId"), c.Param("userId"), &request)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (o *OrganizationController) RemoveMember(c *gin.Context) {
+	if err := o.organizationService.RemoveMember(c.Param("orgId"), c.Param("userId")); err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+var _ = swagger.Swagger().Path("/api/v1/organizations/{orgId}/roles").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get the roles of an organization").
+			OperationID("GetOrganizationRoles").
+			Tag("OrganizationController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("orgId", func(param openapi.Parameter) {
+				param.Description("ID of the organization").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Roles of the organization").
+					SchemaFromDTO(&[]*role.GetRoleByIdResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Create an organization role").
+			Description("Only permissions of other services and organization management permissions can be granted.").
+			OperationID("CreateOrganizationRole").
+			Tag("OrganizationController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			PathParameter("orgId", func(param openapi.Parameter) {
+				param.Description("ID of the organization").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Code and permissions of the role").
+					Required(true).
+					SchemaFromDTO(&organization.OrganizationRoleRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Created role").
+					SchemaFromDTO(&role.GetRoleByIdResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (o *OrganizationController) GetRoles(c *gin.Context) {
+	response, err := o.organizationService.GetRoles(c.Param("orgId"))
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (o *OrganizationController) CreateRole(c *gin.Context) {
+	var request organization.OrganizationRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := o.organizationService.CreateRole(c.Param("orgId"), &request)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/organizations/{orgId}/roles/{roleId}").
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Update an organization role").
+			Description("The ORG_ADMIN role cannot be renamed (409) and must keep its organization management permissions.").
+			OperationID("UpdateOrganizationRole").
+			Tag("OrganizationController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			PathParameter("orgId", func(param openapi.Parameter) {
+				param.Description("ID of the organization").
+					Required(true).
+					Type("string")
+			}).
+			PathParameter("roleId", func(param openapi.Parameter) {
+				param.Description("ID of the role").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Code and permissions of the role").
+					Required(true).
+					SchemaFromDTO(&organization.OrganizationRoleRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Updated role").
+					SchemaFromDTO(&role.GetRoleByIdResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Delete(func(operation openapi.Operation) {
+		operation.Summary("Delete an organization role").
+			Description("The role is also removed from the members that had it. The ORG_ADMIN role cannot be deleted (409).").
+			OperationID("DeleteOrganizationRole").
+			Tag("OrganizationController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("orgId", func(param openapi.Parameter) {
+				param.Description("ID of the organization").
+					Required(true).
+					Type("string")
+			}).
+			PathParameter("roleId", func(param openapi.Parameter) {
+				param.Description("ID of the role").
+					Required(true).
+					Type("string")
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (o *OrganizationController) UpdateRole(c *gin.Context) {
+	var request organization.OrganizationRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := o.organizationService.UpdateRole(c.Param("orgId"), c.Param("roleId"), &request)
+	if err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (o *OrganizationController) DeleteRole(c *gin.Context) {
+	if err := o.organizationService.DeleteRole(c.Param("orgId"), c.Param("roleId")); err != nil {
+		writeOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func writeOrganizationError(c *gin.Context, err error) {
+	if writeSodViolation(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidOrganization), errors.Is(err, service.ErrInvalidOrganizationRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+	case errors.Is(err, service.ErrOrganizationMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organization member not found"})
+	case errors.Is(err, service.ErrOrganizationRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrSystemRole):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process organization"})
+	}
+}
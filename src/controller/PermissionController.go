@@ -1,11 +1,14 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ruiborda/ecommerce-user-service/src/dto/permission"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
 	"github.com/ruiborda/ecommerce-user-service/src/service"
 	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
 	"github.com/ruiborda/go-swagger-generator/src/openapi"
@@ -42,6 +45,24 @@ func (p *PermissionController) GetAllPermissions(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+var _ = swagger.Swagger().Path("/api/v1/permissions/groups").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get permission groups").
+			Description("Permissions are grouped by the hundreds of their id. A role granting the wildcard of a group (e.g. users:*) receives every permission of the group, including ones registered later.").
+			OperationID("GetPermissionGroups").
+			Tag("PermissionController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Permission groups with their current permission ids").
+					SchemaFromDTO(&[]*permission.PermissionGroupResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (p *PermissionController) GetPermissionGroups(c *gin.Context) {
+	c.JSON(http.StatusOK, p.permissionService.GetPermissionGroups())
+}
+
 var _ = swagger.Swagger().Path("/api/v1/permissions/{id}").
 	Get(func(operation openapi.Operation) {
 		operation.Summary("Get permission by ID").
@@ -133,3 +154,86 @@ func (p *PermissionController) GetPermissionsByIds(c *gin.Context) {
 	response := p.permissionService.GetPermissionsByIdsAsArray(&request)
 	c.JSON(http.StatusOK, response)
 }
+
+var _ = swagger.Swagger().Path("/api/v1/permissions/catalog/{service}").
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Register the permission catalog of a service").
+			Description("Replaces the permissions published by a service. Only the service account of that service may call it; permissions missing from the request are flagged as deprecated.").
+			OperationID("RegisterServiceCatalog").
+			Tag("PermissionController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			PathParameter("service", func(param openapi.Parameter) {
+				param.Description("Namespace of the service registering its permissions").
+					Required(true).
+					Type("string")
+			}).
+			HeaderParameter(middleware.ServiceAccountHeader, func(param openapi.Parameter) {
+				param.Description("Service account key").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Permissions published by the service").
+					Required(true).
+					SchemaFromDTO(&permission.RegisterCatalogRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Registered catalog").
+					SchemaFromDTO(&permission.RegisterCatalogResponse{})
+			}).
+			Response(http.StatusConflict, func(response openapi.Response) {
+				response.Description("A permission id belongs to another service").
+					SchemaFromDTO(&permission.ErrorResponse{})
+			})
+	}).Doc()
+
+func (p *PermissionController) RegisterServiceCatalog(c *gin.Context) {
+	serviceName := c.Param("service")
+
+	// Un service account solo puede registrar su propio namespace
+	if c.GetString("serviceAccount") != serviceName {
+		c.JSON(http.StatusForbidden, &permission.ErrorResponse{
+			Success: false,
+			Message: "El service account no puede registrar permisos de otro servicio",
+		})
+		return
+	}
+
+	var request permission.RegisterCatalogRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, &permission.ErrorResponse{
+			Success: false,
+			Message: "Formato de solicitud inválido",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := p.permissionService.RegisterServiceCatalog(serviceName, &request)
+	if err != nil {
+		var collisionErr *repository.PermissionCollisionError
+		switch {
+		case errors.As(err, &collisionErr):
+			c.JSON(http.StatusConflict, &permission.ErrorResponse{
+				Success: false,
+				Message: "ID de permiso ya registrado por otro servicio",
+				Error:   err.Error(),
+			})
+		case errors.Is(err, service.ErrInvalidCatalog):
+			c.JSON(http.StatusBadRequest, &permission.ErrorResponse{
+				Success: false,
+				Message: "Catálogo de permisos inválido",
+				Error:   err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, &permission.ErrorResponse{
+				Success: false,
+				Message: "No se pudo registrar el catálogo de permisos",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type RoleAssignmentController struct {
+	roleAssignmentService service.RoleAssignmentService
+}
+
+func NewRoleAssignmentController() *RoleAssignmentController {
+	return &RoleAssignmentController{
+		roleAssignmentService: impl.NewRoleAssignmentServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/users/{id}/role-assignments").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Grant a role temporarily").
+			Description("Assigns a role to the user between validFrom and validUntil. Outside that window the role is ignored for tokens and authorization checks, and it is removed automatically once expired.").
+			OperationID("GrantTemporaryRole").
+			Tag("RoleAssignmentController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Role and validity window").
+					Required(true).
+					SchemaFromDTO(&user.GrantTemporaryRoleRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Temporary role assignments of the user").
+					SchemaFromDTO(&user.RoleAssignmentsResponse{})
+			}).
+			Response(http.StatusAccepted, func(response openapi.Response) {
+				response.Description("The role is privileged; the grant is pending approval").
+					SchemaFromDTO(&user.RoleAssignmentsResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get temporary role assignments").
+			OperationID("GetRoleAssignments").
+			Tag("RoleAssignmentController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Temporary role assignments of the user and their history").
+					SchemaFromDTO(&user.RoleAssignmentsResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (r *RoleAssignmentController) GrantTemporaryRole(c *gin.Context) {
+	userId := c.Param("id")
+	if _, err := uuid.Parse(userId); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format"})
+		return
+	}
+
+	var request user.GrantTemporaryRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := r.roleAssignmentService.GrantTemporaryRole(userId, &request, middleware.SubjectId(c))
+	if err != nil {
+		writeRoleAssignmentError(c, err)
+		return
+	}
+
+	// Los roles privilegiados se asignan cuando se aprueba la solicitud
+	if response.PendingRoleChangeRequestId != "" {
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (r *RoleAssignmentController) GetRoleAssignments(c *gin.Context) {
+	userId := c.Param("id")
+	if _, err := uuid.Parse(userId); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format"})
+		return
+	}
+
+	response, err := r.roleAssignmentService.GetRoleAssignments(userId)
+	if err != nil {
+		writeRoleAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/users/{id}/role-assignments/{roleId}").
+	Delete(func(operation openapi.Operation) {
+		operation.Summary("Revoke a temporary role").
+			OperationID("RevokeTemporaryRole").
+			Tag("RoleAssignmentController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			PathParameter("roleId", func(param openapi.Parameter) {
+				param.Description("ID of the temporarily assigned role").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Remaining temporary role assignments of the user").
+					SchemaFromDTO(&user.RoleAssignmentsResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (r *RoleAssignmentController) RevokeTemporaryRole(c *gin.Context) {
+	userId := c.Param("id")
+	if _, err := uuid.Parse(userId); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format"})
+		return
+	}
+
+	response, err := r.roleAssignmentService.RevokeTemporaryRole(userId, c.Param("roleId"), middleware.SubjectId(c))
+	if err != nil {
+		writeRoleAssignmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func writeRoleAssignmentError(c *gin.Context, err error) {
+	if writeSodViolation(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidRoleAssignment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+	case errors.Is(err, service.ErrRoleAssignmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role assignment not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role assignments"})
+	}
+}
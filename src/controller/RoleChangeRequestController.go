@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/rolechange"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type RoleChangeRequestController struct {
+	roleChangeRequestService service.RoleChangeRequestService
+}
+
+func NewRoleChangeRequestController() *RoleChangeRequestController {
+	return &RoleChangeRequestController{
+		roleChangeRequestService: impl.NewRoleChangeRequestServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/role-change-requests").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("List role change requests").
+			Description("Assigning a privileged role creates a pending request instead of changing the user. Requests are never deleted and keep every status transition.").
+			OperationID("GetRoleChangeRequests").
+			Tag("RoleChangeRequestController").
+			Produces(mime.ApplicationJSON).
+			QueryParameter("status", func(param openapi.Parameter) {
+				param.Description("PENDING, APPROVED or REJECTED").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("userId", func(param openapi.Parameter) {
+				param.Description("ID of the affected user").
+					Required(false).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Role change requests, newest first").
+					SchemaFromDTO(&[]*rolechange.RoleChangeRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (r *RoleChangeRequestController) GetRoleChangeRequests(c *gin.Context) {
+	response, err := r.roleChangeRequestService.GetRoleChangeRequests(c.Query("status"), c.Query("userId"))
+	if err != nil {
+		writeRoleChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/role-change-requests/{id}").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get a role change request").
+			OperationID("GetRoleChangeRequestById").
+			Tag("RoleChangeRequestController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the request").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Role change request").
+					SchemaFromDTO(&rolechange.RoleChangeRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (r *RoleChangeRequestController) GetRoleChangeRequestById(c *gin.Context) {
+	response, err := r.roleChangeRequestService.GetRoleChangeRequestById(c.Param("id"))
+	if err != nil {
+		writeRoleChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/role-change-requests/{id}/approve").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Approve a role change request").
+			Description("Assigns the requested roles to the user. The requester and the affected user cannot approve it.").
+			OperationID("ApproveRoleChangeRequest").
+			Tag("RoleChangeRequestController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the request").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Optional comment").
+					Required(false).
+					SchemaFromDTO(&rolechange.DecideRoleChangeRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Approved request").
+					SchemaFromDTO(&rolechange.RoleChangeRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (r *RoleChangeRequestController) ApproveRoleChangeRequest(c *gin.Context) {
+	var request rolechange.DecideRoleChangeRequest
+	if !bindOptionalJSON(c, &request) {
+		return
+	}
+
+	response, err := r.roleChangeRequestService.ApproveRoleChangeRequest(c.Param("id"), middleware.SubjectId(c), &request)
+	if err != nil {
+		writeRoleChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/role-change-requests/{id}/reject").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Reject a role change request").
+			OperationID("RejectRoleChangeRequest").
+			Tag("RoleChangeRequestController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the request").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Optional comment").
+					Required(false).
+					SchemaFromDTO(&rolechange.DecideRoleChangeRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Rejected request").
+					SchemaFromDTO(&rolechange.RoleChangeRequestResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (r *RoleChangeRequestController) RejectRoleChangeRequest(c *gin.Context) {
+	var request rolechange.DecideRoleChangeRequest
+	if !bindOptionalJSON(c, &request) {
+		return
+	}
+
+	response, err := r.roleChangeRequestService.RejectRoleChangeRequest(c.Param("id"), middleware.SubjectId(c), &request)
+	if err != nil {
+		writeRoleChangeRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// bindOptionalJSON acepta un body vacío; responde 400 si el body no es JSON válido
+func bindOptionalJSON(c *gin.Context, target any) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(target); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeRoleChangeRequestError(c *gin.Context, err error) {
+	if writeSodViolation(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrRoleChangeRequestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role change request not found"})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrRoleChangeRequestClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSelfApproval):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process role change request"})
+	}
+}
@@ -1,11 +1,14 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
 	"github.com/ruiborda/ecommerce-user-service/src/dto/role"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
 	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
 	"github.com/ruiborda/ecommerce-user-service/src/service"
 	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
 
@@ -38,6 +41,13 @@ var _ = swagger.Swagger().Path("/api/v1/roles").
 					Required(true).
 					SchemaFromDTO(&role.CreateRoleRequest{})
 			}).
+			Response(http.StatusCreated, func(response openapi.Response) {
+				response.Description("Created role").
+					SchemaFromDTO(&role.CreateRoleResponse{})
+			}).
+			Response(http.StatusConflict, func(response openapi.Response) {
+				response.Description("The code is already used by another role")
+			}).
 			Security("BearerAuth")
 	}).Doc()
 
@@ -59,7 +69,19 @@ func (roleController *RoleController) CreateRole(c *gin.Context) {
 		return
 	}
 
-	response := roleController.roleService.CreateRole(createRoleRequest)
+	// Validar que los permisos denegados existen
+	deniedPermissions := model.FindPermissionsByIds(createRoleRequest.DeniedPermissions)
+	if len(*deniedPermissions) != len(createRoleRequest.DeniedPermissions) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Uno o más IDs de permisos denegados no son válidos"})
+		return
+	}
+
+	response, err := roleController.roleService.CreateRole(createRoleRequest)
+	if err != nil {
+		writeRoleError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -108,6 +130,7 @@ func (roleController *RoleController) GetRoleByID(c *gin.Context) {
 var _ = swagger.Swagger().Path("/api/v1/roles/{id}").
 	Delete(func(operation openapi.Operation) {
 		operation.Summary("Delete a role").
+			Description("A role still referenced by users, groups or separation of duty constraints is not deleted and the response is 409 with the usage counts, unless reassignTo or force is given. Pending role change requests for the role must be decided first.").
 			OperationID("DeleteRole").
 			Tag("RoleController").
 			Produces(mime.ApplicationJSON).
@@ -116,6 +139,24 @@ var _ = swagger.Swagger().Path("/api/v1/roles/{id}").
 					Required(true).
 					Type("string")
 			}).
+			QueryParameter("reassignTo", func(param openapi.Parameter) {
+				param.Description("ID of the role that users and groups receive instead").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("force", func(param openapi.Parameter) {
+				param.Description("Remove the role from all users and groups without a replacement").
+					Required(false).
+					Type("boolean")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Role deleted").
+					SchemaFromDTO(&role.DeleteRoleByIdResponse{})
+			}).
+			Response(http.StatusConflict, func(response openapi.Response) {
+				response.Description("The role is still in use, or reassignTo would give a user or group mutually exclusive roles").
+					SchemaFromDTO(&role.RoleUsageResponse{})
+			}).
 			Security("BearerAuth")
 	}).
 	Doc()
@@ -135,18 +176,22 @@ func (roleController *RoleController) DeleteRole(c *gin.Context) {
 		return
 	}
 
-	response := roleController.roleService.DeleteRoleById(id)
-	if response == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process delete operation"})
+	request := role.DeleteRoleByIdRequest{Id: id}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if !response.Success {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": response.Message})
+	response, err := roleController.roleService.DeleteRoleById(&request)
+	if err != nil {
+		if writeSodViolation(c, err) {
+			return
+		}
+		writeRoleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Role deleted successfully"})
+	c.JSON(http.StatusOK, response)
 }
 
 var _ = swagger.Swagger().Path("/api/v1/roles").
@@ -161,6 +206,12 @@ var _ = swagger.Swagger().Path("/api/v1/roles").
 					Required(true).
 					SchemaFromDTO(&role.UpdateRoleRequest{})
 			}).
+			Response(http.StatusForbidden, func(response openapi.Response) {
+				response.Description("privileged was changed without the ApproveRoleChange permission")
+			}).
+			Response(http.StatusConflict, func(response openapi.Response) {
+				response.Description("The code is already used, or the role is a system role and cannot be renamed")
+			}).
 			Security("BearerAuth")
 	}).
 	Doc()
@@ -194,9 +245,17 @@ func (roleController *RoleController) UpdateRole(c *gin.Context) {
 		return
 	}
 
-	response := roleController.roleService.UpdateRoleById(updateRoleRequest)
-	if response == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+	// Validar que los permisos denegados existen
+	deniedPermissions := model.FindPermissionsByIds(updateRoleRequest.DeniedPermissions)
+	if len(*deniedPermissions) != len(updateRoleRequest.DeniedPermissions) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Uno o más IDs de permisos denegados no son válidos"})
+		return
+	}
+
+	claims, _ := middleware.JWTClaims(c)
+	response, err := roleController.roleService.UpdateRoleById(updateRoleRequest, security.HasPermission(claims, model.ApproveRoleChange))
+	if err != nil {
+		writeRoleError(c, err)
 		return
 	}
 
@@ -206,6 +265,7 @@ func (roleController *RoleController) UpdateRole(c *gin.Context) {
 var _ = swagger.Swagger().Path("/api/v1/roles/pages").
 	Get(func(operation openapi.Operation) {
 		operation.Summary("Get roles with pagination").
+			Description("Offset pagination with page and size, or cursor pagination with pageToken. Cursor pagination reads only the requested page, so it is the one to use for deep pages.").
 			OperationID("GetAllByPageAndSize").
 			Tag("RoleController").
 			Produces(mime.ApplicationJSON).
@@ -224,6 +284,11 @@ var _ = swagger.Swagger().Path("/api/v1/roles/pages").
 					Required(false).
 					Type("string")
 			}).
+			QueryParameter("pageToken", func(param openapi.Parameter) {
+				param.Description("Switches to cursor pagination: empty for the first page, then the token of links.nextPageToken. page is ignored and links.prev is always empty").
+					Required(false).
+					Type("string")
+			}).
 			Security("BearerAuth")
 	}).
 	Doc()
@@ -232,9 +297,40 @@ var _ = swagger.Swagger().Path("/api/v1/roles/pages").
 func (roleController *RoleController) GetAllByPageAndSize(c *gin.Context) {
 	// Crear objeto Pageable desde parámetros de consulta
 	pageable := dto.NewPageable(c.Query("page"), c.Query("size"), c.Query("query"))
+	if pageToken, ok := c.GetQuery("pageToken"); ok {
+		pageable = dto.NewCursorPageable(pageToken, c.Query("size"), c.Query("query"))
+	}
 
 	// Usar el nuevo método del servicio que maneja toda la paginación
-	response := roleController.roleService.FindAllRolesPaginated(c, pageable)
+	response, err := roleController.roleService.FindAllRolesPaginated(c, pageable)
+	if errors.Is(err, dto.ErrInvalidPageToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch roles"})
+		return
+	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+func writeRoleError(c *gin.Context, err error) {
+	var inUse *service.RoleInUseError
+	switch {
+	case errors.As(err, &inUse):
+		c.JSON(http.StatusConflict, gin.H{"error": inUse.Error(), "usage": inUse.Usage})
+	case errors.Is(err, service.ErrInvalidRoleCode),
+		errors.Is(err, service.ErrInvalidRolePermissions),
+		errors.Is(err, service.ErrInvalidRoleReassignment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRoleCodeTaken), errors.Is(err, service.ErrSystemRole):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPrivilegedChangeNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process role"})
+	}
+}
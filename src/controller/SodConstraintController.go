@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/sod"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type SodConstraintController struct {
+	sodConstraintService service.SodConstraintService
+}
+
+func NewSodConstraintController() *SodConstraintController {
+	return &SodConstraintController{
+		sodConstraintService: impl.NewSodConstraintServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/sod-constraints").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Create a separation of duty constraint").
+			Description("A user may hold at most one of the roles of a constraint. Assignments that break it are rejected with 409.").
+			OperationID("CreateSodConstraint").
+			Tag("SodConstraintController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Name and mutually exclusive roles").
+					Required(true).
+					SchemaFromDTO(&sod.CreateSodConstraintRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Created constraint").
+					SchemaFromDTO(&sod.SodConstraintResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get all separation of duty constraints").
+			OperationID("GetAllSodConstraints").
+			Tag("SodConstraintController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("List of constraints").
+					SchemaFromDTO(&[]*sod.SodConstraintResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Update a separation of duty constraint").
+			Description("Existing violations are not changed; use the violations report to find them.").
+			OperationID("UpdateSodConstraint").
+			Tag("SodConstraintController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("Constraint to update").
+					Required(true).
+					SchemaFromDTO(&sod.UpdateSodConstraintRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Updated constraint").
+					SchemaFromDTO(&sod.SodConstraintResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (s *SodConstraintController) CreateSodConstraint(c *gin.Context) {
+	var request sod.CreateSodConstraintRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := s.sodConstraintService.CreateSodConstraint(&request)
+	if err != nil {
+		writeSodConstraintError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (s *SodConstraintController) GetAllSodConstraints(c *gin.Context) {
+	response, err := s.sodConstraintService.GetAllSodConstraints()
+	if err != nil {
+		writeSodConstraintError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (s *SodConstraintController) UpdateSodConstraint(c *gin.Context) {
+	var request sod.UpdateSodConstraintRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := s.sodConstraintService.UpdateSodConstraint(&request)
+	if err != nil {
+		writeSodConstraintError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/sod-constraints/{id}").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Get a separation of duty constraint").
+			OperationID("GetSodConstraintById").
+			Tag("SodConstraintController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the constraint").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Constraint").
+					SchemaFromDTO(&sod.SodConstraintResponse{})
+			}).
+			Security("BearerAuth")
+	}).
+	Delete(func(operation openapi.Operation) {
+		operation.Summary("Delete a separation of duty constraint").
+			OperationID("DeleteSodConstraintById").
+			Tag("SodConstraintController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the constraint").
+					Required(true).
+					Type("string")
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (s *SodConstraintController) GetSodConstraintById(c *gin.Context) {
+	response, err := s.sodConstraintService.GetSodConstraintById(c.Param("id"))
+	if err != nil {
+		writeSodConstraintError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (s *SodConstraintController) DeleteSodConstraintById(c *gin.Context) {
+	if err := s.sodConstraintService.DeleteSodConstraintById(c.Param("id")); err != nil {
+		writeSodConstraintError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+var _ = swagger.Swagger().Path("/api/v1/sod-constraints/violations").
+	Get(func(operation openapi.Operation) {
+		operation.Summary("Report separation of duty violations").
+			Description("Lists users that already hold more than one role of a constraint, counting temporary assignments that have not expired.").
+			OperationID("GetSodViolations").
+			Tag("SodConstraintController").
+			Produces(mime.ApplicationJSON).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Existing violations").
+					SchemaFromDTO(&[]*sod.SodViolationResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (s *SodConstraintController) GetSodViolations(c *gin.Context) {
+	response, err := s.sodConstraintService.GetSodViolations()
+	if err != nil {
+		writeSodConstraintError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// writeSodViolation responde 409 con los roles en conflicto si err es una violación de separación de funciones
+func writeSodViolation(c *gin.Context, err error) bool {
+	var violation *service.SodViolationError
+	if !errors.As(err, &violation) {
+		return false
+	}
+	body := gin.H{
+		"error":            violation.Error(),
+		"constraintId":     violation.ConstraintId,
+		"constraintName":   violation.ConstraintName,
+		"conflictingRoles": violation.RoleCodes,
+	}
+	if violation.UserId != "" {
+		body["userId"] = violation.UserId
+	}
+	if violation.GroupId != "" {
+		body["groupId"] = violation.GroupId
+	}
+	c.JSON(http.StatusConflict, body)
+	return true
+}
+
+func writeSodConstraintError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSodConstraint):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSodConstraintNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Separation of duty constraint not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process separation of duty constraints"})
+	}
+}
@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
 	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
 	"github.com/ruiborda/ecommerce-user-service/src/service"
 	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
 
@@ -18,11 +21,13 @@ import (
 
 type UserController struct {
 	userService service.UserService
+	meService   service.MeService
 }
 
 func NewUserController() *UserController {
 	return &UserController{
 		userService: impl.NewUserServiceImpl(),
+		meService:   impl.NewMeServiceImpl(),
 	}
 }
 
@@ -49,7 +54,27 @@ func (userController *UserController) CreateUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, userController.userService.CreateUser(createUserRequest))
+	// Validar que los permisos denegados existen
+	deniedPermissions := model.FindPermissionsByIds(createUserRequest.DeniedPermissionIds)
+	if len(*deniedPermissions) != len(createUserRequest.DeniedPermissionIds) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Uno o más IDs de permisos denegados no son válidos"})
+		return
+	}
+
+	response, err := userController.userService.CreateUser(createUserRequest, middleware.SubjectId(c))
+	if writeSodViolation(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrInvalidRoleAssignment) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 var _ = swagger.Swagger().Path("/api/v1/users/{id}").
@@ -145,11 +170,45 @@ func (userController *UserController) UpdateUserById(c *gin.Context) {
 		return
 	}
 
-	response := userController.userService.UpdateUserById(updateUserRequest)
-	if response == nil {
+	// Quien edita su propio usuario mediante una política solo cambia el perfil, igual que con PUT /api/v1/me.
+	// La política ya rechaza el email, la contraseña, los roles y las denegaciones.
+	if _, grantedByPolicy := c.Get("grantedByPolicy"); grantedByPolicy {
+		var profile user.UpdateMeRequest
+		if updateUserRequest.FullName != "" {
+			profile.FullName = &updateUserRequest.FullName
+		}
+		response, err := userController.meService.UpdateMe(updateUserRequest.Id, &profile)
+		if err != nil {
+			writeMeError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	// Validar que los permisos denegados existen
+	deniedPermissions := model.FindPermissionsByIds(updateUserRequest.DeniedPermissionIds)
+	if len(*deniedPermissions) != len(updateUserRequest.DeniedPermissionIds) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Uno o más IDs de permisos denegados no son válidos"})
+		return
+	}
+
+	response, err := userController.userService.UpdateUserById(updateUserRequest, middleware.SubjectId(c))
+	if writeSodViolation(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrInvalidRoleAssignment) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrUserNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
 
 	c.JSON(http.StatusOK, response)
 }
@@ -157,6 +216,7 @@ func (userController *UserController) UpdateUserById(c *gin.Context) {
 var _ = swagger.Swagger().Path("/api/v1/users/{id}").
 	Delete(func(operation openapi.Operation) {
 		operation.Summary("Delete a user").
+			Description("Soft delete: the user is marked as DELETED, its tokens stop working and it can be restored until the retention period ends, when it is purged.").
 			OperationID("DeleteUserById").
 			Tag("UserController").
 			Produces(mime.ApplicationJSON).
@@ -188,8 +248,12 @@ func (userController *UserController) DeleteUserById(c *gin.Context) {
 		return
 	}
 
-	response := userController.userService.DeleteUserById(id)
-	if response == nil {
+	response, err := userController.userService.DeleteUserById(id, middleware.SubjectId(c))
+	if errors.Is(err, service.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process delete operation"})
 		return
 	}
@@ -205,6 +269,7 @@ func (userController *UserController) DeleteUserById(c *gin.Context) {
 var _ = swagger.Swagger().Path("/api/v1/users/pages").
 	Get(func(operation openapi.Operation) {
 		operation.Summary("Get users with pagination").
+			Description("Offset pagination with page and size, or cursor pagination with pageToken, which reads only the requested page and is the one to use for deep pages. All filters are optional and combined with AND. The pagination links keep the filters. emailPrefix is case-sensitive and requires sort=email, which is the default when it is given. Sorting by fullName leaves out users without a name. To download every matching user at once, use GET /api/v1/users/export.").
 			OperationID("FindAllUsersByPageAndSize").
 			Tag("UserController").
 			Produces(mime.ApplicationJSON).
@@ -218,6 +283,56 @@ var _ = swagger.Swagger().Path("/api/v1/users/pages").
 					Required(true).
 					Type("integer")
 			}).
+			QueryParameter("pageToken", func(param openapi.Parameter) {
+				param.Description("Switches to cursor pagination: empty for the first page, then the token of links.nextPageToken. page is ignored and links.prev is always empty").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("query", func(param openapi.Parameter) {
+				param.Description("Text contained in the email or the full name, case-insensitive").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("emailPrefix", func(param openapi.Parameter) {
+				param.Description("Prefix of the email").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("name", func(param openapi.Parameter) {
+				param.Description("Text contained in the full name, case-insensitive").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("roleId", func(param openapi.Parameter) {
+				param.Description("ID of a role directly assigned to the user").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("status", func(param openapi.Parameter) {
+				param.Description("Comma-separated statuses: ACTIVE, SUSPENDED, DEACTIVATED, DELETED or ERASED; all but DELETED and ERASED by default").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("createdFrom", func(param openapi.Parameter) {
+				param.Description("Minimum creation date, inclusive, as RFC 3339 or YYYY-MM-DD").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("createdTo", func(param openapi.Parameter) {
+				param.Description("Maximum creation date, inclusive, as RFC 3339 or YYYY-MM-DD; a date includes the whole day").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("sort", func(param openapi.Parameter) {
+				param.Description("Sort field: createdAt (default), updatedAt, email or fullName").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("direction", func(param openapi.Parameter) {
+				param.Description("asc or desc; dates default to desc and text fields to asc").
+					Required(false).
+					Type("string")
+			}).
 			Security("BearerAuth")
 	}).
 	Doc()
@@ -225,9 +340,26 @@ var _ = swagger.Swagger().Path("/api/v1/users/pages").
 func (userController *UserController) FindAllUsersByPageAndSize(c *gin.Context) {
 	// Crear objeto Pageable desde parámetros de consulta
 	pageable := dto.NewPageable(c.Query("page"), c.Query("size"), c.Query("query"))
+	if pageToken, ok := c.GetQuery("pageToken"); ok {
+		pageable = dto.NewCursorPageable(pageToken, c.Query("size"), c.Query("query"))
+	}
+
+	var search user.UserSearchRequest
+	if err := c.ShouldBindQuery(&search); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Usar el nuevo método del servicio que maneja toda la paginación
-	response := userController.userService.FindAllUsersPaginated(c, pageable)
+	response, err := userController.userService.FindAllUsersPaginated(c, pageable, &search)
+	if errors.Is(err, service.ErrInvalidUserFilter) || errors.Is(err, dto.ErrInvalidPageToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
 
 	c.JSON(http.StatusOK, response)
 }
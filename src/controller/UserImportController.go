@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type UserImportController struct {
+	userImportService service.UserImportService
+}
+
+func NewUserImportController() *UserImportController {
+	return &UserImportController{
+		userImportService: impl.NewUserImportServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/users/import").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Import users from a CSV or NDJSON file").
+			Description("Every row is validated before anything is written: if a row is invalid, no user is created and the response is 422 with the report. Columns are email, fullName, roles (role codes separated by ;), password and invite; a new user needs a password or invite=true, in which case it signs in with Google. Privileged roles wait for approval as in POST /api/v1/users. Users are written in batches of 500; a failed batch marks its rows as FAILED and the import continues.").
+			OperationID("ImportUsers").
+			Tag("UserImportController").
+			Consume(mime.MimeType("multipart/form-data")).
+			Produces(mime.ApplicationJSON).
+			FormParameter("file", func(param openapi.Parameter) {
+				param.Description("CSV with a header row, or NDJSON with one user object per line, up to 10 MiB and 5000 rows").
+					Required(true).
+					Type("file")
+			}).
+			QueryParameter("format", func(param openapi.Parameter) {
+				param.Description("csv or ndjson; by default taken from the file extension (.csv, .ndjson or .jsonl)").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("dryRun", func(param openapi.Parameter) {
+				param.Description("Validate and return the report without writing").
+					Required(false).
+					Type("boolean")
+			}).
+			QueryParameter("onDuplicate", func(param openapi.Parameter) {
+				param.Description("What to do with registered emails: skip (default), update (set fullName and password, add roles) or fail").
+					Required(false).
+					Type("string")
+			}).
+			QueryParameter("report", func(param openapi.Parameter) {
+				param.Description("json (default) or csv to download the report as a file").
+					Required(false).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Result of each row").
+					SchemaFromDTO(&user.UserImportReport{})
+			}).
+			Response(http.StatusUnprocessableEntity, func(response openapi.Response) {
+				response.Description("Some rows are invalid and nothing was written").
+					SchemaFromDTO(&user.UserImportReport{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (u *UserImportController) ImportUsers(c *gin.Context) {
+	var request user.UserImportRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	request.Report = strings.ToLower(request.Report)
+	if request.Report != "" && request.Report != "json" && request.Report != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "report must be json or csv"})
+		return
+	}
+
+	// The multipart envelope adds a few bytes on top of the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxUserImportBytes+64<<10)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			writeUserImportError(c, service.ErrUserImportTooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A multipart file field named file is required"})
+		return
+	}
+	if fileHeader.Size > service.MaxUserImportBytes {
+		writeUserImportError(c, service.ErrUserImportTooLarge)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, service.MaxUserImportBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+
+	report, err := u.userImportService.ImportUsers(data, fileHeader.Filename, &request, middleware.SubjectId(c))
+	if err != nil {
+		writeUserImportError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.Invalid > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	if request.Report == "csv" {
+		writeUserImportReportCsv(c, status, report)
+		return
+	}
+	c.JSON(status, report)
+}
+
+// writeUserImportReportCsv sends the report as a CSV file with one line per imported row
+func writeUserImportReportCsv(c *gin.Context, status int, report *user.UserImportReport) {
+	c.Header("Content-Disposition", `attachment; filename="user-import-report.csv"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(status)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"line", "email", "result", "userId", "invited", "pendingRoleChangeRequestId", "errors"})
+	for _, row := range report.Rows {
+		writer.Write([]string{
+			strconv.Itoa(row.Line),
+			row.Email,
+			row.Result,
+			row.UserId,
+			strconv.FormatBool(row.Invited),
+			row.PendingRoleChangeRequestId,
+			strings.Join(row.Errors, "; "),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Error writing user import report: %v", err)
+	}
+}
+
+func writeUserImportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserImportTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidUserImport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import users"})
+	}
+}
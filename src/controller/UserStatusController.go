@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"github.com/ruiborda/go-swagger-generator/src/openapi"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec/mime"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+type UserStatusController struct {
+	userStatusService service.UserStatusService
+}
+
+func NewUserStatusController() *UserStatusController {
+	return &UserStatusController{
+		userStatusService: impl.NewUserStatusServiceImpl(),
+	}
+}
+
+var _ = swagger.Swagger().Path("/api/v1/users/{id}/status").
+	Put(func(operation openapi.Operation) {
+		operation.Summary("Suspend, deactivate or reactivate a user").
+			Description("Allowed transitions: ACTIVE to SUSPENDED or DEACTIVATED, SUSPENDED to ACTIVE or DEACTIVATED, and DEACTIVATED to ACTIVE. Suspending requires a reason and accepts an optional suspendedUntil, after which the user is active again. Leaving ACTIVE invalidates the tokens of the user. Users cannot change their own status.").
+			OperationID("ChangeUserStatus").
+			Tag("UserStatusController").
+			Consume(mime.ApplicationJSON).
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			BodyParameter(func(param openapi.Parameter) {
+				param.Description("New status").
+					Required(true).
+					SchemaFromDTO(&user.ChangeUserStatusRequest{})
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("User with the new status").
+					SchemaFromDTO(&user.GetUserByIdResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (u *UserStatusController) ChangeStatus(c *gin.Context) {
+	var request user.ChangeUserStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := u.userStatusService.ChangeStatus(c.Param("id"), &request, middleware.SubjectId(c))
+	if err != nil {
+		writeUserStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+var _ = swagger.Swagger().Path("/api/v1/users/{id}/restore").
+	Post(func(operation openapi.Operation) {
+		operation.Summary("Restore a deleted user").
+			Description("Only possible until the retention period ends and the user is purged.").
+			OperationID("RestoreUser").
+			Tag("UserStatusController").
+			Produces(mime.ApplicationJSON).
+			PathParameter("id", func(param openapi.Parameter) {
+				param.Description("ID of the user").
+					Required(true).
+					Type("string")
+			}).
+			Response(http.StatusOK, func(response openapi.Response) {
+				response.Description("Restored user").
+					SchemaFromDTO(&user.GetUserByIdResponse{})
+			}).
+			Security("BearerAuth")
+	}).Doc()
+
+func (u *UserStatusController) RestoreUser(c *gin.Context) {
+	response, err := u.userStatusService.RestoreUser(c.Param("id"), middleware.SubjectId(c))
+	if err != nil {
+		writeUserStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func writeUserStatusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidUserStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserStatusTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user status"})
+	}
+}
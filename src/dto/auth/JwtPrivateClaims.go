@@ -3,5 +3,26 @@ package auth
 type JwtPrivateClaims struct {
 	Email         string   `json:"email"`
 	Roles         []string `json:"roles"`
-	PermissionIds []int    `json:"permissionIds"`
+	PermissionIds []int    `json:"permissionIds,omitempty"`
+	// Permisos denegados explícitamente; prevalecen sobre PermissionIds
+	DeniedPermissionIds []int `json:"deniedPermissionIds,omitempty"`
+	// Codificación compacta de PermissionIds y DeniedPermissionIds: bitset en base64 sobre el índice
+	// de permisos con la versión CatalogVersion (su número de posiciones). security.VerifyToken los expande a los arrays.
+	PermissionBits       string `json:"pb,omitempty"`
+	DeniedPermissionBits string `json:"db,omitempty"`
+	CatalogVersion       string `json:"cv,omitempty"`
+	// Roles y permisos por organización; solo aplican cuando la petición selecciona esa organización
+	Organizations map[string]*OrganizationClaims `json:"orgs,omitempty"`
+	// Versión de autorización del usuario y de cada rol (por ID) al emitir el token;
+	// si alguna cambió, RequireJWT rechaza el token con TOKEN_STALE
+	AuthzVersion int64            `json:"av"`
+	RoleVersions map[string]int64 `json:"rv"`
+}
+
+type OrganizationClaims struct {
+	Roles                []string `json:"roles"`
+	PermissionIds        []int    `json:"permissionIds,omitempty"`
+	DeniedPermissionIds  []int    `json:"deniedPermissionIds,omitempty"`
+	PermissionBits       string   `json:"pb,omitempty"`
+	DeniedPermissionBits string   `json:"db,omitempty"`
 }
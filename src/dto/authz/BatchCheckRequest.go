@@ -0,0 +1,5 @@
+package authz
+
+type BatchCheckRequest struct {
+	Checks []CheckRequest `json:"checks"`
+}
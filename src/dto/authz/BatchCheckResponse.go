@@ -0,0 +1,5 @@
+package authz
+
+type BatchCheckResponse struct {
+	Results []CheckResponse `json:"results"`
+}
@@ -0,0 +1,22 @@
+package authz
+
+type CheckRequest struct {
+	Subject       Subject   `json:"subject"`
+	PermissionIds []int     `json:"permissionIds"`
+	Resource      *Resource `json:"resource,omitempty"`
+	// Si se indica, también cuentan los roles de la membresía del sujeto en esa organización
+	OrganizationId string `json:"organizationId,omitempty"`
+}
+
+// Subject identifica al usuario por su ID o por un token emitido por este servicio
+type Subject struct {
+	UserId string `json:"userId,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
+// Resource permite resolver el permiso requerido a partir del endpoint registrado en el catálogo
+type Resource struct {
+	Service string `json:"service,omitempty"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+}
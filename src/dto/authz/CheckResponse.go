@@ -0,0 +1,14 @@
+package authz
+
+type CheckResponse struct {
+	Allowed   bool                 `json:"allowed"`
+	Reason    string               `json:"reason"`
+	SubjectId string               `json:"subjectId,omitempty"`
+	Decisions []PermissionDecision `json:"decisions"`
+}
+
+type PermissionDecision struct {
+	PermissionId int    `json:"permissionId"`
+	Allowed      bool   `json:"allowed"`
+	Reason       string `json:"reason"`
+}
@@ -0,0 +1,20 @@
+package authz
+
+type ExplainResponse struct {
+	UserId       string `json:"userId"`
+	PermissionId int    `json:"permissionId"`
+	Allowed      bool   `json:"allowed"`
+	Reason       string `json:"reason"`
+	// DecidedBy es la regla que determinó el resultado: una denegación prevalece sobre cualquier concesión
+	DecidedBy *Source  `json:"decidedBy,omitempty"`
+	Grants    []Source `json:"grants"`
+	Denies    []Source `json:"denies"`
+}
+
+// Source identifica el rol, grupo o usuario que concede o deniega un permiso.
+// Para un grupo, Code es "GRUPO/ROL" con el rol heredado que aplica.
+type Source struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+	Code string `json:"code"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// ExportRequest son las opciones de una exportación masiva, tal como llegan en la URL
+type ExportRequest struct {
+	// ndjson (por defecto) o csv
+	Format string `form:"format"`
+	// Campos separados por comas, en el orden de salida; sin indicar, los campos por defecto
+	Fields string `form:"fields"`
+}
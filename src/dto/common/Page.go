@@ -48,14 +48,49 @@ func NewPaginationResponse[T any](c *gin.Context, data *[]*T, totalElements int,
 	}
 }
 
+// NewCursorPaginationResponse construye la respuesta de la paginación por cursor. Solo se puede avanzar:
+// Next lleva el token de la página siguiente y Prev queda vacío. currentPage se omite porque el cursor no lo conoce.
+func NewCursorPaginationResponse[T any](c *gin.Context, data *[]*T, totalElements int, pageable *Pageable, nextPageToken string) *PaginationResponse[T] {
+	totalPages := (totalElements + pageable.Size - 1) / pageable.Size
+
+	baseURL := c.Request.URL.Path
+	query := c.Request.URL.Query()
+	query.Del("page")
+	query.Set("size", strconv.Itoa(pageable.Size))
+	query.Set("pageToken", pageable.PageToken)
+	currentURL := baseURL + "?" + query.Encode()
+
+	nextURL := ""
+	if nextPageToken != "" {
+		query.Set("pageToken", nextPageToken)
+		nextURL = baseURL + "?" + query.Encode()
+	}
+
+	return &PaginationResponse[T]{
+		Links: PageLinks{
+			Self:          currentURL,
+			Next:          nextURL,
+			NextPageToken: nextPageToken,
+		},
+		Data: data,
+		Page: Page{
+			Size:          pageable.Size,
+			TotalElements: totalElements,
+			TotalPages:    totalPages,
+		},
+	}
+}
+
 type PageLinks struct {
 	Self string `json:"self"`
 	Next string `json:"next"`
 	Prev string `json:"prev"`
+	// Solo en la paginación por cursor
+	NextPageToken string `json:"nextPageToken,omitempty"`
 }
 
 type Page struct {
-	CurrentPage   int `json:"currentPage"`
+	CurrentPage   int `json:"currentPage,omitempty"`
 	Size          int `json:"size"`
 	TotalElements int `json:"totalElements"`
 	TotalPages    int `json:"totalPages"`
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidPageToken indica que el token de página no es válido o no corresponde a la consulta
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// EncodePageToken serializa el cursor de una página como un token opaco apto para la URL
+func EncodePageToken(cursor any) string {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageToken recupera en cursor el contenido de un token generado por EncodePageToken
+func DecodePageToken(token string, cursor any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return ErrInvalidPageToken
+	}
+	return nil
+}
@@ -6,6 +6,9 @@ type Pageable struct {
 	Page  int    `json:"page"`
 	Size  int    `json:"size"`
 	Query string `json:"query"`
+	// Cursor activa la paginación por cursor; PageToken vacío es la primera página
+	Cursor    bool   `json:"-"`
+	PageToken string `json:"pageToken,omitempty"`
 }
 
 func NewPageable(pageStr string, sizeStr string, query string) *Pageable {
@@ -19,3 +22,11 @@ func NewPageable(pageStr string, sizeStr string, query string) *Pageable {
 	}
 	return &Pageable{Page: page, Size: size, Query: query}
 }
+
+// NewCursorPageable crea un Pageable para la paginación por cursor. page no se usa en este modo.
+func NewCursorPageable(pageToken string, sizeStr string, query string) *Pageable {
+	pageable := NewPageable("", sizeStr, query)
+	pageable.Cursor = true
+	pageable.PageToken = pageToken
+	return pageable
+}
@@ -0,0 +1,73 @@
+package datarequest
+
+import (
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+// DataExportArchive es el contenido del archivo JSON que recibe el usuario al exportar sus datos
+type DataExportArchive struct {
+	GeneratedAt time.Time          `json:"generatedAt"`
+	User        ExportedUser       `json:"user"`
+	Identities  []ExportedIdentity `json:"identities"`
+	Roles       []ExportedRole     `json:"roles"`
+	// El servicio emite tokens JWT sin estado y no guarda sesiones
+	Sessions               []any                         `json:"sessions"`
+	Groups                 []ExportedGroup               `json:"groups"`
+	Organizations          []ExportedOrganization        `json:"organizations"`
+	FavoriteNewsArticleIds []string                      `json:"favoriteNewsArticleIds"`
+	AuditEntries           ExportedAuditEntries          `json:"auditEntries"`
+	DataSubjectRequests    []*DataSubjectRequestResponse `json:"dataSubjectRequests"`
+}
+
+// ExportedUser son los datos del perfil; nunca incluye el hash de la contraseña
+type ExportedUser struct {
+	Id              string     `json:"id"`
+	Email           string     `json:"email"`
+	FullName        string     `json:"fullName"`
+	ImageUrl        string     `json:"imageUrl,omitempty"`
+	PictureUrl      string     `json:"pictureUrl,omitempty"`
+	Status          string     `json:"status"`
+	StatusReason    string     `json:"statusReason,omitempty"`
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty"`
+	SuspendedUntil  *time.Time `json:"suspendedUntil,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// ExportedIdentity es un método con el que el usuario puede iniciar sesión
+type ExportedIdentity struct {
+	// password o google
+	Provider string `json:"provider"`
+	Email    string `json:"email"`
+}
+
+type ExportedRole struct {
+	Id   string `json:"id"`
+	Code string `json:"code"`
+	// PERMANENT o TEMPORARY; los temporales indican su ventana de vigencia
+	Assignment string     `json:"assignment"`
+	ValidFrom  *time.Time `json:"validFrom,omitempty"`
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+}
+
+type ExportedGroup struct {
+	Id      string   `json:"id"`
+	Name    string   `json:"name"`
+	RoleIds []string `json:"roleIds"`
+}
+
+type ExportedOrganization struct {
+	Id       string    `json:"id"`
+	Name     string    `json:"name"`
+	RoleIds  []string  `json:"roleIds"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// ExportedAuditEntries son los registros que otros usuarios o procesos hicieron sobre la cuenta
+type ExportedAuditEntries struct {
+	RoleAssignmentHistory []model.RoleAssignmentHistory `json:"roleAssignmentHistory"`
+	RoleChangeRequests    []*model.RoleChangeRequest    `json:"roleChangeRequests"`
+	DeniedPermissionIds   []int                         `json:"deniedPermissionIds"`
+}
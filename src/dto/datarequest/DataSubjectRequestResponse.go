@@ -0,0 +1,19 @@
+package datarequest
+
+import "time"
+
+type DataSubjectRequestResponse struct {
+	Id          string     `json:"id"`
+	UserId      string     `json:"userId"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	RequestedBy string     `json:"requestedBy"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	Attempts    int        `json:"attempts"`
+	Error       string     `json:"error,omitempty"`
+	// Indica si el archivo de una exportación se puede descargar
+	ArchiveAvailable bool       `json:"archiveAvailable"`
+	ArchiveExpiresAt *time.Time `json:"archiveExpiresAt,omitempty"`
+}
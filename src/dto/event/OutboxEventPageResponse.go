@@ -0,0 +1,9 @@
+package event
+
+import "github.com/ruiborda/ecommerce-user-service/src/model"
+
+type OutboxEventPageResponse struct {
+	Events []*model.OutboxEvent `json:"events"`
+	// Token para pedir los eventos siguientes; se devuelve aunque no haya más, para continuar después desde ese punto
+	NextPageToken string `json:"nextPageToken"`
+}
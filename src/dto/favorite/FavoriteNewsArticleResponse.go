@@ -0,0 +1,5 @@
+package favorite
+
+type FavoriteNewsArticleResponse struct {
+	ArticleId string `json:"articleId"`
+}
@@ -0,0 +1,10 @@
+package favorite
+
+// FavoriteNewsStatusResponse indica si la noticia está en los favoritos del usuario
+type FavoriteNewsStatusResponse struct {
+	ArticleId string `json:"articleId"`
+	Favorite  bool   `json:"favorite"`
+	// Número de favoritos del usuario y máximo permitido
+	Total int `json:"total"`
+	Limit int `json:"limit"`
+}
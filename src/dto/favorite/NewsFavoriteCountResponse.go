@@ -0,0 +1,7 @@
+package favorite
+
+// NewsFavoriteCountResponse es el número de usuarios que tienen la noticia en favoritos
+type NewsFavoriteCountResponse struct {
+	ArticleId string `json:"articleId"`
+	Count     int64  `json:"count"`
+}
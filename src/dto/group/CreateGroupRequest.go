@@ -0,0 +1,9 @@
+package group
+
+type CreateGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Roles que heredan los miembros del grupo
+	RoleIds   []string `json:"roleIds"`
+	MemberIds []string `json:"memberIds"`
+}
@@ -0,0 +1,14 @@
+package group
+
+import "time"
+
+type GroupResponse struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	MemberIds   []string  `json:"memberIds"`
+	RoleIds     []string  `json:"roleIds"`
+	RoleCodes   []string  `json:"roleCodes"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
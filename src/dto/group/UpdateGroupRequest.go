@@ -0,0 +1,9 @@
+package group
+
+// UpdateGroupRequest actualiza los datos y roles del grupo; los miembros se gestionan por separado
+type UpdateGroupRequest struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	RoleIds     []string `json:"roleIds"`
+}
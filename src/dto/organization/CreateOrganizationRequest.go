@@ -0,0 +1,7 @@
+package organization
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name"`
+	// Usuario que administra la organización; recibe el rol ORG_ADMIN de la organización
+	OwnerId string `json:"ownerId"`
+}
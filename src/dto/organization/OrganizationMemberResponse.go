@@ -0,0 +1,14 @@
+package organization
+
+import "time"
+
+type OrganizationMemberResponse struct {
+	OrganizationId string    `json:"organizationId"`
+	UserId         string    `json:"userId"`
+	Email          string    `json:"email"`
+	FullName       string    `json:"fullName"`
+	RoleIds        []string  `json:"roleIds"`
+	RoleCodes      []string  `json:"roleCodes"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,11 @@
+package organization
+
+import "time"
+
+type OrganizationResponse struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	OwnerId   string    `json:"ownerId"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
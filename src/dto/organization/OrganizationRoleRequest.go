@@ -0,0 +1,10 @@
+package organization
+
+// OrganizationRoleRequest crea o actualiza un rol de la organización.
+// Solo admite permisos que se pueden conceder dentro de una organización.
+type OrganizationRoleRequest struct {
+	Code        string `json:"code"`
+	Permissions []int  `json:"permissions"`
+	// Permisos que el rol deniega dentro de la organización; al actualizar, si se omite se conservan los actuales
+	DeniedPermissions []int `json:"deniedPermissions"`
+}
@@ -0,0 +1,6 @@
+package organization
+
+type SetOrganizationMemberRequest struct {
+	// Roles de la organización; reemplazan a los que tenía el miembro
+	RoleIds []string `json:"roleIds"`
+}
@@ -0,0 +1,5 @@
+package organization
+
+type UpdateOrganizationRequest struct {
+	Name string `json:"name"`
+}
@@ -1,9 +1,16 @@
 package permission
 
+import "github.com/ruiborda/ecommerce-user-service/src/model"
+
 type GetPermissionByIdResponse struct {
 	Id          int    `json:"id"`
 	Method      string `json:"method"`
 	Path        string `json:"path"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Service     string `json:"service"`
+	Group       string `json:"group,omitempty"`
+	Deprecated  bool   `json:"deprecated"`
+	// Rutas de este servicio protegidas por el permiso
+	Endpoints []model.PermissionEndpoint `json:"endpoints,omitempty"`
 }
\ No newline at end of file
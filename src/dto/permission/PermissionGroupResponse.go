@@ -0,0 +1,9 @@
+package permission
+
+type PermissionGroupResponse struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// Concesión comodín que otorga todos los permisos del grupo, por ejemplo "users:*"
+	Wildcard      string `json:"wildcard"`
+	PermissionIds []int  `json:"permissionIds"`
+}
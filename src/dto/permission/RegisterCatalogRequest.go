@@ -0,0 +1,14 @@
+package permission
+
+type RegisterCatalogRequest struct {
+	Permissions []CatalogPermissionRequest `json:"permissions"`
+}
+
+type CatalogPermissionRequest struct {
+	Id          int    `json:"id"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Deprecated  bool   `json:"deprecated"`
+}
@@ -0,0 +1,7 @@
+package permission
+
+type RegisterCatalogResponse struct {
+	Service       string                      `json:"service"`
+	Permissions   []GetPermissionByIdResponse `json:"permissions"`
+	DeprecatedIds []int                       `json:"deprecatedIds"`
+}
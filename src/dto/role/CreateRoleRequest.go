@@ -3,4 +3,10 @@ package role
 type CreateRoleRequest struct {
 	Code        string `json:"code"`
 	Permissions []int  `json:"permissions"`
+	// Concesiones comodín por grupo de permisos, por ejemplo "users:*"
+	PermissionGroups []string `json:"permissionGroups"`
+	// Permisos que el rol deniega aunque otro rol los conceda
+	DeniedPermissions []int `json:"deniedPermissions"`
+	// Su asignación a usuarios requiere aprobación
+	Privileged bool `json:"privileged"`
 }
@@ -3,7 +3,11 @@ package role
 import "github.com/ruiborda/ecommerce-user-service/src/model"
 
 type CreateRoleResponse struct {
-	Id          string              `json:"id"`
-	Code        string              `json:"code"`
-	Permissions *[]model.Permission `json:"permissions"`
+	Id                  string              `json:"id"`
+	Code                string              `json:"code"`
+	Permissions         *[]model.Permission `json:"permissions"`
+	PermissionGroups    []string            `json:"permissionGroups"`
+	DeniedPermissionIds []int               `json:"deniedPermissionIds"`
+	Privileged          bool                `json:"privileged"`
+	System              bool                `json:"system"`
 }
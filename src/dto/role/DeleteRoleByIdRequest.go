@@ -0,0 +1,10 @@
+package role
+
+// DeleteRoleByIdRequest indica qué hacer con las referencias al rol; sin opciones, un rol en uso no se elimina
+type DeleteRoleByIdRequest struct {
+	Id string `json:"-"`
+	// Rol que reciben en su lugar los usuarios y grupos que tenían el rol eliminado
+	ReassignTo string `form:"reassignTo"`
+	// Quita el rol a todos los usuarios y grupos sin asignar otro
+	Force bool `form:"force"`
+}
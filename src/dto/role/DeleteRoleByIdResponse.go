@@ -3,4 +3,8 @@ package role
 type DeleteRoleByIdResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
-}
\ No newline at end of file
+	// Referencias que se migraron o quitaron antes de eliminar el rol
+	Usage        *RoleUsageResponse `json:"usage,omitempty"`
+	ReassignedTo string             `json:"reassignedTo,omitempty"`
+	UpdatedUsers int                `json:"updatedUsers,omitempty"`
+}
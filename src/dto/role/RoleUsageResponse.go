@@ -0,0 +1,15 @@
+package role
+
+// RoleUsageResponse indica cuántos usuarios, grupos, restricciones y solicitudes siguen haciendo referencia a un rol
+type RoleUsageResponse struct {
+	Users                     int `json:"users"`
+	TemporaryAssignments      int `json:"temporaryAssignments"`
+	Groups                    int `json:"groups"`
+	SodConstraints            int `json:"sodConstraints"`
+	PendingRoleChangeRequests int `json:"pendingRoleChangeRequests"`
+}
+
+// InUse indica si alguna referencia impide eliminar el rol
+func (u *RoleUsageResponse) InUse() bool {
+	return u.Users > 0 || u.TemporaryAssignments > 0 || u.Groups > 0 || u.SodConstraints > 0 || u.PendingRoleChangeRequests > 0
+}
@@ -4,4 +4,10 @@ type UpdateRoleRequest struct {
 	Id          string `json:"id"`
 	Code        string `json:"code"`
 	Permissions []int  `json:"permissions"`
+	// Concesiones comodín por grupo de permisos, por ejemplo "users:*"
+	PermissionGroups []string `json:"permissionGroups"`
+	// Permisos que el rol deniega aunque otro rol los conceda; si se omite se conservan los actuales
+	DeniedPermissions []int `json:"deniedPermissions"`
+	// Su asignación a usuarios requiere aprobación; si se omite se conserva. Cambiarlo requiere ApproveRoleChange
+	Privileged *bool `json:"privileged,omitempty"`
 }
@@ -0,0 +1,5 @@
+package rolechange
+
+type DecideRoleChangeRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
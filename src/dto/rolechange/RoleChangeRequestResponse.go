@@ -0,0 +1,22 @@
+package rolechange
+
+import (
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type RoleChangeRequestResponse struct {
+	Id          string                  `json:"id"`
+	UserId      string                  `json:"userId"`
+	RoleIds     []string                `json:"roleIds"`
+	RoleCodes   []string                `json:"roleCodes"`
+	ValidFrom   *time.Time              `json:"validFrom,omitempty"`
+	ValidUntil  *time.Time              `json:"validUntil,omitempty"`
+	Status      string                  `json:"status"`
+	RequestedBy string                  `json:"requestedBy"`
+	RequestedAt time.Time               `json:"requestedAt"`
+	DecidedBy   string                  `json:"decidedBy,omitempty"`
+	DecidedAt   *time.Time              `json:"decidedAt,omitempty"`
+	History     []model.RoleChangeEvent `json:"history"`
+}
@@ -0,0 +1,8 @@
+package sod
+
+type CreateSodConstraintRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Roles mutuamente excluyentes; al menos dos
+	RoleIds []string `json:"roleIds"`
+}
@@ -0,0 +1,13 @@
+package sod
+
+import "time"
+
+type SodConstraintResponse struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	RoleIds     []string  `json:"roleIds"`
+	RoleCodes   []string  `json:"roleCodes"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
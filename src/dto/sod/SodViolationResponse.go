@@ -0,0 +1,11 @@
+package sod
+
+// SodViolationResponse describe un usuario que ya tiene varios roles de una misma restricción
+type SodViolationResponse struct {
+	UserId         string   `json:"userId"`
+	Email          string   `json:"email"`
+	ConstraintId   string   `json:"constraintId"`
+	ConstraintName string   `json:"constraintName"`
+	RoleIds        []string `json:"roleIds"`
+	RoleCodes      []string `json:"roleCodes"`
+}
@@ -0,0 +1,8 @@
+package sod
+
+type UpdateSodConstraintRequest struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	RoleIds     []string `json:"roleIds"`
+}
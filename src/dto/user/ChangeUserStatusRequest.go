@@ -0,0 +1,13 @@
+package user
+
+import "time"
+
+// ChangeUserStatusRequest cambia el estado de una cuenta a ACTIVE, SUSPENDED o DEACTIVATED.
+// La eliminación y la restauración tienen sus propias rutas.
+type ChangeUserStatusRequest struct {
+	Status string `json:"status"`
+	// Obligatorio al suspender; se guarda para la auditoría y no se muestra al usuario
+	Reason string `json:"reason,omitempty"`
+	// Fin de la suspensión; si se omite la suspensión es indefinida
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+}
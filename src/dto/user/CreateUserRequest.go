@@ -5,4 +5,6 @@ type CreateUserRequest struct {
 	Password string   `json:"password"`
 	FullName string   `json:"fullName"`
 	RoleIds  []string `json:"roleIds"`
+	// Permisos denegados al usuario aunque alguno de sus roles los conceda
+	DeniedPermissionIds []int `json:"deniedPermissionIds"`
 }
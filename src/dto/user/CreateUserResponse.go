@@ -6,12 +6,15 @@ import (
 )
 
 type CreateUserResponse struct {
-	Id           string        `json:"id"`
-	Email        string        `json:"email"`
-	FullName     string        `json:"fullName"`
-	ImageFileKey string        `json:"imageFileKey,omitempty"`
-	PictureUrl   string        `json:"pictureUrl,omitempty"`
-	CreatedAt    time.Time     `json:"createdAt"`
-	UpdatedAt    time.Time     `json:"updatedAt"`
-	Roles        *[]model.Role `json:"roles,omitempty"`
+	Id                  string        `json:"id"`
+	Email               string        `json:"email"`
+	FullName            string        `json:"fullName"`
+	ImageFileKey        string        `json:"imageFileKey,omitempty"`
+	PictureUrl          string        `json:"pictureUrl,omitempty"`
+	CreatedAt           time.Time     `json:"createdAt"`
+	UpdatedAt           time.Time     `json:"updatedAt"`
+	Roles               *[]model.Role `json:"roles,omitempty"`
+	DeniedPermissionIds []int         `json:"deniedPermissionIds,omitempty"`
+	// Solicitud de aprobación creada para los roles privilegiados, que aún no están asignados
+	PendingRoleChangeRequestId string `json:"pendingRoleChangeRequestId,omitempty"`
 }
@@ -6,13 +6,27 @@ import (
 )
 
 type GetUserByIdResponse struct {
-	Id                     string        `json:"id"`
-	Email                  string        `json:"email"`
-	FullName               string        `json:"fullName"`
-	ImageFileKey           string        `json:"imageFileKey,omitempty"`
-	PictureUrl             string        `json:"pictureUrl,omitempty"`
-	CreatedAt              time.Time     `json:"createdAt"`
-	UpdatedAt              time.Time     `json:"updatedAt"`
-	Roles                  *[]model.Role `json:"roles,omitempty"`
-	FavoriteNewsArticleIds []string      `json:"favoriteNewsArticleIds,omitempty"`
+	Id           string        `json:"id"`
+	Email        string        `json:"email"`
+	FullName     string        `json:"fullName"`
+	ImageFileKey string        `json:"imageFileKey,omitempty"`
+	PictureUrl   string        `json:"pictureUrl,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+	Roles        *[]model.Role `json:"roles,omitempty"`
+	// Roles heredados de los grupos del usuario
+	InheritedRoles         []InheritedRoleResponse `json:"inheritedRoles,omitempty"`
+	DeniedPermissionIds    []int                   `json:"deniedPermissionIds,omitempty"`
+	RoleAssignments        []model.RoleAssignment  `json:"roleAssignments,omitempty"`
+	FavoriteNewsArticleIds []string                `json:"favoriteNewsArticleIds,omitempty"`
+	// Imagen de perfil a mostrar: la subida por el usuario o, si no hay, la de Google (PictureUrl)
+	ImageUrl string `json:"imageUrl,omitempty"`
+	// URLs de las versiones de la imagen subida por lado en píxeles, por ejemplo "64"
+	ThumbnailUrls map[string]string `json:"thumbnailUrls,omitempty"`
+	// Estado actual de la cuenta; una suspensión vencida ya figura como ACTIVE
+	Status         string     `json:"status"`
+	StatusReason   string     `json:"statusReason,omitempty"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+	// Momento en que se purgará una cuenta eliminada
+	PurgeAt *time.Time `json:"purgeAt,omitempty"`
 }
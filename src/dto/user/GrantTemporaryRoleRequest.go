@@ -0,0 +1,10 @@
+package user
+
+import "time"
+
+type GrantTemporaryRoleRequest struct {
+	RoleId string `json:"roleId"`
+	// Si se omite la asignación es vigente desde ahora
+	ValidFrom  *time.Time `json:"validFrom,omitempty"`
+	ValidUntil time.Time  `json:"validUntil"`
+}
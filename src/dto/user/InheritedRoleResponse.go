@@ -0,0 +1,9 @@
+package user
+
+// InheritedRoleResponse es un rol que el usuario hereda de un grupo del que es miembro
+type InheritedRoleResponse struct {
+	Id        string `json:"id"`
+	Code      string `json:"code"`
+	GroupId   string `json:"groupId"`
+	GroupName string `json:"groupName"`
+}
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type RoleAssignmentResponse struct {
+	RoleId     string    `json:"roleId"`
+	RoleCode   string    `json:"roleCode,omitempty"`
+	ValidFrom  time.Time `json:"validFrom"`
+	ValidUntil time.Time `json:"validUntil"`
+	GrantedBy  string    `json:"grantedBy,omitempty"`
+	GrantedAt  time.Time `json:"grantedAt"`
+	Active     bool      `json:"active"`
+}
+
+type RoleAssignmentsResponse struct {
+	UserId      string                        `json:"userId"`
+	Assignments []RoleAssignmentResponse      `json:"assignments"`
+	History     []model.RoleAssignmentHistory `json:"history"`
+	// Solicitud de aprobación creada cuando el rol concedido es privilegiado
+	PendingRoleChangeRequestId string `json:"pendingRoleChangeRequestId,omitempty"`
+}
@@ -0,0 +1,7 @@
+package user
+
+// UpdateMeRequest contiene los únicos campos que un usuario puede cambiar de su propio perfil.
+// Los campos omitidos se conservan.
+type UpdateMeRequest struct {
+	FullName *string `json:"fullName,omitempty"`
+}
@@ -6,4 +6,6 @@ type UpdateUserRequest struct {
 	Password string   `json:"password,omitempty"`
 	FullName string   `json:"fullName"`
 	RoleIds  []string `json:"roleIds"`
+	// Si se omite se conservan los permisos denegados actuales
+	DeniedPermissionIds []int `json:"deniedPermissionIds,omitempty"`
 }
@@ -14,5 +14,8 @@ type UpdateUserResponse struct {
 	CreatedAt              time.Time     `json:"createdAt"`
 	UpdatedAt              time.Time     `json:"updatedAt"`
 	Roles                  *[]model.Role `json:"roles,omitempty"`
+	DeniedPermissionIds    []int         `json:"deniedPermissionIds,omitempty"`
 	FavoriteNewsArticleIds []string      `json:"favoriteNewsArticleIds,omitempty"`
+	// Solicitud de aprobación creada para los roles privilegiados, que aún no están asignados
+	PendingRoleChangeRequestId string `json:"pendingRoleChangeRequestId,omitempty"`
 }
@@ -0,0 +1,40 @@
+package user
+
+// Resultados de una fila de la importación. En una simulación indican lo que se haría.
+const (
+	UserImportCreated = "CREATED"
+	UserImportUpdated = "UPDATED"
+	UserImportSkipped = "SKIPPED"
+	// La fila no es válida; si hay alguna, no se escribe ninguna
+	UserImportInvalid = "INVALID"
+	// La fila era válida pero falló la escritura de su lote
+	UserImportFailed = "FAILED"
+)
+
+type UserImportReport struct {
+	DryRun bool `json:"dryRun"`
+	// Indica si se escribieron los cambios: falso en una simulación o si alguna fila no es válida
+	Applied     bool                  `json:"applied"`
+	Format      string                `json:"format"`
+	OnDuplicate string                `json:"onDuplicate"`
+	Total       int                   `json:"total"`
+	Created     int                   `json:"created"`
+	Updated     int                   `json:"updated"`
+	Skipped     int                   `json:"skipped"`
+	Invalid     int                   `json:"invalid"`
+	Failed      int                   `json:"failed"`
+	Rows        []UserImportRowResult `json:"rows"`
+}
+
+type UserImportRowResult struct {
+	// Línea del archivo, contando la cabecera del CSV
+	Line   int    `json:"line"`
+	Email  string `json:"email"`
+	Result string `json:"result"`
+	// Vacío en las altas de una simulación
+	UserId  string   `json:"userId,omitempty"`
+	Invited bool     `json:"invited,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+	// Solicitud de aprobación creada para los roles privilegiados de la fila
+	PendingRoleChangeRequestId string `json:"pendingRoleChangeRequestId,omitempty"`
+}
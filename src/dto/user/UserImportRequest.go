@@ -0,0 +1,23 @@
+package user
+
+// UserImportRequest son las opciones de una importación de usuarios, tal como llegan en la URL
+type UserImportRequest struct {
+	// csv o ndjson; sin indicar, se deduce de la extensión del archivo
+	Format string `form:"format"`
+	// Valida el archivo y devuelve el informe sin escribir nada
+	DryRun bool `form:"dryRun"`
+	// Qué hacer con los emails que ya existen: skip (por defecto), update o fail
+	OnDuplicate string `form:"onDuplicate"`
+	// Formato del informe: json (por defecto) o csv
+	Report string `form:"report"`
+}
+
+// UserImportRow es una fila del archivo de importación. En CSV, roles separa los códigos con ";".
+type UserImportRow struct {
+	Email    string   `json:"email"`
+	FullName string   `json:"fullName"`
+	Roles    []string `json:"roles"`
+	Password string   `json:"password"`
+	// Crea la cuenta sin contraseña; el usuario entra con Google usando el mismo email
+	Invite bool `json:"invite"`
+}
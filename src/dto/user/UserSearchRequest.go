@@ -0,0 +1,20 @@
+package user
+
+// UserSearchRequest son los parámetros de búsqueda del listado paginado de usuarios, tal como llegan en la URL.
+// Todos son opcionales; el servicio los valida y los traduce al filtro del repositorio.
+type UserSearchRequest struct {
+	// Texto que debe aparecer en el email o en el nombre, sin distinguir mayúsculas
+	Query       string `form:"query"`
+	EmailPrefix string `form:"emailPrefix"`
+	Name        string `form:"name"`
+	RoleId      string `form:"roleId"`
+	// Estados separados por comas; sin indicar, todos menos DELETED y ERASED
+	Status string `form:"status"`
+	// Fechas en RFC 3339 o YYYY-MM-DD; createdTo con solo la fecha incluye todo ese día
+	CreatedFrom string `form:"createdFrom"`
+	CreatedTo   string `form:"createdTo"`
+	// createdAt, updatedAt, email o fullName
+	Sort string `form:"sort"`
+	// asc o desc
+	Direction string `form:"direction"`
+}
@@ -0,0 +1,37 @@
+package job
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+)
+
+// DefaultDataRequestWorkerInterval es la frecuencia del proceso de solicitudes de datos si no se configura otra
+const DefaultDataRequestWorkerInterval = time.Minute
+
+// StartDataSubjectRequestWorker atiende periódicamente las solicitudes de exportación y supresión de datos
+// y borra los archivos de exportación vencidos
+func StartDataSubjectRequestWorker(interval time.Duration) {
+	var dataSubjectRequestService service.DataSubjectRequestService = impl.NewDataSubjectRequestServiceImpl()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			processed, expired, err := dataSubjectRequestService.ProcessRequests()
+			if err != nil {
+				slog.Error("Failed to process data subject requests", "error", err)
+			}
+			if processed > 0 {
+				slog.Info("Data subject requests processed", "count", processed)
+			}
+			if expired > 0 {
+				slog.Info("Expired data exports deleted", "count", expired)
+			}
+			<-ticker.C
+		}
+	}()
+}
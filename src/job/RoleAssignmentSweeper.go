@@ -0,0 +1,33 @@
+package job
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+)
+
+// DefaultRoleAssignmentSweepInterval es la frecuencia de limpieza si no se configura otra
+const DefaultRoleAssignmentSweepInterval = 5 * time.Minute
+
+// StartRoleAssignmentSweeper elimina periódicamente las asignaciones temporales de roles vencidas.
+// Las asignaciones vencidas ya se ignoran al resolver permisos; la limpieza las retira del usuario y registra la expiración.
+func StartRoleAssignmentSweeper(interval time.Duration) {
+	var roleAssignmentService service.RoleAssignmentService = impl.NewRoleAssignmentServiceImpl()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			removed, err := roleAssignmentService.SweepExpiredRoleAssignments()
+			if err != nil {
+				slog.Error("Failed to sweep expired role assignments", "error", err)
+			} else if removed > 0 {
+				slog.Info("Expired role assignments removed", "count", removed)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,44 @@
+package job
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+)
+
+// DefaultUserStatusSweepInterval es la frecuencia del proceso de estados si no se configura otra
+const DefaultUserStatusSweepInterval = 15 * time.Minute
+
+// StartUserStatusSweeper marca primero como activas las cuentas anteriores al campo de estado y después,
+// periódicamente, reactiva las suspensiones vencidas y purga las cuentas eliminadas cuya retención terminó.
+// Una suspensión vencida ya no impide iniciar sesión; el proceso solo actualiza el estado guardado.
+func StartUserStatusSweeper(interval time.Duration) {
+	var userStatusService service.UserStatusService = impl.NewUserStatusServiceImpl()
+
+	go func() {
+		if updated, err := userStatusService.EnsureUserStatuses(); err != nil {
+			slog.Error("Failed to backfill user statuses", "error", err)
+		} else if updated > 0 {
+			slog.Info("User statuses backfilled", "count", updated)
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			reactivated, purged, err := userStatusService.SweepUserStatuses()
+			if err != nil {
+				slog.Error("Failed to sweep user statuses", "error", err)
+			}
+			if reactivated > 0 {
+				slog.Info("Expired suspensions lifted", "count", reactivated)
+			}
+			if purged > 0 {
+				slog.Info("Deleted users purged", "count", purged)
+			}
+			<-ticker.C
+		}
+	}()
+}
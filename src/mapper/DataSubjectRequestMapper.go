@@ -0,0 +1,93 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/datarequest"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type DataSubjectRequestMapper struct{}
+
+func (m *DataSubjectRequestMapper) DataSubjectRequestToResponse(request *model.DataSubjectRequest) *datarequest.DataSubjectRequestResponse {
+	return &datarequest.DataSubjectRequestResponse{
+		Id:               request.Id,
+		UserId:           request.UserId,
+		Type:             request.Type,
+		Status:           request.Status,
+		RequestedBy:      request.RequestedBy,
+		RequestedAt:      request.RequestedAt,
+		StartedAt:        request.StartedAt,
+		CompletedAt:      request.CompletedAt,
+		Attempts:         request.Attempts,
+		Error:            request.Error,
+		ArchiveAvailable: request.ArchiveKey != "",
+		ArchiveExpiresAt: request.ArchiveExpiresAt,
+	}
+}
+
+func (m *DataSubjectRequestMapper) DataSubjectRequestsToResponses(requests []*model.DataSubjectRequest) []*datarequest.DataSubjectRequestResponse {
+	responses := make([]*datarequest.DataSubjectRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		responses = append(responses, m.DataSubjectRequestToResponse(request))
+	}
+	return responses
+}
+
+// UserToExportedUser copia el perfil del usuario sin el hash de la contraseña ni los datos internos de autorización
+func (m *DataSubjectRequestMapper) UserToExportedUser(userModel *model.User, now time.Time) datarequest.ExportedUser {
+	exported := datarequest.ExportedUser{
+		Id:              userModel.Id,
+		Email:           userModel.Email,
+		FullName:        userModel.FullName,
+		PictureUrl:      userModel.PictureUrl,
+		Status:          userModel.CurrentStatus(now),
+		StatusChangedAt: userModel.StatusChangedAt,
+		CreatedAt:       userModel.CreatedAt,
+		UpdatedAt:       userModel.UpdatedAt,
+	}
+	if exported.Status == userModel.Status {
+		exported.StatusReason = userModel.StatusReason
+		exported.SuspendedUntil = userModel.SuspendedUntil
+	}
+	exported.ImageUrl, _ = (&UserMapper{}).imageUrls(userModel)
+	return exported
+}
+
+// UserToIdentities deduce los métodos de inicio de sesión: la contraseña si tiene una y Google si tiene su foto
+func (m *DataSubjectRequestMapper) UserToIdentities(userModel *model.User) []datarequest.ExportedIdentity {
+	identities := []datarequest.ExportedIdentity{}
+	if userModel.PasswordHash != "" {
+		identities = append(identities, datarequest.ExportedIdentity{Provider: "password", Email: userModel.Email})
+	}
+	if userModel.PictureUrl != "" {
+		identities = append(identities, datarequest.ExportedIdentity{Provider: "google", Email: userModel.Email})
+	}
+	return identities
+}
+
+// UserToExportedRoles lista los roles permanentes y las asignaciones temporales con su vigencia
+func (m *DataSubjectRequestMapper) UserToExportedRoles(userModel *model.User, rolesById map[string]*model.Role) []datarequest.ExportedRole {
+	roles := make([]datarequest.ExportedRole, 0, len(userModel.RoleIds)+len(userModel.RoleAssignments))
+	for _, roleId := range userModel.RoleIds {
+		exported := datarequest.ExportedRole{Id: roleId, Assignment: "PERMANENT"}
+		if role, ok := rolesById[roleId]; ok {
+			exported.Code = role.Code
+		}
+		roles = append(roles, exported)
+	}
+	for _, assignment := range userModel.RoleAssignments {
+		validFrom, validUntil := assignment.ValidFrom, assignment.ValidUntil
+		exported := datarequest.ExportedRole{
+			Id:         assignment.RoleId,
+			Assignment: "TEMPORARY",
+			ValidFrom:  &validFrom,
+			ValidUntil: &validUntil,
+		}
+		if role, ok := rolesById[assignment.RoleId]; ok {
+			exported.Code = role.Code
+		}
+		roles = append(roles, exported)
+	}
+	return roles
+}
@@ -0,0 +1,75 @@
+package mapper
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+// ExportField es una columna de una exportación masiva. Solo se exportan los campos declarados aquí, así que un
+// campo nuevo del modelo, o uno sensible como el hash de la contraseña, no sale hasta que se añade explícitamente.
+type ExportField[T any] struct {
+	Name string
+	// Solo en los campos por defecto
+	Default bool
+	// Devuelve string, bool, int64, []string, []int, time.Time o *time.Time
+	Value func(item T) any
+}
+
+// UserExportFields son los campos exportables de los usuarios; roleCodes usa rolesById para traducir los IDs
+func UserExportFields(rolesById map[string]*model.Role, now time.Time) []ExportField[*model.User] {
+	return []ExportField[*model.User]{
+		{Name: "id", Default: true, Value: func(u *model.User) any { return u.Id }},
+		{Name: "email", Default: true, Value: func(u *model.User) any { return u.Email }},
+		{Name: "fullName", Default: true, Value: func(u *model.User) any { return u.FullName }},
+		{Name: "status", Default: true, Value: func(u *model.User) any { return u.CurrentStatus(now) }},
+		{Name: "statusReason", Value: func(u *model.User) any { return u.StatusReason }},
+		{Name: "suspendedUntil", Value: func(u *model.User) any { return u.SuspendedUntil }},
+		{Name: "purgeAt", Value: func(u *model.User) any { return u.PurgeAt }},
+		{Name: "roleIds", Value: func(u *model.User) any { return nonNil(u.RoleIds) }},
+		{Name: "roleCodes", Default: true, Value: func(u *model.User) any {
+			codes := make([]string, 0, len(u.RoleIds))
+			for _, roleId := range u.RoleIds {
+				if role, ok := rolesById[roleId]; ok {
+					codes = append(codes, role.Code)
+				}
+			}
+			return codes
+		}},
+		{Name: "deniedPermissionIds", Value: func(u *model.User) any { return nonNil(u.DeniedPermissionIds) }},
+		{Name: "pictureUrl", Value: func(u *model.User) any { return u.PictureUrl }},
+		{Name: "favoriteNewsArticleIds", Value: func(u *model.User) any { return nonNil(u.FavoriteNewsArticleIds) }},
+		{Name: "createdAt", Default: true, Value: func(u *model.User) any { return u.CreatedAt }},
+		{Name: "updatedAt", Default: true, Value: func(u *model.User) any { return u.UpdatedAt }},
+	}
+}
+
+// RoleExportFields son los campos exportables de los roles globales
+func RoleExportFields() []ExportField[*model.Role] {
+	return []ExportField[*model.Role]{
+		{Name: "id", Default: true, Value: func(r *model.Role) any { return r.Id }},
+		{Name: "code", Default: true, Value: func(r *model.Role) any { return r.Code }},
+		{Name: "privileged", Default: true, Value: func(r *model.Role) any { return r.Privileged }},
+		{Name: "system", Default: true, Value: func(r *model.Role) any { return r.System }},
+		{Name: "permissionIds", Default: true, Value: func(r *model.Role) any {
+			permissionIds := []int{}
+			if r.Permissions != nil {
+				for _, permission := range *r.Permissions {
+					permissionIds = append(permissionIds, permission.Id)
+				}
+			}
+			return permissionIds
+		}},
+		{Name: "permissionGroups", Default: true, Value: func(r *model.Role) any { return nonNil(r.PermissionGroups) }},
+		{Name: "deniedPermissionIds", Default: true, Value: func(r *model.Role) any { return nonNil(r.DeniedPermissionIds) }},
+	}
+}
+
+// nonNil devuelve una lista vacía en lugar de nil, para exportar [] y no null
+func nonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return slices.Clip(values)
+}
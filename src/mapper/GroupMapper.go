@@ -0,0 +1,44 @@
+package mapper
+
+import (
+	"github.com/ruiborda/ecommerce-user-service/src/dto/group"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type GroupMapper struct{}
+
+func (m *GroupMapper) CreateGroupRequestToGroup(request *group.CreateGroupRequest) *model.Group {
+	return &model.Group{
+		Name:        request.Name,
+		Description: request.Description,
+		RoleIds:     request.RoleIds,
+		MemberIds:   request.MemberIds,
+	}
+}
+
+func (m *GroupMapper) UpdateGroupRequestToGroup(request *group.UpdateGroupRequest, existingModel *model.Group) *model.Group {
+	existingModel.Name = request.Name
+	existingModel.Description = request.Description
+	existingModel.RoleIds = request.RoleIds
+	return existingModel
+}
+
+func (m *GroupMapper) GroupToResponse(groupModel *model.Group, rolesById map[string]*model.Role) *group.GroupResponse {
+	response := &group.GroupResponse{
+		Id:          groupModel.Id,
+		Name:        groupModel.Name,
+		Description: groupModel.Description,
+		MemberIds:   groupModel.MemberIds,
+		RoleIds:     groupModel.RoleIds,
+		RoleCodes:   roleCodesOf(groupModel.RoleIds, rolesById),
+		CreatedAt:   groupModel.CreatedAt,
+		UpdatedAt:   groupModel.UpdatedAt,
+	}
+	if response.MemberIds == nil {
+		response.MemberIds = []string{}
+	}
+	if response.RoleIds == nil {
+		response.RoleIds = []string{}
+	}
+	return response
+}
@@ -0,0 +1,56 @@
+package mapper
+
+import (
+	"github.com/ruiborda/ecommerce-user-service/src/dto/organization"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type OrganizationMapper struct{}
+
+func (m *OrganizationMapper) CreateOrganizationRequestToOrganization(request *organization.CreateOrganizationRequest) *model.Organization {
+	return &model.Organization{
+		Name:    request.Name,
+		OwnerId: request.OwnerId,
+	}
+}
+
+func (m *OrganizationMapper) OrganizationToResponse(organizationModel *model.Organization) *organization.OrganizationResponse {
+	return &organization.OrganizationResponse{
+		Id:        organizationModel.Id,
+		Name:      organizationModel.Name,
+		OwnerId:   organizationModel.OwnerId,
+		CreatedAt: organizationModel.CreatedAt,
+		UpdatedAt: organizationModel.UpdatedAt,
+	}
+}
+
+func (m *OrganizationMapper) OrganizationRoleRequestToRole(organizationId string, request *organization.OrganizationRoleRequest, existingModel *model.Role) *model.Role {
+	if existingModel == nil {
+		existingModel = &model.Role{OrganizationId: organizationId}
+	}
+	existingModel.Code = request.Code
+	existingModel.Permissions = model.FindPermissionsByIds(request.Permissions)
+	if request.DeniedPermissions != nil {
+		existingModel.DeniedPermissionIds = request.DeniedPermissions
+	}
+	return existingModel
+}
+
+func (m *OrganizationMapper) ToOrganizationMemberResponse(membership *model.OrganizationMembership, user *model.User, rolesById map[string]*model.Role) *organization.OrganizationMemberResponse {
+	response := &organization.OrganizationMemberResponse{
+		OrganizationId: membership.OrganizationId,
+		UserId:         membership.UserId,
+		RoleIds:        membership.RoleIds,
+		RoleCodes:      roleCodesOf(membership.RoleIds, rolesById),
+		CreatedAt:      membership.CreatedAt,
+		UpdatedAt:      membership.UpdatedAt,
+	}
+	if response.RoleIds == nil {
+		response.RoleIds = []string{}
+	}
+	if user != nil {
+		response.Email = user.Email
+		response.FullName = user.FullName
+	}
+	return response
+}
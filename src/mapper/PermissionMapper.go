@@ -8,12 +8,43 @@ import (
 type PermissionMapper struct {}
 
 func (m *PermissionMapper) PermissionToGetPermissionByIdResponse(modelPermission *model.Permission) *permission.GetPermissionByIdResponse {
-	return &permission.GetPermissionByIdResponse{
+	response := &permission.GetPermissionByIdResponse{
 		Id:          modelPermission.Id,
 		Method:      modelPermission.Method,
 		Path:        modelPermission.Path,
 		Name:        modelPermission.Name,
 		Description: modelPermission.Description,
+		Service:     modelPermission.Service,
+		Deprecated:  modelPermission.Deprecated,
+		Endpoints:   modelPermission.Endpoints,
+	}
+	if group := model.PermissionGroupOf(modelPermission.Id); group != nil {
+		response.Group = group.Key
+	}
+	return response
+}
+
+func (m *PermissionMapper) PermissionGroupToResponse(group *model.PermissionGroup) *permission.PermissionGroupResponse {
+	permissionIds := group.PermissionIds()
+	if permissionIds == nil {
+		permissionIds = []int{}
+	}
+	return &permission.PermissionGroupResponse{
+		Key:           group.Key,
+		Name:          group.Name,
+		Wildcard:      group.Wildcard(),
+		PermissionIds: permissionIds,
+	}
+}
+
+func (m *PermissionMapper) CatalogPermissionRequestToPermission(request *permission.CatalogPermissionRequest) *model.Permission {
+	return &model.Permission{
+		Id:          request.Id,
+		Method:      request.Method,
+		Path:        request.Path,
+		Name:        request.Name,
+		Description: request.Description,
+		Deprecated:  request.Deprecated,
 	}
 }
 
@@ -0,0 +1,51 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type RoleAssignmentMapper struct{}
+
+func (m *RoleAssignmentMapper) GrantTemporaryRoleRequestToRoleAssignment(request *user.GrantTemporaryRoleRequest, grantedBy string, now time.Time) model.RoleAssignment {
+	validFrom := now
+	if request.ValidFrom != nil {
+		validFrom = *request.ValidFrom
+	}
+	return model.RoleAssignment{
+		RoleId:     request.RoleId,
+		ValidFrom:  validFrom,
+		ValidUntil: request.ValidUntil,
+		GrantedBy:  grantedBy,
+		GrantedAt:  now,
+	}
+}
+
+func (m *RoleAssignmentMapper) ToRoleAssignmentsResponse(userModel *model.User, rolesById map[string]*model.Role, history []*model.RoleAssignmentHistory, now time.Time) *user.RoleAssignmentsResponse {
+	response := &user.RoleAssignmentsResponse{
+		UserId:      userModel.Id,
+		Assignments: make([]user.RoleAssignmentResponse, 0, len(userModel.RoleAssignments)),
+		History:     make([]model.RoleAssignmentHistory, 0, len(history)),
+	}
+	for _, assignment := range userModel.RoleAssignments {
+		roleCode := ""
+		if role, ok := rolesById[assignment.RoleId]; ok {
+			roleCode = role.Code
+		}
+		response.Assignments = append(response.Assignments, user.RoleAssignmentResponse{
+			RoleId:     assignment.RoleId,
+			RoleCode:   roleCode,
+			ValidFrom:  assignment.ValidFrom,
+			ValidUntil: assignment.ValidUntil,
+			GrantedBy:  assignment.GrantedBy,
+			GrantedAt:  assignment.GrantedAt,
+			Active:     assignment.IsActive(now),
+		})
+	}
+	for _, entry := range history {
+		response.History = append(response.History, *entry)
+	}
+	return response
+}
@@ -0,0 +1,36 @@
+package mapper
+
+import (
+	"github.com/ruiborda/ecommerce-user-service/src/dto/rolechange"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type RoleChangeRequestMapper struct{}
+
+func (m *RoleChangeRequestMapper) RoleChangeRequestToResponse(request *model.RoleChangeRequest, rolesById map[string]*model.Role) *rolechange.RoleChangeRequestResponse {
+	roleCodes := make([]string, 0, len(request.RoleIds))
+	for _, roleId := range request.RoleIds {
+		if role, ok := rolesById[roleId]; ok {
+			roleCodes = append(roleCodes, role.Code)
+		}
+	}
+	history := request.History
+	if history == nil {
+		history = []model.RoleChangeEvent{}
+	}
+
+	return &rolechange.RoleChangeRequestResponse{
+		Id:          request.Id,
+		UserId:      request.UserId,
+		RoleIds:     request.RoleIds,
+		RoleCodes:   roleCodes,
+		ValidFrom:   request.ValidFrom,
+		ValidUntil:  request.ValidUntil,
+		Status:      request.Status,
+		RequestedBy: request.RequestedBy,
+		RequestedAt: request.RequestedAt,
+		DecidedBy:   request.DecidedBy,
+		DecidedAt:   request.DecidedAt,
+		History:     history,
+	}
+}
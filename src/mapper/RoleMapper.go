@@ -13,8 +13,11 @@ func (m *RoleMapper) CreateRoleRequestToRole(request *role.CreateRoleRequest) *m
 	permissions := model.FindPermissionsByIds(request.Permissions)
 
 	return &model.Role{
-		Code:        request.Code,
-		Permissions: permissions,
+		Code:                request.Code,
+		Permissions:         permissions,
+		PermissionGroups:    request.PermissionGroups,
+		DeniedPermissionIds: request.DeniedPermissions,
+		Privileged:          request.Privileged,
 	}
 }
 
@@ -26,9 +29,13 @@ func (m *RoleMapper) RoleToCreateRoleResponse(roleModel *model.Role) *role.Creat
 	}
 
 	return &role.CreateRoleResponse{
-		Id:          roleModel.Id,
-		Code:        roleModel.Code,
-		Permissions: permissions,
+		Id:                  roleModel.Id,
+		Code:                roleModel.Code,
+		Permissions:         permissions,
+		PermissionGroups:    permissionGroups(roleModel),
+		DeniedPermissionIds: deniedPermissionIds(roleModel),
+		Privileged:          roleModel.Privileged,
+		System:              roleModel.System,
 	}
 }
 
@@ -40,9 +47,14 @@ func (m *RoleMapper) RoleToGetRoleByIdResponse(roleModel *model.Role) *role.GetR
 	}
 
 	return &role.GetRoleByIdResponse{
-		Id:          roleModel.Id,
-		Code:        roleModel.Code,
-		Permissions: permissions,
+		Id:                  roleModel.Id,
+		Code:                roleModel.Code,
+		Permissions:         permissions,
+		PermissionGroups:    permissionGroups(roleModel),
+		DeniedPermissionIds: deniedPermissionIds(roleModel),
+		Privileged:          roleModel.Privileged,
+		System:              roleModel.System,
+		OrganizationId:      roleModel.OrganizationId,
 	}
 }
 
@@ -51,6 +63,13 @@ func (m *RoleMapper) UpdateRoleRequestToRole(request *role.UpdateRoleRequest, ex
 
 	existingModel.Code = request.Code
 	existingModel.Permissions = permissions
+	existingModel.PermissionGroups = request.PermissionGroups
+	if request.DeniedPermissions != nil {
+		existingModel.DeniedPermissionIds = request.DeniedPermissions
+	}
+	if request.Privileged != nil {
+		existingModel.Privileged = *request.Privileged
+	}
 
 	return existingModel
 }
@@ -63,9 +82,13 @@ func (m *RoleMapper) RoleToUpdateRoleResponse(roleModel *model.Role) *role.Updat
 	}
 
 	return &role.UpdateRoleResponse{
-		Id:          roleModel.Id,
-		Code:        roleModel.Code,
-		Permissions: permissions,
+		Id:                  roleModel.Id,
+		Code:                roleModel.Code,
+		Permissions:         permissions,
+		PermissionGroups:    permissionGroups(roleModel),
+		DeniedPermissionIds: deniedPermissionIds(roleModel),
+		Privileged:          roleModel.Privileged,
+		System:              roleModel.System,
 	}
 }
 
@@ -76,6 +99,20 @@ func (m *RoleMapper) RoleToDeleteRoleByIdResponse(roleId string, success bool) *
 	}
 }
 
+func deniedPermissionIds(roleModel *model.Role) []int {
+	if roleModel.DeniedPermissionIds == nil {
+		return []int{}
+	}
+	return roleModel.DeniedPermissionIds
+}
+
+func permissionGroups(roleModel *model.Role) []string {
+	if roleModel.PermissionGroups == nil {
+		return []string{}
+	}
+	return roleModel.PermissionGroups
+}
+
 func getDeleteRoleMessage(roleId string, success bool) string {
 	if success {
 		return "Role with ID " + roleId + " was successfully deleted"
@@ -94,9 +131,13 @@ func (m *RoleMapper) RolesToGetRolesResponse(models []*model.Role) []*role.GetRo
 		}
 
 		response := &role.GetRoleByIdResponse{
-			Id:          roleModel.Id,
-			Code:        roleModel.Code,
-			Permissions: permissions,
+			Id:                  roleModel.Id,
+			Code:                roleModel.Code,
+			Permissions:         permissions,
+			PermissionGroups:    permissionGroups(roleModel),
+			DeniedPermissionIds: deniedPermissionIds(roleModel),
+			Privileged:          roleModel.Privileged,
+			System:              roleModel.System,
 		}
 
 		responses = append(responses, response)
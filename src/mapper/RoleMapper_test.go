@@ -0,0 +1,52 @@
+package mapper
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/organization"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/role"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func TestUpdateRoleRequestToRoleDeniedPermissions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []int
+	}{
+		{"omitted keeps denies", `{"code":"SUPPORT"}`, []int{model.DeleteUser}},
+		{"null keeps denies", `{"code":"SUPPORT","deniedPermissions":null}`, []int{model.DeleteUser}},
+		{"empty clears denies", `{"code":"SUPPORT","deniedPermissions":[]}`, []int{}},
+		{"replaces denies", `{"code":"SUPPORT","deniedPermissions":[504,505]}`, []int{model.DeleteUser, model.GetUsersPaginated}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var request role.UpdateRoleRequest
+			if err := json.Unmarshal([]byte(test.body), &request); err != nil {
+				t.Fatal(err)
+			}
+			existing := &model.Role{Code: "SUPPORT", DeniedPermissionIds: []int{model.DeleteUser}}
+
+			updated := (&RoleMapper{}).UpdateRoleRequestToRole(&request, existing)
+			if !slices.Equal(updated.DeniedPermissionIds, test.want) {
+				t.Fatalf("DeniedPermissionIds = %v, want %v", updated.DeniedPermissionIds, test.want)
+			}
+		})
+	}
+}
+
+func TestOrganizationRoleRequestToRoleKeepsOmittedDenies(t *testing.T) {
+	var request organization.OrganizationRoleRequest
+	if err := json.Unmarshal([]byte(`{"code":"ORG_SUPPORT","permissions":[702]}`), &request); err != nil {
+		t.Fatal(err)
+	}
+	existing := &model.Role{OrganizationId: "o1", Code: "ORG_SUPPORT", DeniedPermissionIds: []int{model.UpdateOrganization}}
+
+	updated := (&OrganizationMapper{}).OrganizationRoleRequestToRole("o1", &request, existing)
+	if !slices.Equal(updated.DeniedPermissionIds, []int{model.UpdateOrganization}) {
+		t.Fatalf("DeniedPermissionIds = %v, want %v", updated.DeniedPermissionIds, []int{model.UpdateOrganization})
+	}
+}
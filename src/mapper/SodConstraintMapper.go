@@ -0,0 +1,57 @@
+package mapper
+
+import (
+	"github.com/ruiborda/ecommerce-user-service/src/dto/sod"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type SodConstraintMapper struct{}
+
+func (m *SodConstraintMapper) CreateSodConstraintRequestToSodConstraint(request *sod.CreateSodConstraintRequest) *model.SodConstraint {
+	return &model.SodConstraint{
+		Name:        request.Name,
+		Description: request.Description,
+		RoleIds:     request.RoleIds,
+	}
+}
+
+func (m *SodConstraintMapper) UpdateSodConstraintRequestToSodConstraint(request *sod.UpdateSodConstraintRequest, existingModel *model.SodConstraint) *model.SodConstraint {
+	existingModel.Name = request.Name
+	existingModel.Description = request.Description
+	existingModel.RoleIds = request.RoleIds
+	return existingModel
+}
+
+func (m *SodConstraintMapper) SodConstraintToResponse(constraint *model.SodConstraint, rolesById map[string]*model.Role) *sod.SodConstraintResponse {
+	return &sod.SodConstraintResponse{
+		Id:          constraint.Id,
+		Name:        constraint.Name,
+		Description: constraint.Description,
+		RoleIds:     constraint.RoleIds,
+		RoleCodes:   roleCodesOf(constraint.RoleIds, rolesById),
+		CreatedAt:   constraint.CreatedAt,
+		UpdatedAt:   constraint.UpdatedAt,
+	}
+}
+
+func (m *SodConstraintMapper) ToSodViolationResponse(user *model.User, constraint *model.SodConstraint, conflictingRoleIds []string, rolesById map[string]*model.Role) *sod.SodViolationResponse {
+	return &sod.SodViolationResponse{
+		UserId:         user.Id,
+		Email:          user.Email,
+		ConstraintId:   constraint.Id,
+		ConstraintName: constraint.Name,
+		RoleIds:        conflictingRoleIds,
+		RoleCodes:      roleCodesOf(conflictingRoleIds, rolesById),
+	}
+}
+
+// roleCodesOf devuelve los códigos de los roles conocidos, en el orden de roleIds
+func roleCodesOf(roleIds []string, rolesById map[string]*model.Role) []string {
+	roleCodes := make([]string, 0, len(roleIds))
+	for _, roleId := range roleIds {
+		if role, ok := rolesById[roleId]; ok {
+			roleCodes = append(roleCodes, role.Code)
+		}
+	}
+	return roleCodes
+}
@@ -1,18 +1,24 @@
 package mapper
 
 import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/ruiborda/ecommerce-user-service/src/blob"
 	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
 	"github.com/ruiborda/ecommerce-user-service/src/model"
 	"time"
 )
 
-type UserMapper struct {}
+type UserMapper struct{}
 
 func (m *UserMapper) CreateUserRequestToUser(request *user.CreateUserRequest) *model.User {
 	return &model.User{
 		Email:                  request.Email,
 		FullName:               request.FullName,
 		RoleIds:                request.RoleIds,
+		DeniedPermissionIds:    request.DeniedPermissionIds,
 		CreatedAt:              time.Now(),
 		UpdatedAt:              time.Now(),
 		FavoriteNewsArticleIds: []string{},
@@ -21,19 +27,20 @@ func (m *UserMapper) CreateUserRequestToUser(request *user.CreateUserRequest) *m
 
 func (m *UserMapper) UserToCreateUserResponse(model *model.User, roles *[]model.Role) *user.CreateUserResponse {
 	return &user.CreateUserResponse{
-		Id:           model.Id,
-		Email:        model.Email,
-		FullName:     model.FullName,
-		ImageFileKey: model.ImageFileKey,
-		PictureUrl:   model.PictureUrl,
-		CreatedAt:    model.CreatedAt,
-		UpdatedAt:    model.UpdatedAt,
-		Roles:        roles,
+		Id:                  model.Id,
+		Email:               model.Email,
+		FullName:            model.FullName,
+		ImageFileKey:        model.ImageFileKey,
+		PictureUrl:          model.PictureUrl,
+		CreatedAt:           model.CreatedAt,
+		UpdatedAt:           model.UpdatedAt,
+		Roles:               roles,
+		DeniedPermissionIds: model.DeniedPermissionIds,
 	}
 }
 
 func (m *UserMapper) UserToGetUserByIdResponse(model *model.User, roles *[]model.Role) *user.GetUserByIdResponse {
-	return &user.GetUserByIdResponse{
+	response := &user.GetUserByIdResponse{
 		Id:                     model.Id,
 		Email:                  model.Email,
 		FullName:               model.FullName,
@@ -42,17 +49,69 @@ func (m *UserMapper) UserToGetUserByIdResponse(model *model.User, roles *[]model
 		CreatedAt:              model.CreatedAt,
 		UpdatedAt:              model.UpdatedAt,
 		Roles:                  roles,
+		DeniedPermissionIds:    model.DeniedPermissionIds,
+		RoleAssignments:        model.RoleAssignments,
 		FavoriteNewsArticleIds: model.FavoriteNewsArticleIds,
+		Status:                 model.CurrentStatus(time.Now()),
+		PurgeAt:                model.PurgeAt,
+	}
+	if response.Status == model.Status {
+		response.StatusReason = model.StatusReason
+		response.SuspendedUntil = model.SuspendedUntil
 	}
+	response.ImageUrl, response.ThumbnailUrls = m.imageUrls(model)
+	return response
+}
+
+// imageUrls resuelve las URLs de la imagen subida por el usuario, que tiene prioridad sobre la de Google
+func (m *UserMapper) imageUrls(userModel *model.User) (string, map[string]string) {
+	store := blob.Default()
+	if userModel.ImageFileKey == "" || store == nil {
+		return userModel.PictureUrl, nil
+	}
+
+	thumbnailUrls := make(map[string]string, len(model.AvatarSizes))
+	for _, size := range model.AvatarSizes {
+		url, err := store.URL(context.Background(), model.AvatarKey(userModel.ImageFileKey, size))
+		if err != nil {
+			log.Printf("Error resolving avatar url: %v", err)
+			return userModel.PictureUrl, nil
+		}
+		thumbnailUrls[strconv.Itoa(size)] = url
+	}
+	return thumbnailUrls[strconv.Itoa(model.AvatarSizes[0])], thumbnailUrls
+}
+
+// ToInheritedRoles lista los roles que el usuario hereda de cada grupo, en el orden de los grupos
+func (m *UserMapper) ToInheritedRoles(groups []*model.Group, rolesById map[string]*model.Role) []user.InheritedRoleResponse {
+	var inheritedRoles []user.InheritedRoleResponse
+	for _, group := range groups {
+		for _, roleId := range group.RoleIds {
+			role, ok := rolesById[roleId]
+			if !ok {
+				continue
+			}
+			inheritedRoles = append(inheritedRoles, user.InheritedRoleResponse{
+				Id:        role.Id,
+				Code:      role.Code,
+				GroupId:   group.Id,
+				GroupName: group.Name,
+			})
+		}
+	}
+	return inheritedRoles
 }
 
 func (m *UserMapper) UpdateUserRequestToUser(request *user.UpdateUserRequest, existingModel *model.User) *model.User {
 	existingModel.Email = request.Email
 	existingModel.FullName = request.FullName
 	existingModel.RoleIds = request.RoleIds
+	if request.DeniedPermissionIds != nil {
+		existingModel.DeniedPermissionIds = request.DeniedPermissionIds
+	}
 
 	if request.Password != "" {
-		existingModel.PasswordHash = request.Password 
+		existingModel.PasswordHash = request.Password
 	}
 
 	existingModel.UpdatedAt = time.Now()
@@ -70,6 +129,7 @@ func (m *UserMapper) UserToUpdateUserResponse(model *model.User, roles *[]model.
 		CreatedAt:              model.CreatedAt,
 		UpdatedAt:              model.UpdatedAt,
 		Roles:                  roles,
+		DeniedPermissionIds:    model.DeniedPermissionIds,
 		FavoriteNewsArticleIds: model.FavoriteNewsArticleIds,
 	}
 }
@@ -102,12 +162,14 @@ func (m *UserMapper) UsersToGetUsersByIdsResponse(models []*model.User, rolesMap
 			PictureUrl:             model.PictureUrl,
 			CreatedAt:              model.CreatedAt,
 			UpdatedAt:              model.UpdatedAt,
+			DeniedPermissionIds:    model.DeniedPermissionIds,
 			FavoriteNewsArticleIds: model.FavoriteNewsArticleIds,
 		}
 
 		if exists {
 			response.Roles = roles
 		}
+		response.ImageUrl, response.ThumbnailUrls = m.imageUrls(model)
 
 		userResponses = append(userResponses, response)
 	}
@@ -0,0 +1,37 @@
+package mapper
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func TestUpdateUserRequestToUserDeniedPermissions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []int
+	}{
+		{"omitted keeps denies", `{"id":"u1","email":"a@example.com"}`, []int{model.DeleteUser}},
+		{"empty clears denies", `{"id":"u1","email":"a@example.com","deniedPermissionIds":[]}`, []int{}},
+		{"replaces denies", `{"id":"u1","email":"a@example.com","deniedPermissionIds":[505]}`, []int{model.GetUsersPaginated}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var request user.UpdateUserRequest
+			if err := json.Unmarshal([]byte(test.body), &request); err != nil {
+				t.Fatal(err)
+			}
+			existing := &model.User{Id: "u1", DeniedPermissionIds: []int{model.DeleteUser}}
+
+			updated := (&UserMapper{}).UpdateUserRequestToUser(&request, existing)
+			if !slices.Equal(updated.DeniedPermissionIds, test.want) {
+				t.Fatalf("DeniedPermissionIds = %v, want %v", updated.DeniedPermissionIds, test.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,14 @@
 package middleware
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
-	"github.com/ruiborda/go-jwt/src/application/ports/input"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
 	"github.com/ruiborda/go-jwt/src/domain/entity"
-	input2 "github.com/ruiborda/go-jwt/src/infrastructure/adapters/input"
 	"log/slog"
 	"net/http"
-	"os"
-	"strings"
+	"slices"
 )
 
 // RequireJWT middleware checks if a valid JWT token is present
@@ -22,45 +21,70 @@ func RequireJWT() gin.HandlerFunc {
 		}
 
 		// Extract JWT token from bearer format
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		token, ok := security.BearerToken(authHeader)
+		if !ok {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
 			return
 		}
-		token := tokenParts[1]
-
-		// Verify token
-		jwtSecret := os.Getenv("JWT_SECRET")
-		if jwtSecret == "" {
-			slog.Error("JWT_SECRET environment variable is not set")
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-			return
-		}
-
-		inputPort := input.NewJWTHS256InputPort[*auth.JwtPrivateClaims]([]byte(jwtSecret))
-		inputAdapter := input2.NewJwtInputAdapter[*auth.JwtPrivateClaims](inputPort)
 
-		err := inputAdapter.VerifyToken(token)
+		// Verify token and extract claims for use in handlers
+		claims, err := security.VerifyToken(token)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			switch {
+			case errors.Is(err, security.ErrSecretNotConfigured):
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			case errors.Is(err, security.ErrInvalidTokenFormat):
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
+			case errors.Is(err, security.ErrTokenStale):
+				// Compact token issued with another version of the permission catalog
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": security.TokenStaleCode})
+			default:
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			}
 			return
 		}
 
-		// Extract claims from the token for use in handlers
-		jwt := entity.NewJwtFromToken[*auth.JwtPrivateClaims](token)
-		if jwt == nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
+		// Reject tokens issued before the user's roles or permissions changed
+		if err := security.CheckAuthzVersion(claims); err != nil {
+			if errors.Is(err, security.ErrTokenStale) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": security.TokenStaleCode})
+			} else {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			}
 			return
 		}
 
 		// Store claims in context for later use
-		c.Set("jwtClaims", jwt.Claims)
+		c.Set("jwtClaims", claims)
 		c.Next()
 	}
 }
 
-// RequirePermission middleware checks if user has the required permission ID
+// OrganizationHeader selects the organization in which the request acts
+const OrganizationHeader = security.OrganizationHeader
+
+// RequirePermission middleware checks if user has the required permission ID.
+// When the request selects an organization (X-Organization-Id header or :orgId path segment),
+// the roles of the user's membership in that organization are also considered.
+// Wildcard grants such as "users:*" are expanded when the token is issued.
 func RequirePermission(permissionId int) gin.HandlerFunc {
+	return requirePermissions([]int{permissionId}, true)
+}
+
+// RequireAnyPermission middleware allows the request when the user has at least one of the permission IDs
+func RequireAnyPermission(permissionIds ...int) gin.HandlerFunc {
+	return requirePermissions(permissionIds, false)
+}
+
+// RequireAllPermissions middleware allows the request only when the user has every permission ID
+func RequireAllPermissions(permissionIds ...int) gin.HandlerFunc {
+	return requirePermissions(permissionIds, true)
+}
+
+func requirePermissions(permissionIds []int, requireAll bool) gin.HandlerFunc {
+	if len(permissionIds) == 0 {
+		panic("middleware: at least one permission ID is required")
+	}
 	return func(c *gin.Context) {
 		// First ensure JWT middleware has been run
 		claimsValue, exists := c.Get("jwtClaims")
@@ -75,19 +99,25 @@ func RequirePermission(permissionId int) gin.HandlerFunc {
 			return
 		}
 
-		// Check if user has the required permission
-		hasPermission := false
-		if claims.PrivateClaims != nil && claims.PrivateClaims.PermissionIds != nil {
-			for _, id := range claims.PrivateClaims.PermissionIds {
-				if id == permissionId {
-					hasPermission = true
-					break
-				}
-			}
+		organizationId, ok := selectedOrganization(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": OrganizationHeader + " header does not match the organization in the path"})
+			return
 		}
 
-		if !hasPermission {
-			slog.Info("Access denied: missing required permission", "requiredPermission", permissionId, "email", claims.PrivateClaims.Email)
+		// Check if user has the required permissions
+		hasPermission := func(permissionId int) bool {
+			return security.HasPermissionInOrganization(claims, organizationId, permissionId)
+		}
+		var allowed bool
+		if requireAll {
+			allowed = !slices.ContainsFunc(permissionIds, func(permissionId int) bool { return !hasPermission(permissionId) })
+		} else {
+			allowed = slices.ContainsFunc(permissionIds, hasPermission)
+		}
+
+		if !allowed {
+			slog.Info("Access denied: missing required permission", "requiredPermissions", permissionIds, "requireAll", requireAll, "email", claims.PrivateClaims.Email, "organizationId", organizationId)
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this resource"})
 			return
 		}
@@ -95,3 +125,45 @@ func RequirePermission(permissionId int) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// JWTClaims returns the claims stored by RequireJWT
+func JWTClaims(c *gin.Context) (*entity.JWTClaims[*auth.JwtPrivateClaims], bool) {
+	claimsValue, exists := c.Get("jwtClaims")
+	if !exists {
+		return nil, false
+	}
+	claims, ok := claimsValue.(*entity.JWTClaims[*auth.JwtPrivateClaims])
+	return claims, ok
+}
+
+// SubjectId returns the id of the authenticated user, or an empty string if there are no claims
+func SubjectId(c *gin.Context) string {
+	claims, ok := JWTClaims(c)
+	if !ok || claims.RegisteredClaims == nil {
+		return ""
+	}
+	return claims.RegisteredClaims.Subject
+}
+
+// OrganizationId returns the organization selected by the request, validated by RequirePermission
+func OrganizationId(c *gin.Context) string {
+	return c.GetString("organizationId")
+}
+
+// selectedOrganization reads the organization from the path or the header; both must match when present
+func selectedOrganization(c *gin.Context) (string, bool) {
+	pathOrganizationId := c.Param("orgId")
+	headerOrganizationId := c.GetHeader(OrganizationHeader)
+	if pathOrganizationId != "" && headerOrganizationId != "" && pathOrganizationId != headerOrganizationId {
+		return "", false
+	}
+
+	organizationId := pathOrganizationId
+	if organizationId == "" {
+		organizationId = headerOrganizationId
+	}
+	if organizationId != "" {
+		c.Set("organizationId", organizationId)
+	}
+	return organizationId, true
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/ecommerce-user-service/src/policy"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
+	"github.com/ruiborda/go-jwt/src/domain/entity"
+)
+
+// RequirePolicy middleware allows the request when the user holds the permission through a role,
+// or when a policy for that permission is satisfied by the subject, the loaded resource,
+// the JSON body (request.*) and the path params (params.*).
+func RequirePolicy(permissionId int, loader policy.ResourceLoader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// First ensure JWT middleware has been run
+		claimsValue, exists := c.Get("jwtClaims")
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No JWT claims found"})
+			return
+		}
+
+		claims, ok := claimsValue.(*entity.JWTClaims[*auth.JwtPrivateClaims])
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Invalid JWT claims format"})
+			return
+		}
+
+		// A permission granted by a role is not restricted by policies
+		if security.HasPermission(claims, permissionId) {
+			c.Next()
+			return
+		}
+
+		// An explicit deny cannot be overridden by a policy
+		if security.IsDenied(claims, permissionId) {
+			denyPolicy(c, permissionId, claims)
+			return
+		}
+
+		var roleCodes []string
+		if claims.PrivateClaims != nil {
+			roleCodes = claims.PrivateClaims.Roles
+		}
+
+		var candidates []*policy.Policy
+		for _, candidate := range policy.ForPermission(permissionId) {
+			if candidate.AppliesTo(roleCodes) {
+				candidates = append(candidates, candidate)
+			}
+		}
+		if len(candidates) == 0 {
+			denyPolicy(c, permissionId, claims)
+			return
+		}
+
+		attributes, err := buildPolicyAttributes(c, claims, roleCodes, loader, candidates)
+		if err != nil {
+			slog.Error("Failed to load policy attributes", "requiredPermission", permissionId, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Evaluate(attributes) {
+				c.Set("grantedByPolicy", candidate.Id)
+				c.Next()
+				return
+			}
+		}
+
+		denyPolicy(c, permissionId, claims)
+	}
+}
+
+func buildPolicyAttributes(c *gin.Context, claims *entity.JWTClaims[*auth.JwtPrivateClaims], roleCodes []string, loader policy.ResourceLoader, candidates []*policy.Policy) (policy.Attributes, error) {
+	subject := map[string]any{
+		"id":    claims.RegisteredClaims.Subject,
+		"roles": roleCodes,
+	}
+	if claims.PrivateClaims != nil {
+		subject["email"] = claims.PrivateClaims.Email
+	}
+
+	// Role ids are not part of the token, load them only when a policy needs them
+	for _, candidate := range candidates {
+		if candidate.References("subject.roleIds") || candidate.References("subject.activeRoleIds") {
+			user, err := policy.LoadUserAttributes(claims.RegisteredClaims.Subject)
+			if err != nil {
+				return nil, err
+			}
+			if user != nil {
+				subject["roleIds"] = user["roleIds"]
+				subject["activeRoleIds"] = user["activeRoleIds"]
+			}
+			break
+		}
+	}
+
+	params := make(map[string]any, len(c.Params))
+	for _, param := range c.Params {
+		params[param.Key] = param.Value
+	}
+
+	request, err := readJSONBody(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var resource map[string]any
+	if loader != nil {
+		resource, err = loader(c, request)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return policy.Attributes{
+		"subject":  subject,
+		"resource": resource,
+		"request":  request,
+		"params":   params,
+	}, nil
+}
+
+// readJSONBody decodes the JSON body and restores it so the handler can bind it again
+func readJSONBody(c *gin.Context) (map[string]any, error) {
+	if c.Request.Body == nil || c.ContentType() != gin.MIMEJSON {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var request map[string]any
+	if len(body) > 0 && json.Unmarshal(body, &request) != nil {
+		// The handler reports malformed bodies; policies just see no request attributes
+		return nil, nil
+	}
+	return request, nil
+}
+
+func denyPolicy(c *gin.Context, permissionId int, claims *entity.JWTClaims[*auth.JwtPrivateClaims]) {
+	slog.Info("Access denied: missing required permission or policy", "requiredPermission", permissionId, "subject", claims.RegisteredClaims.Subject)
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this resource"})
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
+)
+
+// ServiceAccountHeader es el header con la clave del service account que realiza la llamada
+const ServiceAccountHeader = security.ServiceAccountHeader
+
+// RequireServiceAccount middleware checks that the caller is a configured service account.
+// Service accounts are configured in SERVICE_ACCOUNT_KEYS as "service=key" pairs separated by commas.
+func RequireServiceAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(ServiceAccountHeader)
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": ServiceAccountHeader + " header is required"})
+			return
+		}
+
+		serviceName := security.FindServiceAccount(key)
+		if serviceName == "" {
+			slog.Info("Access denied: invalid service account key", "path", c.FullPath())
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid service account key"})
+			return
+		}
+
+		// Store the service account name in context for later use
+		c.Set("serviceAccount", serviceName)
+		c.Next()
+	}
+}
@@ -0,0 +1,15 @@
+package model
+
+import "fmt"
+
+// AvatarKeyPrefix es el prefijo de las imágenes de perfil en el almacenamiento de objetos
+const AvatarKeyPrefix = "avatars/"
+
+// AvatarSizes son los lados en píxeles de las versiones cuadradas de la imagen de perfil; la primera es la principal
+var AvatarSizes = []int{512, 128, 64}
+
+// AvatarKey devuelve la clave de la versión de la imagen de perfil con el lado indicado.
+// User.ImageFileKey guarda el prefijo común de todas las versiones.
+func AvatarKey(imageFileKey string, size int) string {
+	return fmt.Sprintf("%s/%d.jpg", imageFileKey, size)
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// Tipos de solicitud de un interesado sobre sus datos personales
+const (
+	DataSubjectRequestExport  = "EXPORT"
+	DataSubjectRequestErasure = "ERASURE"
+)
+
+// Estados de una solicitud de datos; el proceso en segundo plano las lleva de PENDING a COMPLETED o FAILED
+const (
+	DataSubjectRequestPending    = "PENDING"
+	DataSubjectRequestProcessing = "PROCESSING"
+	DataSubjectRequestCompleted  = "COMPLETED"
+	DataSubjectRequestFailed     = "FAILED"
+)
+
+// MaxDataSubjectRequestAttempts es el número de intentos antes de dar una solicitud por fallida
+const MaxDataSubjectRequestAttempts = 3
+
+// DataExportKeyPrefix es el prefijo de los archivos exportados en el almacenamiento de objetos
+const DataExportKeyPrefix = "data-exports/"
+
+// DataSubjectRequest es una solicitud de exportación o de supresión de los datos de un usuario (RGPD)
+type DataSubjectRequest struct {
+	Id     string `json:"id" firestore:"id,omitempty"`
+	UserId string `json:"userId" firestore:"userId"`
+	Type   string `json:"type" firestore:"type"`
+	Status string `json:"status" firestore:"status"`
+	// Usuario que la solicitó: el propio interesado o un administrador
+	RequestedBy string     `json:"requestedBy" firestore:"requestedBy"`
+	RequestedAt time.Time  `json:"requestedAt" firestore:"requestedAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty" firestore:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty" firestore:"completedAt,omitempty"`
+	Attempts    int        `json:"attempts" firestore:"attempts"`
+	// Último error del proceso, sin datos personales
+	Error string `json:"error,omitempty" firestore:"error,omitempty"`
+	// Archivo de una exportación completada; se borra al llegar ArchiveExpiresAt
+	ArchiveKey       string     `json:"archiveKey,omitempty" firestore:"archiveKey,omitempty"`
+	ArchiveExpiresAt *time.Time `json:"archiveExpiresAt,omitempty" firestore:"archiveExpiresAt,omitempty"`
+}
+
+// IsOpen indica si la solicitud aún no ha terminado
+func (r *DataSubjectRequest) IsOpen() bool {
+	return r.Status == DataSubjectRequestPending || r.Status == DataSubjectRequestProcessing
+}
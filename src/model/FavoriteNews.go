@@ -0,0 +1,14 @@
+package model
+
+import "regexp"
+
+// MaxFavoriteNewsArticles es el número máximo de noticias favoritas por usuario; acota el tamaño del documento
+const MaxFavoriteNewsArticles = 500
+
+// newsArticleIdPattern admite los IDs del servicio de noticias (UUIDs u otros identificadores sin "/")
+var newsArticleIdPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// IsValidNewsArticleId indica si el ID de la noticia tiene un formato válido
+func IsValidNewsArticleId(articleId string) bool {
+	return newsArticleIdPattern.MatchString(articleId)
+}
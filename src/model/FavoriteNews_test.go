@@ -0,0 +1,30 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsValidNewsArticleId(t *testing.T) {
+	tests := []struct {
+		articleId string
+		want      bool
+	}{
+		{"3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b", true},
+		{"news_2026-03-01", true},
+		{strings.Repeat("a", 128), true},
+		{"", false},
+		{strings.Repeat("a", 129), false},
+		{"news/1", false},
+		{"news 1", false},
+		{"../users", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.articleId, func(t *testing.T) {
+			if got := IsValidNewsArticleId(tt.articleId); got != tt.want {
+				t.Fatalf("IsValidNewsArticleId(%q) = %v, want %v", tt.articleId, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Group agrupa usuarios para asignarles roles en conjunto; sus miembros heredan los roles del grupo
+type Group struct {
+	Id          string    `json:"id" firestore:"id,omitempty"`
+	Name        string    `json:"name" firestore:"name"`
+	Description string    `json:"description" firestore:"description,omitempty"`
+	MemberIds   []string  `json:"memberIds" firestore:"memberIds"`
+	RoleIds     []string  `json:"roleIds" firestore:"roleIds"`
+	CreatedAt   time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
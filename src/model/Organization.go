@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Organization es una tienda del marketplace con su propio personal y roles
+type Organization struct {
+	Id        string    `json:"id" firestore:"id,omitempty"`
+	Name      string    `json:"name" firestore:"name"`
+	OwnerId   string    `json:"ownerId" firestore:"ownerId"`
+	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// OrganizationMembership asigna a un usuario roles de una organización; solo aplican dentro de ella
+type OrganizationMembership struct {
+	Id             string    `json:"id" firestore:"id,omitempty"`
+	OrganizationId string    `json:"organizationId" firestore:"organizationId"`
+	UserId         string    `json:"userId" firestore:"userId"`
+	RoleIds        []string  `json:"roleIds" firestore:"roleIds"`
+	CreatedAt      time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// MembershipId es el ID del documento de membresía, único por organización y usuario
+func MembershipId(organizationId, userId string) string {
+	return organizationId + "_" + userId
+}
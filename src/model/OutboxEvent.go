@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Tipos de evento que este servicio publica para otros microservicios
+const (
+	// UserErasedEvent avisa de que se suprimieron los datos de un usuario y los demás servicios deben hacer lo mismo
+	UserErasedEvent = "user.erased"
+)
+
+// OutboxEvent es un evento guardado en la misma transacción que el cambio que lo origina, de modo que no se pierde
+// ni se publica sin el cambio. Los demás servicios lo leen del feed de eventos en orden de CreatedAt.
+type OutboxEvent struct {
+	Id   string `json:"id" firestore:"id,omitempty"`
+	Type string `json:"type" firestore:"type"`
+	// ID del usuario u otra entidad a la que se refiere el evento
+	Subject   string         `json:"subject" firestore:"subject"`
+	Data      map[string]any `json:"data,omitempty" firestore:"data,omitempty"`
+	CreatedAt time.Time      `json:"createdAt" firestore:"createdAt"`
+}
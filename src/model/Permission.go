@@ -1,200 +1,466 @@
 package model
 
+import (
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
 type Permission struct {
 	Id          int    `json:"id" firestore:"id,omitempty"`
 	Method      string `json:"method" firestore:"method,omitempty"`
 	Path        string `json:"path" firestore:"path,omitempty"`
 	Name        string `json:"name" firestore:"name,omitempty"`
 	Description string `json:"description" firestore:"description,omitempty"`
+	// Service que registró el permiso en el catálogo (namespace)
+	Service    string `json:"service" firestore:"service,omitempty"`
+	Deprecated bool   `json:"deprecated" firestore:"deprecated"`
+	// Rutas de este servicio protegidas por el permiso; se generan al registrar las rutas
+	Endpoints []PermissionEndpoint `json:"endpoints,omitempty" firestore:"-"`
 }
 
+// PermissionEndpoint es una ruta de la API protegida por un permiso
+type PermissionEndpoint struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// PermissionSource carga los permisos registrados por otros servicios en el catálogo persistido
+type PermissionSource func() ([]*Permission, error)
+
+// UserServiceNamespace es el namespace de los permisos definidos en este servicio
+const UserServiceNamespace = "user-service"
+
+// PermissionCacheTTL es el tiempo que se reutiliza la vista cacheada del catálogo
+const PermissionCacheTTL = time.Minute
+
 var Permissions *[]Permission
 var PermissionsMap *map[int]Permission
 
+var (
+	permissionSource    PermissionSource
+	permissionsLoadedAt time.Time
+	permissionsMutex    sync.Mutex
+	builtinEndpoints    map[int][]PermissionEndpoint
+	// permissionsGeneration cambia con cada invalidación; una carga iniciada antes no se guarda en la cache
+	permissionsGeneration uint64
+	permissionLoads       singleflight.Group
+)
+
 const (
 	// Permission Management
-	GetAllPermissions   = 301
-	GetPermissionById   = 302
-	GetPermissionsByIds = 303
+	GetAllPermissions    = 301
+	GetPermissionById    = 302
+	GetPermissionsByIds  = 303
+	ExplainAuthorization = 304
 
 	// Role Management
-	CreateRole        = 401
-	GetRoleById       = 402
-	GetRolesPaginated = 404
-	DeleteRole        = 405
-	UpdateRole        = 406
+	CreateRole           = 401
+	GetRoleById          = 402
+	GetRolesPaginated    = 404
+	DeleteRole           = 405
+	UpdateRole           = 406
+	ApproveRoleChange    = 407
+	ManageSodConstraints = 408
+	GetSodViolations     = 409
+	ExportRoles          = 410
 
 	// User Management
-	CreateUser        = 501
-	GetUserById       = 502
-	UpdateUser        = 503
-	DeleteUser        = 504
-	GetUsersPaginated = 505
-
-	// Product Management
-	CreateProduct        = 601
-	GetProductById       = 602
-	UpdateProduct        = 603
-	DeleteProduct        = 604
-	GetProductsPaginated = 605
-	AdjustProductStock   = 606
-	SearchProducts       = 607
+	CreateUser           = 501
+	GetUserById          = 502
+	UpdateUser           = 503
+	DeleteUser           = 504
+	GetUsersPaginated    = 505
+	GrantTemporaryRole   = 506
+	GetNewsFavoriteCount = 507
+	ManageUserStatus     = 508
+	RestoreUser          = 509
+	ManageDataRequests   = 510
+	ImportUsers          = 511
+	ExportUsers          = 512
+
+	// Organization Management
+	CreateOrganization        = 701
+	GetOrganization           = 702
+	UpdateOrganization        = 703
+	ManageOrganizationMembers = 704
+	ManageOrganizationRoles   = 705
+	GetAllOrganizations       = 706
+
+	// Group Management
+	CreateGroup        = 801
+	GetGroupById       = 802
+	GetAllGroups       = 803
+	UpdateGroup        = 804
+	DeleteGroup        = 805
+	ManageGroupMembers = 806
 )
 
+// SeedPermissions son los permisos de otros servicios que antes estaban definidos en este (601-607).
+// Se siembran en el catálogo persistido para que los roles que los referencian no los pierdan
+// mientras el servicio dueño aún no registró su catálogo; su registro los reemplaza.
+var SeedPermissions = map[string][]Permission{
+	"product-service": {
+		{Id: 601, Method: "POST", Path: "/products", Name: "Crear Producto", Description: "Permiso para crear un nuevo producto en el catálogo"},
+		{Id: 602, Method: "GET", Path: "/products/:id", Name: "Ver Producto por ID", Description: "Permiso para obtener los detalles de un producto específico por su ID"},
+		{Id: 603, Method: "PUT", Path: "/products", Name: "Actualizar Producto", Description: "Permiso para modificar la información de un producto existente"},
+		{Id: 604, Method: "DELETE", Path: "/products/:id", Name: "Eliminar Producto", Description: "Permiso para eliminar un producto del catálogo por su ID"},
+		{Id: 605, Method: "GET", Path: "/products/pages", Name: "Ver Productos Paginados", Description: "Permiso para obtener productos de forma paginada"},
+		{Id: 606, Method: "PATCH", Path: "/products/:id/stock", Name: "Ajustar Stock de Producto", Description: "Permiso para incrementar o decrementar el stock de un producto"},
+		{Id: 607, Method: "GET", Path: "/products/search", Name: "Buscar Productos con Filtros", Description: "Permiso para buscar productos por términos y filtros avanzados (similar a Amazon)"},
+	},
+}
+
+// organizationScopedPermissions son los permisos propios que un rol de organización puede conceder
+var organizationScopedPermissions = map[int]bool{
+	GetOrganization:           true,
+	UpdateOrganization:        true,
+	ManageOrganizationMembers: true,
+	ManageOrganizationRoles:   true,
+}
+
+// SetPermissionSource configura el origen del catálogo persistido e invalida la cache
+func SetPermissionSource(source PermissionSource) {
+	permissionsMutex.Lock()
+	defer permissionsMutex.Unlock()
+	permissionSource = source
+	PermissionsMap = nil
+	Permissions = nil
+	permissionsGeneration++
+}
+
+// SetPermissionEndpoints registra las rutas que protege cada permiso propio e invalida la cache.
+// Se llama una sola vez al registrar las rutas, antes de atender peticiones.
+func SetPermissionEndpoints(endpoints map[int][]PermissionEndpoint) {
+	permissionsMutex.Lock()
+	defer permissionsMutex.Unlock()
+	builtinEndpoints = endpoints
+	PermissionsMap = nil
+	Permissions = nil
+	permissionsGeneration++
+}
+
+// BuiltinPermissionIds devuelve los IDs de los permisos definidos en este servicio, ordenados
+func BuiltinPermissionIds() []int {
+	permissionIds := make([]int, 0)
+	for id := range builtinPermissions() {
+		permissionIds = append(permissionIds, id)
+	}
+	sort.Ints(permissionIds)
+	return permissionIds
+}
+
+// InvalidatePermissionCache fuerza la recarga del catálogo en la siguiente consulta
+func InvalidatePermissionCache() {
+	permissionsMutex.Lock()
+	defer permissionsMutex.Unlock()
+	PermissionsMap = nil
+	Permissions = nil
+	permissionsGeneration++
+}
+
+// GetAllPermissionsMap devuelve los permisos propios del servicio junto con los registrados
+// por otros servicios en el catálogo. La vista se cachea durante PermissionCacheTTL.
 func GetAllPermissionsMap() *map[int]Permission {
-	if PermissionsMap != nil {
+	permissionsMutex.Lock()
+	current := PermissionsMap
+	if current != nil && (permissionSource == nil || time.Since(permissionsLoadedAt) < PermissionCacheTTL) {
+		permissionsMutex.Unlock()
+		return current
+	}
+	source := permissionSource
+	generation := permissionsGeneration
+	permissionsMutex.Unlock()
+
+	// El catálogo se consulta fuera del mutex y una sola vez por generación. Si la vista venció se sigue
+	// usando mientras se recarga en segundo plano; solo se espera la consulta cuando no hay vista.
+	key := strconv.FormatUint(generation, 10)
+	refresh := func() (any, error) {
+		return refreshPermissionsMap(source, generation), nil
+	}
+	if current != nil {
+		permissionLoads.DoChan(key, refresh)
+		return current
+	}
+	loaded, _, _ := permissionLoads.Do(key, refresh)
+	return loaded.(*map[int]Permission)
+}
+
+// refreshPermissionsMap carga el catálogo y lo guarda como vista cacheada, salvo que la cache se haya
+// invalidado durante la carga. Si el catálogo no se puede leer se mantiene la última vista conocida.
+func refreshPermissionsMap(source PermissionSource, generation uint64) *map[int]Permission {
+	permissionsMap, err := loadPermissionsMap(source)
+	if err != nil {
+		slog.Error("Failed to load permission catalog", "error", err)
+	}
+
+	permissionsMutex.Lock()
+	defer permissionsMutex.Unlock()
+	if generation != permissionsGeneration {
+		// El resultado sirve a quien esperaba la carga pero no se guarda
+		return permissionsMap
+	}
+	if err != nil && PermissionsMap != nil {
+		permissionsLoadedAt = time.Now()
 		return PermissionsMap
 	}
-	PermissionsMap = &map[int]Permission{
+	PermissionsMap = permissionsMap
+	Permissions = nil
+	permissionsLoadedAt = time.Now()
+	return PermissionsMap
+}
+
+// loadPermissionsMap combina los permisos propios con el catálogo persistido. Si el catálogo no se
+// puede leer devuelve solo los permisos propios junto con el error.
+func loadPermissionsMap(source PermissionSource) (*map[int]Permission, error) {
+	permissionsMap := builtinPermissions()
+	if source == nil {
+		return &permissionsMap, nil
+	}
+	catalog, err := source()
+	if err != nil {
+		return &permissionsMap, err
+	}
+	for _, permission := range catalog {
+		// Los permisos propios del servicio no pueden ser sobrescritos por el catálogo
+		if _, exists := permissionsMap[permission.Id]; exists {
+			continue
+		}
+		permissionsMap[permission.Id] = *permission
+	}
+	return &permissionsMap, nil
+}
+
+// IsBuiltinPermission indica si el ID pertenece a los permisos definidos en este servicio
+func IsBuiltinPermission(id int) bool {
+	_, ok := builtinPermissions()[id]
+	return ok
+}
+
+// IsOrganizationScoped indica si un rol de organización puede conceder el permiso.
+// Los permisos de otros servicios se pueden conceder; de este servicio solo los de gestión de la propia organización.
+func IsOrganizationScoped(id int) bool {
+	if organizationScopedPermissions[id] {
+		return true
+	}
+	permission := FindPermissionById(id)
+	return permission != nil && permission.Service != UserServiceNamespace
+}
+
+func builtinPermissions() map[int]Permission {
+	permissions := map[int]Permission{
 		GetAllPermissions: {
 			Id:          GetAllPermissions,
-			Method:      "GET",
-			Path:        "/permissions",
 			Name:        "Ver Todos los Permisos",
 			Description: "Permiso para obtener todos los permisos del sistema",
 		},
 		GetPermissionById: {
 			Id:          GetPermissionById,
-			Method:      "GET",
-			Path:        "/permissions/:id",
 			Name:        "Ver Permiso por ID",
 			Description: "Permiso para obtener un permiso específico por su ID",
 		},
 		GetPermissionsByIds: {
 			Id:          GetPermissionsByIds,
-			Method:      "POST",
-			Path:        "/permissions/by-ids",
 			Name:        "Ver Permisos por IDs",
 			Description: "Permiso para obtener múltiples permisos por sus IDs",
 		},
+		ExplainAuthorization: {
+			Id:          ExplainAuthorization,
+			Name:        "Explicar Autorización",
+			Description: "Permiso para ver qué concesión o denegación decide un permiso para un usuario",
+		},
 		CreateRole: {
 			Id:          CreateRole,
-			Method:      "POST",
-			Path:        "/roles",
 			Name:        "Crear Rol",
 			Description: "Permiso para crear un nuevo rol con permisos asociados",
 		},
 		GetRoleById: {
 			Id:          GetRoleById,
-			Method:      "GET",
-			Path:        "/roles/:id",
 			Name:        "Ver Rol por ID",
 			Description: "Permiso para obtener información detallada de un rol específico por su ID",
 		},
 		GetRolesPaginated: {
 			Id:          GetRolesPaginated,
-			Method:      "GET",
-			Path:        "/roles/pages",
 			Name:        "Ver Roles Paginados",
 			Description: "Permiso para obtener roles de forma paginada para mejor rendimiento",
 		},
 		DeleteRole: {
 			Id:          DeleteRole,
-			Method:      "DELETE",
-			Path:        "/roles/:id",
 			Name:        "Eliminar Rol",
 			Description: "Permiso para eliminar un rol específico del sistema por su ID",
 		},
 		UpdateRole: {
 			Id:          UpdateRole,
-			Method:      "PUT",
-			Path:        "/roles",
 			Name:        "Actualizar Rol",
 			Description: "Permiso para actualizar la información y permisos de un rol existente",
 		},
+		ApproveRoleChange: {
+			Id:          ApproveRoleChange,
+			Name:        "Aprobar Cambios de Roles",
+			Description: "Permiso para ver, aprobar o rechazar solicitudes de asignación de roles privilegiados",
+		},
+		ManageSodConstraints: {
+			Id:          ManageSodConstraints,
+			Name:        "Gestionar Separación de Funciones",
+			Description: "Permiso para crear, ver, actualizar y eliminar restricciones de roles mutuamente excluyentes",
+		},
+		GetSodViolations: {
+			Id:          GetSodViolations,
+			Name:        "Ver Violaciones de Separación de Funciones",
+			Description: "Permiso para listar usuarios que tienen roles mutuamente excluyentes",
+		},
+		ExportRoles: {
+			Id:          ExportRoles,
+			Name:        "Exportar Roles",
+			Description: "Permiso para descargar todos los roles globales en formato NDJSON o CSV",
+		},
 		CreateUser: {
 			Id:          CreateUser,
-			Method:      "POST",
-			Path:        "/users",
 			Name:        "Crear Usuario",
 			Description: "Permiso para crear un nuevo usuario en el sistema",
 		},
 		GetUserById: {
 			Id:          GetUserById,
-			Method:      "GET",
-			Path:        "/users/:id",
 			Name:        "Ver Usuario por ID",
 			Description: "Permiso para recuperar información de un usuario específico por su ID",
 		},
 		UpdateUser: {
 			Id:          UpdateUser,
-			Method:      "PUT",
-			Path:        "/users",
 			Name:        "Actualizar Usuario",
 			Description: "Permiso para actualizar la información de un usuario existente",
 		},
 		DeleteUser: {
 			Id:          DeleteUser,
-			Method:      "DELETE",
-			Path:        "/users/:id",
 			Name:        "Eliminar Usuario",
 			Description: "Permiso para eliminar un usuario del sistema",
 		},
 		GetUsersPaginated: {
 			Id:          GetUsersPaginated,
-			Method:      "GET",
-			Path:        "/users/pages",
 			Name:        "Ver Usuarios Paginados",
 			Description: "Permiso para obtener usuarios de forma paginada",
 		},
-		CreateProduct: {
-			Id:          CreateProduct,
-			Method:      "POST",
-			Path:        "/products",
-			Name:        "Crear Producto",
-			Description: "Permiso para crear un nuevo producto en el catálogo",
-		},
-		GetProductById: {
-			Id:          GetProductById,
-			Method:      "GET",
-			Path:        "/products/:id",
-			Name:        "Ver Producto por ID",
-			Description: "Permiso para obtener los detalles de un producto específico por su ID",
-		},
-		UpdateProduct: {
-			Id:          UpdateProduct,
-			Method:      "PUT",
-			Path:        "/products",
-			Name:        "Actualizar Producto",
-			Description: "Permiso para modificar la información de un producto existente",
-		},
-		DeleteProduct: {
-			Id:          DeleteProduct,
-			Method:      "DELETE",
-			Path:        "/products/:id",
-			Name:        "Eliminar Producto",
-			Description: "Permiso para eliminar un producto del catálogo por su ID",
-		},
-		GetProductsPaginated: {
-			Id:          GetProductsPaginated,
-			Method:      "GET",
-			Path:        "/products/pages",
-			Name:        "Ver Productos Paginados",
-			Description: "Permiso para obtener productos de forma paginada",
-		},
-		AdjustProductStock: {
-			Id:          AdjustProductStock,
-			Method:      "PATCH",
-			Path:        "/products/:id/stock",
-			Name:        "Ajustar Stock de Producto",
-			Description: "Permiso para incrementar o decrementar el stock de un producto",
-		},
-		SearchProducts: {
-			Id:          SearchProducts,
-			Method:      "GET",
-			Path:        "/products/search",
-			Name:        "Buscar Productos con Filtros",
-			Description: "Permiso para buscar productos por términos y filtros avanzados (similar a Amazon)",
+		GrantTemporaryRole: {
+			Id:          GrantTemporaryRole,
+			Name:        "Asignar Rol Temporal",
+			Description: "Permiso para asignar o revocar roles con fecha de vencimiento",
+		},
+		GetNewsFavoriteCount: {
+			Id:          GetNewsFavoriteCount,
+			Name:        "Ver Favoritos de Noticias",
+			Description: "Permiso para consultar cuántos usuarios tienen una noticia en favoritos",
+		},
+		ManageUserStatus: {
+			Id:          ManageUserStatus,
+			Name:        "Gestionar Estado de Usuario",
+			Description: "Permiso para suspender, desactivar o reactivar la cuenta de un usuario",
+		},
+		RestoreUser: {
+			Id:          RestoreUser,
+			Name:        "Restaurar Usuario",
+			Description: "Permiso para restaurar un usuario eliminado antes de su purga",
+		},
+		ManageDataRequests: {
+			Id:          ManageDataRequests,
+			Name:        "Gestionar Solicitudes de Datos",
+			Description: "Permiso para consultar las solicitudes de exportación y supresión de datos y solicitar la supresión de un usuario",
+		},
+		ImportUsers: {
+			Id:          ImportUsers,
+			Name:        "Importar Usuarios",
+			Description: "Permiso para crear o actualizar usuarios en bloque desde un archivo CSV o NDJSON",
+		},
+		ExportUsers: {
+			Id:          ExportUsers,
+			Name:        "Exportar Usuarios",
+			Description: "Permiso para descargar los usuarios filtrados en formato NDJSON o CSV, sin contraseñas",
+		},
+		CreateOrganization: {
+			Id:          CreateOrganization,
+			Name:        "Crear Organización",
+			Description: "Permiso para crear organizaciones (tiendas) del marketplace",
+		},
+		GetOrganization: {
+			Id:          GetOrganization,
+			Name:        "Ver Organización",
+			Description: "Permiso para ver una organización y sus miembros",
+		},
+		UpdateOrganization: {
+			Id:          UpdateOrganization,
+			Name:        "Actualizar Organización",
+			Description: "Permiso para actualizar los datos de una organización",
+		},
+		ManageOrganizationMembers: {
+			Id:          ManageOrganizationMembers,
+			Name:        "Gestionar Miembros de Organización",
+			Description: "Permiso para añadir, quitar y asignar roles a los miembros de una organización",
+		},
+		ManageOrganizationRoles: {
+			Id:          ManageOrganizationRoles,
+			Name:        "Gestionar Roles de Organización",
+			Description: "Permiso para crear, actualizar y eliminar los roles propios de una organización",
+		},
+		GetAllOrganizations: {
+			Id:          GetAllOrganizations,
+			Name:        "Ver Organizaciones",
+			Description: "Permiso para listar todas las organizaciones",
+		},
+		CreateGroup: {
+			Id:          CreateGroup,
+			Name:        "Crear Grupo",
+			Description: "Permiso para crear grupos de usuarios",
+		},
+		GetGroupById: {
+			Id:          GetGroupById,
+			Name:        "Ver Grupo",
+			Description: "Permiso para ver un grupo, sus miembros y sus roles",
+		},
+		GetAllGroups: {
+			Id:          GetAllGroups,
+			Name:        "Ver Grupos",
+			Description: "Permiso para listar todos los grupos",
+		},
+		UpdateGroup: {
+			Id:          UpdateGroup,
+			Name:        "Actualizar Grupo",
+			Description: "Permiso para actualizar un grupo y los roles que heredan sus miembros",
+		},
+		DeleteGroup: {
+			Id:          DeleteGroup,
+			Name:        "Eliminar Grupo",
+			Description: "Permiso para eliminar grupos",
+		},
+		ManageGroupMembers: {
+			Id:          ManageGroupMembers,
+			Name:        "Gestionar Miembros de Grupo",
+			Description: "Permiso para añadir y quitar miembros de un grupo",
 		},
 	}
-	return PermissionsMap
+	for id, permission := range permissions {
+		permission.Service = UserServiceNamespace
+		// El método y la ruta se toman de la primera ruta registrada con el permiso
+		if endpoints := builtinEndpoints[id]; len(endpoints) > 0 {
+			permission.Method = endpoints[0].Method
+			permission.Path = endpoints[0].Path
+			permission.Endpoints = endpoints
+		}
+		permissions[id] = permission
+	}
+	return permissions
 }
 
 func GetAllPermissionsAsSlice() *[]Permission {
+	permissions := GetAllPermissionsMap()
+
+	permissionsMutex.Lock()
+	defer permissionsMutex.Unlock()
 	if Permissions != nil {
 		return Permissions
 	}
-	permissions := GetAllPermissionsMap()
 	var permissionsSlice []Permission
 	for _, permission := range *permissions {
 		permissionsSlice = append(permissionsSlice, permission)
@@ -221,3 +487,23 @@ func FindPermissionsByIds(ids []int) *[]Permission {
 	}
 	return &foundPermissions
 }
+
+// FindPermissionByEndpoint busca en el catálogo el permiso asociado a un endpoint.
+// Si service está vacío se busca en todos los namespaces.
+func FindPermissionByEndpoint(service, method, path string) *Permission {
+	permissions := GetAllPermissionsMap()
+	for _, permission := range *permissions {
+		if service != "" && permission.Service != service {
+			continue
+		}
+		if strings.EqualFold(permission.Method, method) && permission.Path == path {
+			return &permission
+		}
+		for _, endpoint := range permission.Endpoints {
+			if strings.EqualFold(endpoint.Method, method) && endpoint.Path == path {
+				return &permission
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// PermissionGroup agrupa los permisos por la centena de su ID (3xx permisos, 4xx roles, 5xx usuarios...)
+type PermissionGroup struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// Primer ID del rango; el grupo incluye los IDs de Base a Base+99
+	Base int `json:"base"`
+}
+
+// PermissionWildcardSuffix convierte la clave de un grupo en una concesión comodín, por ejemplo "users:*"
+const PermissionWildcardSuffix = ":*"
+
+const permissionGroupSize = 100
+
+// PermissionGroups son los namespaces del catálogo, propios y de otros servicios
+var PermissionGroups = []PermissionGroup{
+	{Key: "permissions", Name: "Gestión de Permisos", Base: 300},
+	{Key: "roles", Name: "Gestión de Roles", Base: 400},
+	{Key: "users", Name: "Gestión de Usuarios", Base: 500},
+	{Key: "products", Name: "Gestión de Productos", Base: 600},
+	{Key: "organizations", Name: "Gestión de Organizaciones", Base: 700},
+	{Key: "groups", Name: "Gestión de Grupos", Base: 800},
+}
+
+// Contains indica si el ID del permiso pertenece al rango del grupo
+func (g *PermissionGroup) Contains(permissionId int) bool {
+	return permissionId >= g.Base && permissionId < g.Base+permissionGroupSize
+}
+
+// Wildcard devuelve la concesión comodín que otorga todos los permisos del grupo
+func (g *PermissionGroup) Wildcard() string {
+	return g.Key + PermissionWildcardSuffix
+}
+
+// PermissionIds devuelve los IDs del catálogo actual que pertenecen al grupo, sin los obsoletos, ordenados
+func (g *PermissionGroup) PermissionIds() []int {
+	var permissionIds []int
+	for id, permission := range *GetAllPermissionsMap() {
+		if g.Contains(id) && !permission.Deprecated {
+			permissionIds = append(permissionIds, id)
+		}
+	}
+	sort.Ints(permissionIds)
+	return permissionIds
+}
+
+// FindPermissionGroup busca un grupo por su clave
+func FindPermissionGroup(key string) *PermissionGroup {
+	for i := range PermissionGroups {
+		if PermissionGroups[i].Key == key {
+			return &PermissionGroups[i]
+		}
+	}
+	return nil
+}
+
+// PermissionGroupOf devuelve el grupo al que pertenece un permiso, o nil si su ID no está en ningún rango
+func PermissionGroupOf(permissionId int) *PermissionGroup {
+	for i := range PermissionGroups {
+		if PermissionGroups[i].Contains(permissionId) {
+			return &PermissionGroups[i]
+		}
+	}
+	return nil
+}
+
+// ParsePermissionWildcard interpreta una concesión comodín como "users:*" y devuelve su grupo
+func ParsePermissionWildcard(wildcard string) (*PermissionGroup, bool) {
+	key, found := strings.CutSuffix(wildcard, PermissionWildcardSuffix)
+	if !found {
+		return nil, false
+	}
+	group := FindPermissionGroup(key)
+	return group, group != nil
+}
+
+// ExpandPermissionWildcards devuelve los IDs que conceden las concesiones comodín válidas; las desconocidas se ignoran
+func ExpandPermissionWildcards(wildcards []string) []int {
+	var permissionIds []int
+	for _, wildcard := range wildcards {
+		if group, ok := ParsePermissionWildcard(wildcard); ok {
+			permissionIds = append(permissionIds, group.PermissionIds()...)
+		}
+	}
+	return permissionIds
+}
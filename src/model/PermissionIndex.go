@@ -0,0 +1,156 @@
+package model
+
+import (
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// PermissionIndex asigna a cada permiso del catálogo una posición fija, usada por la codificación
+// compacta de permisos en los tokens. El índice solo crece: un permiso nuevo se agrega al final y
+// las posiciones existentes no cambian, ni siquiera si un permiso deja el catálogo. La versión es el
+// número de posiciones, así que el índice de una versión anterior es un prefijo del actual.
+type PermissionIndex struct {
+	Version   string
+	ids       []int
+	positions map[int]int
+}
+
+// PermissionIndexStore guarda el índice para que todas las réplicas usen las mismas posiciones
+type PermissionIndexStore interface {
+	// Load devuelve los IDs en el orden de sus posiciones
+	Load() ([]int, error)
+	// Append agrega al final, en una transacción, los IDs que aún no tienen posición y devuelve el índice completo
+	Append(permissionIds []int) ([]int, error)
+}
+
+var (
+	permissionIndex        *PermissionIndex
+	permissionIndexCatalog *map[int]Permission
+	permissionIndexStore   PermissionIndexStore
+	permissionIndexMutex   sync.Mutex
+)
+
+// SetPermissionIndexStore configura dónde se guarda el índice y descarta el cargado.
+// Sin almacenamiento el índice solo vive en memoria, lo que sirve para una sola réplica.
+func SetPermissionIndexStore(store PermissionIndexStore) {
+	permissionIndexMutex.Lock()
+	defer permissionIndexMutex.Unlock()
+	permissionIndexStore = store
+	permissionIndex = nil
+	permissionIndexCatalog = nil
+}
+
+// CurrentPermissionIndex devuelve el índice con todos los permisos del catálogo vigente. Cuando cambia la vista
+// cacheada del catálogo, los permisos que aún no tienen posición se agregan al final del índice guardado.
+func CurrentPermissionIndex() *PermissionIndex {
+	catalog := GetAllPermissionsMap()
+
+	permissionIndexMutex.Lock()
+	defer permissionIndexMutex.Unlock()
+	if permissionIndex != nil && permissionIndexCatalog == catalog {
+		return permissionIndex
+	}
+	if permissionIndex == nil {
+		permissionIndex = loadPermissionIndex()
+	}
+
+	var missing []int
+	for id := range *catalog {
+		if _, ok := permissionIndex.positions[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Ints(missing)
+		ids := append(slices.Clone(permissionIndex.ids), missing...)
+		if permissionIndexStore != nil {
+			stored, err := permissionIndexStore.Append(missing)
+			if err != nil {
+				// Sin posición guardada los permisos nuevos no se codifican y el token conserva los arrays
+				slog.Error("Failed to append permissions to the permission index", "error", err)
+				return permissionIndex
+			}
+			ids = stored
+		}
+		permissionIndex = newPermissionIndex(ids)
+	}
+	permissionIndexCatalog = catalog
+	return permissionIndex
+}
+
+// PermissionIndexForVersion devuelve el índice con el que se emitió un token de esa versión. Si otra réplica
+// agregó posiciones que esta aún no conoce, recarga el índice guardado. Devuelve false si la versión no existe.
+func PermissionIndexForVersion(version string) (*PermissionIndex, bool) {
+	size, err := strconv.Atoi(version)
+	if err != nil || size < 0 {
+		return nil, false
+	}
+
+	current := CurrentPermissionIndex()
+
+	permissionIndexMutex.Lock()
+	defer permissionIndexMutex.Unlock()
+	if size > current.Size() && permissionIndexStore != nil {
+		if reloaded := loadPermissionIndex(); reloaded.Size() > current.Size() {
+			permissionIndex = reloaded
+			current = reloaded
+		}
+	}
+	if size > current.Size() {
+		return nil, false
+	}
+	if size == current.Size() {
+		return current, true
+	}
+	return &PermissionIndex{Version: version, ids: current.ids[:size], positions: current.positions}, true
+}
+
+// loadPermissionIndex lee el índice guardado; si falla empieza con uno vacío, que se completa al agregar los permisos
+func loadPermissionIndex() *PermissionIndex {
+	if permissionIndexStore == nil {
+		return newPermissionIndex(nil)
+	}
+	ids, err := permissionIndexStore.Load()
+	if err != nil {
+		slog.Error("Failed to load the permission index", "error", err)
+		return newPermissionIndex(nil)
+	}
+	return newPermissionIndex(ids)
+}
+
+func newPermissionIndex(ids []int) *PermissionIndex {
+	positions := make(map[int]int, len(ids))
+	for position, id := range ids {
+		positions[id] = position
+	}
+	return &PermissionIndex{
+		Version:   strconv.Itoa(len(ids)),
+		ids:       ids,
+		positions: positions,
+	}
+}
+
+// Size devuelve el número de permisos del índice
+func (i *PermissionIndex) Size() int {
+	return len(i.ids)
+}
+
+// Position devuelve la posición del permiso en el índice, o false si no está en el catálogo
+func (i *PermissionIndex) Position(permissionId int) (int, bool) {
+	position, ok := i.positions[permissionId]
+	if !ok || position >= len(i.ids) {
+		return 0, false
+	}
+	return position, true
+}
+
+// PermissionAt devuelve el ID del permiso en la posición, o false si está fuera del índice
+func (i *PermissionIndex) PermissionAt(position int) (int, bool) {
+	if position < 0 || position >= len(i.ids) {
+		return 0, false
+	}
+	return i.ids[position], true
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// fakePermissionIndexStore guarda el índice en memoria, como lo compartirían varias réplicas
+type fakePermissionIndexStore struct {
+	ids []int
+}
+
+func (s *fakePermissionIndexStore) Load() ([]int, error) {
+	return slices.Clone(s.ids), nil
+}
+
+func (s *fakePermissionIndexStore) Append(permissionIds []int) ([]int, error) {
+	for _, id := range permissionIds {
+		if !slices.Contains(s.ids, id) {
+			s.ids = append(s.ids, id)
+		}
+	}
+	return slices.Clone(s.ids), nil
+}
+
+// usePermissionCatalog configura un catálogo con los permisos propios más los externos indicados
+func usePermissionCatalog(t *testing.T, store PermissionIndexStore, externalIds ...int) {
+	t.Helper()
+	SetPermissionIndexStore(store)
+	setExternalPermissions(externalIds...)
+	t.Cleanup(func() {
+		SetPermissionIndexStore(nil)
+		SetPermissionSource(nil)
+	})
+}
+
+func TestPermissionIndexPositionsAreStable(t *testing.T) {
+	store := &fakePermissionIndexStore{}
+	usePermissionCatalog(t, store)
+
+	before := CurrentPermissionIndex()
+	builtinIds := BuiltinPermissionIds()
+	if before.Size() != len(builtinIds) || before.Version != strconv.Itoa(len(builtinIds)) {
+		t.Fatalf("index size = %d version = %s, want %d builtin permissions", before.Size(), before.Version, len(builtinIds))
+	}
+
+	// Registrar un permiso con un ID menor que los propios no mueve ninguna posición
+	setExternalPermissions(250)
+	after := CurrentPermissionIndex()
+	for _, id := range builtinIds {
+		previous, _ := before.Position(id)
+		if current, ok := after.Position(id); !ok || current != previous {
+			t.Fatalf("position of %d = %d, want %d", id, current, previous)
+		}
+	}
+	if position, ok := after.Position(250); !ok || position != before.Size() {
+		t.Fatalf("position of 250 = %d, want %d", position, before.Size())
+	}
+	if after.Version != strconv.Itoa(before.Size()+1) {
+		t.Fatalf("version = %s, want %d", after.Version, before.Size()+1)
+	}
+
+	// Un permiso que deja el catálogo conserva su posición y no se reutiliza
+	setExternalPermissions(260)
+	latest := CurrentPermissionIndex()
+	if position, ok := latest.Position(250); !ok || position != before.Size() {
+		t.Fatalf("position of removed 250 = %d, %v", position, ok)
+	}
+	if position, ok := latest.Position(260); !ok || position != before.Size()+1 {
+		t.Fatalf("position of 260 = %d, want %d", position, before.Size()+1)
+	}
+}
+
+func TestPermissionIndexUsesStoredPositions(t *testing.T) {
+	// Otra réplica ya guardó posiciones en otro orden
+	store := &fakePermissionIndexStore{ids: []int{ExportUsers, 610, GetAllPermissions}}
+	usePermissionCatalog(t, store, 610)
+
+	index := CurrentPermissionIndex()
+	for position, id := range []int{ExportUsers, 610, GetAllPermissions} {
+		if got, ok := index.PermissionAt(position); !ok || got != id {
+			t.Fatalf("PermissionAt(%d) = %d, want %d", position, got, id)
+		}
+	}
+	if index.Size() != len(BuiltinPermissionIds())+1 || len(store.ids) != index.Size() {
+		t.Fatalf("index size = %d, stored = %d", index.Size(), len(store.ids))
+	}
+}
+
+func TestPermissionIndexForVersion(t *testing.T) {
+	store := &fakePermissionIndexStore{}
+	usePermissionCatalog(t, store)
+	current := CurrentPermissionIndex()
+	size := current.Size()
+
+	// Otra réplica agregó un permiso que esta aún no conoce
+	store.ids = append(store.ids, 999)
+
+	tests := []struct {
+		name     string
+		version  string
+		wantOk   bool
+		wantSize int
+	}{
+		{"current version", current.Version, true, size},
+		{"earlier version", strconv.Itoa(size - 1), true, size - 1},
+		{"empty index", "0", true, 0},
+		{"version appended by another replica", strconv.Itoa(size + 1), true, size + 1},
+		{"unknown version", strconv.Itoa(size + 2), false, 0},
+		{"legacy hash version", "9f86d081884c7d65", false, 0},
+		{"negative version", "-1", false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index, ok := PermissionIndexForVersion(test.version)
+			if ok != test.wantOk {
+				t.Fatalf("PermissionIndexForVersion(%q) ok = %v, want %v", test.version, ok, test.wantOk)
+			}
+			if ok && index.Size() != test.wantSize {
+				t.Fatalf("size = %d, want %d", index.Size(), test.wantSize)
+			}
+		})
+	}
+
+	earlier, _ := PermissionIndexForVersion(strconv.Itoa(size - 1))
+	lastId, _ := current.PermissionAt(size - 1)
+	if _, ok := earlier.Position(lastId); ok {
+		t.Fatalf("earlier version must not have a position for %d", lastId)
+	}
+	if _, ok := earlier.PermissionAt(size - 1); ok {
+		t.Fatal("earlier version must not decode positions past its size")
+	}
+}
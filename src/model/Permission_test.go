@@ -0,0 +1,130 @@
+package model
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingPermissionSource devuelve un catálogo solo cuando se libera, y cuenta las consultas
+type blockingPermissionSource struct {
+	calls   atomic.Int32
+	release chan struct{}
+	ids     []int
+}
+
+func newBlockingPermissionSource(ids ...int) *blockingPermissionSource {
+	return &blockingPermissionSource{release: make(chan struct{}), ids: ids}
+}
+
+func (s *blockingPermissionSource) load() ([]*Permission, error) {
+	s.calls.Add(1)
+	<-s.release
+	var permissions []*Permission
+	for _, id := range s.ids {
+		permissions = append(permissions, &Permission{Id: id, Name: "External", Service: "product-service"})
+	}
+	return permissions, nil
+}
+
+func setExternalPermissions(externalIds ...int) {
+	SetPermissionSource(func() ([]*Permission, error) {
+		var permissions []*Permission
+		for _, id := range externalIds {
+			permissions = append(permissions, &Permission{Id: id, Name: "External " + strconv.Itoa(id), Service: "product-service"})
+		}
+		return permissions, nil
+	})
+}
+
+func TestGetAllPermissionsMapSharesOneLoad(t *testing.T) {
+	source := newBlockingPermissionSource(601)
+	SetPermissionSource(source.load)
+	t.Cleanup(func() { SetPermissionSource(nil) })
+
+	var wg sync.WaitGroup
+	results := make([]*map[int]Permission, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = GetAllPermissionsMap()
+		}()
+	}
+	for source.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(source.release)
+	wg.Wait()
+
+	if calls := source.calls.Load(); calls != 1 {
+		t.Fatalf("catalog loaded %d times, want 1", calls)
+	}
+	for _, result := range results {
+		if _, ok := (*result)[601]; !ok {
+			t.Fatalf("permission 601 missing from %v", result)
+		}
+	}
+}
+
+func TestGetAllPermissionsMapServesStaleViewWhileReloading(t *testing.T) {
+	setExternalPermissions(601)
+	t.Cleanup(func() { SetPermissionSource(nil) })
+	stale := GetAllPermissionsMap()
+
+	// La recarga queda bloqueada en Firestore; las verificaciones de permisos no deben esperarla
+	source := newBlockingPermissionSource(602)
+	permissionsMutex.Lock()
+	permissionSource = source.load
+	permissionsLoadedAt = time.Now().Add(-2 * PermissionCacheTTL)
+	permissionsMutex.Unlock()
+
+	done := make(chan *map[int]Permission)
+	go func() { done <- GetAllPermissionsMap() }()
+	select {
+	case result := <-done:
+		if result != stale {
+			t.Fatal("expected the stale view while the catalog reloads")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("permission lookup blocked behind the catalog load")
+	}
+
+	close(source.release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := (*GetAllPermissionsMap())[602]; ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("catalog was not refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGetAllPermissionsMapDiscardsLoadInvalidatedMidway(t *testing.T) {
+	source := newBlockingPermissionSource(601)
+	SetPermissionSource(source.load)
+	t.Cleanup(func() { SetPermissionSource(nil) })
+
+	done := make(chan *map[int]Permission)
+	go func() { done <- GetAllPermissionsMap() }()
+	for source.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Un registro invalida la cache mientras la carga anterior sigue en curso
+	InvalidatePermissionCache()
+	close(source.release)
+	<-done
+
+	permissionsMutex.Lock()
+	cached := PermissionsMap
+	permissionsMutex.Unlock()
+	if cached != nil {
+		t.Fatal("a load started before the invalidation must not be cached")
+	}
+}
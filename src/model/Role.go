@@ -1,7 +1,35 @@
 package model
 
+import "regexp"
+
+// UserRoleCode es el rol que reciben los usuarios que inician sesión con Google por primera vez
+const UserRoleCode = "USER"
+
+// SystemRoleCodes son los roles de los que depende el servicio; no se pueden eliminar ni renombrar
+var SystemRoleCodes = []string{UserRoleCode}
+
+// roleCodePattern exige códigos en mayúsculas: letras, dígitos y guiones bajos, empezando por una letra
+var roleCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,49}$`)
+
+// IsValidRoleCode indica si el código cumple el formato de los códigos de rol
+func IsValidRoleCode(code string) bool {
+	return roleCodePattern.MatchString(code)
+}
+
 type Role struct {
 	Id          string        `json:"id" firestore:"id,omitempty"`
 	Code        string        `json:"code" firestore:"code,omitempty"`
 	Permissions *[]Permission `json:"permissions" firestore:"permissions,omitempty"`
+	// Concesiones comodín como "users:*"; se expanden con el catálogo vigente al resolver los permisos
+	PermissionGroups []string `json:"permissionGroups" firestore:"permissionGroups,omitempty"`
+	// Permisos denegados explícitamente; prevalecen sobre los concedidos por cualquier rol
+	DeniedPermissionIds []int `json:"deniedPermissionIds" firestore:"deniedPermissionIds,omitempty"`
+	// Asignar un rol privilegiado a un usuario requiere la aprobación de un segundo usuario
+	Privileged bool `json:"privileged" firestore:"privileged"`
+	// Organización a la que pertenece el rol; vacío para los roles globales de la plataforma
+	OrganizationId string `json:"organizationId,omitempty" firestore:"organizationId,omitempty"`
+	// Los roles del sistema no se pueden eliminar ni renombrar, pero sí cambiar sus permisos
+	System bool `json:"system" firestore:"system"`
+	// Aumenta con cada cambio del rol; los tokens emitidos con una versión anterior se rechazan
+	AuthzVersion int64 `json:"authzVersion" firestore:"authzVersion"`
 }
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// RoleAssignment es una asignación temporal de rol, vigente en [ValidFrom, ValidUntil)
+type RoleAssignment struct {
+	RoleId     string    `json:"roleId" firestore:"roleId"`
+	ValidFrom  time.Time `json:"validFrom" firestore:"validFrom"`
+	ValidUntil time.Time `json:"validUntil" firestore:"validUntil"`
+	GrantedBy  string    `json:"grantedBy" firestore:"grantedBy,omitempty"`
+	GrantedAt  time.Time `json:"grantedAt" firestore:"grantedAt"`
+}
+
+// Acciones registradas en el historial de asignaciones temporales
+const (
+	RoleAssignmentGranted = "GRANTED"
+	RoleAssignmentRevoked = "REVOKED"
+	RoleAssignmentExpired = "EXPIRED"
+)
+
+// RoleAssignmentHistory registra la concesión, revocación o expiración de una asignación temporal
+type RoleAssignmentHistory struct {
+	Id         string    `json:"id" firestore:"id,omitempty"`
+	UserId     string    `json:"userId" firestore:"userId"`
+	RoleId     string    `json:"roleId" firestore:"roleId"`
+	Action     string    `json:"action" firestore:"action"`
+	ValidFrom  time.Time `json:"validFrom" firestore:"validFrom"`
+	ValidUntil time.Time `json:"validUntil" firestore:"validUntil"`
+	// Usuario que realizó la acción; vacío cuando la expiración la registra el proceso automático
+	ActorId    string    `json:"actorId" firestore:"actorId,omitempty"`
+	RecordedAt time.Time `json:"recordedAt" firestore:"recordedAt"`
+}
+
+// IsActive indica si la asignación está vigente en el instante indicado
+func (a *RoleAssignment) IsActive(now time.Time) bool {
+	return !now.Before(a.ValidFrom) && now.Before(a.ValidUntil)
+}
+
+// IsExpired indica si la asignación ya terminó su vigencia
+func (a *RoleAssignment) IsExpired(now time.Time) bool {
+	return !now.Before(a.ValidUntil)
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Estados de una solicitud de cambio de roles privilegiados
+const (
+	RoleChangePending  = "PENDING"
+	RoleChangeApproved = "APPROVED"
+	RoleChangeRejected = "REJECTED"
+)
+
+// RoleChangeRequest es una solicitud pendiente de asignar roles privilegiados a un usuario.
+// Las solicitudes no se eliminan: conservan cada transición en History.
+type RoleChangeRequest struct {
+	Id      string   `json:"id" firestore:"id,omitempty"`
+	UserId  string   `json:"userId" firestore:"userId"`
+	RoleIds []string `json:"roleIds" firestore:"roleIds"`
+	// Si se indica ValidUntil, al aprobarse se asigna como rol temporal
+	ValidFrom   *time.Time        `json:"validFrom,omitempty" firestore:"validFrom,omitempty"`
+	ValidUntil  *time.Time        `json:"validUntil,omitempty" firestore:"validUntil,omitempty"`
+	Status      string            `json:"status" firestore:"status"`
+	RequestedBy string            `json:"requestedBy" firestore:"requestedBy"`
+	RequestedAt time.Time         `json:"requestedAt" firestore:"requestedAt"`
+	DecidedBy   string            `json:"decidedBy,omitempty" firestore:"decidedBy,omitempty"`
+	DecidedAt   *time.Time        `json:"decidedAt,omitempty" firestore:"decidedAt,omitempty"`
+	History     []RoleChangeEvent `json:"history" firestore:"history"`
+}
+
+// RoleChangeEvent registra una transición de estado de la solicitud
+type RoleChangeEvent struct {
+	Status  string    `json:"status" firestore:"status"`
+	ActorId string    `json:"actorId" firestore:"actorId"`
+	Comment string    `json:"comment,omitempty" firestore:"comment,omitempty"`
+	At      time.Time `json:"at" firestore:"at"`
+}
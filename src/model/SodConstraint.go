@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// SodConstraint es un conjunto de roles mutuamente excluyentes: un usuario puede tener como máximo uno de ellos
+type SodConstraint struct {
+	Id          string    `json:"id" firestore:"id,omitempty"`
+	Name        string    `json:"name" firestore:"name"`
+	Description string    `json:"description" firestore:"description,omitempty"`
+	RoleIds     []string  `json:"roleIds" firestore:"roleIds"`
+	CreatedAt   time.Time `json:"createdAt" firestore:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// ConflictingRoleIds devuelve los roles de la restricción presentes en roleIds si hay más de uno, o nil
+func (c *SodConstraint) ConflictingRoleIds(roleIds []string) []string {
+	held := make(map[string]bool, len(roleIds))
+	for _, roleId := range roleIds {
+		held[roleId] = true
+	}
+
+	var conflicting []string
+	for _, roleId := range c.RoleIds {
+		if held[roleId] {
+			conflicting = append(conflicting, roleId)
+		}
+	}
+	if len(conflicting) < 2 {
+		return nil
+	}
+	return conflicting
+}
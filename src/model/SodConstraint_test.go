@@ -0,0 +1,30 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSodConstraintConflictingRoleIds(t *testing.T) {
+	constraint := &SodConstraint{RoleIds: []string{"r-approver", "r-requester", "r-auditor"}}
+
+	tests := []struct {
+		name    string
+		roleIds []string
+		want    []string
+	}{
+		{"no roles", nil, nil},
+		{"one role of the constraint", []string{"r-approver", "r-support"}, nil},
+		{"two roles", []string{"r-requester", "r-support", "r-approver"}, []string{"r-approver", "r-requester"}},
+		{"all roles", []string{"r-auditor", "r-requester", "r-approver"}, []string{"r-approver", "r-requester", "r-auditor"}},
+		{"repeated role", []string{"r-approver", "r-approver"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := constraint.ConflictingRoleIds(test.roleIds); !slices.Equal(got, test.want) {
+				t.Fatalf("ConflictingRoleIds() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
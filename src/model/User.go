@@ -1,6 +1,7 @@
 package model
 
 import (
+	"slices"
 	"time"
 )
 
@@ -16,6 +17,104 @@ type User struct {
 	CreatedAt  time.Time `json:"createdAt" firestore:"createdAt,omitempty"`
 	UpdatedAt  time.Time `json:"updatedAt" firestore:"updatedAt,omitempty"`
 	RoleIds    []string  `json:"roleIds" firestore:"roleIds,omitempty"`
+	// Permisos denegados al usuario aunque alguno de sus roles los conceda
+	DeniedPermissionIds []int `json:"deniedPermissionIds" firestore:"deniedPermissionIds,omitempty"`
+	// Roles asignados temporalmente, solo cuentan dentro de su ventana de vigencia
+	RoleAssignments []RoleAssignment `json:"roleAssignments" firestore:"roleAssignments,omitempty"`
+	// Menor ValidUntil de RoleAssignments, usado por el proceso que elimina asignaciones vencidas
+	RoleAssignmentsExpireAt *time.Time `json:"roleAssignmentsExpireAt,omitempty" firestore:"roleAssignmentsExpireAt,omitempty"`
+	// Aumenta cuando cambian los roles, las denegaciones, los grupos o las membresías del usuario;
+	// los tokens emitidos con una versión anterior se rechazan
+	AuthzVersion int64 `json:"authzVersion" firestore:"authzVersion"`
 	// IDs of favorite news articles
 	FavoriteNewsArticleIds []string `json:"favoriteNewsArticleIds" firestore:"favoriteNewsArticleIds,omitempty"`
+	// Estado del ciclo de vida (UserStatus*); vacío en los usuarios anteriores al campo, que cuentan como activos
+	Status string `json:"status" firestore:"status"`
+	// Motivo del último cambio de estado, por ejemplo de una suspensión
+	StatusReason string `json:"statusReason,omitempty" firestore:"statusReason,omitempty"`
+	// Usuario que hizo el último cambio de estado; vacío si lo hizo el proceso automático
+	StatusChangedBy string     `json:"statusChangedBy,omitempty" firestore:"statusChangedBy,omitempty"`
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty" firestore:"statusChangedAt,omitempty"`
+	// Fin de la suspensión; nil es una suspensión indefinida
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty" firestore:"suspendedUntil,omitempty"`
+	// Momento a partir del cual el proceso de purga borra definitivamente un usuario eliminado
+	PurgeAt *time.Time `json:"purgeAt,omitempty" firestore:"purgeAt,omitempty"`
+}
+
+// ActiveRoleIds devuelve los roles permanentes más los temporales vigentes, sin duplicados
+func (u *User) ActiveRoleIds(now time.Time) []string {
+	roleIds := make([]string, 0, len(u.RoleIds)+len(u.RoleAssignments))
+	seen := make(map[string]bool)
+	for _, roleId := range u.RoleIds {
+		if !seen[roleId] {
+			seen[roleId] = true
+			roleIds = append(roleIds, roleId)
+		}
+	}
+	for _, assignment := range u.RoleAssignments {
+		if assignment.IsActive(now) && !seen[assignment.RoleId] {
+			seen[assignment.RoleId] = true
+			roleIds = append(roleIds, assignment.RoleId)
+		}
+	}
+	return roleIds
+}
+
+// HeldRoleIds devuelve los roles permanentes y los temporales que no han vencido, incluidos los que aún no empiezan.
+// Es el conjunto que se evalúa para las restricciones de separación de funciones.
+func (u *User) HeldRoleIds(now time.Time) []string {
+	roleIds := slices.Clone(u.RoleIds)
+	for _, assignment := range u.RoleAssignments {
+		if !assignment.IsExpired(now) && !slices.Contains(roleIds, assignment.RoleId) {
+			roleIds = append(roleIds, assignment.RoleId)
+		}
+	}
+	return roleIds
+}
+
+// RefreshRoleAssignmentsExpireAt recalcula el vencimiento más próximo de las asignaciones temporales
+func (u *User) RefreshRoleAssignmentsExpireAt() {
+	u.RoleAssignmentsExpireAt = nil
+	for _, assignment := range u.RoleAssignments {
+		validUntil := assignment.ValidUntil
+		if u.RoleAssignmentsExpireAt == nil || validUntil.Before(*u.RoleAssignmentsExpireAt) {
+			u.RoleAssignmentsExpireAt = &validUntil
+		}
+	}
+}
+
+// ReplaceRole quita el rol de los roles permanentes y temporales del usuario. Si replacementId no está vacío,
+// el usuario recibe ese rol en su lugar, con la misma vigencia en el caso de las asignaciones temporales.
+// Devuelve false si el usuario no tenía el rol.
+func (u *User) ReplaceRole(roleId, replacementId string) bool {
+	changed := false
+
+	if slices.Contains(u.RoleIds, roleId) {
+		changed = true
+		u.RoleIds = slices.DeleteFunc(u.RoleIds, func(id string) bool { return id == roleId })
+		if replacementId != "" && !slices.Contains(u.RoleIds, replacementId) {
+			u.RoleIds = append(u.RoleIds, replacementId)
+		}
+	}
+
+	assignments := make([]RoleAssignment, 0, len(u.RoleAssignments))
+	for _, assignment := range u.RoleAssignments {
+		if assignment.RoleId != roleId {
+			assignments = append(assignments, assignment)
+			continue
+		}
+		changed = true
+		// Si ya tiene el rol de reemplazo, se conserva la asignación que tenía
+		if replacementId == "" || slices.Contains(u.RoleIds, replacementId) ||
+			slices.ContainsFunc(u.RoleAssignments, func(a RoleAssignment) bool { return a.RoleId == replacementId }) {
+			continue
+		}
+		assignment.RoleId = replacementId
+		assignments = append(assignments, assignment)
+	}
+	if changed {
+		u.RoleAssignments = assignments
+		u.RefreshRoleAssignmentsExpireAt()
+	}
+	return changed
 }
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// Estados del ciclo de vida de una cuenta de usuario
+const (
+	UserStatusActive = "ACTIVE"
+	// Bloqueo temporal o indefinido decidido por un administrador, con un motivo
+	UserStatusSuspended = "SUSPENDED"
+	// Cuenta cerrada que se puede reactivar; no se elimina nunca automáticamente
+	UserStatusDeactivated = "DEACTIVATED"
+	// Eliminación lógica: el documento se conserva hasta PurgeAt para no romper las referencias de otros servicios
+	UserStatusDeleted = "DELETED"
+	// Datos personales suprimidos a petición del interesado; el documento anonimizado se conserva y no cambia más
+	UserStatusErased = "ERASED"
+)
+
+// UserStatuses son los estados válidos
+var UserStatuses = []string{UserStatusActive, UserStatusSuspended, UserStatusDeactivated, UserStatusDeleted, UserStatusErased}
+
+// userStatusTransitions son los cambios de estado permitidos desde cada estado
+var userStatusTransitions = map[string][]string{
+	UserStatusActive:      {UserStatusSuspended, UserStatusDeactivated, UserStatusDeleted},
+	UserStatusSuspended:   {UserStatusActive, UserStatusDeactivated, UserStatusDeleted},
+	UserStatusDeactivated: {UserStatusActive, UserStatusDeleted},
+	// Solo se sale de la eliminación restaurando la cuenta antes de la purga
+	UserStatusDeleted: {UserStatusActive},
+}
+
+// IsValidUserStatus indica si status es uno de los estados conocidos
+func IsValidUserStatus(status string) bool {
+	return slices.Contains(UserStatuses, status)
+}
+
+// CanTransitionUserStatus indica si una cuenta puede pasar del estado from al estado to
+func CanTransitionUserStatus(from, to string) bool {
+	return slices.Contains(userStatusTransitions[from], to)
+}
+
+// CurrentStatus devuelve el estado del usuario en el instante indicado. Los usuarios anteriores al campo
+// no tienen estado y están activos, igual que los suspendidos cuya suspensión ya venció.
+func (u *User) CurrentStatus(now time.Time) string {
+	switch {
+	case u.Status == "":
+		return UserStatusActive
+	case u.Status == UserStatusSuspended && u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil):
+		return UserStatusActive
+	default:
+		return u.Status
+	}
+}
+
+// IsActive indica si el usuario puede iniciar sesión y usar sus tokens
+func (u *User) IsActive(now time.Time) bool {
+	return u.CurrentStatus(now) == UserStatusActive
+}
+
+// SetStatus cambia el estado y limpia el fin de suspensión y la fecha de purga, que el llamador fija
+// de nuevo si el estado los usa. actorId vacío indica un cambio automático.
+func (u *User) SetStatus(status, reason, actorId string, now time.Time) {
+	u.Status = status
+	u.StatusReason = reason
+	u.StatusChangedBy = actorId
+	u.StatusChangedAt = &now
+	u.SuspendedUntil = nil
+	u.PurgeAt = nil
+}
+
+// Anonymize suprime los datos personales y los roles del usuario, e invalida sus tokens. Se conservan el ID y las
+// fechas para que las referencias de otros servicios sigan siendo válidas.
+func (u *User) Anonymize(actorId string, now time.Time) {
+	u.Email = "erased-" + u.Id + "@erased.invalid"
+	u.PasswordHash = ""
+	u.FullName = ""
+	u.ImageFileKey = ""
+	u.PictureUrl = ""
+	u.RoleIds = nil
+	u.DeniedPermissionIds = nil
+	u.RoleAssignments = nil
+	u.RoleAssignmentsExpireAt = nil
+	u.FavoriteNewsArticleIds = nil
+	u.SetStatus(UserStatusErased, "", actorId, now)
+	u.UpdatedAt = now
+	u.AuthzVersion++
+}
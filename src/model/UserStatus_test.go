@@ -0,0 +1,70 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCanTransitionUserStatus(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{UserStatusActive, UserStatusSuspended, true},
+		{UserStatusActive, UserStatusDeactivated, true},
+		{UserStatusActive, UserStatusDeleted, true},
+		{UserStatusActive, UserStatusErased, false},
+		{UserStatusSuspended, UserStatusActive, true},
+		{UserStatusSuspended, UserStatusDeactivated, true},
+		{UserStatusDeactivated, UserStatusActive, true},
+		{UserStatusDeactivated, UserStatusSuspended, false},
+		{UserStatusDeleted, UserStatusActive, true},
+		{UserStatusDeleted, UserStatusSuspended, false},
+		{UserStatusDeleted, UserStatusDeactivated, false},
+		{UserStatusErased, UserStatusActive, false},
+		{UserStatusErased, UserStatusDeleted, false},
+		{"UNKNOWN", UserStatusActive, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := CanTransitionUserStatus(tt.from, tt.to); got != tt.want {
+				t.Fatalf("CanTransitionUserStatus(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserCurrentStatus(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		user User
+		want string
+	}{
+		{"without status", User{}, UserStatusActive},
+		{"active", User{Status: UserStatusActive}, UserStatusActive},
+		{"indefinite suspension", User{Status: UserStatusSuspended}, UserStatusSuspended},
+		{"running suspension", User{Status: UserStatusSuspended, SuspendedUntil: &future}, UserStatusSuspended},
+		{"expired suspension", User{Status: UserStatusSuspended, SuspendedUntil: &past}, UserStatusActive},
+		{"suspension ending now", User{Status: UserStatusSuspended, SuspendedUntil: &now}, UserStatusActive},
+		{"deactivated", User{Status: UserStatusDeactivated}, UserStatusDeactivated},
+		{"deleted past purge", User{Status: UserStatusDeleted, PurgeAt: &past}, UserStatusDeleted},
+		{"erased", User{Status: UserStatusErased}, UserStatusErased},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.CurrentStatus(now); got != tt.want {
+				t.Fatalf("CurrentStatus() = %s, want %s", got, tt.want)
+			}
+			if got := tt.user.IsActive(now); got != (tt.want == UserStatusActive) {
+				t.Fatalf("IsActive() = %v, want %v", got, tt.want == UserStatusActive)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestUserRoleIdsWithAssignments(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	user := &User{
+		RoleIds: []string{"r-permanent", "r-both"},
+		RoleAssignments: []RoleAssignment{
+			{RoleId: "r-active", ValidFrom: now.Add(-time.Hour), ValidUntil: now.Add(time.Hour)},
+			{RoleId: "r-future", ValidFrom: now.Add(time.Hour), ValidUntil: now.Add(2 * time.Hour)},
+			{RoleId: "r-expired", ValidFrom: now.Add(-2 * time.Hour), ValidUntil: now.Add(-time.Hour)},
+			{RoleId: "r-both", ValidFrom: now.Add(-time.Hour), ValidUntil: now.Add(time.Hour)},
+		},
+	}
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"active", user.ActiveRoleIds(now), []string{"r-permanent", "r-both", "r-active"}},
+		{"held", user.HeldRoleIds(now), []string{"r-permanent", "r-both", "r-active", "r-future"}},
+		{"active after expiry", user.ActiveRoleIds(now.Add(3 * time.Hour)), []string{"r-permanent", "r-both"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !slices.Equal(test.got, test.want) {
+				t.Fatalf("got %v, want %v", test.got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Operadores soportados en las condiciones
+const (
+	OperatorEquals    = "=="
+	OperatorNotEquals = "!="
+	OperatorSubset    = "⊆"
+	OperatorSubsetOf  = "subsetOf"
+	OperatorIn        = "in"
+	OperatorContains  = "contains"
+)
+
+// Attributes agrupa los atributos disponibles para las condiciones:
+// subject, resource, request (body JSON) y params (parámetros de la ruta)
+type Attributes map[string]map[string]any
+
+// Condition es una expresión binaria "operando operador operando", por ejemplo "subject.id == resource.id"
+type Condition struct {
+	Expression string
+	left       operand
+	operator   string
+	right      operand
+}
+
+type operand struct {
+	path    []string
+	literal any
+}
+
+// ParseCondition interpreta una expresión de condición
+func ParseCondition(expression string) (*Condition, error) {
+	parts := strings.Fields(expression)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("condition %q must have the form <operand> <operator> <operand>", expression)
+	}
+
+	switch parts[1] {
+	case OperatorEquals, OperatorNotEquals, OperatorSubset, OperatorSubsetOf, OperatorIn, OperatorContains:
+	default:
+		return nil, fmt.Errorf("condition %q uses unknown operator %q", expression, parts[1])
+	}
+
+	left, err := parseOperand(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %v", expression, err)
+	}
+	right, err := parseOperand(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %v", expression, err)
+	}
+
+	return &Condition{Expression: expression, left: left, operator: parts[1], right: right}, nil
+}
+
+func parseOperand(token string) (operand, error) {
+	switch {
+	case len(token) >= 2 && strings.HasPrefix(token, "'") && strings.HasSuffix(token, "'"):
+		return operand{literal: token[1 : len(token)-1]}, nil
+	case token == "true" || token == "false":
+		return operand{literal: token == "true"}, nil
+	}
+	if number, err := strconv.ParseFloat(token, 64); err == nil {
+		return operand{literal: number}, nil
+	}
+
+	path := strings.Split(token, ".")
+	if len(path) < 2 {
+		return operand{}, fmt.Errorf("attribute %q must start with subject, resource, request or params", token)
+	}
+	switch path[0] {
+	case "subject", "resource", "request", "params":
+	default:
+		return operand{}, fmt.Errorf("attribute %q must start with subject, resource, request or params", token)
+	}
+	return operand{path: path}, nil
+}
+
+// References indica si la condición usa el atributo indicado (por ejemplo "subject.roleIds")
+func (c *Condition) References(attribute string) bool {
+	return strings.Join(c.left.path, ".") == attribute || strings.Join(c.right.path, ".") == attribute
+}
+
+// Evaluate evalúa la condición. Un atributo inexistente nunca satisface la condición.
+func (c *Condition) Evaluate(attributes Attributes) bool {
+	left, leftOk := c.left.resolve(attributes)
+	right, rightOk := c.right.resolve(attributes)
+
+	switch c.operator {
+	case OperatorEquals:
+		return leftOk && rightOk && scalarString(left) == scalarString(right)
+	case OperatorNotEquals:
+		return leftOk && rightOk && scalarString(left) != scalarString(right)
+	case OperatorSubset, OperatorSubsetOf:
+		// Un conjunto ausente se considera vacío en el lado izquierdo
+		if !rightOk {
+			return false
+		}
+		return isSubset(toStrings(left), toStrings(right))
+	case OperatorIn:
+		return leftOk && rightOk && containsString(toStrings(right), scalarString(left))
+	case OperatorContains:
+		return leftOk && rightOk && containsString(toStrings(left), scalarString(right))
+	}
+	return false
+}
+
+func (o operand) resolve(attributes Attributes) (any, bool) {
+	if o.path == nil {
+		return o.literal, true
+	}
+
+	var current any = map[string]any(attributes[o.path[0]])
+	for _, key := range o.path[1:] {
+		values, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = values[key]
+		if !ok || current == nil {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func scalarString(value any) string {
+	switch typed := value.(type) {
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(typed)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+func toStrings(value any) []string {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case []string:
+		return typed
+	case []any:
+		values := make([]string, 0, len(typed))
+		for _, item := range typed {
+			values = append(values, scalarString(item))
+		}
+		return values
+	case []int:
+		values := make([]string, 0, len(typed))
+		for _, item := range typed {
+			values = append(values, strconv.Itoa(item))
+		}
+		return values
+	default:
+		return []string{scalarString(typed)}
+	}
+}
+
+func isSubset(values, set []string) bool {
+	for _, value := range values {
+		if !containsString(set, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package policy
+
+import "testing"
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{"equals", "subject.id == resource.id", false},
+		{"subset symbol", "request.roleIds ⊆ subject.roleIds", false},
+		{"subset keyword", "request.roleIds subsetOf subject.roleIds", false},
+		{"in with literal", "'ADMIN' in subject.roles", false},
+		{"number literal", "request.count != 3", false},
+		{"boolean literal", "resource.system == false", false},
+		{"missing operand", "subject.id ==", true},
+		{"extra token", "subject.id == resource.id x", true},
+		{"unknown operator", "subject.id > resource.id", true},
+		{"unknown root", "user.id == resource.id", true},
+		{"bare attribute", "id == resource.id", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseCondition(test.expression)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseCondition(%q) error = %v, wantErr %v", test.expression, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestConditionEvaluate(t *testing.T) {
+	attributes := Attributes{
+		"subject": {
+			"id":      "u1",
+			"roles":   []string{"USER"},
+			"roleIds": []string{"r1", "r2"},
+		},
+		"resource": {
+			"id":      "u1",
+			"roleIds": []any{"r1"},
+			"count":   float64(3),
+		},
+		"request": {
+			"roleIds": []any{"r1", "r3"},
+			"empty":   []any{},
+		},
+		"params": {
+			"id": "u2",
+		},
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"subject.id == resource.id", true},
+		{"subject.id == params.id", false},
+		{"subject.id != params.id", true},
+		{"resource.count == 3", true},
+		{"resource.roleIds ⊆ subject.roleIds", true},
+		{"request.roleIds ⊆ subject.roleIds", false},
+		{"request.empty subsetOf subject.roleIds", true},
+		// A missing set on the left is treated as empty, a missing set on the right never matches
+		{"request.missing ⊆ subject.roleIds", true},
+		{"subject.roleIds ⊆ request.missing", false},
+		{"'USER' in subject.roles", true},
+		{"'ADMIN' in subject.roles", false},
+		{"subject.roleIds contains 'r2'", true},
+		// A missing attribute never satisfies a comparison
+		{"subject.missing == resource.missing", false},
+		{"subject.missing != resource.id", false},
+		{"subject.id.nested == resource.id", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			condition, err := ParseCondition(test.expression)
+			if err != nil {
+				t.Fatalf("ParseCondition(%q) error = %v", test.expression, err)
+			}
+			if got := condition.Evaluate(attributes); got != test.want {
+				t.Fatalf("Evaluate(%q) = %v, want %v", test.expression, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Policy concede un permiso de forma condicionada. Un usuario que no tiene el permiso en sus roles
+// puede acceder si cumple todas las condiciones de alguna política de ese permiso.
+type Policy struct {
+	Id           string `json:"id"`
+	PermissionId int    `json:"permissionId"`
+	Description  string `json:"description"`
+	// Roles restringe la política a usuarios con alguno de estos códigos de rol; vacío aplica a todos
+	Roles      []string `json:"roles"`
+	Conditions []string `json:"conditions"`
+	// Fields limita los campos del body JSON que puede enviar quien accede por la política; vacío no los limita
+	Fields []string `json:"fields"`
+
+	parsedConditions []*Condition
+}
+
+// PolicyFile es el formato del archivo declarativo de políticas
+type PolicyFile struct {
+	Policies []*Policy `json:"policies"`
+}
+
+var (
+	policiesByPermission = make(map[int][]*Policy)
+	policiesMutex        sync.RWMutex
+)
+
+// LoadFromFile carga y valida el archivo de políticas, reemplazando las políticas actuales
+func LoadFromFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read policy file: %v", err)
+	}
+
+	var file PolicyFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return fmt.Errorf("failed to parse policy file: %v", err)
+	}
+
+	return Load(file.Policies)
+}
+
+// Load valida las políticas y las registra
+func Load(policies []*Policy) error {
+	byPermission := make(map[int][]*Policy)
+	for i, policy := range policies {
+		if policy.PermissionId <= 0 {
+			return fmt.Errorf("policy %d (%s) requires a permissionId", i, policy.Id)
+		}
+		if len(policy.Conditions) == 0 {
+			return fmt.Errorf("policy %d (%s) requires at least one condition", i, policy.Id)
+		}
+
+		policy.parsedConditions = make([]*Condition, 0, len(policy.Conditions))
+		for _, expression := range policy.Conditions {
+			condition, err := ParseCondition(expression)
+			if err != nil {
+				return fmt.Errorf("policy %d (%s): %v", i, policy.Id, err)
+			}
+			policy.parsedConditions = append(policy.parsedConditions, condition)
+		}
+
+		byPermission[policy.PermissionId] = append(byPermission[policy.PermissionId], policy)
+	}
+
+	policiesMutex.Lock()
+	defer policiesMutex.Unlock()
+	policiesByPermission = byPermission
+	return nil
+}
+
+// ForPermission devuelve las políticas registradas para un permiso
+func ForPermission(permissionId int) []*Policy {
+	policiesMutex.RLock()
+	defer policiesMutex.RUnlock()
+	return policiesByPermission[permissionId]
+}
+
+// AppliesTo indica si la política aplica a un usuario con los roles indicados
+func (p *Policy) AppliesTo(roleCodes []string) bool {
+	if len(p.Roles) == 0 {
+		return true
+	}
+	for _, role := range p.Roles {
+		if containsString(roleCodes, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// References indica si alguna condición de la política usa el atributo indicado
+func (p *Policy) References(attribute string) bool {
+	for _, condition := range p.parsedConditions {
+		if condition.References(attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate devuelve true si el body solo trae campos permitidos y se cumplen todas las condiciones
+func (p *Policy) Evaluate(attributes Attributes) bool {
+	if len(p.Fields) > 0 {
+		for field := range attributes["request"] {
+			if !containsString(p.Fields, field) {
+				return false
+			}
+		}
+	}
+	for _, condition := range p.parsedConditions {
+		if !condition.Evaluate(attributes) {
+			return false
+		}
+	}
+	return true
+}
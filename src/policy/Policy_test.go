@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func TestUpdateOwnProfilePolicy(t *testing.T) {
+	if err := LoadFromFile("../../policies.json"); err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	var updateOwnProfile *Policy
+	for _, candidate := range ForPermission(model.UpdateUser) {
+		if candidate.Id == "update-own-profile" {
+			updateOwnProfile = candidate
+		}
+	}
+	if updateOwnProfile == nil {
+		t.Fatal("update-own-profile policy not found")
+	}
+
+	tests := []struct {
+		name    string
+		request map[string]any
+		granted bool
+	}{
+		{"full name", map[string]any{"id": "u1", "fullName": "Ana"}, true},
+		{"only id", map[string]any{"id": "u1"}, true},
+		{"email", map[string]any{"id": "u1", "fullName": "Ana", "email": "other@example.com"}, false},
+		{"password", map[string]any{"id": "u1", "password": "new-password"}, false},
+		{"role ids", map[string]any{"id": "u1", "roleIds": []any{"r1"}}, false},
+		{"empty role ids", map[string]any{"id": "u1", "roleIds": []any{}}, false},
+		// Dropping a deny on oneself requires UpdateUser granted by a role
+		{"empty denied permissions", map[string]any{"id": "u1", "deniedPermissionIds": []any{}}, false},
+		{"other denied permissions", map[string]any{"id": "u1", "fullName": "Ana", "deniedPermissionIds": []any{float64(505)}}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attributes := Attributes{
+				"subject":  {"id": "u1"},
+				"resource": {"id": "u1"},
+				"request":  test.request,
+			}
+			if got := updateOwnProfile.Evaluate(attributes); got != test.granted {
+				t.Fatalf("Evaluate() = %v, want %v", got, test.granted)
+			}
+		})
+	}
+
+	t.Run("other user", func(t *testing.T) {
+		attributes := Attributes{
+			"subject":  {"id": "u1"},
+			"resource": {"id": "u2"},
+			"request":  {"id": "u2", "fullName": "Ana"},
+		}
+		if updateOwnProfile.Evaluate(attributes) {
+			t.Fatal("Evaluate() granted an update of another user")
+		}
+	})
+}
+
+func TestPolicyAppliesTo(t *testing.T) {
+	tests := []struct {
+		name      string
+		roles     []string
+		roleCodes []string
+		want      bool
+	}{
+		{"no restriction", nil, []string{"USER"}, true},
+		{"matching role", []string{"USER", "SUPPORT"}, []string{"SUPPORT"}, true},
+		{"other role", []string{"SUPPORT"}, []string{"USER"}, false},
+		{"no roles", []string{"SUPPORT"}, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := &Policy{Roles: test.roles}
+			if got := policy.AppliesTo(test.roleCodes); got != test.want {
+				t.Fatalf("AppliesTo() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *Policy
+	}{
+		{"missing permission", &Policy{Id: "p", Conditions: []string{"subject.id == resource.id"}}},
+		{"missing conditions", &Policy{Id: "p", PermissionId: 503}},
+		{"invalid condition", &Policy{Id: "p", PermissionId: 503, Conditions: []string{"subject.id =="}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := Load([]*Policy{test.policy}); err == nil {
+				t.Fatal("Load() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+)
+
+// ResourceLoader carga los atributos del recurso sobre el que se evalúan las políticas.
+// Devuelve nil si el recurso no existe.
+type ResourceLoader func(c *gin.Context, request map[string]any) (map[string]any, error)
+
+// UserFromParam carga el usuario cuyo ID viene en el parámetro de ruta indicado
+func UserFromParam(param string) ResourceLoader {
+	return func(c *gin.Context, request map[string]any) (map[string]any, error) {
+		return loadUser(c.Param(param))
+	}
+}
+
+// UserFromBody carga el usuario cuyo ID viene en el campo indicado del body JSON
+func UserFromBody(field string) ResourceLoader {
+	return func(c *gin.Context, request map[string]any) (map[string]any, error) {
+		id, _ := request[field].(string)
+		return loadUser(id)
+	}
+}
+
+// LoadUserAttributes carga los atributos de un usuario para usarlos como sujeto o recurso
+func LoadUserAttributes(id string) (map[string]any, error) {
+	return loadUser(id)
+}
+
+func loadUser(id string) (map[string]any, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+
+	user, err := impl.NewUserRepositoryImpl().FindById(id)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return UserAttributes(user), nil
+}
+
+// UserAttributes expone los campos del usuario disponibles para las condiciones (nunca el hash de la contraseña).
+// roleIds son solo los roles permanentes, los que se pueden escribir en RoleIds: comparar con los temporales
+// permitiría convertir una asignación temporal en permanente y perder su vencimiento.
+// activeRoleIds incluye además los temporales vigentes.
+func UserAttributes(user *model.User) map[string]any {
+	roleIds := user.RoleIds
+	if roleIds == nil {
+		roleIds = []string{}
+	}
+	return map[string]any{
+		"id":            user.Id,
+		"email":         user.Email,
+		"fullName":      user.FullName,
+		"roleIds":       roleIds,
+		"activeRoleIds": user.ActiveRoleIds(time.Now()),
+		"createdAt":     user.CreatedAt,
+	}
+}
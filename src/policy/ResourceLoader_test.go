@@ -0,0 +1,55 @@
+package policy
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func TestUserAttributesRoleIds(t *testing.T) {
+	now := time.Now()
+	user := &model.User{
+		Id:      "u1",
+		RoleIds: []string{"r-permanent"},
+		RoleAssignments: []model.RoleAssignment{
+			{RoleId: "r-temporary", ValidFrom: now.Add(-time.Hour), ValidUntil: now.Add(time.Hour)},
+			{RoleId: "r-expired", ValidFrom: now.Add(-2 * time.Hour), ValidUntil: now.Add(-time.Hour)},
+		},
+	}
+
+	attributes := UserAttributes(user)
+	if got := attributes["roleIds"].([]string); !slices.Equal(got, []string{"r-permanent"}) {
+		t.Fatalf("roleIds = %v, want only the permanent role", got)
+	}
+	if got := attributes["activeRoleIds"].([]string); !slices.Equal(got, []string{"r-permanent", "r-temporary"}) {
+		t.Fatalf("activeRoleIds = %v, want the permanent and the active temporary role", got)
+	}
+
+	// A temporary role cannot be copied into the permanent roles through a subset rule
+	condition, err := ParseCondition("request.roleIds ⊆ subject.roleIds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		roleIds []any
+		want    bool
+	}{
+		{"permanent role", []any{"r-permanent"}, true},
+		{"temporary role", []any{"r-permanent", "r-temporary"}, false},
+		{"expired role", []any{"r-expired"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := condition.Evaluate(Attributes{
+				"subject": attributes,
+				"request": {"roleIds": test.roleIds},
+			})
+			if got != test.want {
+				t.Fatalf("Evaluate() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package repository
+
+// AuthzVersionRepository lee solo las versiones de autorización de usuarios y roles,
+// que se comparan en cada petición con las del token
+type AuthzVersionRepository interface {
+	// FindUserVersion devuelve la versión del usuario; found es false si el usuario no existe
+	FindUserVersion(userId string) (version int64, found bool, err error)
+	// FindRoleVersions devuelve la versión de los roles que existen, por ID
+	FindRoleVersions(roleIds []string) (map[string]int64, error)
+}
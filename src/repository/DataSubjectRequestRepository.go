@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+// UserErasure anonimiza el usuario leído dentro de la transacción (nil si ya no existe) y devuelve el evento
+// que se guarda en el outbox junto con el cambio, o nil si no hay nada que avisar
+type UserErasure func(user *model.User) *model.OutboxEvent
+
+type DataSubjectRequestRepository interface {
+	Create(request *model.DataSubjectRequest) (*model.DataSubjectRequest, error)
+	FindById(id string) (*model.DataSubjectRequest, error)
+	// FindAll filtra por estado y usuario; los filtros vacíos se ignoran. Devuelve primero las más recientes.
+	FindAll(status, userId string) ([]*model.DataSubjectRequest, error)
+	Update(request *model.DataSubjectRequest) (*model.DataSubjectRequest, error)
+	// FindClaimable devuelve hasta limit solicitudes pendientes y en proceso desde antes de staleBefore,
+	// cuyo proceso se interrumpió
+	FindClaimable(staleBefore time.Time, limit int) ([]*model.DataSubjectRequest, error)
+	// Claim pasa la solicitud a PROCESSING y cuenta el intento, de forma atómica. Devuelve nil si otra
+	// instancia la tomó antes o ya terminó.
+	Claim(id string, staleBefore, now time.Time) (*model.DataSubjectRequest, error)
+	// FindExpiredArchives devuelve hasta limit exportaciones completadas cuyo archivo venció antes de now
+	FindExpiredArchives(now time.Time, limit int) ([]*model.DataSubjectRequest, error)
+	// CompleteErasure aplica la supresión al usuario, guarda el evento y marca la solicitud como completada
+	// en una sola transacción
+	CompleteErasure(request *model.DataSubjectRequest, erasure UserErasure) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type GroupRepository interface {
+	Create(group *model.Group) (*model.Group, error)
+	FindById(id string) (*model.Group, error)
+	FindAll() ([]*model.Group, error)
+	// FindByMemberId devuelve los grupos de los que el usuario es miembro
+	FindByMemberId(userId string) ([]*model.Group, error)
+	// FindByRoleId devuelve los grupos que asignan el rol a sus miembros
+	FindByRoleId(roleId string) ([]*model.Group, error)
+	Update(group *model.Group) (*model.Group, error)
+	Delete(id string) error
+	// AddMember y RemoveMember modifican la lista de miembros de forma atómica
+	AddMember(groupId, userId string) error
+	RemoveMember(groupId, userId string) error
+}
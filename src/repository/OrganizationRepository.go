@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type OrganizationRepository interface {
+	Create(organization *model.Organization) (*model.Organization, error)
+	FindById(id string) (*model.Organization, error)
+	FindAll() ([]*model.Organization, error)
+	// FindByOwnerId devuelve las organizaciones cuyo propietario es el usuario
+	FindByOwnerId(ownerId string) ([]*model.Organization, error)
+	Update(organization *model.Organization) (*model.Organization, error)
+}
+
+type OrganizationMembershipRepository interface {
+	// Save crea o reemplaza la membresía del usuario en la organización
+	Save(membership *model.OrganizationMembership) (*model.OrganizationMembership, error)
+	FindByOrganizationAndUser(organizationId, userId string) (*model.OrganizationMembership, error)
+	FindByOrganization(organizationId string) ([]*model.OrganizationMembership, error)
+	FindByUserId(userId string) ([]*model.OrganizationMembership, error)
+	Delete(organizationId, userId string) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type OutboxEventRepository interface {
+	// FindAfter devuelve hasta size eventos creados antes de before, en orden de creación a partir del cursor
+	// (desde el principio si es nil)
+	FindAfter(after *PageCursor, before time.Time, size int) ([]*model.OutboxEvent, error)
+}
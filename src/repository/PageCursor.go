@@ -0,0 +1,8 @@
+package repository
+
+// PageCursor identifica el último elemento de una página: el valor de su campo de ordenación y su ID,
+// que desempata los valores repetidos. La página siguiente empieza justo después de él.
+type PageCursor struct {
+	SortValue any
+	Id        string
+}
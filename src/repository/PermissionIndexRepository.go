@@ -0,0 +1,10 @@
+package repository
+
+// PermissionIndexRepository guarda el índice de posiciones de los permisos que usan los tokens compactos.
+// El índice solo crece, así que las posiciones de un permiso no cambian.
+type PermissionIndexRepository interface {
+	// Load devuelve los IDs de los permisos en el orden de sus posiciones; vacío si aún no existe
+	Load() ([]int, error)
+	// Append agrega al final, en una transacción, los IDs que aún no tienen posición y devuelve el índice completo
+	Append(permissionIds []int) ([]int, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type PermissionRepository interface {
+	FindAll() ([]*model.Permission, error)
+	FindByService(service string) ([]*model.Permission, error)
+	// RegisterServiceCatalog reemplaza de forma transaccional el catálogo de un servicio.
+	// Los permisos previamente registrados que no aparecen en la lista se marcan como deprecated.
+	RegisterServiceCatalog(service string, permissions []*model.Permission) ([]*model.Permission, error)
+	// SeedServiceCatalog crea bajo el namespace del servicio los permisos cuyo ID aún no existe en el catálogo,
+	// sin modificar los existentes, y devuelve los creados
+	SeedServiceCatalog(service string, permissions []*model.Permission) ([]*model.Permission, error)
+}
+
+// PermissionCollisionError indica que un ID de permiso ya pertenece a otro servicio
+type PermissionCollisionError struct {
+	Id           int
+	OwnerService string
+}
+
+func (e *PermissionCollisionError) Error() string {
+	return fmt.Sprintf("permission id %d is already registered by service %s", e.Id, e.OwnerService)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+// RoleAssignmentChange modifica las asignaciones temporales de un usuario leído dentro de una transacción
+// y devuelve las entradas de historial a registrar junto con el cambio
+type RoleAssignmentChange func(user *model.User) ([]*model.RoleAssignmentHistory, error)
+
+type RoleAssignmentRepository interface {
+	// UpdateAssignments aplica el cambio y guarda el usuario y el historial de forma atómica.
+	// Devuelve nil si el usuario no existe.
+	UpdateAssignments(userId string, change RoleAssignmentChange) (*model.User, error)
+	// FindUserIdsWithExpiredAssignments devuelve los usuarios con alguna asignación vencida antes de now
+	FindUserIdsWithExpiredAssignments(now time.Time, limit int) ([]string, error)
+	// FindHistoryByUserId devuelve el historial de asignaciones temporales de un usuario, del más reciente al más antiguo
+	FindHistoryByUserId(userId string) ([]*model.RoleAssignmentHistory, error)
+}
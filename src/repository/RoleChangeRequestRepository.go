@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+// RoleChangeDecision aplica la decisión sobre la solicitud y, si corresponde, sobre el usuario leídos dentro de una transacción.
+// Devuelve las entradas de historial de asignaciones temporales a registrar junto con el cambio.
+type RoleChangeDecision func(request *model.RoleChangeRequest, user *model.User) ([]*model.RoleAssignmentHistory, error)
+
+type RoleChangeRequestRepository interface {
+	Create(request *model.RoleChangeRequest) (*model.RoleChangeRequest, error)
+	FindById(id string) (*model.RoleChangeRequest, error)
+	// FindAll filtra por estado y usuario; los filtros vacíos se ignoran
+	FindAll(status, userId string) ([]*model.RoleChangeRequest, error)
+	// Decide guarda la solicitud y el usuario de forma atómica. Devuelve nil si la solicitud no existe;
+	// user es nil en la decisión si el usuario ya no existe.
+	Decide(id string, decision RoleChangeDecision) (*model.RoleChangeRequest, error)
+}
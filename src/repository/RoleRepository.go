@@ -1,9 +1,16 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/ruiborda/ecommerce-user-service/src/model"
 )
 
+// ErrRoleCodeTaken indica que otro rol global ya usa el código
+var ErrRoleCodeTaken = errors.New("role code already in use")
+
+// Create y Update reservan el código de los roles globales en la misma transacción que guardan el rol
+// y devuelven ErrRoleCodeTaken si otro rol ya lo usa; Delete libera la reserva.
 type RoleRepository interface {
 	Create(role *model.Role) (*model.Role, error)
 	FindById(id string) (*model.Role, error)
@@ -13,5 +20,10 @@ type RoleRepository interface {
 	Delete(id string) error
 	FindAllByPageAndSize(page, size int) ([]*model.Role, error)
 	Count() (int64, error)
+	// FindAllAfter devuelve hasta size roles globales ordenados por código a partir del cursor (desde el principio si es nil)
+	FindAllAfter(after *PageCursor, size int) ([]*model.Role, error)
 	FindByIds(ids []string) ([]*model.Role, error)
+	// FindByOrganization devuelve los roles de una organización; FindAll, FindByCode,
+	// FindAllByPageAndSize, FindAllAfter y Count solo consideran roles globales
+	FindByOrganization(organizationId string) ([]*model.Role, error)
 }
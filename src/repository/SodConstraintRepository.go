@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type SodConstraintRepository interface {
+	Create(constraint *model.SodConstraint) (*model.SodConstraint, error)
+	FindById(id string) (*model.SodConstraint, error)
+	FindAll() ([]*model.SodConstraint, error)
+	Update(constraint *model.SodConstraint) (*model.SodConstraint, error)
+	Delete(id string) error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import "time"
+
+// Campos por los que se puede ordenar el listado de usuarios
+const (
+	UserSortCreatedAt = "createdAt"
+	UserSortUpdatedAt = "updatedAt"
+	UserSortEmail     = "email"
+	UserSortFullName  = "fullName"
+)
+
+// UserSortFields son los campos de ordenación admitidos; cada uno tiene sus índices en firestore.indexes.json
+var UserSortFields = []string{UserSortCreatedAt, UserSortUpdatedAt, UserSortEmail, UserSortFullName}
+
+// UserFilter son los criterios de búsqueda del listado de usuarios. Los campos vacíos no filtran.
+// RoleId, Statuses, EmailPrefix y el rango de fechas se resuelven en Firestore cuando la consulta lo permite;
+// Query y NameContains se evalúan en memoria porque Firestore no busca subcadenas.
+type UserFilter struct {
+	// Texto libre que debe aparecer en el email o en el nombre, sin distinguir mayúsculas
+	Query        string
+	EmailPrefix  string
+	NameContains string
+	RoleId       string
+	// Estados admitidos; se resuelve en Firestore con un filtro in
+	Statuses []string
+	// Rango de CreatedAt: desde CreatedFrom (incluido) hasta CreatedBefore (excluido)
+	CreatedFrom   *time.Time
+	CreatedBefore *time.Time
+	SortField     string
+	SortDesc      bool
+}
@@ -1,17 +1,72 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/ruiborda/ecommerce-user-service/src/model"
 )
 
+// ErrFavoriteLimitReached indica que el usuario ya tiene el máximo de favoritos permitido
+var ErrFavoriteLimitReached = errors.New("favorite limit reached")
+
+// UserChange modifica un usuario leído dentro de una transacción; si devuelve un error no se guarda nada
+type UserChange func(user *model.User) error
+
+// UserCondition decide, sobre el usuario leído dentro de una transacción, si se aplica la operación
+type UserCondition func(user *model.User) bool
+
 type UserRepository interface {
 	Create(user *model.User) (*model.User, error)
 	FindById(id string) (*model.User, error)
 	FindByEmail(email string) (*model.User, error)
+	// FindByEmails devuelve los usuarios con alguno de los emails; los que no existen se ignoran
+	FindByEmails(emails []string) ([]*model.User, error)
 	FindAll() ([]*model.User, error)
-	Update(user *model.User) (*model.User, error)
+	// Update aplica el cambio al usuario leído en una transacción y escribe solo los campos modificados; sin cambios no escribe.
+	// Un aumento de AuthzVersion se guarda como incremento. Las asignaciones temporales y los favoritos no se escriben
+	// porque tienen sus propias operaciones atómicas. Devuelve nil si el usuario no existe.
+	Update(id string, change UserChange) (*model.User, error)
 	Delete(id string) error
+	// CreateAll crea los usuarios en transacciones de hasta 500; reciben ID y estado como en Create. Cada transacción
+	// vuelve a buscar los emails y no crea los usuarios cuyo email ya está registrado, que devuelve.
+	// Si falla, las transacciones anteriores ya se aplicaron.
+	CreateAll(users []*model.User) (taken []string, err error)
+	// DeleteIf borra el usuario en una transacción solo si su estado actual cumple la condición.
+	// Devuelve el usuario borrado, o nil si no existe o no la cumple.
+	DeleteIf(id string, condition UserCondition) (*model.User, error)
 	FindAllByPageAndSize(page, size int) ([]*model.User, error)
 	Count() (int64, error)
+	// FindByFilter devuelve la página (desde 0) de los usuarios que cumplen el filtro, en su orden
+	FindByFilter(filter *UserFilter, page, size int) ([]*model.User, error)
+	// FindByFilterAfter devuelve hasta size usuarios que cumplen el filtro a partir del cursor (desde el principio si es nil)
+	FindByFilterAfter(filter *UserFilter, after *PageCursor, size int) ([]*model.User, error)
+	// CountByFilter cuenta los usuarios que cumplen el filtro
+	CountByFilter(filter *UserFilter) (int64, error)
 	FindByIds(ids []string) ([]*model.User, error)
+	// FindByOrganization devuelve solo los usuarios que son miembros de la organización
+	FindByOrganization(organizationId string) ([]*model.User, error)
+	// FindIdsByRoleId devuelve los usuarios que tienen el rol de forma permanente y los que lo tienen asignado temporalmente
+	FindIdsByRoleId(roleId string) (permanentIds []string, temporaryIds []string, err error)
+	// ReplaceRole quita el rol a los usuarios indicados, o lo reemplaza por replacementId si no está vacío,
+	// en una sola transacción. Devuelve cuántos usuarios se modificaron.
+	ReplaceRole(userIds []string, roleId, replacementId string) (int, error)
+	// BumpAuthzVersion aumenta la versión de autorización de los usuarios para que sus tokens actuales se rechacen.
+	// Los usuarios que no existen se ignoran.
+	BumpAuthzVersion(userIds []string) error
+	// FindPurgeable devuelve hasta limit usuarios eliminados cuyo PurgeAt ya pasó
+	FindPurgeable(now time.Time, limit int) ([]*model.User, error)
+	// FindExpiredSuspensions devuelve hasta limit usuarios suspendidos cuyo SuspendedUntil ya pasó
+	FindExpiredSuspensions(now time.Time, limit int) ([]*model.User, error)
+	// BackfillStatus marca como activos los usuarios sin estado, una sola vez: al terminar deja una marca
+	// y las llamadas siguientes no hacen nada. Devuelve cuántos usuarios actualizó.
+	BackfillStatus() (int, error)
+	// AddFavoriteNewsArticle añade la noticia a los favoritos con una unión atómica del array, sin duplicados.
+	// Devuelve ErrFavoriteLimitReached si el usuario ya tiene limit favoritos, y nil si el usuario no existe.
+	AddFavoriteNewsArticle(userId, articleId string, limit int) (*model.User, error)
+	// RemoveFavoriteNewsArticle quita la noticia de los favoritos con una eliminación atómica del array.
+	// Devuelve false si el usuario no existe.
+	RemoveFavoriteNewsArticle(userId, articleId string) (bool, error)
+	// CountByFavoriteNewsArticle cuenta los usuarios que tienen la noticia en favoritos con una consulta de agregación
+	CountByFavoriteNewsArticle(articleId string) (int64, error)
 }
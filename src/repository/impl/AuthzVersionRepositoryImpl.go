@@ -0,0 +1,72 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AuthzVersionRepositoryImpl struct {
+	usersCollection string
+	rolesCollection string
+}
+
+func NewAuthzVersionRepositoryImpl() *AuthzVersionRepositoryImpl {
+	return &AuthzVersionRepositoryImpl{
+		usersCollection: "users",
+		rolesCollection: "roles",
+	}
+}
+
+func (r *AuthzVersionRepositoryImpl) FindUserVersion(userId string) (int64, bool, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	doc, err := client.Collection(r.usersCollection).Doc(userId).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to get user authorization version: %v", err)
+	}
+	return authzVersionOf(doc), true, nil
+}
+
+func (r *AuthzVersionRepositoryImpl) FindRoleVersions(roleIds []string) (map[string]int64, error) {
+	versions := make(map[string]int64, len(roleIds))
+	if len(roleIds) == 0 {
+		return versions, nil
+	}
+
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	refs := make([]*firestore.DocumentRef, 0, len(roleIds))
+	for _, roleId := range roleIds {
+		refs = append(refs, client.Collection(r.rolesCollection).Doc(roleId))
+	}
+	docs, err := client.GetAll(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role authorization versions: %v", err)
+	}
+	for _, doc := range docs {
+		if doc.Exists() {
+			versions[doc.Ref.ID] = authzVersionOf(doc)
+		}
+	}
+	return versions, nil
+}
+
+// authzVersionOf lee la versión del documento; los documentos anteriores a las versiones tienen 0
+func authzVersionOf(doc *firestore.DocumentSnapshot) int64 {
+	value, err := doc.DataAt("authzVersion")
+	if err != nil {
+		return 0
+	}
+	version, _ := value.(int64)
+	return version
+}
@@ -0,0 +1,244 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type DataSubjectRequestRepositoryImpl struct {
+	collectionName   string
+	usersCollection  string
+	outboxCollection string
+}
+
+func NewDataSubjectRequestRepositoryImpl() *DataSubjectRequestRepositoryImpl {
+	return &DataSubjectRequestRepositoryImpl{
+		collectionName:   "data_subject_requests",
+		usersCollection:  "users",
+		outboxCollection: "outbox_events",
+	}
+}
+
+func (r *DataSubjectRequestRepositoryImpl) Create(request *model.DataSubjectRequest) (*model.DataSubjectRequest, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	request.Id = uuid.New().String()
+	_, err := client.Collection(r.collectionName).Doc(request.Id).Set(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data subject request: %v", err)
+	}
+
+	return request, nil
+}
+
+func (r *DataSubjectRequestRepositoryImpl) FindById(id string) (*model.DataSubjectRequest, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	docSnap, err := client.Collection(r.collectionName).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get data subject request: %v", err)
+	}
+
+	return r.toRequest(docSnap)
+}
+
+func (r *DataSubjectRequestRepositoryImpl) FindAll(status, userId string) ([]*model.DataSubjectRequest, error) {
+	query := database.GetFirestoreClient().Collection(r.collectionName).Query
+	if status != "" {
+		query = query.Where("status", "==", status)
+	}
+	if userId != "" {
+		query = query.Where("userId", "==", userId)
+	}
+
+	requests, err := r.collect(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ordenar en memoria evita requerir índices compuestos para cada combinación de filtros
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].RequestedAt.After(requests[j].RequestedAt)
+	})
+
+	return requests, nil
+}
+
+func (r *DataSubjectRequestRepositoryImpl) Update(request *model.DataSubjectRequest) (*model.DataSubjectRequest, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	_, err := client.Collection(r.collectionName).Doc(request.Id).Set(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update data subject request: %v", err)
+	}
+
+	return request, nil
+}
+
+func (r *DataSubjectRequestRepositoryImpl) FindClaimable(staleBefore time.Time, limit int) ([]*model.DataSubjectRequest, error) {
+	collection := database.GetFirestoreClient().Collection(r.collectionName)
+
+	pending, err := r.collect(collection.Where("status", "==", model.DataSubjectRequestPending).Limit(limit))
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) >= limit {
+		return pending, nil
+	}
+
+	stale, err := r.collect(collection.
+		Where("status", "==", model.DataSubjectRequestProcessing).
+		Where("startedAt", "<", staleBefore).
+		Limit(limit - len(pending)))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(pending, stale...), nil
+}
+
+func (r *DataSubjectRequestRepositoryImpl) Claim(id string, staleBefore, now time.Time) (*model.DataSubjectRequest, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+	requestRef := client.Collection(r.collectionName).Doc(id)
+
+	var claimed *model.DataSubjectRequest
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+
+		doc, err := tx.Get(requestRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return fmt.Errorf("failed to get data subject request: %v", err)
+		}
+		request, err := r.toRequest(doc)
+		if err != nil {
+			return err
+		}
+
+		stale := request.Status == model.DataSubjectRequestProcessing && request.StartedAt != nil && request.StartedAt.Before(staleBefore)
+		if request.Status != model.DataSubjectRequestPending && !stale {
+			return nil
+		}
+
+		request.Status = model.DataSubjectRequestProcessing
+		request.StartedAt = &now
+		request.Attempts++
+		if err := tx.Set(requestRef, request); err != nil {
+			return fmt.Errorf("failed to claim data subject request: %v", err)
+		}
+		claimed = request
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (r *DataSubjectRequestRepositoryImpl) FindExpiredArchives(now time.Time, limit int) ([]*model.DataSubjectRequest, error) {
+	query := database.GetFirestoreClient().Collection(r.collectionName).
+		Where("status", "==", model.DataSubjectRequestCompleted).
+		Where("archiveExpiresAt", "<=", now).
+		Limit(limit)
+	return r.collect(query)
+}
+
+func (r *DataSubjectRequestRepositoryImpl) CompleteErasure(request *model.DataSubjectRequest, erasure repository.UserErasure) error {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+	requestRef := client.Collection(r.collectionName).Doc(request.Id)
+	userRef := client.Collection(r.usersCollection).Doc(request.UserId)
+
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var user *model.User
+		userDoc, err := tx.Get(userRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return fmt.Errorf("failed to get user: %v", err)
+		}
+		if err == nil {
+			user = &model.User{}
+			if err := userDoc.DataTo(user); err != nil {
+				return fmt.Errorf("failed to convert document to user: %v", err)
+			}
+			user.Id = userDoc.Ref.ID
+		}
+
+		event := erasure(user)
+
+		if user != nil {
+			if err := tx.Set(userRef, user); err != nil {
+				return fmt.Errorf("failed to erase user: %v", err)
+			}
+		}
+		if event != nil {
+			event.Id = uuid.New().String()
+			if err := tx.Set(client.Collection(r.outboxCollection).Doc(event.Id), event); err != nil {
+				return fmt.Errorf("failed to create outbox event: %v", err)
+			}
+		}
+
+		completed := *request
+		now := time.Now()
+		completed.Status = model.DataSubjectRequestCompleted
+		completed.CompletedAt = &now
+		completed.Error = ""
+		if err := tx.Set(requestRef, &completed); err != nil {
+			return fmt.Errorf("failed to complete data subject request: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *DataSubjectRequestRepositoryImpl) collect(query firestore.Query) ([]*model.DataSubjectRequest, error) {
+	iter := query.Documents(context.Background())
+	defer iter.Stop()
+
+	var requests []*model.DataSubjectRequest
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate data subject requests: %v", err)
+		}
+
+		request, err := r.toRequest(doc)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+func (r *DataSubjectRequestRepositoryImpl) toRequest(doc *firestore.DocumentSnapshot) (*model.DataSubjectRequest, error) {
+	var request model.DataSubjectRequest
+	if err := doc.DataTo(&request); err != nil {
+		return nil, fmt.Errorf("failed to convert document to data subject request: %v", err)
+	}
+	request.Id = doc.Ref.ID
+	return &request, nil
+}
@@ -0,0 +1,182 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type GroupRepositoryImpl struct {
+	collectionName string
+}
+
+func NewGroupRepositoryImpl() *GroupRepositoryImpl {
+	return &GroupRepositoryImpl{
+		collectionName: "groups",
+	}
+}
+
+func (r *GroupRepositoryImpl) Create(group *model.Group) (*model.Group, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	group.Id = uuid.New().String()
+	group.CreatedAt = time.Now()
+	group.UpdatedAt = group.CreatedAt
+	if group.MemberIds == nil {
+		group.MemberIds = []string{}
+	}
+	if group.RoleIds == nil {
+		group.RoleIds = []string{}
+	}
+
+	_, err := client.Collection(r.collectionName).Doc(group.Id).Set(ctx, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group: %v", err)
+	}
+
+	return group, nil
+}
+
+func (r *GroupRepositoryImpl) FindById(id string) (*model.Group, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	docSnap, err := client.Collection(r.collectionName).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get group: %v", err)
+	}
+
+	var group model.Group
+	if err := docSnap.DataTo(&group); err != nil {
+		return nil, fmt.Errorf("failed to convert document to group: %v", err)
+	}
+	group.Id = docSnap.Ref.ID
+
+	return &group, nil
+}
+
+func (r *GroupRepositoryImpl) FindAll() ([]*model.Group, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Documents(ctx)
+	defer iter.Stop()
+
+	return r.collect(iter)
+}
+
+func (r *GroupRepositoryImpl) FindByMemberId(userId string) ([]*model.Group, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Where("memberIds", "array-contains", userId).Documents(ctx)
+	defer iter.Stop()
+
+	return r.collect(iter)
+}
+
+func (r *GroupRepositoryImpl) FindByRoleId(roleId string) ([]*model.Group, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Where("roleIds", "array-contains", roleId).Documents(ctx)
+	defer iter.Stop()
+
+	return r.collect(iter)
+}
+
+func (r *GroupRepositoryImpl) Update(group *model.Group) (*model.Group, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	group.UpdatedAt = time.Now()
+
+	// Los miembros se modifican con AddMember/RemoveMember para no pisar cambios concurrentes
+	_, err := client.Collection(r.collectionName).Doc(group.Id).Update(ctx, []firestore.Update{
+		{Path: "name", Value: group.Name},
+		{Path: "description", Value: group.Description},
+		{Path: "roleIds", Value: group.RoleIds},
+		{Path: "updatedAt", Value: group.UpdatedAt},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update group: %v", err)
+	}
+
+	return group, nil
+}
+
+func (r *GroupRepositoryImpl) Delete(id string) error {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	_, err := client.Collection(r.collectionName).Doc(id).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %v", err)
+	}
+
+	return nil
+}
+
+func (r *GroupRepositoryImpl) AddMember(groupId, userId string) error {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	_, err := client.Collection(r.collectionName).Doc(groupId).Update(ctx, []firestore.Update{
+		{Path: "memberIds", Value: firestore.ArrayUnion(userId)},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add group member: %v", err)
+	}
+
+	return nil
+}
+
+func (r *GroupRepositoryImpl) RemoveMember(groupId, userId string) error {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	_, err := client.Collection(r.collectionName).Doc(groupId).Update(ctx, []firestore.Update{
+		{Path: "memberIds", Value: firestore.ArrayRemove(userId)},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove group member: %v", err)
+	}
+
+	return nil
+}
+
+func (r *GroupRepositoryImpl) collect(iter *firestore.DocumentIterator) ([]*model.Group, error) {
+	var groups []*model.Group
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate groups: %v", err)
+		}
+
+		var group model.Group
+		if err := doc.DataTo(&group); err != nil {
+			return nil, fmt.Errorf("failed to convert document to group: %v", err)
+		}
+		group.Id = doc.Ref.ID
+		groups = append(groups, &group)
+	}
+
+	return groups, nil
+}
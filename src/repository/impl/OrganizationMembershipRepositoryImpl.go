@@ -0,0 +1,117 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type OrganizationMembershipRepositoryImpl struct {
+	collectionName string
+}
+
+func NewOrganizationMembershipRepositoryImpl() *OrganizationMembershipRepositoryImpl {
+	return &OrganizationMembershipRepositoryImpl{
+		collectionName: "organization_memberships",
+	}
+}
+
+func (r *OrganizationMembershipRepositoryImpl) Save(membership *model.OrganizationMembership) (*model.OrganizationMembership, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	membership.Id = model.MembershipId(membership.OrganizationId, membership.UserId)
+	membership.UpdatedAt = time.Now()
+	if membership.CreatedAt.IsZero() {
+		membership.CreatedAt = membership.UpdatedAt
+	}
+
+	_, err := client.Collection(r.collectionName).Doc(membership.Id).Set(ctx, membership)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save organization membership: %v", err)
+	}
+
+	return membership, nil
+}
+
+func (r *OrganizationMembershipRepositoryImpl) FindByOrganizationAndUser(organizationId, userId string) (*model.OrganizationMembership, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	docSnap, err := client.Collection(r.collectionName).Doc(model.MembershipId(organizationId, userId)).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get organization membership: %v", err)
+	}
+
+	var membership model.OrganizationMembership
+	if err := docSnap.DataTo(&membership); err != nil {
+		return nil, fmt.Errorf("failed to convert document to organization membership: %v", err)
+	}
+	membership.Id = docSnap.Ref.ID
+
+	return &membership, nil
+}
+
+func (r *OrganizationMembershipRepositoryImpl) FindByOrganization(organizationId string) ([]*model.OrganizationMembership, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Where("organizationId", "==", organizationId).Documents(ctx)
+	defer iter.Stop()
+
+	return r.collect(iter)
+}
+
+func (r *OrganizationMembershipRepositoryImpl) FindByUserId(userId string) ([]*model.OrganizationMembership, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Where("userId", "==", userId).Documents(ctx)
+	defer iter.Stop()
+
+	return r.collect(iter)
+}
+
+func (r *OrganizationMembershipRepositoryImpl) Delete(organizationId, userId string) error {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	_, err := client.Collection(r.collectionName).Doc(model.MembershipId(organizationId, userId)).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete organization membership: %v", err)
+	}
+
+	return nil
+}
+
+func (r *OrganizationMembershipRepositoryImpl) collect(iter *firestore.DocumentIterator) ([]*model.OrganizationMembership, error) {
+	var memberships []*model.OrganizationMembership
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate organization memberships: %v", err)
+		}
+
+		var membership model.OrganizationMembership
+		if err := doc.DataTo(&membership); err != nil {
+			return nil, fmt.Errorf("failed to convert document to organization membership: %v", err)
+		}
+		membership.Id = doc.Ref.ID
+		memberships = append(memberships, &membership)
+	}
+
+	return memberships, nil
+}
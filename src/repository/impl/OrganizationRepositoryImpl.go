@@ -0,0 +1,118 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type OrganizationRepositoryImpl struct {
+	collectionName string
+}
+
+func NewOrganizationRepositoryImpl() *OrganizationRepositoryImpl {
+	return &OrganizationRepositoryImpl{
+		collectionName: "organizations",
+	}
+}
+
+func (r *OrganizationRepositoryImpl) Create(organization *model.Organization) (*model.Organization, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	organization.Id = uuid.New().String()
+	organization.CreatedAt = time.Now()
+	organization.UpdatedAt = organization.CreatedAt
+
+	_, err := client.Collection(r.collectionName).Doc(organization.Id).Set(ctx, organization)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization: %v", err)
+	}
+
+	return organization, nil
+}
+
+func (r *OrganizationRepositoryImpl) FindById(id string) (*model.Organization, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	docSnap, err := client.Collection(r.collectionName).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get organization: %v", err)
+	}
+
+	var organization model.Organization
+	if err := docSnap.DataTo(&organization); err != nil {
+		return nil, fmt.Errorf("failed to convert document to organization: %v", err)
+	}
+	organization.Id = docSnap.Ref.ID
+
+	return &organization, nil
+}
+
+func (r *OrganizationRepositoryImpl) FindAll() ([]*model.Organization, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Documents(ctx)
+	defer iter.Stop()
+
+	return r.collect(iter)
+}
+
+func (r *OrganizationRepositoryImpl) FindByOwnerId(ownerId string) ([]*model.Organization, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Where("ownerId", "==", ownerId).Documents(ctx)
+	defer iter.Stop()
+
+	return r.collect(iter)
+}
+
+func (r *OrganizationRepositoryImpl) collect(iter *firestore.DocumentIterator) ([]*model.Organization, error) {
+	var organizations []*model.Organization
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate organizations: %v", err)
+		}
+
+		var organization model.Organization
+		if err := doc.DataTo(&organization); err != nil {
+			return nil, fmt.Errorf("failed to convert document to organization: %v", err)
+		}
+		organization.Id = doc.Ref.ID
+		organizations = append(organizations, &organization)
+	}
+
+	return organizations, nil
+}
+
+func (r *OrganizationRepositoryImpl) Update(organization *model.Organization) (*model.Organization, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	organization.UpdatedAt = time.Now()
+
+	_, err := client.Collection(r.collectionName).Doc(organization.Id).Set(ctx, organization)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update organization: %v", err)
+	}
+
+	return organization, nil
+}
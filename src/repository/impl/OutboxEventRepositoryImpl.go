@@ -0,0 +1,58 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"google.golang.org/api/iterator"
+)
+
+type OutboxEventRepositoryImpl struct {
+	collectionName string
+}
+
+func NewOutboxEventRepositoryImpl() *OutboxEventRepositoryImpl {
+	return &OutboxEventRepositoryImpl{
+		collectionName: "outbox_events",
+	}
+}
+
+func (r *OutboxEventRepositoryImpl) FindAfter(after *repository.PageCursor, before time.Time, size int) ([]*model.OutboxEvent, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	query := client.Collection(r.collectionName).
+		Where("createdAt", "<", before).
+		OrderBy("createdAt", firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc)
+	if after != nil {
+		query = query.StartAfter(after.SortValue, after.Id)
+	}
+	iter := query.Limit(size).Documents(ctx)
+	defer iter.Stop()
+
+	var events []*model.OutboxEvent
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate outbox events: %v", err)
+		}
+
+		var event model.OutboxEvent
+		if err := doc.DataTo(&event); err != nil {
+			return nil, fmt.Errorf("failed to convert document to outbox event: %v", err)
+		}
+		event.Id = doc.Ref.ID
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
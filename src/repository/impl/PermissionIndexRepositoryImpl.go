@@ -0,0 +1,90 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// permissionIndexDocument guarda los IDs de los permisos en el orden de sus posiciones
+type permissionIndexDocument struct {
+	PermissionIds []int `firestore:"permissionIds"`
+}
+
+type PermissionIndexRepositoryImpl struct {
+	collectionName string
+	documentId     string
+}
+
+func NewPermissionIndexRepositoryImpl() *PermissionIndexRepositoryImpl {
+	return &PermissionIndexRepositoryImpl{
+		collectionName: "permission_index",
+		documentId:     "positions",
+	}
+}
+
+func (r *PermissionIndexRepositoryImpl) Load() ([]int, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	doc, err := client.Collection(r.collectionName).Doc(r.documentId).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get permission index: %v", err)
+	}
+	var index permissionIndexDocument
+	if err := doc.DataTo(&index); err != nil {
+		return nil, fmt.Errorf("failed to convert document to permission index: %v", err)
+	}
+	return index.PermissionIds, nil
+}
+
+func (r *PermissionIndexRepositoryImpl) Append(permissionIds []int) ([]int, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+	indexRef := client.Collection(r.collectionName).Doc(r.documentId)
+
+	var stored []int
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var index permissionIndexDocument
+		doc, err := tx.Get(indexRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return fmt.Errorf("failed to get permission index: %v", err)
+		}
+		if err == nil {
+			if err := doc.DataTo(&index); err != nil {
+				return fmt.Errorf("failed to convert document to permission index: %v", err)
+			}
+		}
+
+		// Otra réplica pudo agregar algunos de los IDs; las posiciones existentes no se tocan
+		appended := false
+		for _, permissionId := range permissionIds {
+			if !slices.Contains(index.PermissionIds, permissionId) {
+				index.PermissionIds = append(index.PermissionIds, permissionId)
+				appended = true
+			}
+		}
+		stored = index.PermissionIds
+		if !appended {
+			return nil
+		}
+		if err := tx.Set(indexRef, index); err != nil {
+			return fmt.Errorf("failed to save permission index: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
@@ -0,0 +1,167 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PermissionRepositoryImpl struct {
+	collectionName string
+}
+
+func NewPermissionRepositoryImpl() *PermissionRepositoryImpl {
+	return &PermissionRepositoryImpl{
+		collectionName: "permissions",
+	}
+}
+
+func (r *PermissionRepositoryImpl) FindAll() ([]*model.Permission, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Documents(ctx)
+	defer iter.Stop()
+
+	return r.collect(iter)
+}
+
+func (r *PermissionRepositoryImpl) FindByService(service string) ([]*model.Permission, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Where("service", "==", service).Documents(ctx)
+	defer iter.Stop()
+
+	return r.collect(iter)
+}
+
+func (r *PermissionRepositoryImpl) RegisterServiceCatalog(service string, permissions []*model.Permission) ([]*model.Permission, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+	collection := client.Collection(r.collectionName)
+
+	var deprecated []*model.Permission
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		deprecated = nil
+
+		// Verificar que ningún ID pertenezca a otro servicio
+		requested := make(map[int]bool)
+		for _, permission := range permissions {
+			requested[permission.Id] = true
+
+			doc, err := tx.Get(collection.Doc(strconv.Itoa(permission.Id)))
+			if err != nil {
+				if status.Code(err) == codes.NotFound {
+					continue
+				}
+				return fmt.Errorf("failed to get permission %d: %v", permission.Id, err)
+			}
+
+			var existing model.Permission
+			if err := doc.DataTo(&existing); err != nil {
+				return fmt.Errorf("failed to convert document to permission: %v", err)
+			}
+			if existing.Service != service {
+				return &repository.PermissionCollisionError{Id: permission.Id, OwnerService: existing.Service}
+			}
+		}
+
+		// Permisos del servicio que ya no fueron registrados
+		iter := tx.Documents(collection.Where("service", "==", service))
+		current, err := r.collect(iter)
+		if err != nil {
+			return err
+		}
+		for _, permission := range current {
+			if !requested[permission.Id] && !permission.Deprecated {
+				permission.Deprecated = true
+				deprecated = append(deprecated, permission)
+			}
+		}
+
+		for _, permission := range permissions {
+			permission.Service = service
+			if err := tx.Set(collection.Doc(strconv.Itoa(permission.Id)), permission); err != nil {
+				return fmt.Errorf("failed to save permission %d: %v", permission.Id, err)
+			}
+		}
+		for _, permission := range deprecated {
+			if err := tx.Set(collection.Doc(strconv.Itoa(permission.Id)), permission); err != nil {
+				return fmt.Errorf("failed to deprecate permission %d: %v", permission.Id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deprecated, nil
+}
+
+func (r *PermissionRepositoryImpl) SeedServiceCatalog(service string, permissions []*model.Permission) ([]*model.Permission, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+	collection := client.Collection(r.collectionName)
+
+	var created []*model.Permission
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		created = nil
+
+		for _, permission := range permissions {
+			_, err := tx.Get(collection.Doc(strconv.Itoa(permission.Id)))
+			if err == nil {
+				continue
+			}
+			if status.Code(err) != codes.NotFound {
+				return fmt.Errorf("failed to get permission %d: %v", permission.Id, err)
+			}
+			created = append(created, permission)
+		}
+
+		for _, permission := range created {
+			permission.Service = service
+			if err := tx.Create(collection.Doc(strconv.Itoa(permission.Id)), permission); err != nil {
+				return fmt.Errorf("failed to seed permission %d: %v", permission.Id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *PermissionRepositoryImpl) collect(iter *firestore.DocumentIterator) ([]*model.Permission, error) {
+	var permissions []*model.Permission
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate permissions: %v", err)
+		}
+
+		var permission model.Permission
+		if err := doc.DataTo(&permission); err != nil {
+			return nil, fmt.Errorf("failed to convert document to permission: %v", err)
+		}
+		permissions = append(permissions, &permission)
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,154 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RoleAssignmentRepositoryImpl struct {
+	usersCollection   string
+	historyCollection string
+}
+
+func NewRoleAssignmentRepositoryImpl() *RoleAssignmentRepositoryImpl {
+	return &RoleAssignmentRepositoryImpl{
+		usersCollection:   "users",
+		historyCollection: "role_assignment_history",
+	}
+}
+
+func (r *RoleAssignmentRepositoryImpl) UpdateAssignments(userId string, change repository.RoleAssignmentChange) (*model.User, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+	userRef := client.Collection(r.usersCollection).Doc(userId)
+
+	var updated *model.User
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = nil
+
+		doc, err := tx.Get(userRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return fmt.Errorf("failed to get user: %v", err)
+		}
+
+		var user model.User
+		if err := doc.DataTo(&user); err != nil {
+			return fmt.Errorf("failed to convert document to user: %v", err)
+		}
+		user.Id = doc.Ref.ID
+
+		history, err := change(&user)
+		if err != nil {
+			return err
+		}
+		user.RefreshRoleAssignmentsExpireAt()
+		user.UpdatedAt = time.Now()
+
+		var expireAt any = firestore.Delete
+		if user.RoleAssignmentsExpireAt != nil {
+			expireAt = *user.RoleAssignmentsExpireAt
+		}
+		assignments := user.RoleAssignments
+		if assignments == nil {
+			assignments = []model.RoleAssignment{}
+		}
+		err = tx.Update(userRef, []firestore.Update{
+			{Path: "roleAssignments", Value: assignments},
+			{Path: "roleAssignmentsExpireAt", Value: expireAt},
+			{Path: "updatedAt", Value: user.UpdatedAt},
+			{Path: "authzVersion", Value: firestore.Increment(1)},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update role assignments: %v", err)
+		}
+
+		for _, entry := range history {
+			entry.Id = uuid.New().String()
+			entry.UserId = user.Id
+			if err := tx.Set(client.Collection(r.historyCollection).Doc(entry.Id), entry); err != nil {
+				return fmt.Errorf("failed to record role assignment history: %v", err)
+			}
+		}
+
+		updated = &user
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (r *RoleAssignmentRepositoryImpl) FindUserIdsWithExpiredAssignments(now time.Time, limit int) ([]string, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	query := client.Collection(r.usersCollection).
+		Where("roleAssignmentsExpireAt", "<=", now).
+		Limit(limit)
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	var userIds []string
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to query expired role assignments: %v", err)
+		}
+		userIds = append(userIds, doc.Ref.ID)
+	}
+
+	return userIds, nil
+}
+
+func (r *RoleAssignmentRepositoryImpl) FindHistoryByUserId(userId string) ([]*model.RoleAssignmentHistory, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.historyCollection).Where("userId", "==", userId).Documents(ctx)
+	defer iter.Stop()
+
+	var history []*model.RoleAssignmentHistory
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate role assignment history: %v", err)
+		}
+
+		var entry model.RoleAssignmentHistory
+		if err := doc.DataTo(&entry); err != nil {
+			return nil, fmt.Errorf("failed to convert document to role assignment history: %v", err)
+		}
+		entry.Id = doc.Ref.ID
+		history = append(history, &entry)
+	}
+
+	// Ordenar en memoria evita requerir un índice compuesto
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].RecordedAt.After(history[j].RecordedAt)
+	})
+
+	return history, nil
+}
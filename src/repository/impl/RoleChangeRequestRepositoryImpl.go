@@ -0,0 +1,172 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RoleChangeRequestRepositoryImpl struct {
+	collectionName    string
+	usersCollection   string
+	historyCollection string
+}
+
+func NewRoleChangeRequestRepositoryImpl() *RoleChangeRequestRepositoryImpl {
+	return &RoleChangeRequestRepositoryImpl{
+		collectionName:    "role_change_requests",
+		usersCollection:   "users",
+		historyCollection: "role_assignment_history",
+	}
+}
+
+func (r *RoleChangeRequestRepositoryImpl) Create(request *model.RoleChangeRequest) (*model.RoleChangeRequest, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	request.Id = uuid.New().String()
+	_, err := client.Collection(r.collectionName).Doc(request.Id).Set(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role change request: %v", err)
+	}
+
+	return request, nil
+}
+
+func (r *RoleChangeRequestRepositoryImpl) FindById(id string) (*model.RoleChangeRequest, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	docSnap, err := client.Collection(r.collectionName).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get role change request: %v", err)
+	}
+
+	var request model.RoleChangeRequest
+	if err := docSnap.DataTo(&request); err != nil {
+		return nil, fmt.Errorf("failed to convert document to role change request: %v", err)
+	}
+	request.Id = docSnap.Ref.ID
+
+	return &request, nil
+}
+
+func (r *RoleChangeRequestRepositoryImpl) FindAll(status, userId string) ([]*model.RoleChangeRequest, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	query := client.Collection(r.collectionName).Query
+	if status != "" {
+		query = query.Where("status", "==", status)
+	}
+	if userId != "" {
+		query = query.Where("userId", "==", userId)
+	}
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	var requests []*model.RoleChangeRequest
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate role change requests: %v", err)
+		}
+
+		var request model.RoleChangeRequest
+		if err := doc.DataTo(&request); err != nil {
+			return nil, fmt.Errorf("failed to convert document to role change request: %v", err)
+		}
+		request.Id = doc.Ref.ID
+		requests = append(requests, &request)
+	}
+
+	// Ordenar en memoria evita requerir índices compuestos para cada combinación de filtros
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].RequestedAt.After(requests[j].RequestedAt)
+	})
+
+	return requests, nil
+}
+
+func (r *RoleChangeRequestRepositoryImpl) Decide(id string, decision repository.RoleChangeDecision) (*model.RoleChangeRequest, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+	requestRef := client.Collection(r.collectionName).Doc(id)
+
+	var decided *model.RoleChangeRequest
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		decided = nil
+
+		requestDoc, err := tx.Get(requestRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return fmt.Errorf("failed to get role change request: %v", err)
+		}
+		var request model.RoleChangeRequest
+		if err := requestDoc.DataTo(&request); err != nil {
+			return fmt.Errorf("failed to convert document to role change request: %v", err)
+		}
+		request.Id = requestDoc.Ref.ID
+
+		userRef := client.Collection(r.usersCollection).Doc(request.UserId)
+		var user *model.User
+		userDoc, err := tx.Get(userRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return fmt.Errorf("failed to get user: %v", err)
+		}
+		if err == nil {
+			user = &model.User{}
+			if err := userDoc.DataTo(user); err != nil {
+				return fmt.Errorf("failed to convert document to user: %v", err)
+			}
+			user.Id = userDoc.Ref.ID
+		}
+
+		history, err := decision(&request, user)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Set(requestRef, &request); err != nil {
+			return fmt.Errorf("failed to save role change request: %v", err)
+		}
+		if user != nil {
+			if err := tx.Set(userRef, user); err != nil {
+				return fmt.Errorf("failed to update user: %v", err)
+			}
+		}
+		for _, entry := range history {
+			entry.Id = uuid.New().String()
+			entry.UserId = request.UserId
+			if err := tx.Set(client.Collection(r.historyCollection).Doc(entry.Id), entry); err != nil {
+				return fmt.Errorf("failed to record role assignment history: %v", err)
+			}
+		}
+
+		decided = &request
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return decided, nil
+}
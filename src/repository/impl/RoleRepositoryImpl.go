@@ -3,11 +3,13 @@ package impl
 
 import (
 	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	"context"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/ruiborda/ecommerce-user-service/src/database"
 	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
 	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -15,14 +17,22 @@ import (
 
 type RoleRepositoryImpl struct {
 	collectionName string
+	// Un documento por código de rol global, con el ID del rol que lo usa
+	codesCollectionName string
 }
 
 func NewRoleRepositoryImpl() *RoleRepositoryImpl {
 	return &RoleRepositoryImpl{
-		collectionName: "roles",
+		collectionName:      "roles",
+		codesCollectionName: "role_codes",
 	}
 }
 
+// roleCodeReservation es el documento que reserva un código para un rol global
+type roleCodeReservation struct {
+	RoleId string `firestore:"roleId"`
+}
+
 func (r *RoleRepositoryImpl) Create(role *model.Role) (*model.Role, error) {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
@@ -30,25 +40,73 @@ func (r *RoleRepositoryImpl) Create(role *model.Role) (*model.Role, error) {
 	if role.Id == "" {
 		// Generar UUID para nuevos roles
 		role.Id = uuid.New().String()
+	} else if _, err := uuid.Parse(role.Id); err != nil {
+		// Verificar que el ID sea un UUID válido
+		return nil, fmt.Errorf("invalid UUID format for role ID: %v", err)
+	}
 
-		// Guardar el documento con el ID generado
-		_, err := client.Collection(r.collectionName).Doc(role.Id).Set(ctx, role)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create role with generated UUID: %v", err)
+	roleRef := client.Collection(r.collectionName).Doc(role.Id)
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// Los roles de organizaciones no reservan código; su unicidad es por organización
+		if role.OrganizationId == "" {
+			if _, err := r.checkCodeAvailable(tx, role.Code, role.Id); err != nil {
+				return err
+			}
+			if err := tx.Set(r.codeRef(role.Code), roleCodeReservation{RoleId: role.Id}); err != nil {
+				return fmt.Errorf("failed to reserve role code: %v", err)
+			}
 		}
-	} else {
-		// Verificar que el ID sea un UUID válido
-		if _, err := uuid.Parse(role.Id); err != nil {
-			return nil, fmt.Errorf("invalid UUID format for role ID: %v", err)
+		if err := tx.Set(roleRef, role); err != nil {
+			return fmt.Errorf("failed to create role: %v", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		_, err := client.Collection(r.collectionName).Doc(role.Id).Set(ctx, role)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create role with provided ID: %v", err)
+	return role, nil
+}
+
+func (r *RoleRepositoryImpl) codeRef(code string) *firestore.DocumentRef {
+	return database.GetFirestoreClient().Collection(r.codesCollectionName).Doc(code)
+}
+
+// checkCodeAvailable verifica dentro de la transacción que ningún otro rol global use el código.
+// Devuelve el ID del rol que tenía reservado el código, o vacío si no había reserva.
+// También considera los roles creados antes de existir las reservas.
+func (r *RoleRepositoryImpl) checkCodeAvailable(tx *firestore.Transaction, code, roleId string) (string, error) {
+	doc, err := tx.Get(r.codeRef(code))
+	if err == nil {
+		var reservation roleCodeReservation
+		if err := doc.DataTo(&reservation); err != nil {
+			return "", fmt.Errorf("failed to convert document to role code reservation: %v", err)
 		}
+		if reservation.RoleId != roleId {
+			return "", repository.ErrRoleCodeTaken
+		}
+		return reservation.RoleId, nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return "", fmt.Errorf("failed to get role code reservation: %v", err)
 	}
 
-	return role, nil
+	query := database.GetFirestoreClient().Collection(r.collectionName).Where("code", "==", code)
+	iter := tx.Documents(query)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to query role by code: %v", err)
+		}
+		organizationId, _ := doc.DataAt("organizationId")
+		if doc.Ref.ID != roleId && (organizationId == nil || organizationId == "") {
+			return "", repository.ErrRoleCodeTaken
+		}
+	}
 }
 
 func (r *RoleRepositoryImpl) FindById(id string) (*model.Role, error) {
@@ -78,27 +136,63 @@ func (r *RoleRepositoryImpl) FindByCode(code string) (*model.Role, error) {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
 
-	query := client.Collection(r.collectionName).Where("code", "==", code).Limit(1)
+	// Las organizaciones pueden repetir códigos; solo se busca entre los roles globales
+	query := client.Collection(r.collectionName).Where("code", "==", code)
 	iter := query.Documents(ctx)
 	defer iter.Stop()
 
-	doc, err := iter.Next()
-	if err == iterator.Done {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to query role by code: %v", err)
-	}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to query role by code: %v", err)
+		}
 
-	var role model.Role
-	if err := doc.DataTo(&role); err != nil {
-		return nil, fmt.Errorf("failed to convert document to role: %v", err)
+		var role model.Role
+		if err := doc.DataTo(&role); err != nil {
+			return nil, fmt.Errorf("failed to convert document to role: %v", err)
+		}
+		if role.OrganizationId != "" {
+			continue
+		}
+
+		// Ensure the ID is set
+		role.Id = doc.Ref.ID
+
+		return &role, nil
 	}
+}
 
-	// Ensure the ID is set
-	role.Id = doc.Ref.ID
+func (r *RoleRepositoryImpl) FindByOrganization(organizationId string) ([]*model.Role, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
 
-	return &role, nil
+	iter := client.Collection(r.collectionName).Where("organizationId", "==", organizationId).Documents(ctx)
+	defer iter.Stop()
+
+	var roles []*model.Role
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate organization roles: %v", err)
+		}
+
+		var role model.Role
+		if err := doc.DataTo(&role); err != nil {
+			return nil, fmt.Errorf("failed to convert document to role: %v", err)
+		}
+
+		// Ensure the ID is set
+		role.Id = doc.Ref.ID
+		roles = append(roles, &role)
+	}
+
+	return roles, nil
 }
 
 func (r *RoleRepositoryImpl) FindAll() ([]*model.Role, error) {
@@ -123,6 +217,11 @@ func (r *RoleRepositoryImpl) FindAll() ([]*model.Role, error) {
 			return nil, fmt.Errorf("failed to convert document to role: %v", err)
 		}
 
+		// Los roles de organizaciones solo se consultan con FindByOrganization
+		if role.OrganizationId != "" {
+			continue
+		}
+
 		// Ensure the ID is set
 		role.Id = doc.Ref.ID
 		roles = append(roles, &role)
@@ -134,10 +233,52 @@ func (r *RoleRepositoryImpl) FindAll() ([]*model.Role, error) {
 func (r *RoleRepositoryImpl) Update(role *model.Role) (*model.Role, error) {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
+	roleRef := client.Collection(r.collectionName).Doc(role.Id)
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		stored, err := r.storedRole(tx, roleRef)
+		if err != nil {
+			return err
+		}
+		// Cualquier cambio invalida los tokens emitidos con la versión anterior
+		role.AuthzVersion = 1
+		if stored != nil {
+			role.AuthzVersion = stored.AuthzVersion + 1
+		}
+
+		if role.OrganizationId == "" {
+			var previousCode string
+			if stored != nil {
+				previousCode = stored.Code
+			}
+			reservedBy, err := r.checkCodeAvailable(tx, role.Code, role.Id)
+			if err != nil {
+				return err
+			}
+			previousReservedBy, err := r.reservationOwner(tx, previousCode)
+			if err != nil {
+				return err
+			}
 
-	_, err := client.Collection(r.collectionName).Doc(role.Id).Set(ctx, role)
+			if reservedBy == "" {
+				if err := tx.Set(r.codeRef(role.Code), roleCodeReservation{RoleId: role.Id}); err != nil {
+					return fmt.Errorf("failed to reserve role code: %v", err)
+				}
+			}
+			// Al renombrar se libera el código anterior
+			if previousCode != "" && previousCode != role.Code && previousReservedBy == role.Id {
+				if err := tx.Delete(r.codeRef(previousCode)); err != nil {
+					return fmt.Errorf("failed to release role code: %v", err)
+				}
+			}
+		}
+		if err := tx.Set(roleRef, role); err != nil {
+			return fmt.Errorf("failed to update role: %v", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update role: %v", err)
+		return nil, err
 	}
 
 	return role, nil
@@ -146,13 +287,68 @@ func (r *RoleRepositoryImpl) Update(role *model.Role) (*model.Role, error) {
 func (r *RoleRepositoryImpl) Delete(id string) error {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
+	roleRef := client.Collection(r.collectionName).Doc(id)
+
+	return client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		stored, err := r.storedRole(tx, roleRef)
+		if err != nil {
+			return err
+		}
+		var code string
+		if stored != nil {
+			code = stored.Code
+		}
+		reservedBy, err := r.reservationOwner(tx, code)
+		if err != nil {
+			return err
+		}
+
+		if reservedBy == id {
+			if err := tx.Delete(r.codeRef(code)); err != nil {
+				return fmt.Errorf("failed to release role code: %v", err)
+			}
+		}
+		if err := tx.Delete(roleRef); err != nil {
+			return fmt.Errorf("failed to delete role: %v", err)
+		}
+		return nil
+	})
+}
 
-	_, err := client.Collection(r.collectionName).Doc(id).Delete(ctx)
+// storedRole lee el rol guardado dentro de la transacción; nil si el rol no existe
+func (r *RoleRepositoryImpl) storedRole(tx *firestore.Transaction, roleRef *firestore.DocumentRef) (*model.Role, error) {
+	doc, err := tx.Get(roleRef)
 	if err != nil {
-		return fmt.Errorf("failed to delete role: %v", err)
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get role: %v", err)
 	}
+	var role model.Role
+	if err := doc.DataTo(&role); err != nil {
+		return nil, fmt.Errorf("failed to convert document to role: %v", err)
+	}
+	role.Id = doc.Ref.ID
+	return &role, nil
+}
 
-	return nil
+// reservationOwner devuelve el ID del rol que tiene reservado el código, o vacío si no hay reserva
+func (r *RoleRepositoryImpl) reservationOwner(tx *firestore.Transaction, code string) (string, error) {
+	if code == "" {
+		return "", nil
+	}
+	doc, err := tx.Get(r.codeRef(code))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get role code reservation: %v", err)
+	}
+	var reservation roleCodeReservation
+	if err := doc.DataTo(&reservation); err != nil {
+		return "", fmt.Errorf("failed to convert document to role code reservation: %v", err)
+	}
+	return reservation.RoleId, nil
 }
 
 func (r *RoleRepositoryImpl) FindAllByPageAndSize(page, size int) ([]*model.Role, error) {
@@ -178,6 +374,16 @@ func (r *RoleRepositoryImpl) FindAllByPageAndSize(page, size int) ([]*model.Role
 			return nil, fmt.Errorf("failed to iterate roles: %v", err)
 		}
 
+		var role model.Role
+		if err := doc.DataTo(&role); err != nil {
+			return nil, fmt.Errorf("failed to convert document to role: %v", err)
+		}
+
+		// Los roles de organizaciones no forman parte del listado global
+		if role.OrganizationId != "" {
+			continue
+		}
+
 		// Skip documents before offset
 		if index < offset {
 			index++
@@ -189,11 +395,6 @@ func (r *RoleRepositoryImpl) FindAllByPageAndSize(page, size int) ([]*model.Role
 			break
 		}
 
-		var role model.Role
-		if err := doc.DataTo(&role); err != nil {
-			return nil, fmt.Errorf("failed to convert document to role: %v", err)
-		}
-
 		// Ensure the ID is set
 		role.Id = doc.Ref.ID
 		roles = append(roles, &role)
@@ -203,28 +404,70 @@ func (r *RoleRepositoryImpl) FindAllByPageAndSize(page, size int) ([]*model.Role
 	return roles, nil
 }
 
-func (r *RoleRepositoryImpl) Count() (int64, error) {
+func (r *RoleRepositoryImpl) FindAllAfter(after *repository.PageCursor, size int) ([]*model.Role, error) {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
 
-	// Firestore doesn't provide a direct count operation
-	// We need to iterate through all documents
-	iter := client.Collection(r.collectionName).Documents(ctx)
+	// Los roles globales no tienen organizationId y Firestore no filtra por campos ausentes,
+	// así que los de organizaciones se saltan en el recorrido
+	query := client.Collection(r.collectionName).OrderBy("code", firestore.Asc).OrderBy(firestore.DocumentID, firestore.Asc)
+	if after != nil {
+		query = query.StartAfter(after.SortValue, after.Id)
+	}
+	iter := query.Documents(ctx)
 	defer iter.Stop()
 
-	var count int64
-	for {
-		_, err := iter.Next()
+	var roles []*model.Role
+	for len(roles) < size {
+		doc, err := iter.Next()
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
-			return 0, fmt.Errorf("failed to count roles: %v", err)
+			return nil, fmt.Errorf("failed to iterate roles: %v", err)
+		}
+
+		var role model.Role
+		if err := doc.DataTo(&role); err != nil {
+			return nil, fmt.Errorf("failed to convert document to role: %v", err)
+		}
+		if role.OrganizationId != "" {
+			continue
 		}
-		count++
+
+		role.Id = doc.Ref.ID
+		roles = append(roles, &role)
 	}
 
-	return count, nil
+	return roles, nil
+}
+
+// Count resta a todos los roles los de organizaciones con dos consultas de agregación, sin leer los documentos
+func (r *RoleRepositoryImpl) Count() (int64, error) {
+	collection := database.GetFirestoreClient().Collection(r.collectionName)
+
+	total, err := r.count(collection.Query)
+	if err != nil {
+		return 0, err
+	}
+	organizationRoles, err := r.count(collection.Where("organizationId", ">", ""))
+	if err != nil {
+		return 0, err
+	}
+	return total - organizationRoles, nil
+}
+
+func (r *RoleRepositoryImpl) count(query firestore.Query) (int64, error) {
+	result, err := query.NewAggregationQuery().WithCount("count").Get(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to count roles: %v", err)
+	}
+
+	count, ok := result["count"].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("unexpected count result: %v", result["count"])
+	}
+	return count.GetIntegerValue(), nil
 }
 
 func (r *RoleRepositoryImpl) FindByIds(ids []string) ([]*model.Role, error) {
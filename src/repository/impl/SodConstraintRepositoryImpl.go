@@ -0,0 +1,115 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/database"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type SodConstraintRepositoryImpl struct {
+	collectionName string
+}
+
+func NewSodConstraintRepositoryImpl() *SodConstraintRepositoryImpl {
+	return &SodConstraintRepositoryImpl{
+		collectionName: "sod_constraints",
+	}
+}
+
+func (r *SodConstraintRepositoryImpl) Create(constraint *model.SodConstraint) (*model.SodConstraint, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	constraint.Id = uuid.New().String()
+	constraint.CreatedAt = time.Now()
+	constraint.UpdatedAt = constraint.CreatedAt
+
+	_, err := client.Collection(r.collectionName).Doc(constraint.Id).Set(ctx, constraint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create separation of duty constraint: %v", err)
+	}
+
+	return constraint, nil
+}
+
+func (r *SodConstraintRepositoryImpl) FindById(id string) (*model.SodConstraint, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	docSnap, err := client.Collection(r.collectionName).Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get separation of duty constraint: %v", err)
+	}
+
+	var constraint model.SodConstraint
+	if err := docSnap.DataTo(&constraint); err != nil {
+		return nil, fmt.Errorf("failed to convert document to separation of duty constraint: %v", err)
+	}
+	constraint.Id = docSnap.Ref.ID
+
+	return &constraint, nil
+}
+
+func (r *SodConstraintRepositoryImpl) FindAll() ([]*model.SodConstraint, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Documents(ctx)
+	defer iter.Stop()
+
+	var constraints []*model.SodConstraint
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate separation of duty constraints: %v", err)
+		}
+
+		var constraint model.SodConstraint
+		if err := doc.DataTo(&constraint); err != nil {
+			return nil, fmt.Errorf("failed to convert document to separation of duty constraint: %v", err)
+		}
+		constraint.Id = doc.Ref.ID
+		constraints = append(constraints, &constraint)
+	}
+
+	return constraints, nil
+}
+
+func (r *SodConstraintRepositoryImpl) Update(constraint *model.SodConstraint) (*model.SodConstraint, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	constraint.UpdatedAt = time.Now()
+
+	_, err := client.Collection(r.collectionName).Doc(constraint.Id).Set(ctx, constraint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update separation of duty constraint: %v", err)
+	}
+
+	return constraint, nil
+}
+
+func (r *SodConstraintRepositoryImpl) Delete(id string) error {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	_, err := client.Collection(r.collectionName).Doc(id).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete separation of duty constraint: %v", err)
+	}
+
+	return nil
+}
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"github.com/ruiborda/ecommerce-user-service/src/database"
 	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"slices"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	"github.com/google/uuid"
 	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
@@ -16,11 +20,14 @@ import (
 
 type UserRepositoryImpl struct {
 	collectionName string
+	// Marcas de las migraciones de datos ya aplicadas
+	migrationsCollectionName string
 }
 
 func NewUserRepositoryImpl() *UserRepositoryImpl {
 	return &UserRepositoryImpl{
-		collectionName: "users",
+		collectionName:           "users",
+		migrationsCollectionName: "migrations",
 	}
 }
 
@@ -28,6 +35,10 @@ func (r *UserRepositoryImpl) Create(user *model.User) (*model.User, error) {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
 
+	if user.Status == "" {
+		user.Status = model.UserStatusActive
+	}
+
 	if user.Id == "" {
 		// Guardar el documento con el ID generado
 		user.Id = uuid.New().String()
@@ -109,6 +120,38 @@ func (r *UserRepositoryImpl) FindByEmail(email string) (*model.User, error) {
 	return &user, nil
 }
 
+func (r *UserRepositoryImpl) FindByEmails(emails []string) ([]*model.User, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	var users []*model.User
+	// Firestore admite hasta 30 valores en un filtro "in"
+	for batch := range slices.Chunk(emails, 30) {
+		iter := client.Collection(r.collectionName).Where("email", "in", batch).Documents(ctx)
+		for {
+			doc, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				iter.Stop()
+				return nil, fmt.Errorf("failed to query users by email: %v", err)
+			}
+
+			var user model.User
+			if err := doc.DataTo(&user); err != nil {
+				iter.Stop()
+				return nil, fmt.Errorf("failed to convert document to user: %v", err)
+			}
+			user.Id = doc.Ref.ID
+			users = append(users, &user)
+		}
+		iter.Stop()
+	}
+
+	return users, nil
+}
+
 func (r *UserRepositoryImpl) FindAll() ([]*model.User, error) {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
@@ -139,19 +182,102 @@ func (r *UserRepositoryImpl) FindAll() ([]*model.User, error) {
 	return users, nil
 }
 
-func (r *UserRepositoryImpl) Update(user *model.User) (*model.User, error) {
+func (r *UserRepositoryImpl) Update(id string, change repository.UserChange) (*model.User, error) {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
+	userRef := client.Collection(r.collectionName).Doc(id)
+
+	var updated *model.User
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = nil
+
+		doc, err := tx.Get(userRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return fmt.Errorf("failed to get user: %v", err)
+		}
+
+		var before model.User
+		if err := doc.DataTo(&before); err != nil {
+			return fmt.Errorf("failed to convert document to user: %v", err)
+		}
+		before.Id = doc.Ref.ID
+
+		user := before
+		user.RoleIds = slices.Clone(before.RoleIds)
+		user.DeniedPermissionIds = slices.Clone(before.DeniedPermissionIds)
+		if err := change(&user); err != nil {
+			return err
+		}
 
-	// Update the timestamp
-	user.UpdatedAt = time.Now()
+		updates := changedUserFields(&before, &user)
+		if len(updates) == 0 {
+			updated = &before
+			return nil
+		}
+		user.UpdatedAt = time.Now()
+		updates = append(updates, firestore.Update{Path: "updatedAt", Value: user.UpdatedAt})
+		if err := tx.Update(userRef, updates); err != nil {
+			return fmt.Errorf("failed to update user: %v", err)
+		}
 
-	_, err := client.Collection(r.collectionName).Doc(user.Id).Set(ctx, user)
+		// Lo que el cambio haya tocado fuera de los campos escritos no se guarda, así que se devuelve lo leído
+		user.RoleAssignments = before.RoleAssignments
+		user.RoleAssignmentsExpireAt = before.RoleAssignmentsExpireAt
+		user.FavoriteNewsArticleIds = before.FavoriteNewsArticleIds
+		user.AuthzVersion = max(user.AuthzVersion, before.AuthzVersion)
+		updated = &user
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user: %v", err)
+		return nil, err
 	}
 
-	return user, nil
+	return updated, nil
+}
+
+// changedUserFields devuelve las escrituras de los campos que el cambio modificó. Los campos vacíos se borran,
+// como los omite Set; authzVersion solo puede aumentar y se escribe como incremento para no pisar otro aumento.
+func changedUserFields(before, after *model.User) []firestore.Update {
+	var updates []firestore.Update
+	set := func(path string, changed bool, value any, empty bool) {
+		if !changed {
+			return
+		}
+		if empty {
+			value = firestore.Delete
+		}
+		updates = append(updates, firestore.Update{Path: path, Value: value})
+	}
+	setTime := func(path string, before, after *time.Time) {
+		changed := (before == nil) != (after == nil) || (before != nil && !before.Equal(*after))
+		if after == nil {
+			set(path, changed, nil, true)
+		} else {
+			set(path, changed, *after, false)
+		}
+	}
+
+	set("email", before.Email != after.Email, after.Email, after.Email == "")
+	set("passwordHash", before.PasswordHash != after.PasswordHash, after.PasswordHash, after.PasswordHash == "")
+	set("fullName", before.FullName != after.FullName, after.FullName, after.FullName == "")
+	set("imageFileKey", before.ImageFileKey != after.ImageFileKey, after.ImageFileKey, after.ImageFileKey == "")
+	set("pictureUrl", before.PictureUrl != after.PictureUrl, after.PictureUrl, after.PictureUrl == "")
+	set("roleIds", !slices.Equal(before.RoleIds, after.RoleIds), after.RoleIds, len(after.RoleIds) == 0)
+	set("deniedPermissionIds", !slices.Equal(before.DeniedPermissionIds, after.DeniedPermissionIds), after.DeniedPermissionIds, len(after.DeniedPermissionIds) == 0)
+	set("status", before.Status != after.Status, after.Status, false)
+	set("statusReason", before.StatusReason != after.StatusReason, after.StatusReason, after.StatusReason == "")
+	set("statusChangedBy", before.StatusChangedBy != after.StatusChangedBy, after.StatusChangedBy, after.StatusChangedBy == "")
+	setTime("statusChangedAt", before.StatusChangedAt, after.StatusChangedAt)
+	setTime("suspendedUntil", before.SuspendedUntil, after.SuspendedUntil)
+	setTime("purgeAt", before.PurgeAt, after.PurgeAt)
+	if after.AuthzVersion > before.AuthzVersion {
+		updates = append(updates, firestore.Update{Path: "authzVersion", Value: firestore.Increment(after.AuthzVersion - before.AuthzVersion)})
+	}
+	return updates
 }
 
 func (r *UserRepositoryImpl) Delete(id string) error {
@@ -166,21 +292,101 @@ func (r *UserRepositoryImpl) Delete(id string) error {
 	return nil
 }
 
-func (r *UserRepositoryImpl) FindAllByPageAndSize(page, size int) ([]*model.User, error) {
+func (r *UserRepositoryImpl) DeleteIf(id string, condition repository.UserCondition) (*model.User, error) {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
+	userRef := client.Collection(r.collectionName).Doc(id)
+
+	var deleted *model.User
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		deleted = nil
+
+		doc, err := tx.Get(userRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return fmt.Errorf("failed to get user: %v", err)
+		}
+
+		var user model.User
+		if err := doc.DataTo(&user); err != nil {
+			return fmt.Errorf("failed to convert document to user: %v", err)
+		}
+		user.Id = doc.Ref.ID
+		if !condition(&user) {
+			return nil
+		}
+
+		if err := tx.Delete(userRef); err != nil {
+			return fmt.Errorf("failed to delete user: %v", err)
+		}
+		deleted = &user
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+func (r *UserRepositoryImpl) FindAllByPageAndSize(page, size int) ([]*model.User, error) {
+	client := database.GetFirestoreClient()
+
+	// Firestore salta el offset en el servidor, aunque cobra igualmente las lecturas saltadas;
+	// para páginas profundas es mejor FindByFilterAfter
+	query := client.Collection(r.collectionName).OrderBy("createdAt", firestore.Desc).Offset(page * size).Limit(size)
+	return r.collectUsers(query, nil, size)
+}
+
+func (r *UserRepositoryImpl) Count() (int64, error) {
+	return r.count(database.GetFirestoreClient().Collection(r.collectionName).Query)
+}
+
+func (r *UserRepositoryImpl) FindByFilter(filter *repository.UserFilter, page, size int) ([]*model.User, error) {
+	query, matches := r.filterQuery(filter)
 
-	// Calculate offset
 	offset := page * size
+	if matches == nil {
+		return r.collectUsers(query.Offset(offset).Limit(size), nil, size)
+	}
+
+	// Los criterios en memoria impiden usar Offset, así que se salta la página en el recorrido
+	users, err := r.collectUsers(query, matches, offset+size)
+	if err != nil {
+		return nil, err
+	}
+	return users[min(offset, len(users)):], nil
+}
+
+func (r *UserRepositoryImpl) FindByFilterAfter(filter *repository.UserFilter, after *repository.PageCursor, size int) ([]*model.User, error) {
+	query, matches := r.filterQuery(filter)
+
+	// El ID desempata los usuarios con el mismo valor de ordenación, así que el cursor es exacto
+	direction := firestore.Asc
+	if filter.SortDesc {
+		direction = firestore.Desc
+	}
+	query = query.OrderBy(firestore.DocumentID, direction)
+	if after != nil {
+		query = query.StartAfter(after.SortValue, after.Id)
+	}
+	if matches == nil {
+		query = query.Limit(size)
+	}
 
-	// Get all documents and apply pagination in memory
-	// Note: Firestore doesn't natively support offset-based pagination
-	iter := client.Collection(r.collectionName).OrderBy("createdAt", firestore.Desc).Documents(ctx)
+	return r.collectUsers(query, matches, size)
+}
+
+// collectUsers recorre la consulta hasta reunir limit usuarios que cumplan matches (todos si es nil)
+func (r *UserRepositoryImpl) collectUsers(query firestore.Query, matches func(*model.User) bool, limit int) ([]*model.User, error) {
+	iter := query.Documents(context.Background())
 	defer iter.Stop()
 
 	var users []*model.User
-	index := 0
-	for {
+	for len(users) < limit {
 		doc, err := iter.Next()
 		if err == iterator.Done {
 			break
@@ -189,55 +395,117 @@ func (r *UserRepositoryImpl) FindAllByPageAndSize(page, size int) ([]*model.User
 			return nil, fmt.Errorf("failed to iterate users: %v", err)
 		}
 
-		// Skip documents before offset
-		if index < offset {
-			index++
-			continue
-		}
-
-		// Break if we've collected enough documents
-		if len(users) >= size {
-			break
-		}
-
 		var user model.User
 		if err := doc.DataTo(&user); err != nil {
 			return nil, fmt.Errorf("failed to convert document to user: %v", err)
 		}
-
-		// Ensure the ID is set
 		user.Id = doc.Ref.ID
+		if matches != nil && !matches(&user) {
+			continue
+		}
 		users = append(users, &user)
-		index++
 	}
 
 	return users, nil
 }
 
-func (r *UserRepositoryImpl) Count() (int64, error) {
+func (r *UserRepositoryImpl) CountByFilter(filter *repository.UserFilter) (int64, error) {
 	ctx := context.Background()
-	client := database.GetFirestoreClient()
+	query, matches := r.filterQuery(filter)
 
-	// Firestore doesn't provide a direct count operation
-	// We need to iterate through all documents
-	iter := client.Collection(r.collectionName).Documents(ctx)
+	if matches == nil {
+		return r.count(query)
+	}
+
+	iter := query.Documents(ctx)
 	defer iter.Stop()
 
 	var count int64
 	for {
-		_, err := iter.Next()
+		doc, err := iter.Next()
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
 			return 0, fmt.Errorf("failed to count users: %v", err)
 		}
-		count++
+
+		var user model.User
+		if err := doc.DataTo(&user); err != nil {
+			return 0, fmt.Errorf("failed to convert document to user: %v", err)
+		}
+		if matches(&user) {
+			count++
+		}
 	}
 
 	return count, nil
 }
 
+// filterQuery traduce a Firestore los criterios que admite: el rol con array-contains, los estados con in y un único rango,
+// el del prefijo del email o el de la fecha de creación cuando se ordena por ella. Los demás criterios se
+// devuelven como una función que se evalúa en memoria, o nil si no queda ninguno.
+func (r *UserRepositoryImpl) filterQuery(filter *repository.UserFilter) (firestore.Query, func(*model.User) bool) {
+	query := database.GetFirestoreClient().Collection(r.collectionName).Query
+
+	if filter.RoleId != "" {
+		query = query.Where("roleIds", "array-contains", filter.RoleId)
+	}
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status", "in", filter.Statuses)
+	}
+
+	if filter.EmailPrefix != "" {
+		// \uf8ff es el último punto de código habitual, así que acota todos los emails con el prefijo
+		query = query.Where("email", ">=", filter.EmailPrefix).Where("email", "<", filter.EmailPrefix+"\uf8ff")
+	}
+
+	// Firestore exige ordenar primero por el campo del rango, así que el rango de fechas solo se resuelve
+	// allí si ya se ordena por la fecha de creación y no hay otro rango
+	createdRangeInQuery := filter.EmailPrefix == "" && filter.SortField == repository.UserSortCreatedAt
+	if createdRangeInQuery {
+		if filter.CreatedFrom != nil {
+			query = query.Where("createdAt", ">=", *filter.CreatedFrom)
+		}
+		if filter.CreatedBefore != nil {
+			query = query.Where("createdAt", "<", *filter.CreatedBefore)
+		}
+	}
+
+	direction := firestore.Asc
+	if filter.SortDesc {
+		direction = firestore.Desc
+	}
+	query = query.OrderBy(filter.SortField, direction)
+
+	text := strings.ToLower(filter.Query)
+	nameContains := strings.ToLower(filter.NameContains)
+	createdInMemory := !createdRangeInQuery && (filter.CreatedFrom != nil || filter.CreatedBefore != nil)
+	if text == "" && nameContains == "" && !createdInMemory {
+		return query, nil
+	}
+
+	return query, func(user *model.User) bool {
+		fullName := strings.ToLower(user.FullName)
+		if text != "" && !strings.Contains(strings.ToLower(user.Email), text) && !strings.Contains(fullName, text) {
+			return false
+		}
+		if nameContains != "" && !strings.Contains(fullName, nameContains) {
+			return false
+		}
+		if createdInMemory {
+			if filter.CreatedFrom != nil && user.CreatedAt.Before(*filter.CreatedFrom) {
+				return false
+			}
+			if filter.CreatedBefore != nil && !user.CreatedAt.Before(*filter.CreatedBefore) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 func (r *UserRepositoryImpl) FindByIds(ids []string) ([]*model.User, error) {
 	ctx := context.Background()
 	client := database.GetFirestoreClient()
@@ -268,3 +536,393 @@ func (r *UserRepositoryImpl) FindByIds(ids []string) ([]*model.User, error) {
 
 	return users, nil
 }
+
+func (r *UserRepositoryImpl) FindByOrganization(organizationId string) ([]*model.User, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	// Los miembros se obtienen a partir de las membresías de la organización
+	iter := client.Collection("organization_memberships").Where("organizationId", "==", organizationId).Documents(ctx)
+	defer iter.Stop()
+
+	var userIds []string
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate organization memberships: %v", err)
+		}
+
+		userId, err := doc.DataAt("userId")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read organization membership: %v", err)
+		}
+		if id, ok := userId.(string); ok {
+			userIds = append(userIds, id)
+		}
+	}
+
+	return r.FindByIds(userIds)
+}
+
+func (r *UserRepositoryImpl) FindIdsByRoleId(roleId string) (permanentIds []string, temporaryIds []string, err error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	iter := client.Collection(r.collectionName).Where("roleIds", "array-contains", roleId).Select().Documents(ctx)
+	defer iter.Stop()
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query users by role: %v", err)
+		}
+		permanentIds = append(permanentIds, doc.Ref.ID)
+	}
+
+	// Firestore no permite buscar dentro de los objetos de un array; solo se leen los usuarios con asignaciones temporales
+	assignmentsIter := client.Collection(r.collectionName).Where("roleAssignmentsExpireAt", "!=", nil).Select("roleAssignments").Documents(ctx)
+	defer assignmentsIter.Stop()
+	for {
+		doc, err := assignmentsIter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query users with role assignments: %v", err)
+		}
+
+		var user model.User
+		if err := doc.DataTo(&user); err != nil {
+			return nil, nil, fmt.Errorf("failed to convert document to user: %v", err)
+		}
+		for _, assignment := range user.RoleAssignments {
+			if assignment.RoleId == roleId {
+				temporaryIds = append(temporaryIds, doc.Ref.ID)
+				break
+			}
+		}
+	}
+
+	return permanentIds, temporaryIds, nil
+}
+
+func (r *UserRepositoryImpl) ReplaceRole(userIds []string, roleId, replacementId string) (int, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	refs := make([]*firestore.DocumentRef, 0, len(userIds))
+	for _, userId := range userIds {
+		refs = append(refs, client.Collection(r.collectionName).Doc(userId))
+	}
+
+	var updated int
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = 0
+
+		docs, err := tx.GetAll(refs)
+		if err != nil {
+			return fmt.Errorf("failed to get users: %v", err)
+		}
+
+		now := time.Now()
+		for _, doc := range docs {
+			if !doc.Exists() {
+				continue
+			}
+			var user model.User
+			if err := doc.DataTo(&user); err != nil {
+				return fmt.Errorf("failed to convert document to user: %v", err)
+			}
+			if !user.ReplaceRole(roleId, replacementId) {
+				continue
+			}
+
+			var expireAt any = firestore.Delete
+			if user.RoleAssignmentsExpireAt != nil {
+				expireAt = *user.RoleAssignmentsExpireAt
+			}
+			roleIds := user.RoleIds
+			if roleIds == nil {
+				roleIds = []string{}
+			}
+			assignments := user.RoleAssignments
+			if assignments == nil {
+				assignments = []model.RoleAssignment{}
+			}
+			err = tx.Update(doc.Ref, []firestore.Update{
+				{Path: "roleIds", Value: roleIds},
+				{Path: "roleAssignments", Value: assignments},
+				{Path: "roleAssignmentsExpireAt", Value: expireAt},
+				{Path: "updatedAt", Value: now},
+				{Path: "authzVersion", Value: firestore.Increment(1)},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update user roles: %v", err)
+			}
+			updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
+func (r *UserRepositoryImpl) FindPurgeable(now time.Time, limit int) ([]*model.User, error) {
+	query := database.GetFirestoreClient().Collection(r.collectionName).
+		Where("status", "==", model.UserStatusDeleted).
+		Where("purgeAt", "<=", now).
+		Limit(limit)
+	return r.collectUsers(query, nil, limit)
+}
+
+func (r *UserRepositoryImpl) FindExpiredSuspensions(now time.Time, limit int) ([]*model.User, error) {
+	query := database.GetFirestoreClient().Collection(r.collectionName).
+		Where("status", "==", model.UserStatusSuspended).
+		Where("suspendedUntil", "<=", now).
+		Limit(limit)
+	return r.collectUsers(query, nil, limit)
+}
+
+func (r *UserRepositoryImpl) BackfillStatus() (int, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	marker := client.Collection(r.migrationsCollectionName).Doc("user_status")
+	if _, err := marker.Get(ctx); err == nil {
+		return 0, nil
+	} else if status.Code(err) != codes.NotFound {
+		return 0, fmt.Errorf("failed to get migration marker: %v", err)
+	}
+
+	// Firestore no consulta campos ausentes, así que se recorren todos los usuarios leyendo solo el estado
+	iter := client.Collection(r.collectionName).Select("status").Documents(ctx)
+	defer iter.Stop()
+
+	var refs []*firestore.DocumentRef
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to iterate users: %v", err)
+		}
+		if value, err := doc.DataAt("status"); err != nil || value == nil || value == "" {
+			refs = append(refs, doc.Ref)
+		}
+	}
+
+	// Una transacción admite hasta 500 escrituras
+	for batch := range slices.Chunk(refs, 500) {
+		err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			for _, ref := range batch {
+				if err := tx.Update(ref, []firestore.Update{{Path: "status", Value: model.UserStatusActive}}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to backfill user status: %v", err)
+		}
+	}
+
+	if _, err := marker.Set(ctx, map[string]any{"completedAt": time.Now(), "updated": len(refs)}); err != nil {
+		return 0, fmt.Errorf("failed to set migration marker: %v", err)
+	}
+	return len(refs), nil
+}
+
+func (r *UserRepositoryImpl) CreateAll(users []*model.User) ([]string, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+	now := time.Now()
+
+	for _, user := range users {
+		if user.Id == "" {
+			user.Id = uuid.New().String()
+		}
+		if user.Status == "" {
+			user.Status = model.UserStatusActive
+		}
+		if user.CreatedAt.IsZero() {
+			user.CreatedAt = now
+		}
+		if user.UpdatedAt.IsZero() {
+			user.UpdatedAt = now
+		}
+	}
+
+	var taken []string
+	// Una transacción admite hasta 500 escrituras
+	for batch := range slices.Chunk(users, 500) {
+		var batchTaken []string
+		err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			batchTaken = nil
+
+			emails := make([]string, 0, len(batch))
+			for _, user := range batch {
+				emails = append(emails, user.Email)
+			}
+			// Firestore admite hasta 30 valores en un filtro "in"
+			for emailBatch := range slices.Chunk(emails, 30) {
+				iter := tx.Documents(client.Collection(r.collectionName).Where("email", "in", emailBatch))
+				for {
+					doc, err := iter.Next()
+					if err == iterator.Done {
+						break
+					}
+					if err != nil {
+						iter.Stop()
+						return fmt.Errorf("failed to query users by email: %v", err)
+					}
+					if email, err := doc.DataAt("email"); err == nil {
+						batchTaken = append(batchTaken, fmt.Sprint(email))
+					}
+				}
+				iter.Stop()
+			}
+
+			for _, user := range batch {
+				if slices.Contains(batchTaken, user.Email) {
+					continue
+				}
+				if err := tx.Create(client.Collection(r.collectionName).Doc(user.Id), user); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return taken, fmt.Errorf("failed to create users: %v", err)
+		}
+		taken = append(taken, batchTaken...)
+	}
+
+	return taken, nil
+}
+
+func (r *UserRepositoryImpl) BumpAuthzVersion(userIds []string) error {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	// Una transacción admite hasta 500 escrituras
+	for batch := range slices.Chunk(userIds, 500) {
+		refs := make([]*firestore.DocumentRef, 0, len(batch))
+		for _, userId := range batch {
+			refs = append(refs, client.Collection(r.collectionName).Doc(userId))
+		}
+
+		err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			docs, err := tx.GetAll(refs)
+			if err != nil {
+				return fmt.Errorf("failed to get users: %v", err)
+			}
+			for _, doc := range docs {
+				if !doc.Exists() {
+					continue
+				}
+				if err := tx.Update(doc.Ref, []firestore.Update{{Path: "authzVersion", Value: firestore.Increment(1)}}); err != nil {
+					return fmt.Errorf("failed to bump authorization version: %v", err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *UserRepositoryImpl) AddFavoriteNewsArticle(userId, articleId string, limit int) (*model.User, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+	userRef := client.Collection(r.collectionName).Doc(userId)
+
+	var updated *model.User
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = nil
+
+		doc, err := tx.Get(userRef)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return fmt.Errorf("failed to get user: %v", err)
+		}
+		var user model.User
+		if err := doc.DataTo(&user); err != nil {
+			return fmt.Errorf("failed to convert document to user: %v", err)
+		}
+		user.Id = doc.Ref.ID
+		updated = &user
+
+		if slices.Contains(user.FavoriteNewsArticleIds, articleId) {
+			return nil
+		}
+		if len(user.FavoriteNewsArticleIds) >= limit {
+			return repository.ErrFavoriteLimitReached
+		}
+
+		user.FavoriteNewsArticleIds = append(user.FavoriteNewsArticleIds, articleId)
+		user.UpdatedAt = time.Now()
+		// La unión del array evita duplicados aunque otra escritura haya añadido la misma noticia
+		err = tx.Update(userRef, []firestore.Update{
+			{Path: "favoriteNewsArticleIds", Value: firestore.ArrayUnion(articleId)},
+			{Path: "updatedAt", Value: user.UpdatedAt},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add favorite: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *UserRepositoryImpl) RemoveFavoriteNewsArticle(userId, articleId string) (bool, error) {
+	ctx := context.Background()
+	client := database.GetFirestoreClient()
+
+	_, err := client.Collection(r.collectionName).Doc(userId).Update(ctx, []firestore.Update{
+		{Path: "favoriteNewsArticleIds", Value: firestore.ArrayRemove(articleId)},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to remove favorite: %v", err)
+	}
+	return true, nil
+}
+
+func (r *UserRepositoryImpl) CountByFavoriteNewsArticle(articleId string) (int64, error) {
+	query := database.GetFirestoreClient().Collection(r.collectionName).Where("favoriteNewsArticleIds", "array-contains", articleId)
+	return r.count(query)
+}
+
+// count usa una consulta de agregación, que Firestore resuelve sobre el índice sin leer los documentos
+func (r *UserRepositoryImpl) count(query firestore.Query) (int64, error) {
+	result, err := query.NewAggregationQuery().WithCount("count").Get(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %v", err)
+	}
+
+	count, ok := result["count"].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("unexpected count result: %v", result["count"])
+	}
+	return count.GetIntegerValue(), nil
+}
@@ -0,0 +1,82 @@
+package impl
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func TestChangedUserFields(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	base := func() *model.User {
+		return &model.User{
+			Id:                     "u1",
+			Email:                  "ana@example.com",
+			FullName:               "Ana",
+			RoleIds:                []string{"r1"},
+			DeniedPermissionIds:    []int{503},
+			RoleAssignments:        []model.RoleAssignment{{RoleId: "r2", ValidFrom: now, ValidUntil: later}},
+			AuthzVersion:           3,
+			FavoriteNewsArticleIds: []string{"n1"},
+			Status:                 model.UserStatusActive,
+			PurgeAt:                &now,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		change    func(user *model.User)
+		wantPaths []string
+	}{
+		{"no change", func(user *model.User) {}, nil},
+		{"profile field", func(user *model.User) { user.FullName = "Ana María" }, []string{"fullName"}},
+		{"cleared field is deleted", func(user *model.User) { user.RoleIds = nil }, []string{"roleIds"}},
+		{"time pointer", func(user *model.User) { user.PurgeAt = &later }, []string{"purgeAt"}},
+		{"equal time pointer", func(user *model.User) { purgeAt := now; user.PurgeAt = &purgeAt }, nil},
+		{"authz version increment", func(user *model.User) { user.AuthzVersion++ }, []string{"authzVersion"}},
+		{"authz version never decreases", func(user *model.User) { user.AuthzVersion = 0 }, nil},
+		{"role assignments are never written", func(user *model.User) { user.RoleAssignments = nil }, nil},
+		{"favorites are never written", func(user *model.User) { user.FavoriteNewsArticleIds = nil }, nil},
+		{"several fields", func(user *model.User) {
+			user.Status = model.UserStatusSuspended
+			user.StatusReason = "fraud"
+			user.AuthzVersion++
+		}, []string{"status", "statusReason", "authzVersion"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before, after := base(), base()
+			test.change(after)
+
+			updates := changedUserFields(before, after)
+			var paths []string
+			for _, update := range updates {
+				paths = append(paths, update.Path)
+			}
+			if !slices.Equal(paths, test.wantPaths) {
+				t.Fatalf("changed fields = %v, want %v", paths, test.wantPaths)
+			}
+		})
+	}
+}
+
+func TestChangedUserFieldsValues(t *testing.T) {
+	before := &model.User{RoleIds: []string{"r1"}, AuthzVersion: 3}
+	after := &model.User{AuthzVersion: 5}
+
+	updates := changedUserFields(before, after)
+	if len(updates) != 2 {
+		t.Fatalf("changedUserFields() = %v, want roleIds and authzVersion", updates)
+	}
+	if updates[0].Value != firestore.Delete {
+		t.Errorf("roleIds value = %v, want firestore.Delete", updates[0].Value)
+	}
+	if _, ok := updates[1].Value.(int64); ok {
+		t.Errorf("authzVersion value = %v, want an increment instead of the absolute version", updates[1].Value)
+	}
+}
@@ -1,127 +1,516 @@
 package router
 
 import (
+	"github.com/ruiborda/ecommerce-user-service/src/blob"
 	"github.com/ruiborda/ecommerce-user-service/src/controller"
-	"github.com/ruiborda/ecommerce-user-service/src/middleware"
 	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/policy"
 
 	"github.com/gin-gonic/gin"
 )
 
-func ApiRouter(router *gin.Engine) {
+// ApiRouter registers the API routes. Every route is declared with the permission that protects it;
+// the permission catalog is generated from these declarations and any drift is returned as an error.
+func ApiRouter(router *gin.Engine) error {
+	routes := newRouteRegistry(router)
 	userController := controller.NewUserController()
 	authController := controller.NewAuthController()
 	roleController := controller.NewRoleController()
 	permissionController := controller.NewPermissionController()
+	authorizationController := controller.NewAuthorizationController()
+	roleAssignmentController := controller.NewRoleAssignmentController()
+	roleChangeRequestController := controller.NewRoleChangeRequestController()
+	sodConstraintController := controller.NewSodConstraintController()
+	organizationController := controller.NewOrganizationController()
+	groupController := controller.NewGroupController()
+	meController := controller.NewMeController()
+	blobController := controller.NewBlobController()
+	favoriteNewsController := controller.NewFavoriteNewsController()
+	userStatusController := controller.NewUserStatusController()
+	dataSubjectRequestController := controller.NewDataSubjectRequestController()
+	eventController := controller.NewEventController()
+	userImportController := controller.NewUserImportController()
+	exportController := controller.NewExportController()
 
 	// Auth routes - these should not be protected as they're for login
-	router.POST(
+	routes.POST(
 		"/api/v1/auth/login-with-google",
+		public(),
 		authController.LoginWithGoogle,
 	)
-	router.POST(
+	routes.POST(
 		"/api/v1/auth/login-with-email",
+		public(),
 		authController.LoginWithEmail,
 	)
 
+	// Self-service routes - act on the user of the token, so they only require a valid JWT
+	routes.GET(
+		"/api/v1/me",
+		authenticated(),
+		meController.GetMe,
+	)
+
+	routes.PATCH(
+		"/api/v1/me",
+		authenticated(),
+		meController.UpdateMe,
+	)
+
+	routes.PUT(
+		"/api/v1/me/password",
+		authenticated(),
+		meController.ChangePassword,
+	)
+
+	routes.DELETE(
+		"/api/v1/me",
+		authenticated(),
+		meController.DeleteMe,
+	)
+
+	routes.POST(
+		"/api/v1/me/avatar",
+		authenticated(),
+		meController.UploadAvatar,
+	)
+
+	routes.DELETE(
+		"/api/v1/me/avatar",
+		authenticated(),
+		meController.DeleteAvatar,
+	)
+
+	// Data subject requests (GDPR) - processed in the background, the status is polled
+	routes.POST(
+		"/api/v1/me/data-export",
+		authenticated(),
+		dataSubjectRequestController.RequestDataExport,
+	)
+
+	routes.POST(
+		"/api/v1/me/erasure-request",
+		authenticated(),
+		dataSubjectRequestController.RequestMyErasure,
+	)
+
+	routes.GET(
+		"/api/v1/me/data-requests",
+		authenticated(),
+		dataSubjectRequestController.GetMyRequests,
+	)
+
+	routes.GET(
+		"/api/v1/me/data-requests/:id",
+		authenticated(),
+		dataSubjectRequestController.GetMyRequest,
+	)
+
+	routes.GET(
+		"/api/v1/me/data-requests/:id/archive",
+		authenticated(),
+		dataSubjectRequestController.DownloadDataExport,
+	)
+
+	routes.GET(
+		"/api/v1/me/favorites/news",
+		authenticated(),
+		favoriteNewsController.GetFavorites,
+	)
+
+	routes.GET(
+		"/api/v1/me/favorites/news/:articleId",
+		authenticated(),
+		favoriteNewsController.GetFavorite,
+	)
+
+	routes.PUT(
+		"/api/v1/me/favorites/news/:articleId",
+		authenticated(),
+		favoriteNewsController.AddFavorite,
+	)
+
+	routes.DELETE(
+		"/api/v1/me/favorites/news/:articleId",
+		authenticated(),
+		favoriteNewsController.RemoveFavorite,
+	)
+
+	// Reverse lookup of favorites for the content team
+	routes.GET(
+		"/api/v1/news/:articleId/favorites/count",
+		permission(model.GetNewsFavoriteCount),
+		favoriteNewsController.CountFavorites,
+	)
+
+	// Objects of the local blob store, e.g. profile images; image tags cannot send a token
+	routes.GET(
+		blob.LocalBlobPath+"/*key",
+		public(),
+		blobController.GetBlob,
+	)
+
 	// User routes - protected with JWT and specific permissions
-	router.POST(
+	routes.POST(
 		"/api/v1/users",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.CreateUser),
+		permission(model.CreateUser),
 		userController.CreateUser,
 	)
 
-	router.GET(
+	routes.GET(
 		"/api/v1/users",
-		middleware.RequireJWT(),
+		permission(model.GetUsersPaginated),
 		userController.GetAllUsers,
 	)
 
-	router.GET(
+	routes.GET(
 		"/api/v1/users/:id",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.GetUserById),
+		permissionOrPolicy(model.GetUserById, policy.UserFromParam("id")),
 		userController.GetUserById,
 	)
 
-	router.PUT(
+	routes.PUT(
 		"/api/v1/users",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.UpdateUser),
+		permissionOrPolicy(model.UpdateUser, policy.UserFromBody("id")),
 		userController.UpdateUserById,
 	)
 
-	router.DELETE(
+	routes.DELETE(
 		"/api/v1/users/:id",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.DeleteUser),
+		permission(model.DeleteUser),
 		userController.DeleteUserById,
 	)
 
-	router.GET(
+	routes.POST(
+		"/api/v1/users/import",
+		permission(model.ImportUsers),
+		userImportController.ImportUsers,
+	)
+
+	routes.GET(
 		"/api/v1/users/pages",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.GetUsersPaginated),
+		permission(model.GetUsersPaginated),
 		userController.FindAllUsersByPageAndSize,
 	)
 
+	// Bulk exports - streamed in chunks, for datasets too large for the paginated endpoints
+	routes.GET(
+		"/api/v1/users/export",
+		permission(model.ExportUsers),
+		exportController.ExportUsers,
+	)
+
+	// Account lifecycle - DELETE /api/v1/users/:id is a soft delete that can be restored until the purge
+	routes.PUT(
+		"/api/v1/users/:id/status",
+		permission(model.ManageUserStatus),
+		userStatusController.ChangeStatus,
+	)
+
+	routes.POST(
+		"/api/v1/users/:id/restore",
+		permission(model.RestoreUser),
+		userStatusController.RestoreUser,
+	)
+
+	routes.POST(
+		"/api/v1/users/:id/erasure-request",
+		permission(model.ManageDataRequests),
+		dataSubjectRequestController.RequestUserErasure,
+	)
+
+	routes.GET(
+		"/api/v1/data-requests",
+		permission(model.ManageDataRequests),
+		dataSubjectRequestController.GetRequests,
+	)
+
+	// Temporary role assignments - expired ones are ignored and removed by the sweeper
+	routes.POST(
+		"/api/v1/users/:id/role-assignments",
+		permission(model.GrantTemporaryRole),
+		roleAssignmentController.GrantTemporaryRole,
+	)
+
+	routes.GET(
+		"/api/v1/users/:id/role-assignments",
+		permission(model.GetUserById),
+		roleAssignmentController.GetRoleAssignments,
+	)
+
+	routes.DELETE(
+		"/api/v1/users/:id/role-assignments/:roleId",
+		permission(model.GrantTemporaryRole),
+		roleAssignmentController.RevokeTemporaryRole,
+	)
+
 	// Role routes - protected with JWT and specific permissions
-	router.POST(
+	routes.POST(
 		"/api/v1/roles",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.CreateRole),
+		permission(model.CreateRole),
 		roleController.CreateRole,
 	)
 
-	router.GET(
+	routes.GET(
 		"/api/v1/roles/:id",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.GetRoleById),
+		permission(model.GetRoleById),
 		roleController.GetRoleByID,
 	)
 
-	router.PUT(
+	routes.PUT(
 		"/api/v1/roles",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.UpdateRole),
+		permission(model.UpdateRole),
 		roleController.UpdateRole,
 	)
 
-	router.DELETE(
+	routes.DELETE(
 		"/api/v1/roles/:id",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.DeleteRole),
+		permission(model.DeleteRole),
 		roleController.DeleteRole,
 	)
 
-	router.GET(
+	routes.GET(
 		"/api/v1/roles/pages",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.GetRolesPaginated),
+		permission(model.GetRolesPaginated),
 		roleController.GetAllByPageAndSize,
 	)
 
+	routes.GET(
+		"/api/v1/roles/export",
+		permission(model.ExportRoles),
+		exportController.ExportRoles,
+	)
+
+	// Role change requests - privileged roles are assigned only after a second user approves
+	routes.GET(
+		"/api/v1/role-change-requests",
+		permission(model.ApproveRoleChange),
+		roleChangeRequestController.GetRoleChangeRequests,
+	)
+
+	routes.GET(
+		"/api/v1/role-change-requests/:id",
+		permission(model.ApproveRoleChange),
+		roleChangeRequestController.GetRoleChangeRequestById,
+	)
+
+	routes.POST(
+		"/api/v1/role-change-requests/:id/approve",
+		permission(model.ApproveRoleChange),
+		roleChangeRequestController.ApproveRoleChangeRequest,
+	)
+
+	routes.POST(
+		"/api/v1/role-change-requests/:id/reject",
+		permission(model.ApproveRoleChange),
+		roleChangeRequestController.RejectRoleChangeRequest,
+	)
+
+	// Separation of duty constraints - mutually exclusive roles
+	routes.POST(
+		"/api/v1/sod-constraints",
+		permission(model.ManageSodConstraints),
+		sodConstraintController.CreateSodConstraint,
+	)
+
+	routes.GET(
+		"/api/v1/sod-constraints",
+		permission(model.ManageSodConstraints),
+		sodConstraintController.GetAllSodConstraints,
+	)
+
+	routes.PUT(
+		"/api/v1/sod-constraints",
+		permission(model.ManageSodConstraints),
+		sodConstraintController.UpdateSodConstraint,
+	)
+
+	routes.GET(
+		"/api/v1/sod-constraints/violations",
+		permission(model.GetSodViolations),
+		sodConstraintController.GetSodViolations,
+	)
+
+	routes.GET(
+		"/api/v1/sod-constraints/:id",
+		permission(model.ManageSodConstraints),
+		sodConstraintController.GetSodConstraintById,
+	)
+
+	routes.DELETE(
+		"/api/v1/sod-constraints/:id",
+		permission(model.ManageSodConstraints),
+		sodConstraintController.DeleteSodConstraintById,
+	)
+
+	// Group routes - members inherit the roles of their groups
+	routes.POST(
+		"/api/v1/groups",
+		permission(model.CreateGroup),
+		groupController.CreateGroup,
+	)
+
+	routes.GET(
+		"/api/v1/groups",
+		permission(model.GetAllGroups),
+		groupController.GetAllGroups,
+	)
+
+	routes.PUT(
+		"/api/v1/groups",
+		permission(model.UpdateGroup),
+		groupController.UpdateGroup,
+	)
+
+	routes.GET(
+		"/api/v1/groups/:id",
+		permission(model.GetGroupById),
+		groupController.GetGroupById,
+	)
+
+	routes.DELETE(
+		"/api/v1/groups/:id",
+		permission(model.DeleteGroup),
+		groupController.DeleteGroupById,
+	)
+
+	routes.PUT(
+		"/api/v1/groups/:id/members/:userId",
+		permission(model.ManageGroupMembers),
+		groupController.AddMember,
+	)
+
+	routes.DELETE(
+		"/api/v1/groups/:id/members/:userId",
+		permission(model.ManageGroupMembers),
+		groupController.RemoveMember,
+	)
+
+	// Organization routes - permissions are evaluated within the organization of the path
+	routes.POST(
+		"/api/v1/organizations",
+		permission(model.CreateOrganization),
+		organizationController.CreateOrganization,
+	)
+
+	routes.GET(
+		"/api/v1/organizations",
+		permission(model.GetAllOrganizations),
+		organizationController.GetAllOrganizations,
+	)
+
+	routes.GET(
+		"/api/v1/organizations/:orgId",
+		permission(model.GetOrganization),
+		organizationController.GetOrganizationById,
+	)
+
+	routes.PUT(
+		"/api/v1/organizations/:orgId",
+		permission(model.UpdateOrganization),
+		organizationController.UpdateOrganization,
+	)
+
+	routes.GET(
+		"/api/v1/organizations/:orgId/members",
+		permission(model.GetOrganization),
+		organizationController.GetMembers,
+	)
+
+	routes.PUT(
+		"/api/v1/organizations/:orgId/members/:userId",
+		permission(model.ManageOrganizationMembers),
+		organizationController.SetMember,
+	)
+
+	routes.DELETE(
+		"/api/v1/organizations/:orgId/members/:userId",
+		permission(model.ManageOrganizationMembers),
+		organizationController.RemoveMember,
+	)
+
+	routes.GET(
+		"/api/v1/organizations/:orgId/roles",
+		permission(model.GetOrganization),
+		organizationController.GetRoles,
+	)
+
+	routes.POST(
+		"/api/v1/organizations/:orgId/roles",
+		permission(model.ManageOrganizationRoles),
+		organizationController.CreateRole,
+	)
+
+	routes.PUT(
+		"/api/v1/organizations/:orgId/roles/:roleId",
+		permission(model.ManageOrganizationRoles),
+		organizationController.UpdateRole,
+	)
+
+	routes.DELETE(
+		"/api/v1/organizations/:orgId/roles/:roleId",
+		permission(model.ManageOrganizationRoles),
+		organizationController.DeleteRole,
+	)
+
 	// Permission routes - protected with JWT and specific permissions
-	// Solo rutas de consulta ya que los permisos están en hard code
-	router.GET(
+	// Los permisos propios se definen en el modelo y sus rutas se toman de este registro; los de otros servicios vienen del catálogo
+	routes.GET(
 		"/api/v1/permissions",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.GetAllPermissions),
+		permission(model.GetAllPermissions),
 		permissionController.GetAllPermissions,
 	)
 
-	router.GET(
+	routes.GET(
+		"/api/v1/permissions/groups",
+		permission(model.GetAllPermissions),
+		permissionController.GetPermissionGroups,
+	)
+
+	routes.GET(
 		"/api/v1/permissions/:id",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.GetPermissionById),
+		permission(model.GetPermissionById),
 		permissionController.GetPermissionById,
 	)
 
-	router.POST(
+	routes.POST(
 		"/api/v1/permissions/by-ids",
-		middleware.RequireJWT(),
-		middleware.RequirePermission(model.GetPermissionsByIds),
+		permission(model.GetPermissionsByIds),
 		permissionController.GetPermissionsByIds,
 	)
+
+	// Permission catalog - other microservices register their own permissions
+	routes.PUT(
+		"/api/v1/permissions/catalog/:service",
+		serviceAccount(),
+		permissionController.RegisterServiceCatalog,
+	)
+
+	// Authorization decisions for other microservices
+	routes.POST(
+		"/api/v1/authz/check",
+		serviceAccount(),
+		authorizationController.Check,
+	)
+
+	routes.POST(
+		"/api/v1/authz/check/batch",
+		serviceAccount(),
+		authorizationController.CheckBatch,
+	)
+
+	// Event feed for other microservices, e.g. user.erased to erase their own data
+	routes.GET(
+		"/api/v1/events",
+		serviceAccount(),
+		eventController.GetEvents,
+	)
+
+	routes.GET(
+		"/api/v1/authz/explain",
+		permission(model.ExplainAuthorization),
+		authorizationController.Explain,
+	)
+
+	return routes.publish()
 }
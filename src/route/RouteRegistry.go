@@ -0,0 +1,283 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/middleware"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/policy"
+	"github.com/ruiborda/go-swagger-generator/src/openapi_spec"
+	"github.com/ruiborda/go-swagger-generator/src/swagger"
+)
+
+// accessKind describes who may call a route
+type accessKind int
+
+const (
+	accessPublic accessKind = iota
+	accessServiceAccount
+	accessAuthenticated
+	accessPermission
+	accessPolicy
+)
+
+// permissionMode tells whether a route needs any or all of its permissions
+type permissionMode int
+
+const (
+	requireAll permissionMode = iota
+	requireAny
+)
+
+// routeAccess is the protection of a route and the middleware that enforces it
+type routeAccess struct {
+	kind          accessKind
+	permissionIds []int
+	mode          permissionMode
+	handlers      []gin.HandlerFunc
+}
+
+// public routes need no credentials, e.g. login
+func public() routeAccess {
+	return routeAccess{kind: accessPublic}
+}
+
+// serviceAccount routes are called by other microservices with their service account key
+func serviceAccount() routeAccess {
+	return routeAccess{kind: accessServiceAccount, handlers: []gin.HandlerFunc{middleware.RequireServiceAccount()}}
+}
+
+// authenticated routes only require a valid JWT; they act on the user of the token, e.g. /api/v1/me
+func authenticated() routeAccess {
+	return routeAccess{kind: accessAuthenticated, handlers: []gin.HandlerFunc{middleware.RequireJWT()}}
+}
+
+// permission routes require a JWT holding the permission
+func permission(permissionId int) routeAccess {
+	return routeAccess{
+		kind:          accessPermission,
+		permissionIds: []int{permissionId},
+		mode:          requireAll,
+		handlers:      []gin.HandlerFunc{middleware.RequireJWT(), middleware.RequirePermission(permissionId)},
+	}
+}
+
+// anyPermission routes require a JWT holding at least one of the permissions
+func anyPermission(permissionIds ...int) routeAccess {
+	return routeAccess{
+		kind:          accessPermission,
+		permissionIds: permissionIds,
+		mode:          requireAny,
+		handlers:      []gin.HandlerFunc{middleware.RequireJWT(), middleware.RequireAnyPermission(permissionIds...)},
+	}
+}
+
+// allPermissions routes require a JWT holding every one of the permissions
+func allPermissions(permissionIds ...int) routeAccess {
+	return routeAccess{
+		kind:          accessPermission,
+		permissionIds: permissionIds,
+		mode:          requireAll,
+		handlers:      []gin.HandlerFunc{middleware.RequireJWT(), middleware.RequireAllPermissions(permissionIds...)},
+	}
+}
+
+// permissionOrPolicy routes require a JWT holding the permission or satisfying one of its policies
+func permissionOrPolicy(permissionId int, loader policy.ResourceLoader) routeAccess {
+	return routeAccess{
+		kind:          accessPolicy,
+		permissionIds: []int{permissionId},
+		mode:          requireAll,
+		handlers:      []gin.HandlerFunc{middleware.RequireJWT(), middleware.RequirePolicy(permissionId, loader)},
+	}
+}
+
+// protected reports whether the route requires permissions, and so is part of the catalog
+func (a routeAccess) protected() bool {
+	return a.kind == accessPermission || a.kind == accessPolicy
+}
+
+// registeredRoute is a route declared through the registry
+type registeredRoute struct {
+	method string
+	path   string
+	access routeAccess
+}
+
+// routeRegistry declares each route together with its permission, so the permission catalog,
+// the OpenAPI document and the router cannot disagree
+type routeRegistry struct {
+	router *gin.Engine
+	routes []registeredRoute
+}
+
+func newRouteRegistry(router *gin.Engine) *routeRegistry {
+	return &routeRegistry{router: router}
+}
+
+func (r *routeRegistry) GET(path string, access routeAccess, handler gin.HandlerFunc) {
+	r.handle(http.MethodGet, path, access, handler)
+}
+
+func (r *routeRegistry) POST(path string, access routeAccess, handler gin.HandlerFunc) {
+	r.handle(http.MethodPost, path, access, handler)
+}
+
+func (r *routeRegistry) PUT(path string, access routeAccess, handler gin.HandlerFunc) {
+	r.handle(http.MethodPut, path, access, handler)
+}
+
+func (r *routeRegistry) PATCH(path string, access routeAccess, handler gin.HandlerFunc) {
+	r.handle(http.MethodPatch, path, access, handler)
+}
+
+func (r *routeRegistry) DELETE(path string, access routeAccess, handler gin.HandlerFunc) {
+	r.handle(http.MethodDelete, path, access, handler)
+}
+
+func (r *routeRegistry) handle(method, path string, access routeAccess, handler gin.HandlerFunc) {
+	r.routes = append(r.routes, registeredRoute{method: method, path: path, access: access})
+	r.router.Handle(method, path, append(access.handlers, handler)...)
+}
+
+// publish checks the registered routes against the permission catalog, fills the catalog
+// endpoints from them and documents the required permission in each OpenAPI operation
+func (r *routeRegistry) publish() error {
+	if err := r.checkDrift(); err != nil {
+		return err
+	}
+	model.SetPermissionEndpoints(r.permissionEndpoints())
+	r.documentPermissions()
+	return nil
+}
+
+// checkDrift fails when a route was registered outside the registry (and so has no declared
+// protection), when a protected route declares no permission, when a route requires a permission
+// missing from the catalog, or when a permission of the catalog protects no route
+func (r *routeRegistry) checkDrift() error {
+	var errs []error
+
+	declared := make(map[string]bool, len(r.routes))
+	for _, route := range r.routes {
+		declared[route.method+" "+route.path] = true
+	}
+	for _, info := range r.router.Routes() {
+		if !declared[info.Method+" "+info.Path] {
+			errs = append(errs, fmt.Errorf("route %s %s was registered without a declared permission", info.Method, info.Path))
+		}
+	}
+	for _, route := range r.routes {
+		if route.access.protected() && len(route.access.permissionIds) == 0 {
+			errs = append(errs, fmt.Errorf("route %s %s is protected but declares no permission", route.method, route.path))
+		}
+	}
+
+	endpoints := r.permissionEndpoints()
+	for permissionId := range endpoints {
+		if !model.IsBuiltinPermission(permissionId) {
+			for _, endpoint := range endpoints[permissionId] {
+				errs = append(errs, fmt.Errorf("route %s %s requires permission %d, which is not in the catalog", endpoint.Method, endpoint.Path, permissionId))
+			}
+		}
+	}
+	for _, permissionId := range model.BuiltinPermissionIds() {
+		if len(endpoints[permissionId]) == 0 {
+			errs = append(errs, fmt.Errorf("permission %d protects no route", permissionId))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("route and permission catalog drift: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// permissionEndpoints groups the protected routes by permission, in registration order.
+// A route that requires several permissions is listed under each of them.
+func (r *routeRegistry) permissionEndpoints() map[int][]model.PermissionEndpoint {
+	endpoints := make(map[int][]model.PermissionEndpoint)
+	for _, route := range r.routes {
+		if !route.access.protected() {
+			continue
+		}
+		for _, permissionId := range route.access.permissionIds {
+			endpoints[permissionId] = append(endpoints[permissionId], model.PermissionEndpoint{
+				Method: route.method,
+				Path:   route.path,
+			})
+		}
+	}
+	return endpoints
+}
+
+var pathParamPattern = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// documentPermissions appends the required permission to the description of the documented operations
+func (r *routeRegistry) documentPermissions() {
+	doc := swagger.Swagger().Build()
+	for _, route := range r.routes {
+		if !route.access.protected() {
+			continue
+		}
+		pathItem, ok := doc.Paths[pathParamPattern.ReplaceAllString(route.path, "{$1}")]
+		if !ok {
+			continue
+		}
+		operation := operationFor(&pathItem, route.method)
+		if operation == nil {
+			continue
+		}
+
+		note := permissionNote(route.access)
+		if !strings.Contains(operation.Description, note) {
+			operation.Description = strings.TrimSpace(operation.Description + " " + note)
+		}
+	}
+}
+
+// permissionNote describes the permissions a route requires, e.g. "Required permissions: any of 501 (Crear Usuario), 502 (Ver Usuario por ID)."
+func permissionNote(access routeAccess) string {
+	names := make([]string, 0, len(access.permissionIds))
+	for _, permissionId := range access.permissionIds {
+		name := fmt.Sprintf("%d", permissionId)
+		if permissionModel := model.FindPermissionById(permissionId); permissionModel != nil {
+			name += " (" + permissionModel.Name + ")"
+		}
+		names = append(names, name)
+	}
+
+	var note string
+	switch {
+	case len(names) == 1:
+		note = "Required permission: " + names[0]
+	case access.mode == requireAny:
+		note = "Required permissions: any of " + strings.Join(names, ", ")
+	default:
+		note = "Required permissions: all of " + strings.Join(names, ", ")
+	}
+	if access.kind == accessPolicy {
+		note += ", or an access policy for it"
+	}
+	return note + "."
+}
+
+func operationFor(pathItem *openapi_spec.PathItemEntity, method string) *openapi_spec.OperationEntity {
+	switch method {
+	case http.MethodGet:
+		return pathItem.Get
+	case http.MethodPost:
+		return pathItem.Post
+	case http.MethodPut:
+		return pathItem.Put
+	case http.MethodPatch:
+		return pathItem.Patch
+	case http.MethodDelete:
+		return pathItem.Delete
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package router
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func noContent(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+
+func TestApiRouterHasNoDrift(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if err := ApiRouter(gin.New()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPermissionEndpointsListsEachPermissionOfASet(t *testing.T) {
+	routes := newRouteRegistry(gin.New())
+	routes.GET("/any", anyPermission(model.GetUserById, model.GetUsersPaginated), noContent)
+	routes.GET("/all", allPermissions(model.GetUserById, model.GetRoleById), noContent)
+
+	endpoints := routes.permissionEndpoints()
+	tests := []struct {
+		permissionId int
+		want         []string
+	}{
+		{model.GetUserById, []string{"/any", "/all"}},
+		{model.GetUsersPaginated, []string{"/any"}},
+		{model.GetRoleById, []string{"/all"}},
+	}
+	for _, test := range tests {
+		var paths []string
+		for _, endpoint := range endpoints[test.permissionId] {
+			paths = append(paths, endpoint.Path)
+		}
+		if !slices.Equal(paths, test.want) {
+			t.Errorf("endpoints of %d = %v, want %v", test.permissionId, paths, test.want)
+		}
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	tests := []struct {
+		name    string
+		access  routeAccess
+		wantErr string
+	}{
+		{"unknown permission in any set", anyPermission(model.GetUserById, 599), "requires permission 599"},
+		{"unknown permission in all set", allPermissions(599, model.GetUserById), "requires permission 599"},
+		{"empty permission set", routeAccess{kind: accessPermission}, "declares no permission"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			routes := newRouteRegistry(gin.New())
+			routes.GET("/drift", test.access, noContent)
+
+			err := routes.checkDrift()
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("checkDrift() error = %v, want it to contain %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestPermissionNote(t *testing.T) {
+	tests := []struct {
+		name   string
+		access routeAccess
+		want   string
+	}{
+		{"single permission", permission(model.GetUserById), "Required permission: 502 (Ver Usuario por ID)."},
+		{"any of", anyPermission(model.CreateUser, model.GetUserById), "Required permissions: any of 501 (Crear Usuario), 502 (Ver Usuario por ID)."},
+		{"all of", allPermissions(model.CreateUser, model.GetUserById), "Required permissions: all of 501 (Crear Usuario), 502 (Ver Usuario por ID)."},
+		{"policy", permissionOrPolicy(model.GetUserById, nil), "Required permission: 502 (Ver Usuario por ID), or an access policy for it."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := permissionNote(test.access); got != test.want {
+				t.Fatalf("permissionNote() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
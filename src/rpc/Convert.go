@@ -0,0 +1,164 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/authz"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/role"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/rpc/userv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Conversiones entre los DTOs de los servicios y los mensajes generados desde user_service.proto
+
+func toUserMessage(response *user.GetUserByIdResponse) *userv1.User {
+	message := &userv1.User{
+		Id:                     response.Id,
+		Email:                  response.Email,
+		FullName:               response.FullName,
+		ImageUrl:               response.ImageUrl,
+		ThumbnailUrls:          response.ThumbnailUrls,
+		PictureUrl:             response.PictureUrl,
+		FavoriteNewsArticleIds: response.FavoriteNewsArticleIds,
+		CreatedAt:              timestamppb.New(response.CreatedAt),
+		UpdatedAt:              timestamppb.New(response.UpdatedAt),
+		DeniedPermissionIds:    toInt32s(response.DeniedPermissionIds),
+		Status:                 response.Status,
+		StatusReason:           response.StatusReason,
+		SuspendedUntil:         toTimestampPtr(response.SuspendedUntil),
+		PurgeAt:                toTimestampPtr(response.PurgeAt),
+	}
+	if response.Roles != nil {
+		for i := range *response.Roles {
+			message.Roles = append(message.Roles, toRoleMessageFromModel(&(*response.Roles)[i]))
+		}
+	}
+	for _, inherited := range response.InheritedRoles {
+		message.InheritedRoles = append(message.InheritedRoles, &userv1.InheritedRole{
+			Id:        inherited.Id,
+			Code:      inherited.Code,
+			GroupId:   inherited.GroupId,
+			GroupName: inherited.GroupName,
+		})
+	}
+	for _, assignment := range response.RoleAssignments {
+		message.RoleAssignments = append(message.RoleAssignments, &userv1.RoleAssignment{
+			RoleId:     assignment.RoleId,
+			ValidFrom:  timestamppb.New(assignment.ValidFrom),
+			ValidUntil: timestamppb.New(assignment.ValidUntil),
+			GrantedBy:  assignment.GrantedBy,
+			GrantedAt:  timestamppb.New(assignment.GrantedAt),
+		})
+	}
+	return message
+}
+
+func toRoleMessage(response *role.GetRoleByIdResponse) *userv1.Role {
+	return &userv1.Role{
+		Id:                  response.Id,
+		Code:                response.Code,
+		Permissions:         toPermissionMessages(response.Permissions),
+		PermissionGroups:    response.PermissionGroups,
+		DeniedPermissionIds: toInt32s(response.DeniedPermissionIds),
+		Privileged:          response.Privileged,
+		OrganizationId:      response.OrganizationId,
+		System:              response.System,
+	}
+}
+
+func toRoleMessageFromModel(roleModel *model.Role) *userv1.Role {
+	return &userv1.Role{
+		Id:                  roleModel.Id,
+		Code:                roleModel.Code,
+		Permissions:         toPermissionMessages(roleModel.Permissions),
+		PermissionGroups:    roleModel.PermissionGroups,
+		DeniedPermissionIds: toInt32s(roleModel.DeniedPermissionIds),
+		Privileged:          roleModel.Privileged,
+		OrganizationId:      roleModel.OrganizationId,
+		System:              roleModel.System,
+	}
+}
+
+func toPermissionMessages(permissions *[]model.Permission) []*userv1.Permission {
+	if permissions == nil {
+		return nil
+	}
+	messages := make([]*userv1.Permission, 0, len(*permissions))
+	for _, permission := range *permissions {
+		messages = append(messages, &userv1.Permission{
+			Id:          int32(permission.Id),
+			Name:        permission.Name,
+			Description: permission.Description,
+			Service:     permission.Service,
+			Deprecated:  permission.Deprecated,
+		})
+	}
+	return messages
+}
+
+func toCheckRequest(request *userv1.CheckPermissionRequest) *authz.CheckRequest {
+	checkRequest := &authz.CheckRequest{
+		Subject: authz.Subject{
+			UserId: request.GetSubject().GetUserId(),
+			Token:  request.GetSubject().GetToken(),
+		},
+		PermissionIds:  toInts(request.GetPermissionIds()),
+		OrganizationId: request.GetOrganizationId(),
+	}
+	if resource := request.GetResource(); resource != nil {
+		checkRequest.Resource = &authz.Resource{
+			Service: resource.GetService(),
+			Method:  resource.GetMethod(),
+			Path:    resource.GetPath(),
+		}
+	}
+	return checkRequest
+}
+
+func toCheckPermissionResponse(response *authz.CheckResponse) *userv1.CheckPermissionResponse {
+	message := &userv1.CheckPermissionResponse{
+		Allowed:   response.Allowed,
+		Reason:    response.Reason,
+		SubjectId: response.SubjectId,
+		Decisions: make([]*userv1.PermissionDecision, 0, len(response.Decisions)),
+	}
+	for _, decision := range response.Decisions {
+		message.Decisions = append(message.Decisions, &userv1.PermissionDecision{
+			PermissionId: int32(decision.PermissionId),
+			Allowed:      decision.Allowed,
+			Reason:       decision.Reason,
+		})
+	}
+	return message
+}
+
+func toInt32s(values []int) []int32 {
+	if values == nil {
+		return nil
+	}
+	result := make([]int32, 0, len(values))
+	for _, value := range values {
+		result = append(result, int32(value))
+	}
+	return result
+}
+
+func toInts(values []int32) []int {
+	if values == nil {
+		return nil
+	}
+	result := make([]int, 0, len(values))
+	for _, value := range values {
+		result = append(result, int(value))
+	}
+	return result
+}
+
+func toTimestampPtr(value *time.Time) *timestamppb.Timestamp {
+	if value == nil {
+		return nil
+	}
+	return timestamppb.New(*value)
+}
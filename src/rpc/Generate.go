@@ -0,0 +1,5 @@
+package rpc
+
+// El contrato del servicio está en proto/ecommerce/user/v1/user_service.proto. El paquete userv1 se genera
+// con protoc, protoc-gen-go y protoc-gen-go-grpc desde la raíz del módulo con `go generate ./src/rpc`.
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/ruiborda/ecommerce-user-service --go-grpc_out=../.. --go-grpc_opt=module=github.com/ruiborda/ecommerce-user-service ecommerce/user/v1/user_service.proto
@@ -0,0 +1,117 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/rpc/userv1"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
+	"github.com/ruiborda/go-jwt/src/domain/entity"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodRule describe la autenticación requerida por un método, equivalente a los middlewares de Gin
+type methodRule struct {
+	// serviceAccount indica que el método es solo para otros microservicios
+	serviceAccount bool
+	// permissionId es el permiso requerido en el JWT cuando el método no es de service account
+	permissionId int
+}
+
+var methodRules = map[string]methodRule{
+	userv1.UserService_GetUser_FullMethodName:         {permissionId: model.GetUserById},
+	userv1.UserService_GetUsersByIds_FullMethodName:   {permissionId: model.GetUserById},
+	userv1.UserService_GetRolesByIds_FullMethodName:   {permissionId: model.GetRoleById},
+	userv1.UserService_CheckPermission_FullMethodName: {serviceAccount: true},
+	userv1.UserService_VerifyToken_FullMethodName:     {serviceAccount: true},
+}
+
+type claimsContextKey struct{}
+
+// ClaimsFromContext devuelve los claims del JWT validado por el interceptor
+func ClaimsFromContext(ctx context.Context) (*entity.JWTClaims[*auth.JwtPrivateClaims], bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*entity.JWTClaims[*auth.JwtPrivateClaims])
+	return claims, ok
+}
+
+// AuthUnaryInterceptor aplica la misma validación de JWT, permisos y service accounts que la API REST
+func AuthUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		rule, ok := methodRules[info.FullMethod]
+		if !ok {
+			// Los métodos sin regla se rechazan por defecto
+			return nil, status.Error(codes.PermissionDenied, "method is not exposed")
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		if rule.serviceAccount {
+			serviceName := security.FindServiceAccount(firstMetadataValue(md, strings.ToLower(security.ServiceAccountHeader)))
+			if serviceName == "" {
+				return nil, status.Error(codes.Unauthenticated, "invalid or missing service account key")
+			}
+			return handler(ctx, req)
+		}
+
+		authHeader := firstMetadataValue(md, "authorization")
+		if authHeader == "" {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata is required")
+		}
+		token, ok := security.BearerToken(authHeader)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization format")
+		}
+
+		claims, err := security.VerifyToken(token)
+		if err != nil {
+			switch {
+			case errors.Is(err, security.ErrSecretNotConfigured):
+				return nil, status.Error(codes.Internal, "internal server error")
+			case errors.Is(err, security.ErrTokenStale):
+				return nil, tokenStaleError(err)
+			}
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		// Los tokens emitidos antes de que cambiaran los roles o permisos del usuario se rechazan, como en RequireJWT
+		if err := security.CheckAuthzVersion(claims); err != nil {
+			if errors.Is(err, security.ErrTokenStale) {
+				return nil, tokenStaleError(err)
+			}
+			slog.Error("Failed to check token authorization version", "method", info.FullMethod, "error", err)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+
+		if !security.HasPermission(claims, rule.permissionId) {
+			slog.Info("Access denied: missing required permission", "requiredPermission", rule.permissionId, "method", info.FullMethod, "subject", claims.RegisteredClaims.Subject)
+			return nil, status.Error(codes.PermissionDenied, "you don't have permission to access this resource")
+		}
+
+		return handler(context.WithValue(ctx, claimsContextKey{}, claims), req)
+	}
+}
+
+// tokenStaleError es Unauthenticated con el motivo TOKEN_STALE en los detalles, para que el cliente sepa que debe renovar el token
+func tokenStaleError(err error) error {
+	stale := status.New(codes.Unauthenticated, err.Error())
+	if withDetails, detailsErr := stale.WithDetails(&errdetails.ErrorInfo{Reason: security.TokenStaleCode}); detailsErr == nil {
+		return withDetails.Err()
+	}
+	return stale.Err()
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
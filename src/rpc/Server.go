@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/rpc/userv1"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/ecommerce-user-service/src/service/impl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxIdsPerRequest limita el número de IDs en las consultas por lote
+const MaxIdsPerRequest = 100
+
+type userServiceServer struct {
+	userv1.UnimplementedUserServiceServer
+	userService          service.UserService
+	roleService          service.RoleService
+	authorizationService service.AuthorizationService
+}
+
+// NewServer crea el servidor gRPC con el servicio registrado y los interceptores de autenticación
+func NewServer() *grpc.Server {
+	return newServer(&userServiceServer{
+		userService:          impl.NewUserServiceImpl(),
+		roleService:          impl.NewRoleServiceImpl(),
+		authorizationService: impl.NewAuthorizationServiceImpl(),
+	})
+}
+
+func newServer(implementation userv1.UserServiceServer) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(AuthUnaryInterceptor()))
+	userv1.RegisterUserServiceServer(server, implementation)
+	return server
+}
+
+func (s *userServiceServer) GetUser(ctx context.Context, request *userv1.GetUserRequest) (*userv1.User, error) {
+	if _, err := uuid.Parse(request.GetId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid UUID format")
+	}
+
+	response := s.userService.GetUserById(request.GetId())
+	if response == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return toUserMessage(response), nil
+}
+
+func (s *userServiceServer) GetUsersByIds(ctx context.Context, request *userv1.GetUsersByIdsRequest) (*userv1.GetUsersByIdsResponse, error) {
+	if err := validateIds(request.GetIds()); err != nil {
+		return nil, err
+	}
+
+	response := &userv1.GetUsersByIdsResponse{}
+	for _, user := range s.userService.GetUsersByIds(request.GetIds()) {
+		response.Users = append(response.Users, toUserMessage(user))
+	}
+	return response, nil
+}
+
+func (s *userServiceServer) GetRolesByIds(ctx context.Context, request *userv1.GetRolesByIdsRequest) (*userv1.GetRolesByIdsResponse, error) {
+	if err := validateIds(request.GetIds()); err != nil {
+		return nil, err
+	}
+
+	response := &userv1.GetRolesByIdsResponse{}
+	for _, role := range s.roleService.GetRolesByIds(request.GetIds()) {
+		response.Roles = append(response.Roles, toRoleMessage(role))
+	}
+	return response, nil
+}
+
+func (s *userServiceServer) CheckPermission(ctx context.Context, request *userv1.CheckPermissionRequest) (*userv1.CheckPermissionResponse, error) {
+	response, err := s.authorizationService.Check(toCheckRequest(request))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAuthorizationRequest) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "failed to evaluate authorization")
+	}
+	return toCheckPermissionResponse(response), nil
+}
+
+func (s *userServiceServer) VerifyToken(ctx context.Context, request *userv1.VerifyTokenRequest) (*userv1.VerifyTokenResponse, error) {
+	if request.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	claims, err := security.VerifyToken(request.GetToken())
+	if err != nil {
+		if errors.Is(err, security.ErrSecretNotConfigured) {
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		return &userv1.VerifyTokenResponse{Valid: false, Reason: err.Error()}, nil
+	}
+	if err := security.CheckAuthzVersion(claims); err != nil {
+		if errors.Is(err, security.ErrTokenStale) {
+			return &userv1.VerifyTokenResponse{Valid: false, Reason: err.Error()}, nil
+		}
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	response := &userv1.VerifyTokenResponse{
+		Valid:     true,
+		UserId:    claims.RegisteredClaims.Subject,
+		ExpiresAt: claims.RegisteredClaims.ExpirationTime,
+	}
+	if claims.PrivateClaims != nil {
+		response.Email = claims.PrivateClaims.Email
+		response.Roles = claims.PrivateClaims.Roles
+		response.PermissionIds = toInt32s(claims.PrivateClaims.PermissionIds)
+	}
+	return response, nil
+}
+
+func validateIds(ids []string) error {
+	if len(ids) > MaxIdsPerRequest {
+		return status.Errorf(codes.InvalidArgument, "at most %d ids are allowed", MaxIdsPerRequest)
+	}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid UUID format: %s", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,446 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/authz"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/role"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/rpc/userv1"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+	"github.com/ruiborda/go-jwt/src/application/ports/input"
+	"github.com/ruiborda/go-jwt/src/domain/entity"
+	input2 "github.com/ruiborda/go-jwt/src/infrastructure/adapters/input"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testJwtSecret         = "test-secret"
+	testServiceAccountKey = "orders-key"
+	testUserId            = "7f1c2d9e-6a0b-4c57-9d1e-3b2a4f5c6d7e"
+	testRoleId            = "0b8e5a1f-2c3d-4e5f-8a9b-1c2d3e4f5a6b"
+	missingUserId         = "00000000-0000-4000-8000-000000000000"
+)
+
+type fakeUserService struct {
+	service.UserService
+	users map[string]*user.GetUserByIdResponse
+}
+
+func (s *fakeUserService) GetUserById(id string) *user.GetUserByIdResponse {
+	return s.users[id]
+}
+
+func (s *fakeUserService) GetUsersByIds(ids []string) []*user.GetUserByIdResponse {
+	var users []*user.GetUserByIdResponse
+	for _, id := range ids {
+		if found, ok := s.users[id]; ok {
+			users = append(users, found)
+		}
+	}
+	return users
+}
+
+type fakeRoleService struct {
+	service.RoleService
+	roles map[string]*role.GetRoleByIdResponse
+}
+
+func (s *fakeRoleService) GetRolesByIds(ids []string) []*role.GetRoleByIdResponse {
+	var roles []*role.GetRoleByIdResponse
+	for _, id := range ids {
+		if found, ok := s.roles[id]; ok {
+			roles = append(roles, found)
+		}
+	}
+	return roles
+}
+
+type fakeAuthorizationService struct {
+	service.AuthorizationService
+	lastRequest *authz.CheckRequest
+}
+
+func (s *fakeAuthorizationService) Check(request *authz.CheckRequest) (*authz.CheckResponse, error) {
+	s.lastRequest = request
+	if len(request.PermissionIds) == 0 && request.Resource == nil {
+		return nil, fmt.Errorf("%w: permissionIds or resource is required", service.ErrInvalidAuthorizationRequest)
+	}
+	response := &authz.CheckResponse{Allowed: true, Reason: "all permissions granted", SubjectId: request.Subject.UserId}
+	for _, permissionId := range request.PermissionIds {
+		response.Decisions = append(response.Decisions, authz.PermissionDecision{PermissionId: permissionId, Allowed: true, Reason: "granted by role ADMIN"})
+	}
+	return response, nil
+}
+
+// startTestServer levanta el servidor gRPC sobre bufconn con servicios en memoria
+func startTestServer(t *testing.T) (userv1.UserServiceClient, *fakeAuthorizationService) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", testJwtSecret)
+	t.Setenv("SERVICE_ACCOUNT_KEYS", "orders="+testServiceAccountKey)
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	authorizationService := &fakeAuthorizationService{}
+	server := newServer(&userServiceServer{
+		userService: &fakeUserService{users: map[string]*user.GetUserByIdResponse{
+			testUserId: {
+				Id:        testUserId,
+				Email:     "ana@example.com",
+				FullName:  "Ana",
+				Status:    model.UserStatusActive,
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Roles:     &[]model.Role{{Id: testRoleId, Code: "SUPPORT"}},
+			},
+		}},
+		roleService: &fakeRoleService{roles: map[string]*role.GetRoleByIdResponse{
+			testRoleId: {
+				Id:                  testRoleId,
+				Code:                "SUPPORT",
+				Permissions:         &[]model.Permission{{Id: model.GetUserById, Name: "Obtener Usuario"}},
+				DeniedPermissionIds: []int{model.DeleteUser},
+			},
+		}},
+		authorizationService: authorizationService,
+	})
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	connection, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { connection.Close() })
+
+	return userv1.NewUserServiceClient(connection), authorizationService
+}
+
+func signTestToken(t *testing.T, secret string, privateClaims *auth.JwtPrivateClaims) string {
+	t.Helper()
+	inputAdapter := input2.NewJwtInputAdapter[*auth.JwtPrivateClaims](input.NewJWTHS256InputPort[*auth.JwtPrivateClaims]([]byte(secret)))
+	jwt, err := inputAdapter.CreateJwt(
+		&entity.JOSEHeader{Algorithm: "HS256", Type: "JWT"},
+		&entity.JWTClaims[*auth.JwtPrivateClaims]{
+			RegisteredClaims: &entity.RegisteredClaims{
+				Issuer:         "ecommerce-user-service",
+				Subject:        testUserId,
+				ExpirationTime: time.Now().Add(time.Hour).Unix(),
+			},
+			PrivateClaims: privateClaims,
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwt.Token.GetToken()
+}
+
+func withBearer(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func withServiceAccount(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-service-account-key", key)
+}
+
+func TestAuthentication(t *testing.T) {
+	client, _ := startTestServer(t)
+	reader := signTestToken(t, testJwtSecret, &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById}})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		call func(ctx context.Context) error
+		want codes.Code
+	}{
+		{"missing authorization", context.Background(), getUser(client), codes.Unauthenticated},
+		{"invalid authorization format", metadata.AppendToOutgoingContext(context.Background(), "authorization", reader), getUser(client), codes.Unauthenticated},
+		{"token signed with another secret", withBearer(signTestToken(t, "other-secret", &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById}})), getUser(client), codes.Unauthenticated},
+		{"missing permission", withBearer(signTestToken(t, testJwtSecret, &auth.JwtPrivateClaims{PermissionIds: []int{model.GetRoleById}})), getUser(client), codes.PermissionDenied},
+		{"denied permission", withBearer(signTestToken(t, testJwtSecret, &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById}, DeniedPermissionIds: []int{model.GetUserById}})), getUser(client), codes.PermissionDenied},
+		{"other permission for roles", withBearer(reader), getRoles(client), codes.PermissionDenied},
+		{"service account key on user method", withServiceAccount(testServiceAccountKey), getUser(client), codes.Unauthenticated},
+		{"jwt on service account method", withBearer(reader), verifyToken(client, reader), codes.Unauthenticated},
+		{"unknown service account key", withServiceAccount("wrong-key"), verifyToken(client, reader), codes.Unauthenticated},
+		{"granted permission", withBearer(reader), getUser(client), codes.OK},
+		{"valid service account", withServiceAccount(testServiceAccountKey), verifyToken(client, reader), codes.OK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := status.Code(test.call(test.ctx)); got != test.want {
+				t.Fatalf("code = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// fakeAuthzVersionSource devuelve versiones fijas de usuarios y roles
+type fakeAuthzVersionSource struct {
+	users map[string]int64
+	roles map[string]int64
+}
+
+func (s *fakeAuthzVersionSource) FindUserVersion(userId string) (int64, bool, error) {
+	version, found := s.users[userId]
+	return version, found, nil
+}
+
+func (s *fakeAuthzVersionSource) FindRoleVersions(roleIds []string) (map[string]int64, error) {
+	versions := make(map[string]int64)
+	for _, roleId := range roleIds {
+		if version, ok := s.roles[roleId]; ok {
+			versions[roleId] = version
+		}
+	}
+	return versions, nil
+}
+
+func TestAuthzVersion(t *testing.T) {
+	client, _ := startTestServer(t)
+	security.SetAuthzVersionSource(&fakeAuthzVersionSource{
+		users: map[string]int64{testUserId: 2},
+		roles: map[string]int64{testRoleId: 5},
+	})
+	t.Cleanup(func() { security.SetAuthzVersionSource(nil) })
+
+	tokenWithVersions := func(authzVersion int64, roleVersions map[string]int64) context.Context {
+		return withBearer(signTestToken(t, testJwtSecret, &auth.JwtPrivateClaims{
+			PermissionIds: []int{model.GetUserById},
+			AuthzVersion:  authzVersion,
+			RoleVersions:  roleVersions,
+		}))
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		want      codes.Code
+		wantStale bool
+	}{
+		{"current versions", tokenWithVersions(2, map[string]int64{testRoleId: 5}), codes.OK, false},
+		{"user version changed", tokenWithVersions(1, map[string]int64{testRoleId: 5}), codes.Unauthenticated, true},
+		{"role version changed", tokenWithVersions(2, map[string]int64{testRoleId: 4}), codes.Unauthenticated, true},
+		{"role no longer exists", tokenWithVersions(2, map[string]int64{missingUserId: 1}), codes.Unauthenticated, true},
+		{"token without versions", tokenWithVersions(2, nil), codes.Unauthenticated, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := getUser(client)(test.ctx)
+			if got := status.Code(err); got != test.want {
+				t.Fatalf("code = %v, want %v", got, test.want)
+			}
+			if stale := hasErrorReason(err, security.TokenStaleCode); stale != test.wantStale {
+				t.Fatalf("TOKEN_STALE reason = %v, want %v", stale, test.wantStale)
+			}
+		})
+	}
+}
+
+func hasErrorReason(err error, reason string) bool {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetReason() == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func getUser(client userv1.UserServiceClient) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := client.GetUser(ctx, &userv1.GetUserRequest{Id: testUserId})
+		return err
+	}
+}
+
+func getRoles(client userv1.UserServiceClient) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := client.GetRolesByIds(ctx, &userv1.GetRolesByIdsRequest{Ids: []string{testRoleId}})
+		return err
+	}
+}
+
+func verifyToken(client userv1.UserServiceClient, token string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := client.VerifyToken(ctx, &userv1.VerifyTokenRequest{Token: token})
+		return err
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	client, _ := startTestServer(t)
+	ctx := withBearer(signTestToken(t, testJwtSecret, &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById}}))
+
+	found, err := client.GetUser(ctx, &userv1.GetUserRequest{Id: testUserId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.GetEmail() != "ana@example.com" || found.GetStatus() != model.UserStatusActive {
+		t.Fatalf("GetUser() = %v", found)
+	}
+	if len(found.GetRoles()) != 1 || found.GetRoles()[0].GetCode() != "SUPPORT" {
+		t.Fatalf("roles = %v, want SUPPORT", found.GetRoles())
+	}
+	if !found.GetCreatedAt().AsTime().Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("createdAt = %v", found.GetCreatedAt().AsTime())
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		want codes.Code
+	}{
+		{"not found", missingUserId, codes.NotFound},
+		{"invalid id", "not-a-uuid", codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := client.GetUser(ctx, &userv1.GetUserRequest{Id: test.id})
+			if got := status.Code(err); got != test.want {
+				t.Fatalf("code = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetUsersByIds(t *testing.T) {
+	client, _ := startTestServer(t)
+	ctx := withBearer(signTestToken(t, testJwtSecret, &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById}}))
+
+	response, err := client.GetUsersByIds(ctx, &userv1.GetUsersByIdsRequest{Ids: []string{testUserId, missingUserId}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.GetUsers()) != 1 || response.GetUsers()[0].GetId() != testUserId {
+		t.Fatalf("users = %v, want only %s", response.GetUsers(), testUserId)
+	}
+
+	tooMany := make([]string, MaxIdsPerRequest+1)
+	for i := range tooMany {
+		tooMany[i] = testUserId
+	}
+	tests := []struct {
+		name string
+		ids  []string
+		want codes.Code
+	}{
+		{"too many ids", tooMany, codes.InvalidArgument},
+		{"invalid id", []string{testUserId, "not-a-uuid"}, codes.InvalidArgument},
+		{"no ids", nil, codes.OK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := client.GetUsersByIds(ctx, &userv1.GetUsersByIdsRequest{Ids: test.ids})
+			if got := status.Code(err); got != test.want {
+				t.Fatalf("code = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetRolesByIds(t *testing.T) {
+	client, _ := startTestServer(t)
+	ctx := withBearer(signTestToken(t, testJwtSecret, &auth.JwtPrivateClaims{PermissionIds: []int{model.GetRoleById}}))
+
+	response, err := client.GetRolesByIds(ctx, &userv1.GetRolesByIdsRequest{Ids: []string{testRoleId, missingUserId}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.GetRoles()) != 1 {
+		t.Fatalf("roles = %v, want one role", response.GetRoles())
+	}
+	found := response.GetRoles()[0]
+	if found.GetCode() != "SUPPORT" || len(found.GetPermissions()) != 1 || found.GetPermissions()[0].GetId() != model.GetUserById {
+		t.Fatalf("role = %v", found)
+	}
+	if len(found.GetDeniedPermissionIds()) != 1 || found.GetDeniedPermissionIds()[0] != model.DeleteUser {
+		t.Fatalf("deniedPermissionIds = %v, want [%d]", found.GetDeniedPermissionIds(), model.DeleteUser)
+	}
+
+	_, err = client.GetRolesByIds(ctx, &userv1.GetRolesByIdsRequest{Ids: []string{"not-a-uuid"}})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Fatalf("code = %v, want %v", got, codes.InvalidArgument)
+	}
+}
+
+func TestCheckPermission(t *testing.T) {
+	client, authorizationService := startTestServer(t)
+	ctx := withServiceAccount(testServiceAccountKey)
+
+	response, err := client.CheckPermission(ctx, &userv1.CheckPermissionRequest{
+		Subject:        &userv1.Subject{UserId: testUserId},
+		PermissionIds:  []int32{model.GetUserById},
+		Resource:       &userv1.Resource{Service: "orders", Method: "GET", Path: "/api/v1/orders"},
+		OrganizationId: "org-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.GetAllowed() || response.GetSubjectId() != testUserId || len(response.GetDecisions()) != 1 {
+		t.Fatalf("CheckPermission() = %v", response)
+	}
+
+	request := authorizationService.lastRequest
+	if request.Subject.UserId != testUserId || request.OrganizationId != "org-1" || request.Resource == nil || request.Resource.Path != "/api/v1/orders" {
+		t.Fatalf("check request = %+v", request)
+	}
+
+	_, err = client.CheckPermission(ctx, &userv1.CheckPermissionRequest{Subject: &userv1.Subject{UserId: testUserId}})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Fatalf("code = %v, want %v", got, codes.InvalidArgument)
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	client, _ := startTestServer(t)
+	ctx := withServiceAccount(testServiceAccountKey)
+	token := signTestToken(t, testJwtSecret, &auth.JwtPrivateClaims{Email: "ana@example.com", Roles: []string{"SUPPORT"}, PermissionIds: []int{model.GetUserById}})
+
+	tests := []struct {
+		name      string
+		token     string
+		wantValid bool
+		wantCode  codes.Code
+	}{
+		{"valid token", token, true, codes.OK},
+		{"token signed with another secret", signTestToken(t, "other-secret", &auth.JwtPrivateClaims{}), false, codes.OK},
+		{"malformed token", "not-a-jwt", false, codes.OK},
+		{"empty token", "", false, codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, err := client.VerifyToken(ctx, &userv1.VerifyTokenRequest{Token: test.token})
+			if got := status.Code(err); got != test.wantCode {
+				t.Fatalf("code = %v, want %v", got, test.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if response.GetValid() != test.wantValid {
+				t.Fatalf("valid = %v (%s), want %v", response.GetValid(), response.GetReason(), test.wantValid)
+			}
+			if test.wantValid && (response.GetUserId() != testUserId || response.GetEmail() != "ana@example.com" || len(response.GetPermissionIds()) != 1) {
+				t.Fatalf("VerifyToken() = %v", response)
+			}
+		})
+	}
+}
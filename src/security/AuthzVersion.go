@@ -0,0 +1,158 @@
+package security
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/go-jwt/src/domain/entity"
+)
+
+// ErrTokenStale indica que los roles o permisos del usuario cambiaron después de emitir el token
+var ErrTokenStale = errors.New("token authorization is outdated, log in again to refresh it")
+
+// TokenStaleCode es el código de error que indica al cliente que debe renovar el token
+const TokenStaleCode = "TOKEN_STALE"
+
+// AuthzVersionCacheTTL es el tiempo que se reutiliza una versión leída; un cambio de roles
+// tarda como máximo este tiempo en invalidar los tokens ya emitidos
+const AuthzVersionCacheTTL = 30 * time.Second
+
+// authzVersionCacheLimit es el número de entradas a partir del cual se descartan las vencidas
+const authzVersionCacheLimit = 10000
+
+// AuthzVersionSource lee las versiones de autorización actuales de usuarios y roles
+type AuthzVersionSource interface {
+	FindUserVersion(userId string) (version int64, found bool, err error)
+	FindRoleVersions(roleIds []string) (map[string]int64, error)
+}
+
+type cachedAuthzVersion struct {
+	version  int64
+	found    bool
+	loadedAt time.Time
+}
+
+var (
+	authzVersionSource AuthzVersionSource
+	authzVersionMutex  sync.Mutex
+	userVersionCache   = make(map[string]cachedAuthzVersion)
+	roleVersionCache   = make(map[string]cachedAuthzVersion)
+)
+
+// SetAuthzVersionSource configura el origen de las versiones y vacía la cache.
+// Sin origen configurado no se comprueban las versiones de los tokens.
+func SetAuthzVersionSource(source AuthzVersionSource) {
+	authzVersionMutex.Lock()
+	defer authzVersionMutex.Unlock()
+	authzVersionSource = source
+	clear(userVersionCache)
+	clear(roleVersionCache)
+}
+
+// CheckAuthzVersion devuelve ErrTokenStale si la versión del usuario o de alguno de los roles del token
+// cambió, si el usuario o un rol ya no existen, o si el token se emitió antes de incluir versiones
+func CheckAuthzVersion(claims *entity.JWTClaims[*auth.JwtPrivateClaims]) error {
+	authzVersionMutex.Lock()
+	source := authzVersionSource
+	authzVersionMutex.Unlock()
+	if source == nil {
+		return nil
+	}
+
+	if claims == nil || claims.RegisteredClaims == nil || claims.PrivateClaims == nil || claims.PrivateClaims.RoleVersions == nil {
+		return ErrTokenStale
+	}
+
+	userVersion, err := cachedUserVersion(source, claims.RegisteredClaims.Subject)
+	if err != nil {
+		return err
+	}
+	if !userVersion.found || userVersion.version != claims.PrivateClaims.AuthzVersion {
+		return ErrTokenStale
+	}
+
+	roleVersions, err := cachedRoleVersions(source, claims.PrivateClaims.RoleVersions)
+	if err != nil {
+		return err
+	}
+	for roleId, version := range claims.PrivateClaims.RoleVersions {
+		current := roleVersions[roleId]
+		if !current.found || current.version != version {
+			return ErrTokenStale
+		}
+	}
+	return nil
+}
+
+func cachedUserVersion(source AuthzVersionSource, userId string) (cachedAuthzVersion, error) {
+	now := time.Now()
+
+	authzVersionMutex.Lock()
+	cached, ok := userVersionCache[userId]
+	authzVersionMutex.Unlock()
+	if ok && now.Sub(cached.loadedAt) < AuthzVersionCacheTTL {
+		return cached, nil
+	}
+
+	version, found, err := source.FindUserVersion(userId)
+	if err != nil {
+		return cachedAuthzVersion{}, err
+	}
+	cached = cachedAuthzVersion{version: version, found: found, loadedAt: now}
+
+	authzVersionMutex.Lock()
+	defer authzVersionMutex.Unlock()
+	pruneAuthzVersionCache(userVersionCache, now)
+	userVersionCache[userId] = cached
+	return cached, nil
+}
+
+func cachedRoleVersions(source AuthzVersionSource, tokenVersions map[string]int64) (map[string]cachedAuthzVersion, error) {
+	now := time.Now()
+	versions := make(map[string]cachedAuthzVersion, len(tokenVersions))
+	var missing []string
+
+	authzVersionMutex.Lock()
+	for roleId := range tokenVersions {
+		cached, ok := roleVersionCache[roleId]
+		if ok && now.Sub(cached.loadedAt) < AuthzVersionCacheTTL {
+			versions[roleId] = cached
+		} else {
+			missing = append(missing, roleId)
+		}
+	}
+	authzVersionMutex.Unlock()
+	if len(missing) == 0 {
+		return versions, nil
+	}
+
+	loaded, err := source.FindRoleVersions(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	authzVersionMutex.Lock()
+	defer authzVersionMutex.Unlock()
+	pruneAuthzVersionCache(roleVersionCache, now)
+	for _, roleId := range missing {
+		version, found := loaded[roleId]
+		cached := cachedAuthzVersion{version: version, found: found, loadedAt: now}
+		roleVersionCache[roleId] = cached
+		versions[roleId] = cached
+	}
+	return versions, nil
+}
+
+// pruneAuthzVersionCache descarta las entradas vencidas cuando la cache alcanza su límite
+func pruneAuthzVersionCache(cache map[string]cachedAuthzVersion, now time.Time) {
+	if len(cache) < authzVersionCacheLimit {
+		return
+	}
+	for key, cached := range cache {
+		if now.Sub(cached.loadedAt) >= AuthzVersionCacheTTL {
+			delete(cache, key)
+		}
+	}
+}
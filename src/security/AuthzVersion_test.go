@@ -0,0 +1,101 @@
+package security
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/go-jwt/src/domain/entity"
+)
+
+// fakeAuthzVersionSource cuenta las lecturas para comprobar la cache
+type fakeAuthzVersionSource struct {
+	users     map[string]int64
+	roles     map[string]int64
+	err       error
+	userReads int
+}
+
+func (s *fakeAuthzVersionSource) FindUserVersion(userId string) (int64, bool, error) {
+	s.userReads++
+	version, found := s.users[userId]
+	return version, found, s.err
+}
+
+func (s *fakeAuthzVersionSource) FindRoleVersions(roleIds []string) (map[string]int64, error) {
+	versions := make(map[string]int64)
+	for _, roleId := range roleIds {
+		if version, ok := s.roles[roleId]; ok {
+			versions[roleId] = version
+		}
+	}
+	return versions, s.err
+}
+
+func versionedClaims(userId string, authzVersion int64, roleVersions map[string]int64) *entity.JWTClaims[*auth.JwtPrivateClaims] {
+	return &entity.JWTClaims[*auth.JwtPrivateClaims]{
+		RegisteredClaims: &entity.RegisteredClaims{Subject: userId},
+		PrivateClaims:    &auth.JwtPrivateClaims{AuthzVersion: authzVersion, RoleVersions: roleVersions},
+	}
+}
+
+func TestCheckAuthzVersion(t *testing.T) {
+	sourceErr := errors.New("firestore unavailable")
+	tests := []struct {
+		name    string
+		source  *fakeAuthzVersionSource
+		claims  *entity.JWTClaims[*auth.JwtPrivateClaims]
+		wantErr error
+	}{
+		{"current versions", &fakeAuthzVersionSource{users: map[string]int64{"u1": 2}, roles: map[string]int64{"r1": 4}}, versionedClaims("u1", 2, map[string]int64{"r1": 4}), nil},
+		{"no roles", &fakeAuthzVersionSource{users: map[string]int64{"u1": 2}}, versionedClaims("u1", 2, map[string]int64{}), nil},
+		{"user version changed", &fakeAuthzVersionSource{users: map[string]int64{"u1": 3}, roles: map[string]int64{"r1": 4}}, versionedClaims("u1", 2, map[string]int64{"r1": 4}), ErrTokenStale},
+		{"role version changed", &fakeAuthzVersionSource{users: map[string]int64{"u1": 2}, roles: map[string]int64{"r1": 5}}, versionedClaims("u1", 2, map[string]int64{"r1": 4}), ErrTokenStale},
+		{"user no longer exists", &fakeAuthzVersionSource{roles: map[string]int64{"r1": 4}}, versionedClaims("u1", 2, map[string]int64{"r1": 4}), ErrTokenStale},
+		{"role no longer exists", &fakeAuthzVersionSource{users: map[string]int64{"u1": 2}}, versionedClaims("u1", 2, map[string]int64{"r1": 4}), ErrTokenStale},
+		{"token without role versions", &fakeAuthzVersionSource{users: map[string]int64{"u1": 2}}, versionedClaims("u1", 2, nil), ErrTokenStale},
+		{"token without private claims", &fakeAuthzVersionSource{users: map[string]int64{"u1": 2}}, &entity.JWTClaims[*auth.JwtPrivateClaims]{RegisteredClaims: &entity.RegisteredClaims{Subject: "u1"}}, ErrTokenStale},
+		{"source error", &fakeAuthzVersionSource{users: map[string]int64{"u1": 2}, err: sourceErr}, versionedClaims("u1", 2, map[string]int64{}), sourceErr},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetAuthzVersionSource(test.source)
+			t.Cleanup(func() { SetAuthzVersionSource(nil) })
+
+			if err := CheckAuthzVersion(test.claims); !errors.Is(err, test.wantErr) {
+				t.Fatalf("CheckAuthzVersion() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckAuthzVersionWithoutSource(t *testing.T) {
+	SetAuthzVersionSource(nil)
+	if err := CheckAuthzVersion(versionedClaims("u1", 2, nil)); err != nil {
+		t.Fatalf("CheckAuthzVersion() error = %v, want nil without a version source", err)
+	}
+}
+
+func TestCheckAuthzVersionCachesVersions(t *testing.T) {
+	source := &fakeAuthzVersionSource{users: map[string]int64{"u1": 2}, roles: map[string]int64{"r1": 4}}
+	SetAuthzVersionSource(source)
+	t.Cleanup(func() { SetAuthzVersionSource(nil) })
+
+	claims := versionedClaims("u1", 2, map[string]int64{"r1": 4})
+	for range 3 {
+		if err := CheckAuthzVersion(claims); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if source.userReads != 1 {
+		t.Fatalf("user version read %d times, want 1", source.userReads)
+	}
+
+	// Configurar otro origen vacía la cache
+	source.users["u1"] = 3
+	SetAuthzVersionSource(source)
+	if err := CheckAuthzVersion(claims); !errors.Is(err, ErrTokenStale) {
+		t.Fatalf("CheckAuthzVersion() error = %v, want ErrTokenStale", err)
+	}
+}
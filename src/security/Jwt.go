@@ -0,0 +1,87 @@
+package security
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/go-jwt/src/application/ports/input"
+	"github.com/ruiborda/go-jwt/src/domain/entity"
+	input2 "github.com/ruiborda/go-jwt/src/infrastructure/adapters/input"
+)
+
+var (
+	ErrSecretNotConfigured = errors.New("JWT secret not configured")
+	ErrInvalidToken        = errors.New("invalid or expired token")
+	ErrInvalidTokenFormat  = errors.New("invalid token format")
+)
+
+// BearerToken extrae el token de un header Authorization con formato "Bearer <token>"
+func BearerToken(authHeader string) (string, bool) {
+	tokenParts := strings.Split(authHeader, " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		return "", false
+	}
+	return tokenParts[1], true
+}
+
+// VerifyToken valida la firma y expiración del token y devuelve sus claims, con los permisos
+// de los tokens compactos ya expandidos en PermissionIds
+func VerifyToken(token string) (*entity.JWTClaims[*auth.JwtPrivateClaims], error) {
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		slog.Error("JWT_SECRET environment variable is not set")
+		return nil, ErrSecretNotConfigured
+	}
+
+	inputPort := input.NewJWTHS256InputPort[*auth.JwtPrivateClaims]([]byte(jwtSecret))
+	inputAdapter := input2.NewJwtInputAdapter[*auth.JwtPrivateClaims](inputPort)
+
+	if err := inputAdapter.VerifyToken(token); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	jwt := entity.NewJwtFromToken[*auth.JwtPrivateClaims](token)
+	if jwt == nil {
+		return nil, ErrInvalidTokenFormat
+	}
+	if jwt.Claims != nil {
+		if err := ExpandPermissionClaims(jwt.Claims.PrivateClaims); err != nil {
+			return nil, err
+		}
+	}
+
+	return jwt.Claims, nil
+}
+
+// HasPermission indica si los claims del token incluyen el permiso y no está denegado
+func HasPermission(claims *entity.JWTClaims[*auth.JwtPrivateClaims], permissionId int) bool {
+	if claims == nil || claims.PrivateClaims == nil {
+		return false
+	}
+	// Una denegación explícita prevalece sobre cualquier concesión
+	if IsDenied(claims, permissionId) {
+		return false
+	}
+	for _, id := range claims.PrivateClaims.PermissionIds {
+		if id == permissionId {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDenied indica si el permiso está denegado explícitamente en los claims del token
+func IsDenied(claims *entity.JWTClaims[*auth.JwtPrivateClaims], permissionId int) bool {
+	if claims == nil || claims.PrivateClaims == nil {
+		return false
+	}
+	for _, id := range claims.PrivateClaims.DeniedPermissionIds {
+		if id == permissionId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/go-jwt/src/domain/entity"
+)
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     *auth.JwtPrivateClaims
+		permission int
+		want       bool
+		wantDenied bool
+	}{
+		{"granted", &auth.JwtPrivateClaims{PermissionIds: []int{501, 504}}, 504, true, false},
+		{"not granted", &auth.JwtPrivateClaims{PermissionIds: []int{501}}, 504, false, false},
+		{"deny beats grant", &auth.JwtPrivateClaims{PermissionIds: []int{501, 504}, DeniedPermissionIds: []int{504}}, 504, false, true},
+		{"deny without grant", &auth.JwtPrivateClaims{DeniedPermissionIds: []int{504}}, 504, false, true},
+		{"other permission denied", &auth.JwtPrivateClaims{PermissionIds: []int{501, 504}, DeniedPermissionIds: []int{501}}, 504, true, false},
+		{"no private claims", nil, 504, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims := &entity.JWTClaims[*auth.JwtPrivateClaims]{PrivateClaims: test.claims}
+			if got := HasPermission(claims, test.permission); got != test.want {
+				t.Fatalf("HasPermission() = %v, want %v", got, test.want)
+			}
+			if got := IsDenied(claims, test.permission); got != test.wantDenied {
+				t.Fatalf("IsDenied() = %v, want %v", got, test.wantDenied)
+			}
+		})
+	}
+
+	if HasPermission(nil, 504) || IsDenied(nil, 504) {
+		t.Fatal("nil claims must not grant or deny")
+	}
+}
@@ -0,0 +1,35 @@
+package security
+
+import (
+	"slices"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/go-jwt/src/domain/entity"
+)
+
+// OrganizationHeader es el header con el que una petición selecciona la organización en la que actúa
+const OrganizationHeader = "X-Organization-Id"
+
+// HasPermissionInOrganization evalúa el permiso dentro de la organización seleccionada.
+// Los permisos globales siguen aplicando; los de la membresía solo en su organización.
+// Sin organización equivale a HasPermission.
+func HasPermissionInOrganization(claims *entity.JWTClaims[*auth.JwtPrivateClaims], organizationId string, permissionId int) bool {
+	if organizationId == "" {
+		return HasPermission(claims, permissionId)
+	}
+	if claims == nil || claims.PrivateClaims == nil || IsDenied(claims, permissionId) {
+		return false
+	}
+
+	organization := claims.PrivateClaims.Organizations[organizationId]
+	if organization != nil && slices.Contains(organization.DeniedPermissionIds, permissionId) {
+		return false
+	}
+	if HasPermission(claims, permissionId) {
+		return true
+	}
+	return organization != nil &&
+		model.IsOrganizationScoped(permissionId) &&
+		slices.Contains(organization.PermissionIds, permissionId)
+}
@@ -0,0 +1,136 @@
+package security
+
+import (
+	"encoding/base64"
+	"os"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+// PermissionEncodingEnv selecciona cómo se guardan los permisos en los tokens: "compact" para el bitset,
+// cualquier otro valor (o vacío) para los arrays de IDs
+const PermissionEncodingEnv = "JWT_PERMISSION_ENCODING"
+
+// CompactPermissionEncoding es el valor de PermissionEncodingEnv que activa la codificación compacta
+const CompactPermissionEncoding = "compact"
+
+// CompactPermissionsEnabled indica si los tokens nuevos deben usar la codificación compacta
+func CompactPermissionsEnabled() bool {
+	return os.Getenv(PermissionEncodingEnv) == CompactPermissionEncoding
+}
+
+// CompactPermissionClaims sustituye los arrays de permisos de los claims (globales y de cada organización)
+// por bitsets sobre el índice del catálogo vigente. Si algún permiso no está en el catálogo no modifica
+// los claims y devuelve false, de modo que el token conserva los arrays.
+func CompactPermissionClaims(claims *auth.JwtPrivateClaims) bool {
+	index := model.CurrentPermissionIndex()
+
+	permissionBits, ok := encodePermissionBits(index, claims.PermissionIds)
+	if !ok {
+		return false
+	}
+	deniedPermissionBits, ok := encodePermissionBits(index, claims.DeniedPermissionIds)
+	if !ok {
+		return false
+	}
+	organizationBits := make(map[string][2]string, len(claims.Organizations))
+	for organizationId, organization := range claims.Organizations {
+		granted, ok := encodePermissionBits(index, organization.PermissionIds)
+		if !ok {
+			return false
+		}
+		denied, ok := encodePermissionBits(index, organization.DeniedPermissionIds)
+		if !ok {
+			return false
+		}
+		organizationBits[organizationId] = [2]string{granted, denied}
+	}
+
+	claims.CatalogVersion = index.Version
+	claims.PermissionBits, claims.PermissionIds = permissionBits, nil
+	claims.DeniedPermissionBits, claims.DeniedPermissionIds = deniedPermissionBits, nil
+	for organizationId, organization := range claims.Organizations {
+		bits := organizationBits[organizationId]
+		organization.PermissionBits, organization.PermissionIds = bits[0], nil
+		organization.DeniedPermissionBits, organization.DeniedPermissionIds = bits[1], nil
+	}
+	return true
+}
+
+// ExpandPermissionClaims decodifica los bitsets de un token compacto en los arrays de IDs, para que el resto
+// del código lea siempre PermissionIds. Los tokens sin CatalogVersion usan los arrays y no se modifican.
+// Los tokens de versiones anteriores del índice se decodifican con sus posiciones, que no cambian; devuelve
+// ErrTokenStale si la versión del token no existe en el índice.
+func ExpandPermissionClaims(claims *auth.JwtPrivateClaims) error {
+	if claims == nil || claims.CatalogVersion == "" {
+		return nil
+	}
+	index, ok := model.PermissionIndexForVersion(claims.CatalogVersion)
+	if !ok {
+		return ErrTokenStale
+	}
+
+	var err error
+	if claims.PermissionIds, err = decodePermissionBits(index, claims.PermissionBits); err != nil {
+		return err
+	}
+	if claims.DeniedPermissionIds, err = decodePermissionBits(index, claims.DeniedPermissionBits); err != nil {
+		return err
+	}
+	for _, organization := range claims.Organizations {
+		if organization == nil {
+			continue
+		}
+		if organization.PermissionIds, err = decodePermissionBits(index, organization.PermissionBits); err != nil {
+			return err
+		}
+		if organization.DeniedPermissionIds, err = decodePermissionBits(index, organization.DeniedPermissionBits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodePermissionBits marca el bit de la posición de cada permiso (bit 0 = bit menos significativo del primer byte)
+func encodePermissionBits(index *model.PermissionIndex, permissionIds []int) (string, bool) {
+	if len(permissionIds) == 0 {
+		return "", true
+	}
+	bits := make([]byte, (index.Size()+7)/8)
+	for _, permissionId := range permissionIds {
+		position, ok := index.Position(permissionId)
+		if !ok {
+			return "", false
+		}
+		bits[position/8] |= 1 << (position % 8)
+	}
+	// Los bytes finales a cero no aportan información; el decodificador acepta bitsets más cortos
+	for len(bits) > 0 && bits[len(bits)-1] == 0 {
+		bits = bits[:len(bits)-1]
+	}
+	return base64.RawURLEncoding.EncodeToString(bits), true
+}
+
+func decodePermissionBits(index *model.PermissionIndex, encoded string) ([]int, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	bits, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(bits) > (index.Size()+7)/8 {
+		return nil, ErrInvalidTokenFormat
+	}
+
+	var permissionIds []int
+	for position := 0; position < len(bits)*8; position++ {
+		if bits[position/8]&(1<<(position%8)) == 0 {
+			continue
+		}
+		permissionId, ok := index.PermissionAt(position)
+		if !ok {
+			return nil, ErrInvalidTokenFormat
+		}
+		permissionIds = append(permissionIds, permissionId)
+	}
+	return permissionIds, nil
+}
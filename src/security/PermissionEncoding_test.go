@@ -0,0 +1,212 @@
+package security
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+type fakePermissionIndexStore struct {
+	ids []int
+}
+
+func (s *fakePermissionIndexStore) Load() ([]int, error) {
+	return slices.Clone(s.ids), nil
+}
+
+func (s *fakePermissionIndexStore) Append(permissionIds []int) ([]int, error) {
+	for _, id := range permissionIds {
+		if !slices.Contains(s.ids, id) {
+			s.ids = append(s.ids, id)
+		}
+	}
+	return slices.Clone(s.ids), nil
+}
+
+// useExternalPermissions configura un índice en memoria y un catálogo con los permisos externos indicados
+func useExternalPermissions(t *testing.T, externalIds ...int) {
+	t.Helper()
+	model.SetPermissionIndexStore(&fakePermissionIndexStore{})
+	setExternalPermissions(externalIds...)
+	t.Cleanup(func() {
+		model.SetPermissionIndexStore(nil)
+		model.SetPermissionSource(nil)
+	})
+}
+
+func setExternalPermissions(externalIds ...int) {
+	model.SetPermissionSource(func() ([]*model.Permission, error) {
+		var permissions []*model.Permission
+		for _, id := range externalIds {
+			permissions = append(permissions, &model.Permission{Id: id, Name: "External " + strconv.Itoa(id), Service: "product-service"})
+		}
+		return permissions, nil
+	})
+}
+
+func TestCompactPermissionClaimsRoundTrip(t *testing.T) {
+	useExternalPermissions(t, 601, 602)
+
+	tests := []struct {
+		name   string
+		claims *auth.JwtPrivateClaims
+	}{
+		{"no permissions", &auth.JwtPrivateClaims{}},
+		{"granted only", &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById, 601}}},
+		{"granted and denied", &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById, model.CreateUser}, DeniedPermissionIds: []int{602}}},
+		{"organizations", &auth.JwtPrivateClaims{
+			PermissionIds: []int{model.GetAllPermissions},
+			Organizations: map[string]*auth.OrganizationClaims{
+				"org-1": {PermissionIds: []int{model.GetOrganization, 601}, DeniedPermissionIds: []int{model.UpdateOrganization}},
+			},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := cloneClaims(test.claims)
+			claims := cloneClaims(test.claims)
+
+			if !CompactPermissionClaims(claims) {
+				t.Fatal("CompactPermissionClaims() = false, want true")
+			}
+			if claims.PermissionIds != nil || claims.DeniedPermissionIds != nil || claims.CatalogVersion == "" {
+				t.Fatalf("compact claims still carry arrays or lack a version: %+v", claims)
+			}
+			if err := ExpandPermissionClaims(claims); err != nil {
+				t.Fatal(err)
+			}
+			assertSameIds(t, "permissionIds", claims.PermissionIds, want.PermissionIds)
+			assertSameIds(t, "deniedPermissionIds", claims.DeniedPermissionIds, want.DeniedPermissionIds)
+			for organizationId, organization := range want.Organizations {
+				assertSameIds(t, organizationId+" permissionIds", claims.Organizations[organizationId].PermissionIds, organization.PermissionIds)
+				assertSameIds(t, organizationId+" deniedPermissionIds", claims.Organizations[organizationId].DeniedPermissionIds, organization.DeniedPermissionIds)
+			}
+		})
+	}
+}
+
+func TestCompactPermissionClaimsKeepsArraysForUnknownPermissions(t *testing.T) {
+	useExternalPermissions(t)
+
+	claims := &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById, 699}}
+	if CompactPermissionClaims(claims) {
+		t.Fatal("CompactPermissionClaims() = true with a permission outside the catalog")
+	}
+	if claims.CatalogVersion != "" || !slices.Equal(claims.PermissionIds, []int{model.GetUserById, 699}) {
+		t.Fatalf("claims were modified: %+v", claims)
+	}
+}
+
+func TestExpandPermissionClaimsAcceptsEarlierVersions(t *testing.T) {
+	useExternalPermissions(t, 601)
+
+	claims := &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById, 601}, DeniedPermissionIds: []int{model.DeleteUser}}
+	if !CompactPermissionClaims(claims) {
+		t.Fatal("CompactPermissionClaims() = false")
+	}
+
+	// Otro servicio registra permisos después de emitir el token
+	setExternalPermissions(601, 150, 602)
+	if current := model.CurrentPermissionIndex(); current.Version == claims.CatalogVersion {
+		t.Fatalf("index version did not change: %s", current.Version)
+	}
+
+	if err := ExpandPermissionClaims(claims); err != nil {
+		t.Fatalf("ExpandPermissionClaims() error = %v, want earlier versions accepted", err)
+	}
+	assertSameIds(t, "permissionIds", claims.PermissionIds, []int{model.GetUserById, 601})
+	assertSameIds(t, "deniedPermissionIds", claims.DeniedPermissionIds, []int{model.DeleteUser})
+}
+
+func TestExpandPermissionClaimsErrors(t *testing.T) {
+	useExternalPermissions(t)
+	index := model.CurrentPermissionIndex()
+	tooLong := base64.RawURLEncoding.EncodeToString(make([]byte, (index.Size()+7)/8+1))
+
+	tests := []struct {
+		name    string
+		claims  *auth.JwtPrivateClaims
+		wantErr error
+	}{
+		{"unknown version", &auth.JwtPrivateClaims{CatalogVersion: strconv.Itoa(index.Size() + 1)}, ErrTokenStale},
+		{"legacy hash version", &auth.JwtPrivateClaims{CatalogVersion: "9f86d081884c7d65"}, ErrTokenStale},
+		{"invalid base64", &auth.JwtPrivateClaims{CatalogVersion: index.Version, PermissionBits: "%%"}, ErrInvalidTokenFormat},
+		{"bitset longer than the index", &auth.JwtPrivateClaims{CatalogVersion: index.Version, PermissionBits: tooLong}, ErrInvalidTokenFormat},
+		{"bit past an earlier version", &auth.JwtPrivateClaims{CatalogVersion: "1", PermissionBits: base64.RawURLEncoding.EncodeToString([]byte{0b10})}, ErrInvalidTokenFormat},
+		{"array token", &auth.JwtPrivateClaims{PermissionIds: []int{model.GetUserById}}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := ExpandPermissionClaims(test.claims); !errors.Is(err, test.wantErr) {
+				t.Fatalf("ExpandPermissionClaims() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncodePermissionBits(t *testing.T) {
+	useExternalPermissions(t)
+	index := model.CurrentPermissionIndex()
+	idAt := func(position int) int {
+		id, _ := index.PermissionAt(position)
+		return id
+	}
+
+	tests := []struct {
+		name          string
+		permissionIds []int
+		want          []byte
+	}{
+		{"empty", nil, nil},
+		{"first position", []int{idAt(0)}, []byte{0b1}},
+		{"positions in the first byte", []int{idAt(1), idAt(3)}, []byte{0b1010}},
+		{"position in the second byte", []int{idAt(9)}, []byte{0, 0b10}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, ok := encodePermissionBits(index, test.permissionIds)
+			if !ok {
+				t.Fatal("encodePermissionBits() = false")
+			}
+			if want := base64.RawURLEncoding.EncodeToString(test.want); encoded != want {
+				t.Fatalf("encodePermissionBits() = %q, want %q", encoded, want)
+			}
+			decoded, err := decodePermissionBits(index, encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertSameIds(t, "decoded", decoded, test.permissionIds)
+		})
+	}
+}
+
+func cloneClaims(claims *auth.JwtPrivateClaims) *auth.JwtPrivateClaims {
+	clone := *claims
+	clone.PermissionIds = slices.Clone(claims.PermissionIds)
+	clone.DeniedPermissionIds = slices.Clone(claims.DeniedPermissionIds)
+	if claims.Organizations != nil {
+		clone.Organizations = make(map[string]*auth.OrganizationClaims, len(claims.Organizations))
+		for organizationId, organization := range claims.Organizations {
+			organizationClone := *organization
+			organizationClone.PermissionIds = slices.Clone(organization.PermissionIds)
+			organizationClone.DeniedPermissionIds = slices.Clone(organization.DeniedPermissionIds)
+			clone.Organizations[organizationId] = &organizationClone
+		}
+	}
+	return &clone
+}
+
+func assertSameIds(t *testing.T, name string, got, want []int) {
+	t.Helper()
+	if !slices.Equal(slices.Sorted(slices.Values(got)), slices.Sorted(slices.Values(want))) {
+		t.Fatalf("%s = %v, want %v", name, got, want)
+	}
+}
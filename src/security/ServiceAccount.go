@@ -0,0 +1,28 @@
+package security
+
+import (
+	"crypto/subtle"
+	"os"
+	"strings"
+)
+
+// ServiceAccountHeader es el header con la clave del service account que realiza la llamada
+const ServiceAccountHeader = "X-Service-Account-Key"
+
+// FindServiceAccount devuelve el nombre del service account asociado a la clave, o "" si no existe.
+// Los service accounts se configuran en SERVICE_ACCOUNT_KEYS como pares "servicio=clave" separados por comas.
+func FindServiceAccount(key string) string {
+	if key == "" {
+		return ""
+	}
+	for _, entry := range strings.Split(os.Getenv("SERVICE_ACCOUNT_KEYS"), ",") {
+		serviceName, serviceKey, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || serviceName == "" || serviceKey == "" {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(serviceKey), []byte(key)) == 1 {
+			return serviceName
+		}
+	}
+	return ""
+}
@@ -1,9 +1,23 @@
 package service
 
 import (
+	"strings"
+	"time"
+
 	"github.com/ruiborda/ecommerce-user-service/src/dto/auth"
 )
 
+// AccountNotActiveError indica que las credenciales son correctas pero el estado de la cuenta no permite iniciar sesión
+type AccountNotActiveError struct {
+	Status string
+	// Fin de la suspensión, si la tiene
+	SuspendedUntil *time.Time
+}
+
+func (e *AccountNotActiveError) Error() string {
+	return "account is " + strings.ToLower(e.Status)
+}
+
 type AuthService interface {
 	// LoginWithGoogle handles the Google OAuth login process
 	LoginWithGoogle(request *auth.LoginWithGoogleRequestDTO) (*auth.LoginWithAnyResponse, error)
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/authz"
+)
+
+var (
+	// ErrInvalidAuthorizationRequest indica que la solicitud de autorización está incompleta o es ambigua
+	ErrInvalidAuthorizationRequest = errors.New("invalid authorization request")
+	// ErrSubjectNotFound indica que el usuario a evaluar no existe
+	ErrSubjectNotFound = errors.New("subject not found")
+)
+
+type AuthorizationService interface {
+	// Check evalúa los permisos de un sujeto contra los datos actuales de sus roles
+	Check(request *authz.CheckRequest) (*authz.CheckResponse, error)
+	// CheckBatch evalúa varias solicitudes en una sola llamada
+	CheckBatch(request *authz.BatchCheckRequest) (*authz.BatchCheckResponse, error)
+	// Explain muestra qué concesión o denegación decide el resultado de un permiso para un usuario
+	Explain(userId string, permissionId int) (*authz.ExplainResponse, error)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+	"io"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/datarequest"
+)
+
+var (
+	// ErrDataSubjectRequestNotFound indica que la solicitud no existe o es de otro usuario
+	ErrDataSubjectRequestNotFound = errors.New("data subject request not found")
+	// ErrDataExportNotAvailable indica que la exportación aún no terminó o su archivo ya venció
+	ErrDataExportNotAvailable = errors.New("data export archive not available")
+	// ErrUserErased indica que los datos del usuario ya se suprimieron
+	ErrUserErased = errors.New("user data has already been erased")
+)
+
+// DataExportDownload es el archivo de una exportación: se lee de Reader o se descarga desde RedirectUrl,
+// según el almacenamiento de objetos configurado
+type DataExportDownload struct {
+	Reader      io.ReadCloser
+	ContentType string
+	RedirectUrl string
+}
+
+type DataSubjectRequestService interface {
+	// RequestExport registra una exportación de los datos del usuario; si ya hay una abierta, devuelve esa
+	RequestExport(userId, actorId string) (*datarequest.DataSubjectRequestResponse, error)
+	// RequestErasure registra la supresión de los datos del usuario; si ya hay una abierta, devuelve esa
+	RequestErasure(userId, actorId string) (*datarequest.DataSubjectRequestResponse, error)
+	// GetUserRequests lista las solicitudes del usuario, primero las más recientes
+	GetUserRequests(userId string) ([]*datarequest.DataSubjectRequestResponse, error)
+	// GetUserRequest devuelve una solicitud del usuario
+	GetUserRequest(userId, id string) (*datarequest.DataSubjectRequestResponse, error)
+	// GetRequests lista las solicitudes de todos los usuarios, opcionalmente filtradas por estado y usuario
+	GetRequests(status, userId string) ([]*datarequest.DataSubjectRequestResponse, error)
+	// OpenDataExport devuelve el archivo de una exportación completada del usuario
+	OpenDataExport(userId, id string) (*DataExportDownload, error)
+	// ProcessRequests atiende las solicitudes pendientes y borra los archivos de exportación vencidos
+	ProcessRequests() (processed int, expired int, err error)
+}
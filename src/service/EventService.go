@@ -0,0 +1,8 @@
+package service
+
+import "github.com/ruiborda/ecommerce-user-service/src/dto/event"
+
+type EventService interface {
+	// GetEvents devuelve los eventos siguientes al token, o desde el principio si está vacío
+	GetEvents(pageToken string, size int) (*event.OutboxEventPageResponse, error)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"errors"
+	"io"
+
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+)
+
+// ErrInvalidExport indica que el formato o los campos pedidos no son válidos
+var ErrInvalidExport = errors.New("invalid export")
+
+// Export es una exportación ya validada. Write escribe los registros a medida que los lee de la base de datos;
+// si falla, parte del contenido ya se ha escrito.
+type Export struct {
+	ContentType string
+	FileName    string
+	Write       func(w io.Writer) error
+}
+
+type ExportService interface {
+	// ExportUsers exporta los usuarios que cumplen la búsqueda, con los mismos filtros y orden que el listado paginado
+	ExportUsers(search *user.UserSearchRequest, request *dto.ExportRequest) (*Export, error)
+	// ExportRoles exporta los roles globales ordenados por código
+	ExportRoles(request *dto.ExportRequest) (*Export, error)
+}
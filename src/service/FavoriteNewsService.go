@@ -0,0 +1,28 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/favorite"
+)
+
+var (
+	// ErrInvalidNewsArticleId indica que el ID de la noticia no tiene un formato válido
+	ErrInvalidNewsArticleId = errors.New("invalid news article id")
+	// ErrFavoriteLimitReached indica que el usuario ya tiene model.MaxFavoriteNewsArticles favoritos
+	ErrFavoriteLimitReached = errors.New("favorite news limit reached")
+)
+
+// FavoriteNewsService gestiona las noticias favoritas del usuario autenticado
+type FavoriteNewsService interface {
+	// GetFavorites lista los favoritos del más reciente al más antiguo
+	GetFavorites(c *gin.Context, userId string, pageable *dto.Pageable) (*dto.PaginationResponse[favorite.FavoriteNewsArticleResponse], error)
+	GetFavorite(userId, articleId string) (*favorite.FavoriteNewsStatusResponse, error)
+	// AddFavorite y RemoveFavorite son idempotentes
+	AddFavorite(userId, articleId string) (*favorite.FavoriteNewsStatusResponse, error)
+	RemoveFavorite(userId, articleId string) (*favorite.FavoriteNewsStatusResponse, error)
+	// CountFavorites cuenta los usuarios que tienen la noticia en favoritos, para el equipo de contenidos
+	CountFavorites(articleId string) (*favorite.NewsFavoriteCountResponse, error)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/group"
+)
+
+var (
+	// ErrGroupNotFound indica que el grupo no existe
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrInvalidGroup indica que faltan datos del grupo o que alguno de sus roles o miembros no es válido
+	ErrInvalidGroup = errors.New("invalid group")
+)
+
+type GroupService interface {
+	CreateGroup(request *group.CreateGroupRequest) (*group.GroupResponse, error)
+	GetGroupById(id string) (*group.GroupResponse, error)
+	GetAllGroups() ([]*group.GroupResponse, error)
+	UpdateGroup(request *group.UpdateGroupRequest) (*group.GroupResponse, error)
+	DeleteGroupById(id string) error
+	// AddMember y RemoveMember afectan de inmediato a los permisos del usuario en las verificaciones de autorización
+	AddMember(groupId, userId string) (*group.GroupResponse, error)
+	RemoveMember(groupId, userId string) (*group.GroupResponse, error)
+}
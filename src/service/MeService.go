@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+)
+
+var (
+	// ErrInvalidProfile indica que los datos del perfil enviados por el usuario no son válidos
+	ErrInvalidProfile = errors.New("invalid profile")
+	// ErrInvalidPassword indica que la nueva contraseña no cumple los requisitos
+	ErrInvalidPassword = errors.New("invalid password")
+	// ErrWrongCurrentPassword indica que la contraseña actual no coincide o que la cuenta no tiene contraseña
+	ErrWrongCurrentPassword = errors.New("current password is incorrect")
+	// ErrUnsupportedImage indica que el archivo subido no es una imagen en un formato aceptado
+	ErrUnsupportedImage = errors.New("unsupported image format")
+	// ErrInvalidImage indica que la imagen no se puede decodificar o sus dimensiones no son válidas
+	ErrInvalidImage = errors.New("invalid image")
+	// ErrImageTooLarge indica que el archivo supera MaxAvatarBytes
+	ErrImageTooLarge = errors.New("image is too large")
+)
+
+// MaxAvatarBytes es el tamaño máximo del archivo de la imagen de perfil
+const MaxAvatarBytes = 5 << 20
+
+// MeService gestiona el perfil del usuario autenticado; userId es siempre el Subject del token
+type MeService interface {
+	GetMe(userId string) (*user.GetUserByIdResponse, error)
+	// UpdateMe solo modifica los campos del perfil que el usuario puede cambiar; nunca roles ni denegaciones
+	UpdateMe(userId string, request *user.UpdateMeRequest) (*user.GetUserByIdResponse, error)
+	// ChangePassword exige la contraseña actual e invalida los tokens emitidos hasta ahora
+	ChangePassword(userId string, request *user.ChangePasswordRequest) error
+	DeleteMe(userId string) error
+	// UploadAvatar genera las versiones de model.AvatarSizes y reemplaza la imagen de perfil anterior
+	UploadAvatar(userId string, data []byte) (*user.GetUserByIdResponse, error)
+	// DeleteAvatar elimina la imagen subida; el perfil vuelve a mostrar la de Google si existe
+	DeleteAvatar(userId string) (*user.GetUserByIdResponse, error)
+}
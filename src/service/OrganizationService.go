@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/organization"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/role"
+)
+
+var (
+	// ErrOrganizationNotFound indica que la organización no existe
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrInvalidOrganization indica que faltan datos de la organización
+	ErrInvalidOrganization = errors.New("invalid organization")
+	// ErrOrganizationMemberNotFound indica que el usuario no es miembro de la organización
+	ErrOrganizationMemberNotFound = errors.New("organization member not found")
+	// ErrOrganizationRoleNotFound indica que el rol no existe o pertenece a otra organización
+	ErrOrganizationRoleNotFound = errors.New("organization role not found")
+	// ErrInvalidOrganizationRole indica un código repetido o un permiso que no se puede conceder dentro de una organización
+	ErrInvalidOrganizationRole = errors.New("invalid organization role")
+)
+
+type OrganizationService interface {
+	// CreateOrganization crea la organización, su rol ORG_ADMIN y la membresía del propietario
+	CreateOrganization(request *organization.CreateOrganizationRequest) (*organization.OrganizationResponse, error)
+	GetOrganizationById(id string) (*organization.OrganizationResponse, error)
+	GetAllOrganizations() ([]*organization.OrganizationResponse, error)
+	UpdateOrganization(id string, request *organization.UpdateOrganizationRequest) (*organization.OrganizationResponse, error)
+
+	GetMembers(organizationId string) ([]*organization.OrganizationMemberResponse, error)
+	// SetMember agrega al usuario a la organización o reemplaza sus roles en ella
+	SetMember(organizationId, userId string, request *organization.SetOrganizationMemberRequest) (*organization.OrganizationMemberResponse, error)
+	RemoveMember(organizationId, userId string) error
+
+	GetRoles(organizationId string) ([]*role.GetRoleByIdResponse, error)
+	CreateRole(organizationId string, request *organization.OrganizationRoleRequest) (*role.GetRoleByIdResponse, error)
+	UpdateRole(organizationId, roleId string, request *organization.OrganizationRoleRequest) (*role.GetRoleByIdResponse, error)
+	// DeleteRole elimina el rol y lo quita de las membresías que lo tenían. El rol ORG_ADMIN no se puede eliminar.
+	DeleteRole(organizationId, roleId string) error
+}
@@ -1,13 +1,24 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/ruiborda/ecommerce-user-service/src/dto/permission"
 )
 
+// ErrInvalidCatalog indica que la solicitud de registro del catálogo no es válida
+var ErrInvalidCatalog = errors.New("invalid permission catalog")
+
 type PermissionService interface {
 	GetAllPermissions() *permission.GetAllPermissionsResponse
 	GetAllPermissionsAsArray() []permission.GetPermissionByIdResponse
 	GetPermissionById(id int) *permission.GetPermissionByIdResponse
 	GetPermissionsByIds(request *permission.GetPermissionsByIdsRequest) permission.GetPermissionsByIdsResponse
 	GetPermissionsByIdsAsArray(request *permission.GetPermissionsByIdsRequest) []permission.GetPermissionByIdResponse
+	// GetPermissionGroups devuelve los grupos del catálogo con los permisos que concede su comodín
+	GetPermissionGroups() []*permission.PermissionGroupResponse
+	// RegisterServiceCatalog registra (o reemplaza) los permisos publicados por otro microservicio
+	RegisterServiceCatalog(service string, request *permission.RegisterCatalogRequest) (*permission.RegisterCatalogResponse, error)
+	// EnsureSeedPermissions siembra en el catálogo persistido los permisos de model.SeedPermissions que falten
+	EnsureSeedPermissions() error
 }
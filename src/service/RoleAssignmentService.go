@@ -0,0 +1,29 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+)
+
+var (
+	// ErrUserNotFound indica que el usuario indicado no existe
+	ErrUserNotFound = errors.New("user not found")
+	// ErrRoleNotFound indica que el rol indicado no existe
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrInvalidRoleAssignment indica que la ventana de vigencia solicitada no es válida
+	ErrInvalidRoleAssignment = errors.New("invalid role assignment")
+	// ErrRoleAssignmentNotFound indica que el usuario no tiene una asignación temporal para el rol
+	ErrRoleAssignmentNotFound = errors.New("role assignment not found")
+)
+
+type RoleAssignmentService interface {
+	// GrantTemporaryRole asigna un rol durante una ventana de tiempo, reemplazando una asignación temporal previa del mismo rol
+	GrantTemporaryRole(userId string, request *user.GrantTemporaryRoleRequest, grantedBy string) (*user.RoleAssignmentsResponse, error)
+	// GetRoleAssignments lista las asignaciones temporales de un usuario y su historial
+	GetRoleAssignments(userId string) (*user.RoleAssignmentsResponse, error)
+	// RevokeTemporaryRole elimina una asignación temporal antes de su vencimiento
+	RevokeTemporaryRole(userId, roleId, revokedBy string) (*user.RoleAssignmentsResponse, error)
+	// SweepExpiredRoleAssignments elimina las asignaciones vencidas y registra su expiración; devuelve cuántas eliminó
+	SweepExpiredRoleAssignments() (int, error)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/rolechange"
+)
+
+var (
+	// ErrRoleChangeRequestNotFound indica que la solicitud de cambio de roles no existe
+	ErrRoleChangeRequestNotFound = errors.New("role change request not found")
+	// ErrRoleChangeRequestClosed indica que la solicitud ya fue aprobada o rechazada
+	ErrRoleChangeRequestClosed = errors.New("role change request is no longer pending")
+	// ErrSelfApproval indica que quien decide es el solicitante o el usuario afectado
+	ErrSelfApproval = errors.New("role change requests must be decided by a different user")
+)
+
+type RoleChangeRequestService interface {
+	// GetRoleChangeRequests lista las solicitudes, opcionalmente filtradas por estado y usuario
+	GetRoleChangeRequests(status, userId string) ([]*rolechange.RoleChangeRequestResponse, error)
+	GetRoleChangeRequestById(id string) (*rolechange.RoleChangeRequestResponse, error)
+	// ApproveRoleChangeRequest asigna los roles de la solicitud al usuario
+	ApproveRoleChangeRequest(id, approverId string, request *rolechange.DecideRoleChangeRequest) (*rolechange.RoleChangeRequestResponse, error)
+	// RejectRoleChangeRequest cierra la solicitud sin modificar al usuario
+	RejectRoleChangeRequest(id, approverId string, request *rolechange.DecideRoleChangeRequest) (*rolechange.RoleChangeRequestResponse, error)
+}
@@ -2,20 +2,54 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
 	"github.com/ruiborda/ecommerce-user-service/src/dto/role"
 )
 
+var (
+	// ErrInvalidRoleCode indica que el código no cumple el formato: mayúsculas, dígitos y guiones bajos
+	ErrInvalidRoleCode = errors.New("invalid role code: use 2 to 50 uppercase letters, digits or underscores, starting with a letter")
+	// ErrRoleCodeTaken indica que otro rol ya usa el código
+	ErrRoleCodeTaken = errors.New("role code already in use")
+	// ErrInvalidRolePermissions indica que alguno de los permisos concedidos o denegados no existe
+	ErrInvalidRolePermissions = errors.New("one or more permission IDs are not valid")
+	// ErrSystemRole indica que se intentó eliminar o renombrar un rol del sistema
+	ErrSystemRole = errors.New("system roles cannot be deleted or renamed")
+	// ErrPrivilegedChangeNotAllowed indica que quien actualiza el rol no puede cambiar si es privilegiado
+	ErrPrivilegedChangeNotAllowed = errors.New("changing whether a role is privileged requires the ApproveRoleChange permission")
+	// ErrInvalidRoleReassignment indica que el rol de reemplazo no existe, es el mismo rol o se combinó con force
+	ErrInvalidRoleReassignment = errors.New("invalid role reassignment")
+)
+
+// RoleInUseError indica que el rol no se puede eliminar porque aún tiene referencias
+type RoleInUseError struct {
+	Usage *role.RoleUsageResponse
+}
+
+func (e *RoleInUseError) Error() string {
+	if e.Usage.PendingRoleChangeRequests > 0 {
+		return "role is still in use; pending role change requests must be approved or rejected first"
+	}
+	return "role is still in use; use reassignTo or force to remove it from users and groups"
+}
+
 type RoleService interface {
-	CreateRole(request *role.CreateRoleRequest) *role.CreateRoleResponse
+	CreateRole(request *role.CreateRoleRequest) (*role.CreateRoleResponse, error)
 	GetRoleById(id string) *role.GetRoleByIdResponse
 	GetAllRoles() []*role.GetRoleByIdResponse
-	UpdateRoleById(request *role.UpdateRoleRequest) *role.UpdateRoleResponse
-	DeleteRoleById(id string) *role.DeleteRoleByIdResponse
+	// UpdateRoleById permite cambiar los permisos de un rol del sistema, pero no su código.
+	// canChangePrivileged indica si quien actualiza puede aprobar roles privilegiados y, por tanto, cambiar la marca.
+	UpdateRoleById(request *role.UpdateRoleRequest, canChangePrivileged bool) (*role.UpdateRoleResponse, error)
+	// DeleteRoleById elimina un rol sin referencias, o las migra o quita antes según las opciones
+	DeleteRoleById(request *role.DeleteRoleByIdRequest) (*role.DeleteRoleByIdResponse, error)
 	FindAllRolesByPageAndSize(page, size int) []*role.GetRoleByIdResponse
 	CountAllRoles() int64
 	GetRolesByIds(ids []string) []*role.GetRoleByIdResponse
-	// Nuevo método que maneja la paginación completa
-	FindAllRolesPaginated(c *gin.Context, pageable *dto.Pageable) *dto.PaginationResponse[role.GetRoleByIdResponse]
+	// Nuevo método que maneja la paginación completa, por página o por cursor según pageable
+	FindAllRolesPaginated(c *gin.Context, pageable *dto.Pageable) (*dto.PaginationResponse[role.GetRoleByIdResponse], error)
+	// EnsureSystemRoles crea los roles del sistema que falten y marca como tales a los existentes
+	EnsureSystemRoles() error
 }
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/sod"
+)
+
+var (
+	// ErrInvalidSodConstraint indica que la restricción no tiene nombre o no incluye al menos dos roles existentes
+	ErrInvalidSodConstraint = errors.New("invalid separation of duty constraint")
+	// ErrSodConstraintNotFound indica que la restricción no existe
+	ErrSodConstraintNotFound = errors.New("separation of duty constraint not found")
+)
+
+// SodViolationError indica que una asignación dejaría al usuario con roles mutuamente excluyentes
+type SodViolationError struct {
+	ConstraintId   string
+	ConstraintName string
+	RoleIds        []string
+	RoleCodes      []string
+	// UserId y GroupId identifican, cuando se conoce, al usuario o grupo que quedaría con los roles
+	UserId  string
+	GroupId string
+}
+
+func (e *SodViolationError) Error() string {
+	return fmt.Sprintf("separation of duty constraint %q forbids holding roles %s together", e.ConstraintName, strings.Join(e.RoleCodes, ", "))
+}
+
+type SodConstraintService interface {
+	CreateSodConstraint(request *sod.CreateSodConstraintRequest) (*sod.SodConstraintResponse, error)
+	GetSodConstraintById(id string) (*sod.SodConstraintResponse, error)
+	GetAllSodConstraints() ([]*sod.SodConstraintResponse, error)
+	UpdateSodConstraint(request *sod.UpdateSodConstraintRequest) (*sod.SodConstraintResponse, error)
+	DeleteSodConstraintById(id string) error
+	// GetSodViolations lista los usuarios que ya tienen roles mutuamente excluyentes
+	GetSodViolations() ([]*sod.SodViolationResponse, error)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+)
+
+const (
+	// MaxUserImportBytes es el tamaño máximo del archivo de importación
+	MaxUserImportBytes = 10 << 20
+	// MaxUserImportRows es el número máximo de filas de una importación
+	MaxUserImportRows = 5000
+)
+
+var (
+	// ErrInvalidUserImport indica que el archivo o las opciones no son válidos; los errores de cada fila van en el informe
+	ErrInvalidUserImport = errors.New("invalid user import")
+	// ErrUserImportTooLarge indica que el archivo supera MaxUserImportBytes
+	ErrUserImportTooLarge = errors.New("user import file too large")
+)
+
+type UserImportService interface {
+	// ImportUsers valida todas las filas antes de escribir y solo escribe si todas son válidas.
+	// fileName se usa para deducir el formato si no se indica; actorId solicita los roles privilegiados.
+	ImportUsers(data []byte, fileName string, request *user.UserImportRequest, actorId string) (*user.UserImportReport, error)
+}
@@ -1,21 +1,29 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
 	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
 )
 
+// ErrInvalidUserFilter indica que los parámetros de búsqueda del listado de usuarios no son válidos
+var ErrInvalidUserFilter = errors.New("invalid user filter")
+
 type UserService interface {
-	CreateUser(request *user.CreateUserRequest) *user.CreateUserResponse
+	// CreateUser y UpdateUserById dejan pendientes de aprobación los roles privilegiados; actorId es quien solicita el cambio
+	CreateUser(request *user.CreateUserRequest, actorId string) (*user.CreateUserResponse, error)
 	GetUserById(id string) *user.GetUserByIdResponse
 	GetUserByEmail(email string) *user.GetUserByIdResponse
 	GetAllUsers() []*user.GetUserByIdResponse
-	UpdateUserById(request *user.UpdateUserRequest) *user.UpdateUserResponse
-	DeleteUserById(id string) *user.DeleteUserByIdResponse
+	UpdateUserById(request *user.UpdateUserRequest, actorId string) (*user.UpdateUserResponse, error)
+	// DeleteUserById elimina la cuenta de forma lógica; se purga al terminar el periodo de retención
+	DeleteUserById(id string, actorId string) (*user.DeleteUserByIdResponse, error)
 	FindAllUsersByPageAndSize(page, size int) []*user.GetUserByIdResponse
 	CountAllUsers() int64
 	GetUsersByIds(ids []string) []*user.GetUserByIdResponse
-	// Nuevo método que maneja la paginación completa
-	FindAllUsersPaginated(c *gin.Context, pageable *dto.Pageable) *dto.PaginationResponse[user.GetUserByIdResponse]
+	// Nuevo método que maneja la paginación completa, por página o por cursor según pageable,
+	// con los filtros y el orden de search
+	FindAllUsersPaginated(c *gin.Context, pageable *dto.Pageable, search *user.UserSearchRequest) (*dto.PaginationResponse[user.GetUserByIdResponse], error)
 }
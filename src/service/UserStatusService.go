@@ -0,0 +1,25 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+)
+
+var (
+	// ErrInvalidUserStatus indica que el estado o los datos que lo acompañan no son válidos
+	ErrInvalidUserStatus = errors.New("invalid user status")
+	// ErrUserStatusTransition indica que la cuenta no puede pasar de su estado actual al solicitado
+	ErrUserStatusTransition = errors.New("user status transition not allowed")
+)
+
+type UserStatusService interface {
+	// ChangeStatus suspende, desactiva o reactiva una cuenta; actorId no puede cambiar su propio estado
+	ChangeStatus(userId string, request *user.ChangeUserStatusRequest, actorId string) (*user.GetUserByIdResponse, error)
+	// RestoreUser reactiva una cuenta eliminada que aún no se ha purgado
+	RestoreUser(userId, actorId string) (*user.GetUserByIdResponse, error)
+	// SweepUserStatuses reactiva las suspensiones vencidas y purga las cuentas eliminadas cuya retención terminó
+	SweepUserStatuses() (reactivated int, purged int, err error)
+	// EnsureUserStatuses marca como activas las cuentas anteriores al campo de estado; solo trabaja la primera vez
+	EnsureUserStatuses() (int, error)
+}
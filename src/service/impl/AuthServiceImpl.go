@@ -7,8 +7,11 @@ import (
 	"github.com/ruiborda/ecommerce-user-service/src/model"
 	"github.com/ruiborda/ecommerce-user-service/src/repository"
 	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
 	"time"
@@ -20,14 +23,17 @@ import (
 )
 
 type AuthServiceImpl struct {
-	userRepository repository.UserRepository
-	roleRepository repository.RoleRepository
+	userRepository     repository.UserRepository
+	roleRepository     repository.RoleRepository
+	permissionResolver *permissionResolver
 }
 
 func NewAuthServiceImpl() *AuthServiceImpl {
+	roleRepository := impl.NewRoleRepositoryImpl()
 	return &AuthServiceImpl{
-		userRepository: impl.NewUserRepositoryImpl(),
-		roleRepository: impl.NewRoleRepositoryImpl(),
+		userRepository:     impl.NewUserRepositoryImpl(),
+		roleRepository:     roleRepository,
+		permissionResolver: newPermissionResolver(roleRepository),
 	}
 }
 
@@ -85,6 +91,11 @@ func (s *AuthServiceImpl) LoginWithEmail(request *auth.LoginWithEmailRequestDTO)
 		return nil, errors.New("invalid email or password")
 	}
 
+	// The status is only revealed to whoever knows the password
+	if err := checkAccountStatus(user); err != nil {
+		return nil, err
+	}
+
 	// Generate JWT token
 	token, err := s.generateJWTToken(user)
 	if err != nil {
@@ -105,6 +116,14 @@ func (s *AuthServiceImpl) LoginWithEmail(request *auth.LoginWithEmailRequestDTO)
 
 // Helper methods
 
+// checkAccountStatus refuses to log in suspended, deactivated and deleted accounts
+func checkAccountStatus(user *model.User) error {
+	if status := user.CurrentStatus(time.Now()); status != model.UserStatusActive {
+		return &service.AccountNotActiveError{Status: status, SuspendedUntil: user.SuspendedUntil}
+	}
+	return nil
+}
+
 func (s *AuthServiceImpl) getUserInfoFromGoogle(accessToken string) (*auth.GoogleUserInfoResponse, error) {
 	url := "https://openidconnect.googleapis.com/v1/userinfo"
 
@@ -149,41 +168,37 @@ func (s *AuthServiceImpl) findOrCreateUserFromGoogle(googleUserInfo *auth.Google
 	user, err := s.userRepository.FindByEmail(googleUserInfo.Email)
 
 	if err == nil && user != nil {
-		// User exists, update with Google info
-		user.FullName = googleUserInfo.Name
-		user.PictureUrl = googleUserInfo.Picture
-		user.UpdatedAt = time.Now()
+		if err := checkAccountStatus(user); err != nil {
+			return nil, err
+		}
 
-		// Update user in database using repository
-		user, err = s.userRepository.Update(user)
+		// User exists, update with Google info
+		updated, err := s.userRepository.Update(user.Id, func(user *model.User) error {
+			user.FullName = googleUserInfo.Name
+			user.PictureUrl = googleUserInfo.Picture
+			return nil
+		})
 		if err != nil {
 			slog.Warn("Failed to update user from Google login", "email", googleUserInfo.Email, "error", err)
 			// Continue anyway as this is just an update
+		} else if updated != nil {
+			user = updated
 		}
 
 		return user, nil
 	} else {
 		// User doesn't exist, create a new one with USER role
-		// Get all roles from repository
-		roles, err := s.roleRepository.FindAll()
+		userRole, err := s.roleRepository.FindByCode(model.UserRoleCode)
 		if err != nil {
 			slog.Error("Failed to fetch roles", "error", err)
 			return nil, errors.New("failed to fetch roles")
 		}
 
-		// Find USER role
-		var userRoleId string
-		for _, role := range roles {
-			if role.Code == "USER" {
-				userRoleId = role.Id
-				break
-			}
-		}
-
-		if userRoleId == "" {
+		if userRole == nil {
 			slog.Error("USER role not found in the database")
 			return nil, errors.New("required role not found")
 		}
+		userRoleId := userRole.Id
 
 		// Create new user
 		now := time.Now()
@@ -211,23 +226,35 @@ func (s *AuthServiceImpl) findOrCreateUserFromGoogle(googleUserInfo *auth.Google
 func (s *AuthServiceImpl) generateJWTToken(user *model.User) (string, error) {
 	var roleCodes []string
 	var permissionIds []int
+	var deniedPermissionIds []int
+	var organizations map[string]*auth.OrganizationClaims
+	roleVersions := make(map[string]int64)
 
-	// Get roles directly from repository
-	if len(user.RoleIds) > 0 {
-		roles, err := s.roleRepository.FindByIds(user.RoleIds)
-		if err != nil {
-			slog.Error("Failed to fetch roles for user", "userId", user.Id, "error", err)
-			// Continue with empty roles/permissions
-		} else {
-			// Extract role codes and permission IDs
-			for _, role := range roles {
-				roleCodes = append(roleCodes, role.Code)
-				if role.Permissions != nil {
-					for _, permission := range *role.Permissions {
-						permissionIds = append(permissionIds, permission.Id)
-					}
-				}
+	// Resolve roles and permissions from the current role data
+	resolved, err := s.permissionResolver.resolve(user)
+	if err != nil {
+		slog.Error("Failed to fetch roles for user", "userId", user.Id, "error", err)
+		// Continue with empty roles/permissions
+	} else {
+		roleCodes = resolved.RoleCodes
+		permissionIds = resolved.PermissionIds()
+		deniedPermissionIds = resolved.DeniedPermissionIds()
+		maps.Copy(roleVersions, resolved.RoleVersions)
+	}
+
+	// Roles granted through organization memberships only apply within each organization
+	resolvedOrganizations, err := s.permissionResolver.resolveOrganizations(user)
+	if err != nil {
+		slog.Error("Failed to fetch organization memberships for user", "userId", user.Id, "error", err)
+	} else if len(resolvedOrganizations) > 0 {
+		organizations = make(map[string]*auth.OrganizationClaims, len(resolvedOrganizations))
+		for organizationId, organizationResolved := range resolvedOrganizations {
+			organizations[organizationId] = &auth.OrganizationClaims{
+				Roles:               organizationResolved.RoleCodes,
+				PermissionIds:       organizationResolved.PermissionIds(),
+				DeniedPermissionIds: organizationResolved.DeniedPermissionIds(),
 			}
+			maps.Copy(roleVersions, organizationResolved.RoleVersions)
 		}
 	}
 
@@ -238,6 +265,22 @@ func (s *AuthServiceImpl) generateJWTToken(user *model.User) (string, error) {
 		return "", errors.New("JWT secret not configured")
 	}
 
+	privateClaims := &auth.JwtPrivateClaims{
+		Email:         user.Email,
+		Roles:         roleCodes,
+		PermissionIds: permissionIds,
+		// Deny beats allow: the denied ids are already excluded from PermissionIds
+		DeniedPermissionIds: deniedPermissionIds,
+		Organizations:       organizations,
+		AuthzVersion:        user.AuthzVersion,
+		RoleVersions:        roleVersions,
+	}
+	// Compact bitset encoding keeps the Authorization header small; the plain arrays are kept
+	// when a granted permission is missing from the catalog index
+	if security.CompactPermissionsEnabled() && !security.CompactPermissionClaims(privateClaims) {
+		slog.Warn("Permission outside the catalog index, issuing token with plain permission ids", "userId", user.Id)
+	}
+
 	inputPort := input.NewJWTHS256InputPort[*auth.JwtPrivateClaims]([]byte(jwtSecret))
 	inputAdapter := input2.NewJwtInputAdapter[*auth.JwtPrivateClaims](inputPort)
 
@@ -252,11 +295,7 @@ func (s *AuthServiceImpl) generateJWTToken(user *model.User) (string, error) {
 				Subject:        user.Id,
 				ExpirationTime: time.Now().Add(time.Hour * 24).Unix(),
 			},
-			PrivateClaims: &auth.JwtPrivateClaims{
-				Email:         user.Email,
-				Roles:         roleCodes,
-				PermissionIds: permissionIds,
-			},
+			PrivateClaims: privateClaims,
 		})
 
 	if err != nil {
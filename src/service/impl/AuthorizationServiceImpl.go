@@ -0,0 +1,243 @@
+package impl
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/authz"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/security"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// MaxBatchChecks limita el número de verificaciones por solicitud batch
+const MaxBatchChecks = 100
+
+type AuthorizationServiceImpl struct {
+	userRepository     repository.UserRepository
+	permissionResolver *permissionResolver
+}
+
+func NewAuthorizationServiceImpl() *AuthorizationServiceImpl {
+	return &AuthorizationServiceImpl{
+		userRepository:     impl.NewUserRepositoryImpl(),
+		permissionResolver: newPermissionResolver(impl.NewRoleRepositoryImpl()),
+	}
+}
+
+// authorizationSubject guarda el resultado de resolver un sujeto dentro de una misma solicitud
+type authorizationSubject struct {
+	userId     string
+	denyReason string
+	resolved   *resolvedPermissions
+}
+
+// subjectKey identifica un sujeto resuelto en una organización concreta (o globalmente)
+type subjectKey struct {
+	subject        authz.Subject
+	organizationId string
+}
+
+// Check evalúa una solicitud de autorización
+func (s *AuthorizationServiceImpl) Check(request *authz.CheckRequest) (*authz.CheckResponse, error) {
+	return s.check(request, make(map[subjectKey]*authorizationSubject))
+}
+
+// CheckBatch evalúa varias solicitudes reutilizando los sujetos ya resueltos
+func (s *AuthorizationServiceImpl) CheckBatch(request *authz.BatchCheckRequest) (*authz.BatchCheckResponse, error) {
+	if len(request.Checks) == 0 {
+		return nil, fmt.Errorf("%w: at least one check is required", service.ErrInvalidAuthorizationRequest)
+	}
+	if len(request.Checks) > MaxBatchChecks {
+		return nil, fmt.Errorf("%w: at most %d checks are allowed", service.ErrInvalidAuthorizationRequest, MaxBatchChecks)
+	}
+
+	subjects := make(map[subjectKey]*authorizationSubject)
+	response := &authz.BatchCheckResponse{Results: make([]authz.CheckResponse, 0, len(request.Checks))}
+	for i := range request.Checks {
+		result, err := s.check(&request.Checks[i], subjects)
+		if err != nil {
+			return nil, err
+		}
+		response.Results = append(response.Results, *result)
+	}
+
+	return response, nil
+}
+
+func (s *AuthorizationServiceImpl) check(request *authz.CheckRequest, subjects map[subjectKey]*authorizationSubject) (*authz.CheckResponse, error) {
+	if (request.Subject.UserId == "") == (request.Subject.Token == "") {
+		return nil, fmt.Errorf("%w: exactly one of subject.userId or subject.token is required", service.ErrInvalidAuthorizationRequest)
+	}
+	if len(request.PermissionIds) == 0 && request.Resource == nil {
+		return nil, fmt.Errorf("%w: permissionIds or resource is required", service.ErrInvalidAuthorizationRequest)
+	}
+
+	permissionIds := append([]int{}, request.PermissionIds...)
+	if request.Resource != nil {
+		permission := model.FindPermissionByEndpoint(request.Resource.Service, request.Resource.Method, request.Resource.Path)
+		if permission == nil {
+			return deny("", "resource is not registered in the permission catalog"), nil
+		}
+		permissionIds = append(permissionIds, permission.Id)
+	}
+
+	key := subjectKey{subject: request.Subject, organizationId: request.OrganizationId}
+	subject, ok := subjects[key]
+	if !ok {
+		var err error
+		subject, err = s.resolveSubject(&request.Subject, request.OrganizationId)
+		if err != nil {
+			return nil, err
+		}
+		subjects[key] = subject
+	}
+	if subject.denyReason != "" {
+		return deny(subject.userId, subject.denyReason), nil
+	}
+
+	response := &authz.CheckResponse{
+		Allowed:   true,
+		Reason:    "all permissions granted",
+		SubjectId: subject.userId,
+		Decisions: make([]authz.PermissionDecision, 0, len(permissionIds)),
+	}
+	for _, permissionId := range permissionIds {
+		decision := decide(subject.resolved, permissionId)
+		if !decision.Allowed && response.Allowed {
+			response.Allowed = false
+			response.Reason = decision.Reason
+		}
+		response.Decisions = append(response.Decisions, decision)
+	}
+
+	return response, nil
+}
+
+func (s *AuthorizationServiceImpl) resolveSubject(subject *authz.Subject, organizationId string) (*authorizationSubject, error) {
+	userId := subject.UserId
+	if subject.Token != "" {
+		claims, err := security.VerifyToken(subject.Token)
+		if err != nil {
+			return &authorizationSubject{denyReason: "invalid token: " + err.Error()}, nil
+		}
+		userId = claims.RegisteredClaims.Subject
+	}
+
+	if _, err := uuid.Parse(userId); err != nil {
+		return &authorizationSubject{userId: userId, denyReason: "subject not found"}, nil
+	}
+
+	// Se usan los datos actuales y no los claims del token, que pueden estar desactualizados
+	user, err := s.userRepository.FindById(userId)
+	if err != nil {
+		log.Printf("Error fetching authorization subject: %v", err)
+		return nil, err
+	}
+	if user == nil {
+		return &authorizationSubject{userId: userId, denyReason: "subject not found"}, nil
+	}
+	// Una cuenta que no está activa no puede usar sus permisos, aunque su token aún no haya vencido
+	if status := user.CurrentStatus(time.Now()); status != model.UserStatusActive {
+		return &authorizationSubject{userId: userId, denyReason: "subject status is " + status}, nil
+	}
+
+	var resolved *resolvedPermissions
+	if organizationId != "" {
+		resolved, err = s.permissionResolver.resolveInOrganization(user, organizationId)
+	} else {
+		resolved, err = s.permissionResolver.resolve(user)
+	}
+	if err != nil {
+		log.Printf("Error resolving permissions for subject %s: %v", userId, err)
+		return nil, err
+	}
+
+	return &authorizationSubject{userId: userId, resolved: resolved}, nil
+}
+
+func decide(resolved *resolvedPermissions, permissionId int) authz.PermissionDecision {
+	decision := authz.PermissionDecision{PermissionId: permissionId}
+	switch {
+	case model.FindPermissionById(permissionId) == nil:
+		decision.Reason = "permission is not registered in the catalog"
+	case len(resolved.Denies[permissionId]) > 0:
+		decision.Reason = "denied by " + joinSources(resolved.Denies[permissionId])
+	case resolved.Has(permissionId):
+		decision.Allowed = true
+		decision.Reason = "granted by " + joinSources(resolved.Grants[permissionId])
+	default:
+		decision.Reason = "permission not granted by any role"
+	}
+	return decision
+}
+
+// Explain detalla qué concesiones y denegaciones aplican a un usuario para un permiso
+func (s *AuthorizationServiceImpl) Explain(userId string, permissionId int) (*authz.ExplainResponse, error) {
+	if _, err := uuid.Parse(userId); err != nil {
+		return nil, fmt.Errorf("%w: invalid UUID format", service.ErrInvalidAuthorizationRequest)
+	}
+
+	user, err := s.userRepository.FindById(userId)
+	if err != nil {
+		log.Printf("Error fetching user to explain authorization: %v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, service.ErrSubjectNotFound
+	}
+
+	resolved, err := s.permissionResolver.resolve(user)
+	if err != nil {
+		log.Printf("Error resolving permissions for user %s: %v", userId, err)
+		return nil, err
+	}
+
+	decision := decide(resolved, permissionId)
+	response := &authz.ExplainResponse{
+		UserId:       userId,
+		PermissionId: permissionId,
+		Allowed:      decision.Allowed,
+		Reason:       decision.Reason,
+		Grants:       toAuthzSources(resolved.Grants[permissionId]),
+		Denies:       toAuthzSources(resolved.Denies[permissionId]),
+	}
+	switch {
+	case len(response.Denies) > 0:
+		response.DecidedBy = &response.Denies[0]
+	case decision.Allowed:
+		response.DecidedBy = &response.Grants[0]
+	}
+
+	return response, nil
+}
+
+func toAuthzSources(sources []permissionSource) []authz.Source {
+	result := make([]authz.Source, 0, len(sources))
+	for _, source := range sources {
+		result = append(result, authz.Source{Type: source.Type, Id: source.Id, Code: source.Code})
+	}
+	return result
+}
+
+func joinSources(sources []permissionSource) string {
+	descriptions := make([]string, 0, len(sources))
+	for _, source := range sources {
+		descriptions = append(descriptions, source.String())
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+func deny(subjectId, reason string) *authz.CheckResponse {
+	return &authz.CheckResponse{
+		Allowed:   false,
+		Reason:    reason,
+		SubjectId: subjectId,
+		Decisions: []authz.PermissionDecision{},
+	}
+}
@@ -0,0 +1,61 @@
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/authz"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func TestCheckDeniesInactiveSubjects(t *testing.T) {
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	tests := []struct {
+		name           string
+		status         string
+		suspendedUntil *time.Time
+		wantAllowed    bool
+		wantReason     string
+	}{
+		{"active", model.UserStatusActive, nil, true, "all permissions granted"},
+		{"without status", "", nil, true, "all permissions granted"},
+		{"suspension already over", model.UserStatusSuspended, &past, true, "all permissions granted"},
+		{"suspended", model.UserStatusSuspended, &future, false, "subject status is SUSPENDED"},
+		{"suspended indefinitely", model.UserStatusSuspended, nil, false, "subject status is SUSPENDED"},
+		{"deactivated", model.UserStatusDeactivated, nil, false, "subject status is DEACTIVATED"},
+		{"deleted", model.UserStatusDeleted, nil, false, "subject status is DELETED"},
+		{"erased", model.UserStatusErased, nil, false, "subject status is ERASED"},
+	}
+
+	const userId = "7f1c2d9e-6a0b-4c57-9d1e-3b2a4f5c6d7e"
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			authorizationService := &AuthorizationServiceImpl{
+				userRepository: newFakeUserRepository(&model.User{
+					Id:             userId,
+					RoleIds:        []string{"r-support"},
+					Status:         test.status,
+					SuspendedUntil: test.suspendedUntil,
+				}),
+				permissionResolver: &permissionResolver{
+					roleRepository:  newFakeRoleRepository(&model.Role{Id: "r-support", Code: "SUPPORT", Permissions: rolePermissions(model.GetUserById)}),
+					groupRepository: &fakeGroupRepository{},
+				},
+			}
+
+			response, err := authorizationService.Check(&authz.CheckRequest{
+				Subject:       authz.Subject{UserId: userId},
+				PermissionIds: []int{model.GetUserById},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if response.Allowed != test.wantAllowed || response.Reason != test.wantReason {
+				t.Fatalf("Check() = allowed %v reason %q, want allowed %v reason %q", response.Allowed, response.Reason, test.wantAllowed, test.wantReason)
+			}
+			if response.SubjectId != userId {
+				t.Fatalf("subjectId = %q, want %q", response.SubjectId, userId)
+			}
+		})
+	}
+}
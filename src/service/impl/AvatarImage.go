@@ -0,0 +1,101 @@
+package impl
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// avatarContentTypes son los formatos aceptados, detectados por el contenido y no por la extensión
+var avatarContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+const (
+	// maxAvatarDimension evita decodificar imágenes enormes que caben en pocos bytes
+	maxAvatarDimension = 4096
+	avatarJpegQuality  = 85
+)
+
+// decodeAvatar comprueba el formato y las dimensiones antes de decodificar la imagen
+func decodeAvatar(data []byte) (image.Image, error) {
+	if !avatarContentTypes[http.DetectContentType(data)] {
+		return nil, fmt.Errorf("%w: only JPEG, PNG and GIF images are accepted", service.ErrUnsupportedImage)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrInvalidImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > maxAvatarDimension || config.Height > maxAvatarDimension {
+		return nil, fmt.Errorf("%w: width and height must be at most %d pixels", service.ErrInvalidImage, maxAvatarDimension)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrInvalidImage, err)
+	}
+	return img, nil
+}
+
+// squareThumbnail recorta el centro de la imagen en un cuadrado y lo escala a size×size promediando
+// los píxeles de origen que cubre cada píxel de destino. La transparencia se compone sobre blanco.
+func squareThumbnail(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side)
+	source := image.NewRGBA(crop)
+	draw.Draw(source, crop, image.NewUniform(color.White), image.Point{}, draw.Src)
+	offset := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+	draw.Draw(source, crop, img, offset, draw.Over)
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := sourceSpan(y, size, side)
+		for x := 0; x < size; x++ {
+			x0, x1 := sourceSpan(x, size, side)
+			var r, g, b, count int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					offset := source.PixOffset(sx, sy)
+					r += int(source.Pix[offset])
+					g += int(source.Pix[offset+1])
+					b += int(source.Pix[offset+2])
+					count++
+				}
+			}
+			offset := thumbnail.PixOffset(x, y)
+			thumbnail.Pix[offset] = uint8(r / count)
+			thumbnail.Pix[offset+1] = uint8(g / count)
+			thumbnail.Pix[offset+2] = uint8(b / count)
+			thumbnail.Pix[offset+3] = 0xff
+		}
+	}
+	return thumbnail
+}
+
+// sourceSpan devuelve el rango de píxeles de origen que cubre el píxel de destino, con al menos un píxel
+func sourceSpan(position, size, side int) (int, int) {
+	start := position * side / size
+	end := (position + 1) * side / size
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
+
+func encodeJpeg(img image.Image) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: avatarJpegQuality}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
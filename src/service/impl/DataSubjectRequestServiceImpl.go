@@ -0,0 +1,436 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/blob"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/datarequest"
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+const (
+	// DefaultDataExportRetention es el tiempo que se conserva el archivo de una exportación si no se configura otro
+	DefaultDataExportRetention = 7 * 24 * time.Hour
+	// Solicitudes atendidas en cada ejecución del proceso
+	dataSubjectRequestBatchSize = 20
+	// Una solicitud en proceso desde hace más tiempo se considera interrumpida y se vuelve a intentar
+	dataSubjectRequestTimeout = 15 * time.Minute
+)
+
+// dataExportRetention se configura una sola vez al arrancar, antes de atender peticiones
+var dataExportRetention = DefaultDataExportRetention
+
+// SetDataExportRetention cambia el tiempo que se conservan los archivos de exportación
+func SetDataExportRetention(retention time.Duration) {
+	dataExportRetention = retention
+}
+
+type DataSubjectRequestServiceImpl struct {
+	dataSubjectRequestRepository     repository.DataSubjectRequestRepository
+	userRepository                   repository.UserRepository
+	roleRepository                   repository.RoleRepository
+	roleAssignmentRepository         repository.RoleAssignmentRepository
+	roleChangeRequestRepository      repository.RoleChangeRequestRepository
+	groupRepository                  repository.GroupRepository
+	organizationRepository           repository.OrganizationRepository
+	organizationMembershipRepository repository.OrganizationMembershipRepository
+	membershipCleaner                *userMembershipCleaner
+	dataSubjectRequestMapper         *mapper.DataSubjectRequestMapper
+}
+
+func NewDataSubjectRequestServiceImpl() *DataSubjectRequestServiceImpl {
+	return &DataSubjectRequestServiceImpl{
+		dataSubjectRequestRepository:     impl.NewDataSubjectRequestRepositoryImpl(),
+		userRepository:                   impl.NewUserRepositoryImpl(),
+		roleRepository:                   impl.NewRoleRepositoryImpl(),
+		roleAssignmentRepository:         impl.NewRoleAssignmentRepositoryImpl(),
+		roleChangeRequestRepository:      impl.NewRoleChangeRequestRepositoryImpl(),
+		groupRepository:                  impl.NewGroupRepositoryImpl(),
+		organizationRepository:           impl.NewOrganizationRepositoryImpl(),
+		organizationMembershipRepository: impl.NewOrganizationMembershipRepositoryImpl(),
+		membershipCleaner:                newUserMembershipCleaner(),
+		dataSubjectRequestMapper:         &mapper.DataSubjectRequestMapper{},
+	}
+}
+
+func (s *DataSubjectRequestServiceImpl) RequestExport(userId, actorId string) (*datarequest.DataSubjectRequestResponse, error) {
+	return s.createRequest(userId, actorId, model.DataSubjectRequestExport)
+}
+
+func (s *DataSubjectRequestServiceImpl) RequestErasure(userId, actorId string) (*datarequest.DataSubjectRequestResponse, error) {
+	return s.createRequest(userId, actorId, model.DataSubjectRequestErasure)
+}
+
+// createRequest registra la solicitud para que la atienda el proceso en segundo plano. Repetirla mientras
+// otra del mismo tipo está abierta devuelve la existente, así que los reintentos del cliente no duplican trabajo.
+func (s *DataSubjectRequestServiceImpl) createRequest(userId, actorId, requestType string) (*datarequest.DataSubjectRequestResponse, error) {
+	userModel, err := s.userRepository.FindById(userId)
+	if err != nil {
+		log.Printf("Error fetching user for data subject request: %v", err)
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+	if userModel.Status == model.UserStatusErased {
+		return nil, service.ErrUserErased
+	}
+
+	requests, err := s.dataSubjectRequestRepository.FindAll("", userId)
+	if err != nil {
+		log.Printf("Error fetching data subject requests: %v", err)
+		return nil, err
+	}
+	for _, request := range requests {
+		if request.Type == requestType && request.IsOpen() {
+			return s.dataSubjectRequestMapper.DataSubjectRequestToResponse(request), nil
+		}
+	}
+
+	request, err := s.dataSubjectRequestRepository.Create(&model.DataSubjectRequest{
+		UserId:      userId,
+		Type:        requestType,
+		Status:      model.DataSubjectRequestPending,
+		RequestedBy: actorId,
+		RequestedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("Error creating data subject request: %v", err)
+		return nil, err
+	}
+	return s.dataSubjectRequestMapper.DataSubjectRequestToResponse(request), nil
+}
+
+func (s *DataSubjectRequestServiceImpl) GetUserRequests(userId string) ([]*datarequest.DataSubjectRequestResponse, error) {
+	return s.GetRequests("", userId)
+}
+
+func (s *DataSubjectRequestServiceImpl) GetUserRequest(userId, id string) (*datarequest.DataSubjectRequestResponse, error) {
+	request, err := s.findUserRequest(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.dataSubjectRequestMapper.DataSubjectRequestToResponse(request), nil
+}
+
+func (s *DataSubjectRequestServiceImpl) GetRequests(status, userId string) ([]*datarequest.DataSubjectRequestResponse, error) {
+	requests, err := s.dataSubjectRequestRepository.FindAll(status, userId)
+	if err != nil {
+		log.Printf("Error fetching data subject requests: %v", err)
+		return nil, err
+	}
+	return s.dataSubjectRequestMapper.DataSubjectRequestsToResponses(requests), nil
+}
+
+// OpenDataExport lee el archivo del almacenamiento si lo sirve este servicio; si no, devuelve una URL firmada
+func (s *DataSubjectRequestServiceImpl) OpenDataExport(userId, id string) (*service.DataExportDownload, error) {
+	request, err := s.findUserRequest(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	store := blob.Default()
+	if request.Type != model.DataSubjectRequestExport || request.ArchiveKey == "" || store == nil {
+		return nil, service.ErrDataExportNotAvailable
+	}
+
+	ctx := context.Background()
+	if servingStore, ok := store.(blob.ServingBlobStore); ok {
+		reader, contentType, err := servingStore.Open(ctx, request.ArchiveKey)
+		if err == blob.ErrBlobNotFound {
+			return nil, service.ErrDataExportNotAvailable
+		}
+		if err != nil {
+			log.Printf("Error opening data export: %v", err)
+			return nil, err
+		}
+		return &service.DataExportDownload{Reader: reader, ContentType: contentType}, nil
+	}
+
+	url, err := store.URL(ctx, request.ArchiveKey)
+	if err != nil {
+		log.Printf("Error signing data export url: %v", err)
+		return nil, err
+	}
+	return &service.DataExportDownload{RedirectUrl: url}, nil
+}
+
+// findUserRequest devuelve la solicitud solo si pertenece al usuario, para no revelar las de otros
+func (s *DataSubjectRequestServiceImpl) findUserRequest(userId, id string) (*model.DataSubjectRequest, error) {
+	request, err := s.dataSubjectRequestRepository.FindById(id)
+	if err != nil {
+		log.Printf("Error fetching data subject request: %v", err)
+		return nil, err
+	}
+	if request == nil || request.UserId != userId {
+		return nil, service.ErrDataSubjectRequestNotFound
+	}
+	return request, nil
+}
+
+// ProcessRequests toma cada solicitud de forma atómica, de modo que varias instancias pueden ejecutarlo a la vez.
+// Un fallo devuelve la solicitud a PENDING hasta agotar los intentos; entonces queda en FAILED.
+func (s *DataSubjectRequestServiceImpl) ProcessRequests() (int, int, error) {
+	now := time.Now()
+	staleBefore := now.Add(-dataSubjectRequestTimeout)
+	processed, expired := 0, 0
+
+	requests, err := s.dataSubjectRequestRepository.FindClaimable(staleBefore, dataSubjectRequestBatchSize)
+	if err != nil {
+		return processed, expired, err
+	}
+	for _, candidate := range requests {
+		request, err := s.dataSubjectRequestRepository.Claim(candidate.Id, staleBefore, time.Now())
+		if err != nil {
+			return processed, expired, err
+		}
+		if request == nil {
+			continue
+		}
+
+		switch request.Type {
+		case model.DataSubjectRequestExport:
+			err = s.processExport(request)
+		case model.DataSubjectRequestErasure:
+			err = s.processErasure(request)
+		default:
+			err = fmt.Errorf("unknown request type %q", request.Type)
+		}
+		if err != nil {
+			log.Printf("Error processing data subject request %s: %v", request.Id, err)
+			s.recordFailure(request, err)
+			continue
+		}
+		processed++
+	}
+
+	archives, err := s.dataSubjectRequestRepository.FindExpiredArchives(now, dataSubjectRequestBatchSize)
+	if err != nil {
+		return processed, expired, err
+	}
+	for _, request := range archives {
+		if err := s.deleteArchive(request); err != nil {
+			return processed, expired, err
+		}
+		expired++
+	}
+
+	return processed, expired, nil
+}
+
+// processExport genera el archivo JSON con los datos del usuario y lo guarda en el almacenamiento de objetos
+func (s *DataSubjectRequestServiceImpl) processExport(request *model.DataSubjectRequest) error {
+	store := blob.Default()
+	if store == nil {
+		return fmt.Errorf("blob store not configured")
+	}
+
+	archive, err := s.buildExportArchive(request.UserId)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode data export: %v", err)
+	}
+
+	ctx := context.Background()
+	archiveKey := model.DataExportKeyPrefix + request.UserId + "/" + request.Id + "-" + uuid.NewString() + ".json"
+	if err := store.Put(ctx, archiveKey, "application/json", data); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(dataExportRetention)
+	request.Status = model.DataSubjectRequestCompleted
+	request.CompletedAt = &now
+	request.Error = ""
+	request.ArchiveKey = archiveKey
+	request.ArchiveExpiresAt = &expiresAt
+	if _, err := s.dataSubjectRequestRepository.Update(request); err != nil {
+		if deleteErr := store.Delete(ctx, archiveKey); deleteErr != nil {
+			log.Printf("Error deleting data export blob: %v", deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *DataSubjectRequestServiceImpl) buildExportArchive(userId string) (*datarequest.DataExportArchive, error) {
+	now := time.Now()
+	userModel, err := s.userRepository.FindById(userId)
+	if err != nil {
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+	if userModel.Status == model.UserStatusErased {
+		return nil, service.ErrUserErased
+	}
+
+	groups, err := s.groupRepository.FindByMemberId(userId)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := s.organizationMembershipRepository.FindByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.roleAssignmentRepository.FindHistoryByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	roleChangeRequests, err := s.roleChangeRequestRepository.FindAll("", userId)
+	if err != nil {
+		return nil, err
+	}
+	dataSubjectRequests, err := s.dataSubjectRequestRepository.FindAll("", userId)
+	if err != nil {
+		return nil, err
+	}
+
+	roleIds := append([]string{}, userModel.RoleIds...)
+	for _, assignment := range userModel.RoleAssignments {
+		roleIds = append(roleIds, assignment.RoleId)
+	}
+	rolesById := make(map[string]*model.Role)
+	if len(roleIds) > 0 {
+		roles, err := s.roleRepository.FindByIds(roleIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			rolesById[role.Id] = role
+		}
+	}
+
+	archive := &datarequest.DataExportArchive{
+		GeneratedAt:            now,
+		User:                   s.dataSubjectRequestMapper.UserToExportedUser(userModel, now),
+		Identities:             s.dataSubjectRequestMapper.UserToIdentities(userModel),
+		Roles:                  s.dataSubjectRequestMapper.UserToExportedRoles(userModel, rolesById),
+		Sessions:               []any{},
+		Groups:                 make([]datarequest.ExportedGroup, 0, len(groups)),
+		Organizations:          make([]datarequest.ExportedOrganization, 0, len(memberships)),
+		FavoriteNewsArticleIds: userModel.FavoriteNewsArticleIds,
+		AuditEntries: datarequest.ExportedAuditEntries{
+			RoleAssignmentHistory: make([]model.RoleAssignmentHistory, 0, len(history)),
+			RoleChangeRequests:    roleChangeRequests,
+			DeniedPermissionIds:   userModel.DeniedPermissionIds,
+		},
+		DataSubjectRequests: s.dataSubjectRequestMapper.DataSubjectRequestsToResponses(dataSubjectRequests),
+	}
+	if archive.FavoriteNewsArticleIds == nil {
+		archive.FavoriteNewsArticleIds = []string{}
+	}
+	if archive.AuditEntries.RoleChangeRequests == nil {
+		archive.AuditEntries.RoleChangeRequests = []*model.RoleChangeRequest{}
+	}
+	if archive.AuditEntries.DeniedPermissionIds == nil {
+		archive.AuditEntries.DeniedPermissionIds = []int{}
+	}
+	for _, entry := range history {
+		archive.AuditEntries.RoleAssignmentHistory = append(archive.AuditEntries.RoleAssignmentHistory, *entry)
+	}
+	for _, group := range groups {
+		archive.Groups = append(archive.Groups, datarequest.ExportedGroup{Id: group.Id, Name: group.Name, RoleIds: group.RoleIds})
+	}
+	for _, membership := range memberships {
+		organization := datarequest.ExportedOrganization{
+			Id:       membership.OrganizationId,
+			RoleIds:  membership.RoleIds,
+			JoinedAt: membership.CreatedAt,
+		}
+		if org, err := s.organizationRepository.FindById(membership.OrganizationId); err != nil {
+			return nil, err
+		} else if org != nil {
+			organization.Name = org.Name
+		}
+		archive.Organizations = append(archive.Organizations, organization)
+	}
+
+	return archive, nil
+}
+
+// processErasure quita al usuario de sus grupos y organizaciones, igual que la purga, y después, en una transacción,
+// anonimiza la cuenta, guarda el evento user.erased y completa la solicitud. Los pasos previos son idempotentes,
+// así que un reintento los repite sin efecto.
+func (s *DataSubjectRequestServiceImpl) processErasure(request *model.DataSubjectRequest) error {
+	if err := s.membershipCleaner.removeUser(request.UserId); err != nil {
+		return err
+	}
+
+	var imageFileKey string
+	err := s.dataSubjectRequestRepository.CompleteErasure(request, func(userModel *model.User) *model.OutboxEvent {
+		// Si el usuario ya no existe o ya se suprimió, solo queda completar la solicitud
+		if userModel == nil || userModel.Status == model.UserStatusErased {
+			return nil
+		}
+		now := time.Now()
+		imageFileKey = userModel.ImageFileKey
+		userModel.Anonymize(request.RequestedBy, now)
+		return &model.OutboxEvent{
+			Type:      model.UserErasedEvent,
+			Subject:   userModel.Id,
+			Data:      map[string]any{"requestId": request.Id},
+			CreatedAt: now,
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// Los objetos del almacenamiento quedan fuera de la transacción; un fallo solo deja archivos huérfanos
+	deleteAvatarBlobs(imageFileKey)
+	exports, err := s.dataSubjectRequestRepository.FindAll("", request.UserId)
+	if err != nil {
+		log.Printf("Error fetching data exports of erased user: %v", err)
+		return nil
+	}
+	for _, export := range exports {
+		if export.ArchiveKey != "" {
+			if err := s.deleteArchive(export); err != nil {
+				log.Printf("Error deleting data export of erased user: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// recordFailure guarda el error y devuelve la solicitud a la cola, o la da por fallida si agotó los intentos
+func (s *DataSubjectRequestServiceImpl) recordFailure(request *model.DataSubjectRequest, cause error) {
+	request.Error = cause.Error()
+	if request.Attempts >= model.MaxDataSubjectRequestAttempts {
+		now := time.Now()
+		request.Status = model.DataSubjectRequestFailed
+		request.CompletedAt = &now
+	} else {
+		request.Status = model.DataSubjectRequestPending
+	}
+	if _, err := s.dataSubjectRequestRepository.Update(request); err != nil {
+		// La solicitud sigue en PROCESSING y se reintentará cuando se considere interrumpida
+		log.Printf("Error recording data subject request failure: %v", err)
+	}
+}
+
+// deleteArchive borra el archivo de una exportación y lo desvincula de la solicitud
+func (s *DataSubjectRequestServiceImpl) deleteArchive(request *model.DataSubjectRequest) error {
+	if store := blob.Default(); store != nil {
+		if err := store.Delete(context.Background(), request.ArchiveKey); err != nil {
+			return err
+		}
+	}
+	request.ArchiveKey = ""
+	request.ArchiveExpiresAt = nil
+	_, err := s.dataSubjectRequestRepository.Update(request)
+	return err
+}
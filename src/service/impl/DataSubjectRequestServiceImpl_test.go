@@ -0,0 +1,81 @@
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func TestProcessErasure(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	users := newFakeUserRepository(&model.User{
+		Id:                     "u1",
+		Email:                  "ana@example.com",
+		FullName:               "Ana Pérez",
+		PasswordHash:           "hash",
+		RoleIds:                []string{"r1"},
+		DeniedPermissionIds:    []int{502},
+		RoleAssignments:        []model.RoleAssignment{{RoleId: "r2", ValidFrom: past, ValidUntil: time.Now().Add(time.Hour)}},
+		FavoriteNewsArticleIds: []string{"a1"},
+		Status:                 model.UserStatusActive,
+		AuthzVersion:           7,
+	})
+	groups := &fakeGroupRepository{groups: []*model.Group{{Id: "g1", MemberIds: []string{"u1", "u2"}}}}
+	organizations := &fakeOrganizationRepository{organizations: map[string]*model.Organization{
+		"o1": {Id: "o1", OwnerId: "u1"},
+	}}
+	memberships := &fakeOrganizationMembershipRepository{memberships: []*model.OrganizationMembership{
+		{OrganizationId: "o1", UserId: "u1"},
+		{OrganizationId: "o1", UserId: "u2"},
+	}}
+	requests := &fakeDataSubjectRequestRepository{requests: map[string]*model.DataSubjectRequest{}, users: users}
+	dataSubjectRequestService := &DataSubjectRequestServiceImpl{
+		dataSubjectRequestRepository: requests,
+		userRepository:               users,
+		membershipCleaner: &userMembershipCleaner{
+			groupRepository:                  groups,
+			organizationRepository:           organizations,
+			organizationMembershipRepository: memberships,
+		},
+	}
+
+	request := &model.DataSubjectRequest{Id: "d1", UserId: "u1", Type: model.DataSubjectRequestErasure, RequestedBy: "u1"}
+	if err := dataSubjectRequestService.processErasure(request); err != nil {
+		t.Fatal(err)
+	}
+
+	erased := users.users["u1"]
+	if erased.Status != model.UserStatusErased || erased.Email != "erased-u1@erased.invalid" || erased.FullName != "" || erased.PasswordHash != "" {
+		t.Fatalf("erased user = %+v, want personal data removed", erased)
+	}
+	if erased.RoleIds != nil || erased.DeniedPermissionIds != nil || erased.RoleAssignments != nil || erased.FavoriteNewsArticleIds != nil {
+		t.Fatalf("erased user = %+v, want roles and favorites removed", erased)
+	}
+	if erased.AuthzVersion != 8 {
+		t.Fatalf("authzVersion = %d, want 8", erased.AuthzVersion)
+	}
+	if len(requests.events) != 1 || requests.events[0].Type != model.UserErasedEvent || requests.events[0].Subject != "u1" {
+		t.Fatalf("events = %+v, want one user.erased event", requests.events)
+	}
+	if requests.requests["d1"].Status != model.DataSubjectRequestCompleted {
+		t.Fatalf("request status = %s, want %s", requests.requests["d1"].Status, model.DataSubjectRequestCompleted)
+	}
+	if memberIds := groups.groups[0].MemberIds; len(memberIds) != 1 || memberIds[0] != "u2" {
+		t.Fatalf("group members = %v, want [u2]", memberIds)
+	}
+	if len(memberships.memberships) != 1 || memberships.memberships[0].UserId != "u2" {
+		t.Fatalf("memberships = %+v, want only u2", memberships.memberships)
+	}
+	if organizations.organizations["o1"].OwnerId != "" {
+		t.Fatalf("owner = %q, want it cleared", organizations.organizations["o1"].OwnerId)
+	}
+
+	// Un reintento sobre un usuario ya suprimido completa la solicitud sin otro evento
+	if err := dataSubjectRequestService.processErasure(request); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests.events) != 1 || users.users["u1"].AuthzVersion != 8 {
+		t.Fatalf("retry produced %d events and authzVersion %d, want no changes", len(requests.events), users.users["u1"].AuthzVersion)
+	}
+}
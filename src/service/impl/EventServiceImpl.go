@@ -0,0 +1,72 @@
+package impl
+
+import (
+	"log"
+	"time"
+
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/event"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+)
+
+const (
+	defaultEventPageSize = 100
+	maxEventPageSize     = 500
+	// Los eventos más recientes se retienen este tiempo antes de entregarse: una transacción que empezó antes puede
+	// confirmarse después, y su evento quedaría detrás de un cursor ya entregado
+	eventSettleDelay = 10 * time.Second
+)
+
+// eventPageToken es el contenido del token del feed de eventos: la fecha y el ID del último evento entregado
+type eventPageToken struct {
+	CreatedAt time.Time `json:"t"`
+	Id        string    `json:"i"`
+}
+
+type EventServiceImpl struct {
+	outboxEventRepository repository.OutboxEventRepository
+}
+
+func NewEventServiceImpl() *EventServiceImpl {
+	return &EventServiceImpl{
+		outboxEventRepository: impl.NewOutboxEventRepositoryImpl(),
+	}
+}
+
+// GetEvents siempre devuelve un token: sin eventos nuevos es el mismo que se recibió, para seguir consultando desde ahí
+func (s *EventServiceImpl) GetEvents(pageToken string, size int) (*event.OutboxEventPageResponse, error) {
+	if size <= 0 {
+		size = defaultEventPageSize
+	}
+	size = min(size, maxEventPageSize)
+
+	var after *repository.PageCursor
+	if pageToken != "" {
+		var token eventPageToken
+		if err := dto.DecodePageToken(pageToken, &token); err != nil {
+			return nil, err
+		}
+		if token.Id == "" {
+			return nil, dto.ErrInvalidPageToken
+		}
+		after = &repository.PageCursor{SortValue: token.CreatedAt, Id: token.Id}
+	}
+
+	events, err := s.outboxEventRepository.FindAfter(after, time.Now().Add(-eventSettleDelay), size)
+	if err != nil {
+		log.Printf("Error fetching events: %v", err)
+		return nil, err
+	}
+
+	response := &event.OutboxEventPageResponse{Events: events, NextPageToken: pageToken}
+	if len(events) > 0 {
+		last := events[len(events)-1]
+		response.NextPageToken = dto.EncodePageToken(eventPageToken{CreatedAt: last.CreatedAt, Id: last.Id})
+	}
+	if response.Events == nil {
+		response.Events = []*model.OutboxEvent{}
+	}
+	return response, nil
+}
@@ -0,0 +1,134 @@
+package impl
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"time"
+
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// Registros leídos de Firestore en cada consulta de una exportación; cada lote se envía al cliente al leerlo
+const exportChunkSize = 500
+
+type ExportServiceImpl struct {
+	userRepository repository.UserRepository
+	roleRepository repository.RoleRepository
+}
+
+func NewExportServiceImpl() *ExportServiceImpl {
+	return &ExportServiceImpl{
+		userRepository: impl.NewUserRepositoryImpl(),
+		roleRepository: impl.NewRoleRepositoryImpl(),
+	}
+}
+
+// ExportUsers valida la búsqueda y los campos antes de escribir nada; después recorre los usuarios por lotes
+// con un cursor, sin cargar la colección en memoria
+func (s *ExportServiceImpl) ExportUsers(search *user.UserSearchRequest, request *dto.ExportRequest) (*service.Export, error) {
+	format, err := exportFormat(request.Format)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := userFilterFromSearch(search)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rolesById := make(map[string]*model.Role)
+	fields, err := selectExportFields(mapper.UserExportFields(rolesById, now), request.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(fields, func(field mapper.ExportField[*model.User]) bool { return field.Name == "roleCodes" }) {
+		roles, err := s.roleRepository.FindAll()
+		if err != nil {
+			log.Printf("Error fetching roles for export: %v", err)
+			return nil, err
+		}
+		for _, role := range roles {
+			rolesById[role.Id] = role
+		}
+	}
+
+	return &service.Export{
+		ContentType: exportContentType(format),
+		FileName:    fmt.Sprintf("users-%s.%s", now.UTC().Format("20060102-150405"), format),
+		Write: func(w io.Writer) error {
+			writer, err := newExportWriter(w, format, fields)
+			if err != nil {
+				return err
+			}
+			var after *repository.PageCursor
+			for {
+				users, err := s.userRepository.FindByFilterAfter(filter, after, exportChunkSize)
+				if err != nil {
+					return err
+				}
+				for _, userModel := range users {
+					if err := writer.write(userModel); err != nil {
+						return err
+					}
+				}
+				if err := writer.flush(); err != nil {
+					return err
+				}
+				if len(users) < exportChunkSize {
+					return nil
+				}
+				after = userPageCursor(users[len(users)-1], filter)
+			}
+		},
+	}, nil
+}
+
+func (s *ExportServiceImpl) ExportRoles(request *dto.ExportRequest) (*service.Export, error) {
+	format, err := exportFormat(request.Format)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := selectExportFields(mapper.RoleExportFields(), request.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.Export{
+		ContentType: exportContentType(format),
+		FileName:    fmt.Sprintf("roles-%s.%s", time.Now().UTC().Format("20060102-150405"), format),
+		Write: func(w io.Writer) error {
+			writer, err := newExportWriter(w, format, fields)
+			if err != nil {
+				return err
+			}
+			var after *repository.PageCursor
+			for {
+				roles, err := s.roleRepository.FindAllAfter(after, exportChunkSize)
+				if err != nil {
+					return err
+				}
+				for _, role := range roles {
+					if err := writer.write(role); err != nil {
+						return err
+					}
+				}
+				if err := writer.flush(); err != nil {
+					return err
+				}
+				if len(roles) < exportChunkSize {
+					return nil
+				}
+				last := roles[len(roles)-1]
+				after = &repository.PageCursor{SortValue: last.Code, Id: last.Id}
+			}
+		},
+	}, nil
+}
@@ -0,0 +1,175 @@
+package impl
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// Formatos de las exportaciones masivas
+const (
+	exportNdjson = "ndjson"
+	exportCsv    = "csv"
+)
+
+// exportFormat valida el formato pedido; sin indicar, NDJSON
+func exportFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		return exportNdjson, nil
+	}
+	if format != exportNdjson && format != exportCsv {
+		return "", fmt.Errorf("%w: format must be ndjson or csv", service.ErrInvalidExport)
+	}
+	return format, nil
+}
+
+// selectExportFields devuelve los campos pedidos, separados por comas, en ese orden; sin indicar, los de por defecto
+func selectExportFields[T any](fields []mapper.ExportField[T], requested string) ([]mapper.ExportField[T], error) {
+	if strings.TrimSpace(requested) == "" {
+		return slices.DeleteFunc(slices.Clone(fields), func(field mapper.ExportField[T]) bool { return !field.Default }), nil
+	}
+
+	var selected []mapper.ExportField[T]
+	for _, name := range strings.Split(requested, ",") {
+		name = strings.TrimSpace(name)
+		index := slices.IndexFunc(fields, func(field mapper.ExportField[T]) bool { return field.Name == name })
+		if index < 0 {
+			names := make([]string, 0, len(fields))
+			for _, field := range fields {
+				names = append(names, field.Name)
+			}
+			return nil, fmt.Errorf("%w: unknown field %q, use %s", service.ErrInvalidExport, name, strings.Join(names, ", "))
+		}
+		if !slices.ContainsFunc(selected, func(field mapper.ExportField[T]) bool { return field.Name == name }) {
+			selected = append(selected, fields[index])
+		}
+	}
+	return selected, nil
+}
+
+func exportContentType(format string) string {
+	if format == exportCsv {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// exportWriter escribe cada registro en NDJSON, un objeto por línea con los campos en el orden pedido,
+// o en CSV, con una cabecera con los nombres de los campos
+type exportWriter[T any] struct {
+	format    string
+	fields    []mapper.ExportField[T]
+	out       io.Writer
+	buffer    *bufio.Writer
+	csvWriter *csv.Writer
+}
+
+func newExportWriter[T any](w io.Writer, format string, fields []mapper.ExportField[T]) (*exportWriter[T], error) {
+	writer := &exportWriter[T]{format: format, fields: fields, out: w, buffer: bufio.NewWriter(w)}
+	if format == exportCsv {
+		writer.csvWriter = csv.NewWriter(writer.buffer)
+		header := make([]string, 0, len(fields))
+		for _, field := range fields {
+			header = append(header, field.Name)
+		}
+		if err := writer.csvWriter.Write(header); err != nil {
+			return nil, err
+		}
+	}
+	return writer, nil
+}
+
+func (w *exportWriter[T]) write(item T) error {
+	if w.format == exportCsv {
+		record := make([]string, 0, len(w.fields))
+		for _, field := range w.fields {
+			record = append(record, csvExportValue(field.Value(item)))
+		}
+		return w.csvWriter.Write(record)
+	}
+
+	w.buffer.WriteByte('{')
+	for index, field := range w.fields {
+		if index > 0 {
+			w.buffer.WriteByte(',')
+		}
+		name, _ := json.Marshal(field.Name)
+		value, err := json.Marshal(field.Value(item))
+		if err != nil {
+			return err
+		}
+		w.buffer.Write(name)
+		w.buffer.WriteByte(':')
+		w.buffer.Write(value)
+	}
+	w.buffer.WriteString("}\n")
+	return nil
+}
+
+// flush envía lo escrito hasta ahora al cliente, de modo que cada lote leído le llega sin esperar al final
+func (w *exportWriter[T]) flush() error {
+	if w.csvWriter != nil {
+		w.csvWriter.Flush()
+		if err := w.csvWriter.Error(); err != nil {
+			return err
+		}
+	}
+	if err := w.buffer.Flush(); err != nil {
+		return err
+	}
+	if flusher, ok := w.out.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// csvExportValue convierte el valor de un campo en el texto de su celda. Las listas se separan con ";", como en la
+// importación de usuarios, y los textos que una hoja de cálculo tomaría por una fórmula se escapan con "'".
+func csvExportValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case []string:
+		cells := make([]string, 0, len(v))
+		for _, item := range v {
+			cells = append(cells, csvExportValue(item))
+		}
+		return strings.Join(cells, ";")
+	case []int:
+		cells := make([]string, 0, len(v))
+		for _, item := range v {
+			cells = append(cells, strconv.Itoa(item))
+		}
+		return strings.Join(cells, ";")
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return csvExportValue(*v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,44 @@
+package impl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCsvExportValue(t *testing.T) {
+	instant := time.Date(2026, 3, 1, 10, 30, 0, 0, time.FixedZone("UTC-3", -3*60*60))
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"text", "Ana Pérez", "Ana Pérez"},
+		{"empty text", "", ""},
+		{"formula", "=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"plus sign", "+1 555", "'+1 555"},
+		{"minus sign", "-2", "'-2"},
+		{"at sign", "@SUM(A1)", "'@SUM(A1)"},
+		{"tab", "\tcmd", "'\tcmd"},
+		{"carriage return", "\rcmd", "'\rcmd"},
+		{"formula character inside the text", "ana+tag@example.com", "ana+tag@example.com"},
+		{"bool", true, "true"},
+		{"int64", int64(42), "42"},
+		{"string list", []string{"SUPPORT", "=ADMIN"}, "SUPPORT;'=ADMIN"},
+		{"empty string list", []string{}, ""},
+		{"int list", []int{601, 602}, "601;602"},
+		{"time in UTC", instant, "2026-03-01T13:30:00Z"},
+		{"zero time", time.Time{}, ""},
+		{"time pointer", &instant, "2026-03-01T13:30:00Z"},
+		{"nil time pointer", (*time.Time)(nil), ""},
+		{"other types", 3.5, "3.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := csvExportValue(tt.value); got != tt.want {
+				t.Fatalf("csvExportValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
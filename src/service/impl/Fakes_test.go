@@ -0,0 +1,431 @@
+package impl
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+)
+
+// Repositorios en memoria para las pruebas; los métodos no implementados entran en pánico
+
+type fakeRoleRepository struct {
+	repository.RoleRepository
+	roles map[string]*model.Role
+}
+
+func newFakeRoleRepository(roles ...*model.Role) *fakeRoleRepository {
+	repository := &fakeRoleRepository{roles: make(map[string]*model.Role)}
+	for _, role := range roles {
+		repository.roles[role.Id] = role
+	}
+	return repository
+}
+
+func (r *fakeRoleRepository) FindById(id string) (*model.Role, error) {
+	return r.roles[id], nil
+}
+
+func (r *fakeRoleRepository) FindByIds(ids []string) ([]*model.Role, error) {
+	var roles []*model.Role
+	for _, id := range ids {
+		if role, ok := r.roles[id]; ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepository) FindByCode(code string) (*model.Role, error) {
+	for _, role := range r.roles {
+		if role.Code == code && role.OrganizationId == "" {
+			return role, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRoleRepository) FindByOrganization(organizationId string) ([]*model.Role, error) {
+	var roles []*model.Role
+	for _, role := range r.roles {
+		if role.OrganizationId == organizationId {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepository) Update(role *model.Role) (*model.Role, error) {
+	r.roles[role.Id] = role
+	return role, nil
+}
+
+func (r *fakeRoleRepository) Delete(id string) error {
+	delete(r.roles, id)
+	return nil
+}
+
+type fakeGroupRepository struct {
+	repository.GroupRepository
+	groups []*model.Group
+}
+
+func (r *fakeGroupRepository) FindByMemberId(userId string) ([]*model.Group, error) {
+	var groups []*model.Group
+	for _, group := range r.groups {
+		if slices.Contains(group.MemberIds, userId) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func (r *fakeGroupRepository) FindByRoleId(roleId string) ([]*model.Group, error) {
+	var groups []*model.Group
+	for _, group := range r.groups {
+		if slices.Contains(group.RoleIds, roleId) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func (r *fakeGroupRepository) RemoveMember(groupId, userId string) error {
+	for _, group := range r.groups {
+		if group.Id == groupId {
+			group.MemberIds = slices.DeleteFunc(group.MemberIds, func(id string) bool { return id == userId })
+		}
+	}
+	return nil
+}
+
+func (r *fakeGroupRepository) Update(group *model.Group) (*model.Group, error) {
+	for i, existing := range r.groups {
+		if existing.Id == group.Id {
+			r.groups[i] = group
+		}
+	}
+	return group, nil
+}
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	users map[string]*model.User
+}
+
+func newFakeUserRepository(users ...*model.User) *fakeUserRepository {
+	repository := &fakeUserRepository{users: make(map[string]*model.User)}
+	for _, user := range users {
+		repository.users[user.Id] = user
+	}
+	return repository
+}
+
+func (r *fakeUserRepository) FindById(id string) (*model.User, error) {
+	return r.users[id], nil
+}
+
+func (r *fakeUserRepository) FindByIds(ids []string) ([]*model.User, error) {
+	var users []*model.User
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeUserRepository) FindByEmails(emails []string) ([]*model.User, error) {
+	var users []*model.User
+	for _, user := range r.users {
+		if slices.Contains(emails, user.Email) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// Update aplica el cambio sobre una copia y solo la guarda si no devuelve error, como la transacción real
+func (r *fakeUserRepository) Update(id string, change repository.UserChange) (*model.User, error) {
+	stored, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	user := *stored
+	user.RoleIds = slices.Clone(stored.RoleIds)
+	if err := change(&user); err != nil {
+		return nil, err
+	}
+	r.users[id] = &user
+	return &user, nil
+}
+
+// CreateAll no crea los usuarios cuyo email ya está registrado y devuelve esos emails, como la transacción real
+func (r *fakeUserRepository) CreateAll(users []*model.User) ([]string, error) {
+	var taken []string
+	for _, user := range users {
+		if registered, _ := r.FindByEmails([]string{user.Email}); len(registered) > 0 {
+			taken = append(taken, user.Email)
+			continue
+		}
+		if user.Id == "" {
+			user.Id = fmt.Sprintf("user-%d", len(r.users)+1)
+		}
+		r.users[user.Id] = user
+	}
+	return taken, nil
+}
+
+func (r *fakeUserRepository) FindIdsByRoleId(roleId string) ([]string, []string, error) {
+	var permanentIds, temporaryIds []string
+	for _, user := range r.users {
+		if slices.Contains(user.RoleIds, roleId) {
+			permanentIds = append(permanentIds, user.Id)
+		}
+		for _, assignment := range user.RoleAssignments {
+			if assignment.RoleId == roleId {
+				temporaryIds = append(temporaryIds, user.Id)
+			}
+		}
+	}
+	return permanentIds, temporaryIds, nil
+}
+
+func (r *fakeUserRepository) ReplaceRole(userIds []string, roleId, replacementId string) (int, error) {
+	for _, id := range userIds {
+		user := r.users[id]
+		user.RoleIds = slices.DeleteFunc(user.RoleIds, func(id string) bool { return id == roleId })
+		if replacementId != "" && !slices.Contains(user.RoleIds, replacementId) {
+			user.RoleIds = append(user.RoleIds, replacementId)
+		}
+	}
+	return len(userIds), nil
+}
+
+// DeleteIf evalúa la condición sobre una copia, como la transacción real sobre el documento leído
+func (r *fakeUserRepository) DeleteIf(id string, condition repository.UserCondition) (*model.User, error) {
+	stored, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	user := *stored
+	if !condition(&user) {
+		return nil, nil
+	}
+	delete(r.users, id)
+	return &user, nil
+}
+
+func (r *fakeUserRepository) FindPurgeable(now time.Time, limit int) ([]*model.User, error) {
+	var users []*model.User
+	for _, user := range r.users {
+		if user.Status == model.UserStatusDeleted && user.PurgeAt != nil && !user.PurgeAt.After(now) && len(users) < limit {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeUserRepository) FindExpiredSuspensions(now time.Time, limit int) ([]*model.User, error) {
+	var users []*model.User
+	for _, user := range r.users {
+		if user.Status == model.UserStatusSuspended && user.SuspendedUntil != nil && !user.SuspendedUntil.After(now) && len(users) < limit {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeUserRepository) BumpAuthzVersion(userIds []string) error {
+	for _, id := range userIds {
+		if user, ok := r.users[id]; ok {
+			user.AuthzVersion++
+		}
+	}
+	return nil
+}
+
+func rolePermissions(permissionIds ...int) *[]model.Permission {
+	permissions := make([]model.Permission, 0, len(permissionIds))
+	for _, id := range permissionIds {
+		permissions = append(permissions, model.Permission{Id: id})
+	}
+	return &permissions
+}
+
+type fakeSodConstraintRepository struct {
+	repository.SodConstraintRepository
+	constraints []*model.SodConstraint
+}
+
+func (r *fakeSodConstraintRepository) FindAll() ([]*model.SodConstraint, error) {
+	return r.constraints, nil
+}
+
+func (r *fakeSodConstraintRepository) Update(constraint *model.SodConstraint) (*model.SodConstraint, error) {
+	return constraint, nil
+}
+
+func (r *fakeSodConstraintRepository) Delete(id string) error {
+	r.constraints = slices.DeleteFunc(r.constraints, func(constraint *model.SodConstraint) bool { return constraint.Id == id })
+	return nil
+}
+
+type fakeRoleChangeRequestRepository struct {
+	repository.RoleChangeRequestRepository
+	requests map[string]*model.RoleChangeRequest
+	users    *fakeUserRepository
+}
+
+func (r *fakeRoleChangeRequestRepository) Create(request *model.RoleChangeRequest) (*model.RoleChangeRequest, error) {
+	request.Id = fmt.Sprintf("request-%d", len(r.requests)+1)
+	r.requests[request.Id] = request
+	return request, nil
+}
+
+func (r *fakeRoleChangeRequestRepository) FindAll(status, userId string) ([]*model.RoleChangeRequest, error) {
+	var requests []*model.RoleChangeRequest
+	for _, request := range r.requests {
+		if (status == "" || request.Status == status) && (userId == "" || request.UserId == userId) {
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+// Decide aplica la decisión sobre copias y solo las guarda si no devuelve error, como la transacción real
+func (r *fakeRoleChangeRequestRepository) Decide(id string, decision repository.RoleChangeDecision) (*model.RoleChangeRequest, error) {
+	stored, ok := r.requests[id]
+	if !ok {
+		return nil, nil
+	}
+	request := *stored
+	var user *model.User
+	if storedUser := r.users.users[request.UserId]; storedUser != nil {
+		userCopy := *storedUser
+		user = &userCopy
+	}
+	if _, err := decision(&request, user); err != nil {
+		return nil, err
+	}
+	r.requests[id] = &request
+	if user != nil {
+		r.users.users[user.Id] = user
+	}
+	return &request, nil
+}
+
+type fakeOrganizationRepository struct {
+	repository.OrganizationRepository
+	organizations map[string]*model.Organization
+}
+
+func (r *fakeOrganizationRepository) FindById(id string) (*model.Organization, error) {
+	return r.organizations[id], nil
+}
+
+func (r *fakeOrganizationRepository) FindByOwnerId(ownerId string) ([]*model.Organization, error) {
+	var organizations []*model.Organization
+	for _, organization := range r.organizations {
+		if organization.OwnerId == ownerId {
+			organizations = append(organizations, organization)
+		}
+	}
+	return organizations, nil
+}
+
+func (r *fakeOrganizationRepository) Update(organization *model.Organization) (*model.Organization, error) {
+	r.organizations[organization.Id] = organization
+	return organization, nil
+}
+
+type fakeOrganizationMembershipRepository struct {
+	repository.OrganizationMembershipRepository
+	memberships []*model.OrganizationMembership
+}
+
+func (r *fakeOrganizationMembershipRepository) Save(membership *model.OrganizationMembership) (*model.OrganizationMembership, error) {
+	for i, existing := range r.memberships {
+		if existing.OrganizationId == membership.OrganizationId && existing.UserId == membership.UserId {
+			r.memberships[i] = membership
+			return membership, nil
+		}
+	}
+	r.memberships = append(r.memberships, membership)
+	return membership, nil
+}
+
+func (r *fakeOrganizationMembershipRepository) FindByOrganizationAndUser(organizationId, userId string) (*model.OrganizationMembership, error) {
+	for _, membership := range r.memberships {
+		if membership.OrganizationId == organizationId && membership.UserId == userId {
+			return membership, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeOrganizationMembershipRepository) FindByOrganization(organizationId string) ([]*model.OrganizationMembership, error) {
+	var memberships []*model.OrganizationMembership
+	for _, membership := range r.memberships {
+		if membership.OrganizationId == organizationId {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (r *fakeOrganizationMembershipRepository) FindByUserId(userId string) ([]*model.OrganizationMembership, error) {
+	var memberships []*model.OrganizationMembership
+	for _, membership := range r.memberships {
+		if membership.UserId == userId {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (r *fakeOrganizationMembershipRepository) Delete(organizationId, userId string) error {
+	r.memberships = slices.DeleteFunc(r.memberships, func(membership *model.OrganizationMembership) bool {
+		return membership.OrganizationId == organizationId && membership.UserId == userId
+	})
+	return nil
+}
+
+type fakeDataSubjectRequestRepository struct {
+	repository.DataSubjectRequestRepository
+	requests map[string]*model.DataSubjectRequest
+	users    *fakeUserRepository
+	events   []*model.OutboxEvent
+}
+
+func (r *fakeDataSubjectRequestRepository) FindAll(status, userId string) ([]*model.DataSubjectRequest, error) {
+	var requests []*model.DataSubjectRequest
+	for _, request := range r.requests {
+		if (status == "" || request.Status == status) && (userId == "" || request.UserId == userId) {
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+// CompleteErasure aplica la supresión sobre una copia del usuario y guarda todo junto, como la transacción real
+func (r *fakeDataSubjectRequestRepository) CompleteErasure(request *model.DataSubjectRequest, erasure repository.UserErasure) error {
+	var user *model.User
+	if stored := r.users.users[request.UserId]; stored != nil {
+		userCopy := *stored
+		user = &userCopy
+	}
+	event := erasure(user)
+	if user != nil {
+		r.users.users[user.Id] = user
+	}
+	if event != nil {
+		r.events = append(r.events, event)
+	}
+	completed := *request
+	completed.Status = model.DataSubjectRequestCompleted
+	r.requests[request.Id] = &completed
+	return nil
+}
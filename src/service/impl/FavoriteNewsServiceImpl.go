@@ -0,0 +1,133 @@
+package impl
+
+import (
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/favorite"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// maxFavoritesPageSize acota el tamaño de página del listado de favoritos
+const maxFavoritesPageSize = 100
+
+type FavoriteNewsServiceImpl struct {
+	userRepository repository.UserRepository
+}
+
+func NewFavoriteNewsServiceImpl() *FavoriteNewsServiceImpl {
+	return &FavoriteNewsServiceImpl{
+		userRepository: impl.NewUserRepositoryImpl(),
+	}
+}
+
+// GetFavorites pagina los favoritos guardados en el usuario; se añaden al final, así que se recorren al revés
+func (s *FavoriteNewsServiceImpl) GetFavorites(c *gin.Context, userId string, pageable *dto.Pageable) (*dto.PaginationResponse[favorite.FavoriteNewsArticleResponse], error) {
+	userModel, err := s.findUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	pageable.Size = min(pageable.Size, maxFavoritesPageSize)
+
+	articleIds := slices.Clone(userModel.FavoriteNewsArticleIds)
+	slices.Reverse(articleIds)
+
+	start := min((pageable.Page-1)*pageable.Size, len(articleIds))
+	end := min(start+pageable.Size, len(articleIds))
+	favorites := make([]*favorite.FavoriteNewsArticleResponse, 0, end-start)
+	for _, articleId := range articleIds[start:end] {
+		favorites = append(favorites, &favorite.FavoriteNewsArticleResponse{ArticleId: articleId})
+	}
+
+	return dto.NewPaginationResponse(c, &favorites, len(articleIds), pageable), nil
+}
+
+// GetFavorite indica si la noticia está en los favoritos del usuario
+func (s *FavoriteNewsServiceImpl) GetFavorite(userId, articleId string) (*favorite.FavoriteNewsStatusResponse, error) {
+	if !model.IsValidNewsArticleId(articleId) {
+		return nil, service.ErrInvalidNewsArticleId
+	}
+	userModel, err := s.findUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	return favoriteStatus(userModel, articleId), nil
+}
+
+// AddFavorite añade la noticia; si ya estaba no cambia nada
+func (s *FavoriteNewsServiceImpl) AddFavorite(userId, articleId string) (*favorite.FavoriteNewsStatusResponse, error) {
+	if !model.IsValidNewsArticleId(articleId) {
+		return nil, service.ErrInvalidNewsArticleId
+	}
+
+	userModel, err := s.userRepository.AddFavoriteNewsArticle(userId, articleId, model.MaxFavoriteNewsArticles)
+	if errors.Is(err, repository.ErrFavoriteLimitReached) {
+		return nil, service.ErrFavoriteLimitReached
+	}
+	if err != nil {
+		log.Printf("Error adding favorite news article: %v", err)
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+	return favoriteStatus(userModel, articleId), nil
+}
+
+// RemoveFavorite quita la noticia; si no estaba no cambia nada
+func (s *FavoriteNewsServiceImpl) RemoveFavorite(userId, articleId string) (*favorite.FavoriteNewsStatusResponse, error) {
+	if !model.IsValidNewsArticleId(articleId) {
+		return nil, service.ErrInvalidNewsArticleId
+	}
+
+	found, err := s.userRepository.RemoveFavoriteNewsArticle(userId, articleId)
+	if err != nil {
+		log.Printf("Error removing favorite news article: %v", err)
+		return nil, err
+	}
+	if !found {
+		return nil, service.ErrUserNotFound
+	}
+	return s.GetFavorite(userId, articleId)
+}
+
+// CountFavorites usa una consulta de agregación, sin leer los documentos de los usuarios
+func (s *FavoriteNewsServiceImpl) CountFavorites(articleId string) (*favorite.NewsFavoriteCountResponse, error) {
+	if !model.IsValidNewsArticleId(articleId) {
+		return nil, service.ErrInvalidNewsArticleId
+	}
+
+	count, err := s.userRepository.CountByFavoriteNewsArticle(articleId)
+	if err != nil {
+		log.Printf("Error counting favorite news article: %v", err)
+		return nil, err
+	}
+	return &favorite.NewsFavoriteCountResponse{ArticleId: articleId, Count: count}, nil
+}
+
+func (s *FavoriteNewsServiceImpl) findUser(userId string) (*model.User, error) {
+	userModel, err := s.userRepository.FindById(userId)
+	if err != nil {
+		log.Printf("Error fetching user favorites: %v", err)
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+	return userModel, nil
+}
+
+func favoriteStatus(userModel *model.User, articleId string) *favorite.FavoriteNewsStatusResponse {
+	return &favorite.FavoriteNewsStatusResponse{
+		ArticleId: articleId,
+		Favorite:  slices.Contains(userModel.FavoriteNewsArticleIds, articleId),
+		Total:     len(userModel.FavoriteNewsArticleIds),
+		Limit:     model.MaxFavoriteNewsArticles,
+	}
+}
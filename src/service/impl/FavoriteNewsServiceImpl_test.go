@@ -0,0 +1,64 @@
+package impl
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+func TestFavoriteNewsGetFavoritesNewestFirst(t *testing.T) {
+	favoriteService := &FavoriteNewsServiceImpl{userRepository: newFakeUserRepository(
+		&model.User{Id: "u1", FavoriteNewsArticleIds: []string{"a1", "a2", "a3", "a4", "a5"}},
+	)}
+
+	tests := []struct {
+		name  string
+		page  int
+		size  int
+		want  []string
+		total int
+	}{
+		{"first page", 1, 2, []string{"a5", "a4"}, 5},
+		{"last page", 3, 2, []string{"a1"}, 5},
+		{"past the end", 4, 2, []string{}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/v1/me/favorites/news", nil)
+
+			response, err := favoriteService.GetFavorites(c, "u1", &dto.Pageable{Page: tt.page, Size: tt.size})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0)
+			for _, article := range *response.Data {
+				got = append(got, article.ArticleId)
+			}
+			if !slices.Equal(got, tt.want) || response.Page.TotalElements != tt.total {
+				t.Fatalf("GetFavorites() = %v of %d, want %v of %d", got, response.Page.TotalElements, tt.want, tt.total)
+			}
+		})
+	}
+}
+
+func TestFavoriteNewsRejectsInvalidArticleIds(t *testing.T) {
+	favoriteService := &FavoriteNewsServiceImpl{userRepository: newFakeUserRepository()}
+
+	if _, err := favoriteService.GetFavorite("u1", "news/1"); !errors.Is(err, service.ErrInvalidNewsArticleId) {
+		t.Fatalf("GetFavorite() error = %v, want %v", err, service.ErrInvalidNewsArticleId)
+	}
+	if _, err := favoriteService.AddFavorite("u1", ""); !errors.Is(err, service.ErrInvalidNewsArticleId) {
+		t.Fatalf("AddFavorite() error = %v, want %v", err, service.ErrInvalidNewsArticleId)
+	}
+	if _, err := favoriteService.GetFavorite("missing", "a1"); !errors.Is(err, service.ErrUserNotFound) {
+		t.Fatalf("GetFavorite() error = %v, want %v", err, service.ErrUserNotFound)
+	}
+}
@@ -0,0 +1,335 @@
+package impl
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/group"
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+type GroupServiceImpl struct {
+	groupRepository repository.GroupRepository
+	roleRepository  repository.RoleRepository
+	userRepository  repository.UserRepository
+	sodChecker      *sodChecker
+	groupMapper     *mapper.GroupMapper
+}
+
+func NewGroupServiceImpl() *GroupServiceImpl {
+	roleRepository := impl.NewRoleRepositoryImpl()
+	return &GroupServiceImpl{
+		groupRepository: impl.NewGroupRepositoryImpl(),
+		roleRepository:  roleRepository,
+		userRepository:  impl.NewUserRepositoryImpl(),
+		sodChecker:      newSodChecker(roleRepository),
+		groupMapper:     &mapper.GroupMapper{},
+	}
+}
+
+// CreateGroup crea un grupo con sus roles y miembros iniciales
+func (s *GroupServiceImpl) CreateGroup(request *group.CreateGroupRequest) (*group.GroupResponse, error) {
+	groupModel := s.groupMapper.CreateGroupRequestToGroup(request)
+	rolesById, err := s.validate(groupModel)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIds := distinct(groupModel.MemberIds)
+	members, err := s.findMembers(memberIds)
+	if err != nil {
+		return nil, err
+	}
+	groupModel.MemberIds = memberIds
+	if err := s.checkSod(groupModel, members); err != nil {
+		return nil, err
+	}
+
+	created, err := s.groupRepository.Create(groupModel)
+	if err != nil {
+		log.Printf("Error creating group: %v", err)
+		return nil, err
+	}
+	if err := s.bumpMembers(created.MemberIds); err != nil {
+		return nil, err
+	}
+
+	return s.groupMapper.GroupToResponse(created, rolesById), nil
+}
+
+// GetGroupById obtiene un grupo por su ID
+func (s *GroupServiceImpl) GetGroupById(id string) (*group.GroupResponse, error) {
+	groupModel, err := s.findGroup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rolesById, err := s.rolesById(groupModel.RoleIds)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupMapper.GroupToResponse(groupModel, rolesById), nil
+}
+
+// GetAllGroups obtiene todos los grupos
+func (s *GroupServiceImpl) GetAllGroups() ([]*group.GroupResponse, error) {
+	groups, err := s.groupRepository.FindAll()
+	if err != nil {
+		log.Printf("Error getting groups: %v", err)
+		return nil, err
+	}
+
+	var roleIds []string
+	for _, groupModel := range groups {
+		roleIds = append(roleIds, groupModel.RoleIds...)
+	}
+	rolesById, err := s.rolesById(distinct(roleIds))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*group.GroupResponse, 0, len(groups))
+	for _, groupModel := range groups {
+		responses = append(responses, s.groupMapper.GroupToResponse(groupModel, rolesById))
+	}
+	return responses, nil
+}
+
+// UpdateGroup actualiza los datos y roles del grupo; el cambio de roles aplica a todos sus miembros
+func (s *GroupServiceImpl) UpdateGroup(request *group.UpdateGroupRequest) (*group.GroupResponse, error) {
+	existing, err := s.findGroup(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	previousRoleIds := slices.Clone(existing.RoleIds)
+
+	groupModel := s.groupMapper.UpdateGroupRequestToGroup(request, existing)
+	rolesById, err := s.validate(groupModel)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.findMembers(groupModel.MemberIds)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSod(groupModel, members); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.groupRepository.Update(groupModel)
+	if err != nil {
+		log.Printf("Error updating group: %v", err)
+		return nil, err
+	}
+	if !sameElements(previousRoleIds, updated.RoleIds) {
+		if err := s.bumpMembers(updated.MemberIds); err != nil {
+			return nil, err
+		}
+	}
+	return s.groupMapper.GroupToResponse(updated, rolesById), nil
+}
+
+// DeleteGroupById elimina un grupo; sus miembros pierden de inmediato los roles heredados
+func (s *GroupServiceImpl) DeleteGroupById(id string) error {
+	groupModel, err := s.findGroup(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.groupRepository.Delete(id); err != nil {
+		log.Printf("Error deleting group: %v", err)
+		return err
+	}
+	return s.bumpMembers(groupModel.MemberIds)
+}
+
+// AddMember agrega un usuario al grupo
+func (s *GroupServiceImpl) AddMember(groupId, userId string) (*group.GroupResponse, error) {
+	groupModel, err := s.findGroup(groupId)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.findMembers([]string{userId})
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(groupModel.MemberIds, userId) {
+		if err := s.checkSod(groupModel, members); err != nil {
+			return nil, err
+		}
+		if err := s.groupRepository.AddMember(groupId, userId); err != nil {
+			log.Printf("Error adding group member: %v", err)
+			return nil, err
+		}
+		if err := s.bumpMembers([]string{userId}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetGroupById(groupId)
+}
+
+// RemoveMember quita un usuario del grupo
+func (s *GroupServiceImpl) RemoveMember(groupId, userId string) (*group.GroupResponse, error) {
+	groupModel, err := s.findGroup(groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.groupRepository.RemoveMember(groupId, userId); err != nil {
+		log.Printf("Error removing group member: %v", err)
+		return nil, err
+	}
+	if slices.Contains(groupModel.MemberIds, userId) {
+		if err := s.bumpMembers([]string{userId}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetGroupById(groupId)
+}
+
+// bumpMembers invalida los tokens de los usuarios cuyos roles heredados cambiaron
+func (s *GroupServiceImpl) bumpMembers(userIds []string) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	if err := s.userRepository.BumpAuthzVersion(userIds); err != nil {
+		log.Printf("Error bumping authorization version of group members: %v", err)
+		return err
+	}
+	return nil
+}
+
+// validate exige un nombre y roles globales existentes que no sean privilegiados
+func (s *GroupServiceImpl) validate(groupModel *model.Group) (map[string]*model.Role, error) {
+	if strings.TrimSpace(groupModel.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", service.ErrInvalidGroup)
+	}
+	groupModel.RoleIds = distinct(groupModel.RoleIds)
+
+	rolesById, err := s.rolesById(groupModel.RoleIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, roleId := range groupModel.RoleIds {
+		role, ok := rolesById[roleId]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%w: role %s does not exist", service.ErrInvalidGroup, roleId)
+		case role.OrganizationId != "":
+			return nil, fmt.Errorf("%w: role %s belongs to an organization", service.ErrInvalidGroup, role.Code)
+		case role.Privileged:
+			// Los roles privilegiados se asignan de forma individual y con aprobación
+			return nil, fmt.Errorf("%w: privileged role %s cannot be assigned to a group", service.ErrInvalidGroup, role.Code)
+		}
+	}
+	return rolesById, nil
+}
+
+// checkSod verifica que ningún miembro quede con roles mutuamente excluyentes al heredar los roles del grupo
+func (s *GroupServiceImpl) checkSod(groupModel *model.Group, members []*model.User) error {
+	if err := s.sodChecker.check(groupModel.RoleIds); err != nil {
+		return err
+	}
+	if len(groupModel.RoleIds) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, member := range members {
+		roleIds := append(member.HeldRoleIds(now), groupModel.RoleIds...)
+		groups, err := s.groupRepository.FindByMemberId(member.Id)
+		if err != nil {
+			log.Printf("Error getting groups of user %s: %v", member.Id, err)
+			return err
+		}
+		for _, other := range groups {
+			// Los roles actuales de este grupo se reemplazan por los nuevos
+			if other.Id != groupModel.Id {
+				roleIds = append(roleIds, other.RoleIds...)
+			}
+		}
+		if err := s.sodChecker.check(roleIds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GroupServiceImpl) findGroup(id string) (*model.Group, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, service.ErrGroupNotFound
+	}
+	groupModel, err := s.groupRepository.FindById(id)
+	if err != nil {
+		log.Printf("Error getting group: %v", err)
+		return nil, err
+	}
+	if groupModel == nil {
+		return nil, service.ErrGroupNotFound
+	}
+	return groupModel, nil
+}
+
+// findMembers obtiene los usuarios indicados; todos deben existir
+func (s *GroupServiceImpl) findMembers(userIds []string) ([]*model.User, error) {
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+	for _, userId := range userIds {
+		if _, err := uuid.Parse(userId); err != nil {
+			return nil, fmt.Errorf("%w: user %s does not exist", service.ErrInvalidGroup, userId)
+		}
+	}
+
+	users, err := s.userRepository.FindByIds(userIds)
+	if err != nil {
+		log.Printf("Error getting group members: %v", err)
+		return nil, err
+	}
+	for _, userId := range userIds {
+		if !slices.ContainsFunc(users, func(user *model.User) bool { return user.Id == userId }) {
+			return nil, fmt.Errorf("%w: user %s does not exist", service.ErrInvalidGroup, userId)
+		}
+	}
+	return users, nil
+}
+
+func (s *GroupServiceImpl) rolesById(roleIds []string) (map[string]*model.Role, error) {
+	rolesById := make(map[string]*model.Role)
+	if len(roleIds) == 0 {
+		return rolesById, nil
+	}
+
+	roles, err := s.roleRepository.FindByIds(roleIds)
+	if err != nil {
+		log.Printf("Error fetching roles of groups: %v", err)
+		return nil, err
+	}
+	for _, role := range roles {
+		rolesById[role.Id] = role
+	}
+	return rolesById, nil
+}
+
+// distinct devuelve los valores sin repetir, en su orden original
+func distinct(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
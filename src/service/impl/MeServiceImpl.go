@@ -0,0 +1,234 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/blob"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 8
+	// bcrypt ignora lo que sigue a los primeros 72 bytes
+	maxPasswordBytes  = 72
+	maxFullNameLength = 100
+)
+
+type MeServiceImpl struct {
+	userRepository repository.UserRepository
+	userService    service.UserService
+}
+
+func NewMeServiceImpl() *MeServiceImpl {
+	return &MeServiceImpl{
+		userRepository: impl.NewUserRepositoryImpl(),
+		userService:    NewUserServiceImpl(),
+	}
+}
+
+// GetMe devuelve el perfil del usuario autenticado, con sus roles
+func (s *MeServiceImpl) GetMe(userId string) (*user.GetUserByIdResponse, error) {
+	response := s.userService.GetUserById(userId)
+	if response == nil {
+		return nil, service.ErrUserNotFound
+	}
+	return response, nil
+}
+
+// UpdateMe aplica los campos presentes en la petición y devuelve el perfil actualizado
+func (s *MeServiceImpl) UpdateMe(userId string, request *user.UpdateMeRequest) (*user.GetUserByIdResponse, error) {
+	var fullName *string
+	if request.FullName != nil {
+		trimmed := strings.TrimSpace(*request.FullName)
+		if trimmed == "" || utf8.RuneCountInString(trimmed) > maxFullNameLength {
+			return nil, fmt.Errorf("%w: fullName must have between 1 and %d characters", service.ErrInvalidProfile, maxFullNameLength)
+		}
+		fullName = &trimmed
+	}
+
+	userModel, err := s.userRepository.Update(userId, func(userModel *model.User) error {
+		if fullName != nil {
+			userModel.FullName = *fullName
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error updating profile: %v", err)
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+	return s.GetMe(userId)
+}
+
+// ChangePassword reemplaza la contraseña si la actual es correcta. Las cuentas creadas con Google
+// no tienen contraseña y no pueden usarlo.
+func (s *MeServiceImpl) ChangePassword(userId string, request *user.ChangePasswordRequest) error {
+	if len(request.NewPassword) < minPasswordLength || len(request.NewPassword) > maxPasswordBytes {
+		return fmt.Errorf("%w: newPassword must have between %d and %d bytes", service.ErrInvalidPassword, minPasswordLength, maxPasswordBytes)
+	}
+
+	userModel, err := s.userRepository.FindById(userId)
+	if err != nil {
+		log.Printf("Error fetching user to change password: %v", err)
+		return err
+	}
+	if userModel == nil {
+		return service.ErrUserNotFound
+	}
+	if userModel.PasswordHash == "" ||
+		bcrypt.CompareHashAndPassword([]byte(userModel.PasswordHash), []byte(request.CurrentPassword)) != nil {
+		return service.ErrWrongCurrentPassword
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Printf("Error hashing password: %v", err)
+		return err
+	}
+	currentPasswordHash := userModel.PasswordHash
+
+	updated, err := s.userRepository.Update(userId, func(userModel *model.User) error {
+		// La contraseña verificada ya no es la actual si cambió mientras tanto
+		if userModel.PasswordHash != currentPasswordHash {
+			return service.ErrWrongCurrentPassword
+		}
+		userModel.PasswordHash = string(passwordHash)
+		// Las sesiones abiertas con la contraseña anterior deben iniciar sesión de nuevo
+		userModel.AuthzVersion++
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, service.ErrWrongCurrentPassword) {
+			log.Printf("Error updating password: %v", err)
+		}
+		return err
+	}
+	if updated == nil {
+		return service.ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteMe elimina la cuenta del usuario autenticado de forma lógica
+func (s *MeServiceImpl) DeleteMe(userId string) error {
+	// La cuenta se conserva durante la retención y la imagen se borra al purgarla
+	userModel, err := s.userRepository.Update(userId, func(userModel *model.User) error {
+		markUserDeleted(userModel, userId, time.Now())
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error deleting account: %v", err)
+		return err
+	}
+	if userModel == nil {
+		return service.ErrUserNotFound
+	}
+	return nil
+}
+
+// errBlobStoreNotConfigured indica que el servicio arrancó sin almacenamiento de objetos
+var errBlobStoreNotConfigured = errors.New("blob store not configured")
+
+// UploadAvatar guarda las versiones de la imagen bajo un prefijo nuevo y luego apunta el usuario a él,
+// de modo que las URLs de la imagen anterior nunca sirven la nueva
+func (s *MeServiceImpl) UploadAvatar(userId string, data []byte) (*user.GetUserByIdResponse, error) {
+	if len(data) > service.MaxAvatarBytes {
+		return nil, service.ErrImageTooLarge
+	}
+	store := blob.Default()
+	if store == nil {
+		return nil, errBlobStoreNotConfigured
+	}
+
+	img, err := decodeAvatar(data)
+	if err != nil {
+		return nil, err
+	}
+
+	existingUser, err := s.userRepository.FindById(userId)
+	if err != nil {
+		log.Printf("Error fetching user to upload avatar: %v", err)
+		return nil, err
+	}
+	if existingUser == nil {
+		return nil, service.ErrUserNotFound
+	}
+
+	ctx := context.Background()
+	imageFileKey := model.AvatarKeyPrefix + userId + "/" + uuid.NewString()
+	for _, size := range model.AvatarSizes {
+		encoded, err := encodeJpeg(squareThumbnail(img, size))
+		if err == nil {
+			err = store.Put(ctx, model.AvatarKey(imageFileKey, size), "image/jpeg", encoded)
+		}
+		if err != nil {
+			log.Printf("Error storing avatar: %v", err)
+			deleteAvatarBlobs(imageFileKey)
+			return nil, err
+		}
+	}
+
+	var previousImageFileKey string
+	userModel, err := s.userRepository.Update(userId, func(userModel *model.User) error {
+		previousImageFileKey = userModel.ImageFileKey
+		userModel.ImageFileKey = imageFileKey
+		return nil
+	})
+	if err != nil || userModel == nil {
+		deleteAvatarBlobs(imageFileKey)
+		if err != nil {
+			log.Printf("Error updating avatar: %v", err)
+			return nil, err
+		}
+		return nil, service.ErrUserNotFound
+	}
+	deleteAvatarBlobs(previousImageFileKey)
+	return s.GetMe(userId)
+}
+
+// DeleteAvatar quita la imagen subida por el usuario
+func (s *MeServiceImpl) DeleteAvatar(userId string) (*user.GetUserByIdResponse, error) {
+	var previousImageFileKey string
+	userModel, err := s.userRepository.Update(userId, func(userModel *model.User) error {
+		previousImageFileKey = userModel.ImageFileKey
+		userModel.ImageFileKey = ""
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error deleting avatar: %v", err)
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+	deleteAvatarBlobs(previousImageFileKey)
+	return s.GetMe(userId)
+}
+
+// deleteAvatarBlobs elimina las versiones de una imagen; un fallo solo deja objetos huérfanos, así que se registra y se ignora
+func deleteAvatarBlobs(imageFileKey string) {
+	store := blob.Default()
+	if imageFileKey == "" || store == nil {
+		return
+	}
+	for _, size := range model.AvatarSizes {
+		if err := store.Delete(context.Background(), model.AvatarKey(imageFileKey, size)); err != nil {
+			log.Printf("Error deleting avatar blob: %v", err)
+		}
+	}
+}
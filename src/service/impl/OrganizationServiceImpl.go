@@ -0,0 +1,445 @@
+package impl
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/organization"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/role"
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// OrganizationAdminRoleCode es el rol que se crea con cada organización y se asigna a su propietario
+const OrganizationAdminRoleCode = "ORG_ADMIN"
+
+// organizationAdminPermissionIds son los permisos del rol ORG_ADMIN, que no se le pueden quitar ni denegar
+var organizationAdminPermissionIds = []int{model.GetOrganization, model.UpdateOrganization, model.ManageOrganizationMembers, model.ManageOrganizationRoles}
+
+type OrganizationServiceImpl struct {
+	organizationRepository repository.OrganizationRepository
+	membershipRepository   repository.OrganizationMembershipRepository
+	roleRepository         repository.RoleRepository
+	userRepository         repository.UserRepository
+	sodChecker             *sodChecker
+	organizationMapper     *mapper.OrganizationMapper
+	roleMapper             *mapper.RoleMapper
+}
+
+func NewOrganizationServiceImpl() *OrganizationServiceImpl {
+	roleRepository := impl.NewRoleRepositoryImpl()
+	return &OrganizationServiceImpl{
+		organizationRepository: impl.NewOrganizationRepositoryImpl(),
+		membershipRepository:   impl.NewOrganizationMembershipRepositoryImpl(),
+		roleRepository:         roleRepository,
+		userRepository:         impl.NewUserRepositoryImpl(),
+		sodChecker:             newSodChecker(roleRepository),
+		organizationMapper:     &mapper.OrganizationMapper{},
+		roleMapper:             &mapper.RoleMapper{},
+	}
+}
+
+// CreateOrganization crea la organización, su rol ORG_ADMIN y la membresía del propietario
+func (s *OrganizationServiceImpl) CreateOrganization(request *organization.CreateOrganizationRequest) (*organization.OrganizationResponse, error) {
+	if strings.TrimSpace(request.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", service.ErrInvalidOrganization)
+	}
+	if _, err := s.findUser(request.OwnerId); err != nil {
+		return nil, err
+	}
+
+	created, err := s.organizationRepository.Create(s.organizationMapper.CreateOrganizationRequestToOrganization(request))
+	if err != nil {
+		log.Printf("Error creating organization: %v", err)
+		return nil, err
+	}
+
+	adminRole, err := s.roleRepository.Create(&model.Role{
+		Code:           OrganizationAdminRoleCode,
+		Permissions:    model.FindPermissionsByIds(organizationAdminPermissionIds),
+		OrganizationId: created.Id,
+		System:         true,
+	})
+	if err != nil {
+		log.Printf("Error creating admin role of organization %s: %v", created.Id, err)
+		return nil, err
+	}
+
+	_, err = s.membershipRepository.Save(&model.OrganizationMembership{
+		OrganizationId: created.Id,
+		UserId:         request.OwnerId,
+		RoleIds:        []string{adminRole.Id},
+	})
+	if err != nil {
+		log.Printf("Error creating owner membership of organization %s: %v", created.Id, err)
+		return nil, err
+	}
+	if err := s.userRepository.BumpAuthzVersion([]string{request.OwnerId}); err != nil {
+		log.Printf("Error bumping authorization version of user %s: %v", request.OwnerId, err)
+		return nil, err
+	}
+
+	return s.organizationMapper.OrganizationToResponse(created), nil
+}
+
+// GetOrganizationById obtiene una organización por su ID
+func (s *OrganizationServiceImpl) GetOrganizationById(id string) (*organization.OrganizationResponse, error) {
+	organizationModel, err := s.findOrganization(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.organizationMapper.OrganizationToResponse(organizationModel), nil
+}
+
+// GetAllOrganizations obtiene todas las organizaciones
+func (s *OrganizationServiceImpl) GetAllOrganizations() ([]*organization.OrganizationResponse, error) {
+	organizations, err := s.organizationRepository.FindAll()
+	if err != nil {
+		log.Printf("Error getting organizations: %v", err)
+		return nil, err
+	}
+
+	responses := make([]*organization.OrganizationResponse, 0, len(organizations))
+	for _, organizationModel := range organizations {
+		responses = append(responses, s.organizationMapper.OrganizationToResponse(organizationModel))
+	}
+	return responses, nil
+}
+
+// UpdateOrganization actualiza los datos de una organización
+func (s *OrganizationServiceImpl) UpdateOrganization(id string, request *organization.UpdateOrganizationRequest) (*organization.OrganizationResponse, error) {
+	if strings.TrimSpace(request.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", service.ErrInvalidOrganization)
+	}
+	organizationModel, err := s.findOrganization(id)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationModel.Name = request.Name
+	updated, err := s.organizationRepository.Update(organizationModel)
+	if err != nil {
+		log.Printf("Error updating organization: %v", err)
+		return nil, err
+	}
+	return s.organizationMapper.OrganizationToResponse(updated), nil
+}
+
+// GetMembers lista los miembros de una organización con sus roles en ella
+func (s *OrganizationServiceImpl) GetMembers(organizationId string) ([]*organization.OrganizationMemberResponse, error) {
+	if _, err := s.findOrganization(organizationId); err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.membershipRepository.FindByOrganization(organizationId)
+	if err != nil {
+		log.Printf("Error getting members of organization %s: %v", organizationId, err)
+		return nil, err
+	}
+	users, err := s.userRepository.FindByOrganization(organizationId)
+	if err != nil {
+		log.Printf("Error getting users of organization %s: %v", organizationId, err)
+		return nil, err
+	}
+	usersById := make(map[string]*model.User, len(users))
+	for _, user := range users {
+		usersById[user.Id] = user
+	}
+
+	rolesById, err := s.organizationRolesById(organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*organization.OrganizationMemberResponse, 0, len(memberships))
+	for _, membership := range memberships {
+		responses = append(responses, s.organizationMapper.ToOrganizationMemberResponse(membership, usersById[membership.UserId], rolesById))
+	}
+	return responses, nil
+}
+
+// SetMember agrega al usuario a la organización o reemplaza sus roles en ella
+func (s *OrganizationServiceImpl) SetMember(organizationId, userId string, request *organization.SetOrganizationMemberRequest) (*organization.OrganizationMemberResponse, error) {
+	organizationModel, err := s.findOrganization(organizationId)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rolesById, err := s.organizationRolesById(organizationId)
+	if err != nil {
+		return nil, err
+	}
+	roleIds := make([]string, 0, len(request.RoleIds))
+	for _, roleId := range request.RoleIds {
+		// Solo se admiten roles de esta organización
+		if _, ok := rolesById[roleId]; !ok {
+			return nil, fmt.Errorf("%w: role %s does not belong to the organization", service.ErrOrganizationRoleNotFound, roleId)
+		}
+		if !slices.Contains(roleIds, roleId) {
+			roleIds = append(roleIds, roleId)
+		}
+	}
+	// Sin ORG_ADMIN el propietario no podría recuperar la administración y solo un administrador global la repararía
+	if userId == organizationModel.OwnerId && !slices.ContainsFunc(roleIds, func(roleId string) bool { return isOrganizationAdminRole(rolesById[roleId]) }) {
+		return nil, fmt.Errorf("%w: the owner must keep the %s role", service.ErrInvalidOrganization, OrganizationAdminRoleCode)
+	}
+	if err := s.sodChecker.check(roleIds); err != nil {
+		return nil, err
+	}
+
+	membership, err := s.membershipRepository.FindByOrganizationAndUser(organizationId, userId)
+	if err != nil {
+		log.Printf("Error getting organization membership: %v", err)
+		return nil, err
+	}
+	if membership == nil {
+		membership = &model.OrganizationMembership{OrganizationId: organizationId, UserId: userId}
+	}
+	membership.RoleIds = roleIds
+
+	saved, err := s.membershipRepository.Save(membership)
+	if err != nil {
+		log.Printf("Error saving organization membership: %v", err)
+		return nil, err
+	}
+	// Los tokens del usuario incluyen sus roles por organización
+	if err := s.userRepository.BumpAuthzVersion([]string{userId}); err != nil {
+		log.Printf("Error bumping authorization version of user %s: %v", userId, err)
+		return nil, err
+	}
+	return s.organizationMapper.ToOrganizationMemberResponse(saved, user, rolesById), nil
+}
+
+// RemoveMember quita al usuario de la organización
+func (s *OrganizationServiceImpl) RemoveMember(organizationId, userId string) error {
+	organizationModel, err := s.findOrganization(organizationId)
+	if err != nil {
+		return err
+	}
+	if organizationModel.OwnerId == userId {
+		return fmt.Errorf("%w: the owner cannot be removed from the organization", service.ErrInvalidOrganization)
+	}
+
+	membership, err := s.membershipRepository.FindByOrganizationAndUser(organizationId, userId)
+	if err != nil {
+		log.Printf("Error getting organization membership: %v", err)
+		return err
+	}
+	if membership == nil {
+		return service.ErrOrganizationMemberNotFound
+	}
+
+	if err := s.membershipRepository.Delete(organizationId, userId); err != nil {
+		log.Printf("Error deleting organization membership: %v", err)
+		return err
+	}
+	if err := s.userRepository.BumpAuthzVersion([]string{userId}); err != nil {
+		log.Printf("Error bumping authorization version of user %s: %v", userId, err)
+		return err
+	}
+	return nil
+}
+
+// GetRoles lista los roles de una organización
+func (s *OrganizationServiceImpl) GetRoles(organizationId string) ([]*role.GetRoleByIdResponse, error) {
+	if _, err := s.findOrganization(organizationId); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.roleRepository.FindByOrganization(organizationId)
+	if err != nil {
+		log.Printf("Error getting roles of organization %s: %v", organizationId, err)
+		return nil, err
+	}
+
+	responses := make([]*role.GetRoleByIdResponse, 0, len(roles))
+	for _, roleModel := range roles {
+		responses = append(responses, s.roleMapper.RoleToGetRoleByIdResponse(roleModel))
+	}
+	return responses, nil
+}
+
+// CreateRole crea un rol que solo aplica dentro de la organización
+func (s *OrganizationServiceImpl) CreateRole(organizationId string, request *organization.OrganizationRoleRequest) (*role.GetRoleByIdResponse, error) {
+	if _, err := s.findOrganization(organizationId); err != nil {
+		return nil, err
+	}
+	if err := s.validateRole(organizationId, "", request); err != nil {
+		return nil, err
+	}
+
+	created, err := s.roleRepository.Create(s.organizationMapper.OrganizationRoleRequestToRole(organizationId, request, nil))
+	if err != nil {
+		log.Printf("Error creating organization role: %v", err)
+		return nil, err
+	}
+	return s.roleMapper.RoleToGetRoleByIdResponse(created), nil
+}
+
+// UpdateRole actualiza un rol de la organización
+func (s *OrganizationServiceImpl) UpdateRole(organizationId, roleId string, request *organization.OrganizationRoleRequest) (*role.GetRoleByIdResponse, error) {
+	existing, err := s.findRole(organizationId, roleId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateRole(organizationId, roleId, request); err != nil {
+		return nil, err
+	}
+	if isOrganizationAdminRole(existing) {
+		if err := validateOrganizationAdminRole(request); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.roleRepository.Update(s.organizationMapper.OrganizationRoleRequestToRole(organizationId, request, existing))
+	if err != nil {
+		log.Printf("Error updating organization role: %v", err)
+		return nil, err
+	}
+	return s.roleMapper.RoleToGetRoleByIdResponse(updated), nil
+}
+
+// DeleteRole elimina el rol y lo quita de las membresías que lo tenían. El rol ORG_ADMIN no se puede eliminar.
+func (s *OrganizationServiceImpl) DeleteRole(organizationId, roleId string) error {
+	roleModel, err := s.findRole(organizationId, roleId)
+	if err != nil {
+		return err
+	}
+	if isOrganizationAdminRole(roleModel) {
+		return service.ErrSystemRole
+	}
+
+	memberships, err := s.membershipRepository.FindByOrganization(organizationId)
+	if err != nil {
+		log.Printf("Error getting members of organization %s: %v", organizationId, err)
+		return err
+	}
+	for _, membership := range memberships {
+		if !slices.Contains(membership.RoleIds, roleId) {
+			continue
+		}
+		membership.RoleIds = slices.DeleteFunc(membership.RoleIds, func(id string) bool { return id == roleId })
+		if _, err := s.membershipRepository.Save(membership); err != nil {
+			log.Printf("Error removing role %s from organization membership: %v", roleId, err)
+			return err
+		}
+	}
+
+	if err := s.roleRepository.Delete(roleId); err != nil {
+		log.Printf("Error deleting organization role: %v", err)
+		return err
+	}
+	return nil
+}
+
+// validateRole exige un código único en la organización y permisos que se puedan conceder dentro de ella
+func (s *OrganizationServiceImpl) validateRole(organizationId, roleId string, request *organization.OrganizationRoleRequest) error {
+	if !model.IsValidRoleCode(request.Code) {
+		return fmt.Errorf("%w: %w", service.ErrInvalidOrganizationRole, service.ErrInvalidRoleCode)
+	}
+	for _, permissionId := range slices.Concat(request.Permissions, request.DeniedPermissions) {
+		if !model.IsOrganizationScoped(permissionId) {
+			return fmt.Errorf("%w: permission %d cannot be granted within an organization", service.ErrInvalidOrganizationRole, permissionId)
+		}
+	}
+
+	roles, err := s.roleRepository.FindByOrganization(organizationId)
+	if err != nil {
+		log.Printf("Error getting roles of organization %s: %v", organizationId, err)
+		return err
+	}
+	for _, existing := range roles {
+		if existing.Id != roleId && existing.Code == request.Code {
+			return fmt.Errorf("%w: code %s is already used in the organization", service.ErrInvalidOrganizationRole, request.Code)
+		}
+	}
+	return nil
+}
+
+// isOrganizationAdminRole indica si el rol es el ORG_ADMIN de su organización. Los creados antes de marcarlos
+// como roles del sistema se reconocen por el código, que es único dentro de la organización.
+func isOrganizationAdminRole(roleModel *model.Role) bool {
+	return roleModel != nil && roleModel.OrganizationId != "" && (roleModel.System || roleModel.Code == OrganizationAdminRoleCode)
+}
+
+// validateOrganizationAdminRole impide renombrar el rol ORG_ADMIN o quitarle los permisos de administración
+func validateOrganizationAdminRole(request *organization.OrganizationRoleRequest) error {
+	if request.Code != OrganizationAdminRoleCode {
+		return service.ErrSystemRole
+	}
+	for _, permissionId := range organizationAdminPermissionIds {
+		if !slices.Contains(request.Permissions, permissionId) || slices.Contains(request.DeniedPermissions, permissionId) {
+			return fmt.Errorf("%w: the %s role must keep permission %d", service.ErrInvalidOrganizationRole, OrganizationAdminRoleCode, permissionId)
+		}
+	}
+	return nil
+}
+
+func (s *OrganizationServiceImpl) findOrganization(id string) (*model.Organization, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, service.ErrOrganizationNotFound
+	}
+	organizationModel, err := s.organizationRepository.FindById(id)
+	if err != nil {
+		log.Printf("Error getting organization: %v", err)
+		return nil, err
+	}
+	if organizationModel == nil {
+		return nil, service.ErrOrganizationNotFound
+	}
+	return organizationModel, nil
+}
+
+func (s *OrganizationServiceImpl) findUser(id string) (*model.User, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	user, err := s.userRepository.FindById(id)
+	if err != nil {
+		log.Printf("Error getting user: %v", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, service.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// findRole devuelve el rol solo si pertenece a la organización
+func (s *OrganizationServiceImpl) findRole(organizationId, roleId string) (*model.Role, error) {
+	if _, err := s.findOrganization(organizationId); err != nil {
+		return nil, err
+	}
+	roleModel, err := s.roleRepository.FindById(roleId)
+	if err != nil {
+		log.Printf("Error getting organization role: %v", err)
+		return nil, err
+	}
+	if roleModel == nil || roleModel.OrganizationId != organizationId {
+		return nil, service.ErrOrganizationRoleNotFound
+	}
+	return roleModel, nil
+}
+
+func (s *OrganizationServiceImpl) organizationRolesById(organizationId string) (map[string]*model.Role, error) {
+	roles, err := s.roleRepository.FindByOrganization(organizationId)
+	if err != nil {
+		log.Printf("Error getting roles of organization %s: %v", organizationId, err)
+		return nil, err
+	}
+	rolesById := make(map[string]*model.Role, len(roles))
+	for _, roleModel := range roles {
+		rolesById[roleModel.Id] = roleModel
+	}
+	return rolesById, nil
+}
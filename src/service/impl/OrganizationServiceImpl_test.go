@@ -0,0 +1,126 @@
+package impl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/organization"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+const (
+	testOrganizationId = "7a4f3c2e-1b5d-4e6f-8a9b-0c1d2e3f4a5b"
+	testOwnerId        = "1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"
+	testMemberId       = "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a"
+)
+
+func newTestOrganizationService() *OrganizationServiceImpl {
+	roleRepository := newFakeRoleRepository(
+		&model.Role{Id: "r-admin", Code: OrganizationAdminRoleCode, OrganizationId: testOrganizationId, System: true},
+		// Creado antes de marcar ORG_ADMIN como rol del sistema
+		&model.Role{Id: "r-legacy-admin", Code: OrganizationAdminRoleCode, OrganizationId: "other-organization"},
+		&model.Role{Id: "r-seller", Code: "SELLER", OrganizationId: testOrganizationId},
+	)
+	return &OrganizationServiceImpl{
+		organizationRepository: &fakeOrganizationRepository{organizations: map[string]*model.Organization{
+			testOrganizationId: {Id: testOrganizationId, Name: "Store", OwnerId: testOwnerId},
+		}},
+		membershipRepository: &fakeOrganizationMembershipRepository{memberships: []*model.OrganizationMembership{
+			{OrganizationId: testOrganizationId, UserId: testOwnerId, RoleIds: []string{"r-admin"}},
+			{OrganizationId: testOrganizationId, UserId: testMemberId, RoleIds: []string{"r-admin", "r-seller"}},
+		}},
+		roleRepository: roleRepository,
+		userRepository: newFakeUserRepository(
+			&model.User{Id: testOwnerId, Email: "owner@example.com"},
+			&model.User{Id: testMemberId, Email: "member@example.com"},
+		),
+		sodChecker: newTestSodChecker(),
+	}
+}
+
+func TestOrganizationSetMemberKeepsOwnerAdmin(t *testing.T) {
+	tests := []struct {
+		name    string
+		userId  string
+		roleIds []string
+		wantErr error
+	}{
+		{"owner keeps admin", testOwnerId, []string{"r-admin", "r-seller"}, nil},
+		{"owner loses admin", testOwnerId, []string{"r-seller"}, service.ErrInvalidOrganization},
+		{"owner loses every role", testOwnerId, []string{}, service.ErrInvalidOrganization},
+		{"member loses admin", testMemberId, []string{"r-seller"}, nil},
+		{"role of another organization", testMemberId, []string{"r-legacy-admin"}, service.ErrOrganizationRoleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			organizationService := newTestOrganizationService()
+			_, err := organizationService.SetMember(testOrganizationId, tt.userId, &organization.SetOrganizationMemberRequest{RoleIds: tt.roleIds})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetMember() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOrganizationDeleteRoleProtectsAdmin(t *testing.T) {
+	organizationService := newTestOrganizationService()
+
+	if err := organizationService.DeleteRole(testOrganizationId, "r-admin"); !errors.Is(err, service.ErrSystemRole) {
+		t.Fatalf("DeleteRole(ORG_ADMIN) error = %v, want %v", err, service.ErrSystemRole)
+	}
+	if err := organizationService.DeleteRole(testOrganizationId, "r-seller"); err != nil {
+		t.Fatalf("DeleteRole(SELLER) error = %v", err)
+	}
+	member, _ := organizationService.membershipRepository.FindByOrganizationAndUser(testOrganizationId, testMemberId)
+	if len(member.RoleIds) != 1 || member.RoleIds[0] != "r-admin" {
+		t.Fatalf("member roles = %v, want [r-admin]", member.RoleIds)
+	}
+}
+
+func TestIsOrganizationAdminRole(t *testing.T) {
+	tests := []struct {
+		name string
+		role *model.Role
+		want bool
+	}{
+		{"nil", nil, false},
+		{"system admin", &model.Role{Code: OrganizationAdminRoleCode, OrganizationId: "o1", System: true}, true},
+		{"legacy admin", &model.Role{Code: OrganizationAdminRoleCode, OrganizationId: "o1"}, true},
+		{"global role with the same code", &model.Role{Code: OrganizationAdminRoleCode}, false},
+		{"other organization role", &model.Role{Code: "SELLER", OrganizationId: "o1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOrganizationAdminRole(tt.role); got != tt.want {
+				t.Fatalf("isOrganizationAdminRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOrganizationAdminRole(t *testing.T) {
+	adminPermissions := []int{model.GetOrganization, model.UpdateOrganization, model.ManageOrganizationMembers, model.ManageOrganizationRoles}
+
+	tests := []struct {
+		name    string
+		request organization.OrganizationRoleRequest
+		wantErr error
+	}{
+		{"unchanged", organization.OrganizationRoleRequest{Code: OrganizationAdminRoleCode, Permissions: adminPermissions}, nil},
+		{"extra permission", organization.OrganizationRoleRequest{Code: OrganizationAdminRoleCode, Permissions: append([]int{601}, adminPermissions...)}, nil},
+		{"renamed", organization.OrganizationRoleRequest{Code: "OWNER", Permissions: adminPermissions}, service.ErrSystemRole},
+		{"missing permission", organization.OrganizationRoleRequest{Code: OrganizationAdminRoleCode, Permissions: adminPermissions[:3]}, service.ErrInvalidOrganizationRole},
+		{"denied permission", organization.OrganizationRoleRequest{Code: OrganizationAdminRoleCode, Permissions: adminPermissions, DeniedPermissions: []int{model.ManageOrganizationRoles}}, service.ErrInvalidOrganizationRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateOrganizationAdminRole(&tt.request); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateOrganizationAdminRole() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
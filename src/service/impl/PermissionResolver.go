@@ -0,0 +1,278 @@
+package impl
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+)
+
+// Tipos de origen de una concesión o denegación
+const (
+	permissionSourceRole  = "role"
+	permissionSourceUser  = "user"
+	permissionSourceGroup = "group"
+)
+
+// permissionResolver calcula los permisos efectivos de un usuario a partir de los datos
+// actuales de sus roles. Es compartido por la generación de tokens y las decisiones de autorización.
+type permissionResolver struct {
+	roleRepository       repository.RoleRepository
+	membershipRepository repository.OrganizationMembershipRepository
+	groupRepository      repository.GroupRepository
+}
+
+// permissionSource identifica el rol, grupo o usuario que concede o deniega un permiso
+type permissionSource struct {
+	Type string
+	Id   string
+	Code string
+}
+
+// resolvedPermissions contiene los roles del usuario y, por cada permiso, quién lo concede o deniega.
+// Una denegación siempre prevalece sobre cualquier concesión.
+type resolvedPermissions struct {
+	RoleCodes []string
+	Grants    map[int][]permissionSource
+	Denies    map[int][]permissionSource
+	// Versión de autorización de cada rol usado, por ID; se incluye en el token
+	RoleVersions map[string]int64
+}
+
+func newPermissionResolver(roleRepository repository.RoleRepository) *permissionResolver {
+	return &permissionResolver{
+		roleRepository:       roleRepository,
+		membershipRepository: impl.NewOrganizationMembershipRepositoryImpl(),
+		groupRepository:      impl.NewGroupRepositoryImpl(),
+	}
+}
+
+func (r *permissionResolver) resolve(user *model.User) (*resolvedPermissions, error) {
+	resolved := &resolvedPermissions{
+		Grants:       make(map[int][]permissionSource),
+		Denies:       make(map[int][]permissionSource),
+		RoleVersions: make(map[string]int64),
+	}
+
+	userSource := permissionSource{Type: permissionSourceUser, Id: user.Id, Code: user.Email}
+	for _, permissionId := range user.DeniedPermissionIds {
+		resolved.Denies[permissionId] = append(resolved.Denies[permissionId], userSource)
+	}
+
+	// Los roles de los grupos se consultan en cada resolución para que quitar a un miembro tenga efecto inmediato
+	groups, err := r.groupRepository.FindByMemberId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Las asignaciones temporales fuera de su ventana de vigencia se ignoran
+	roleIds := user.ActiveRoleIds(time.Now())
+	fetchRoleIds := slices.Clone(roleIds)
+	for _, group := range groups {
+		for _, roleId := range group.RoleIds {
+			if !slices.Contains(fetchRoleIds, roleId) {
+				fetchRoleIds = append(fetchRoleIds, roleId)
+			}
+		}
+	}
+	if len(fetchRoleIds) == 0 {
+		return resolved, nil
+	}
+
+	roles, err := r.roleRepository.FindByIds(fetchRoleIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los roles de organizaciones solo aplican a través de una membresía
+	rolesById := make(map[string]*model.Role, len(roles))
+	for _, role := range roles {
+		if role.OrganizationId == "" {
+			rolesById[role.Id] = role
+		}
+	}
+
+	resolved.addRoles(rolesIn(roleIds, rolesById))
+	for _, group := range groups {
+		resolved.addGroupRoles(group, rolesIn(group.RoleIds, rolesById))
+	}
+
+	return resolved, nil
+}
+
+// rolesIn devuelve los roles conocidos de roleIds, en su orden
+func rolesIn(roleIds []string, rolesById map[string]*model.Role) []*model.Role {
+	roles := make([]*model.Role, 0, len(roleIds))
+	for _, roleId := range roleIds {
+		if role, ok := rolesById[roleId]; ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// resolveOrganizations calcula, por cada organización de la que el usuario es miembro,
+// los permisos que le conceden o deniegan los roles de esa organización
+func (r *permissionResolver) resolveOrganizations(user *model.User) (map[string]*resolvedPermissions, error) {
+	memberships, err := r.membershipRepository.FindByUserId(user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	organizations := make(map[string]*resolvedPermissions, len(memberships))
+	for _, membership := range memberships {
+		resolved, err := r.resolveMembership(membership)
+		if err != nil {
+			return nil, err
+		}
+		organizations[membership.OrganizationId] = resolved
+	}
+	return organizations, nil
+}
+
+// resolveInOrganization combina los permisos globales del usuario con los de su membresía en la organización
+func (r *permissionResolver) resolveInOrganization(user *model.User, organizationId string) (*resolvedPermissions, error) {
+	resolved, err := r.resolve(user)
+	if err != nil {
+		return nil, err
+	}
+
+	membership, err := r.membershipRepository.FindByOrganizationAndUser(organizationId, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if membership == nil {
+		return resolved, nil
+	}
+
+	organizationResolved, err := r.resolveMembership(membership)
+	if err != nil {
+		return nil, err
+	}
+	resolved.merge(organizationResolved)
+	return resolved, nil
+}
+
+func (r *permissionResolver) resolveMembership(membership *model.OrganizationMembership) (*resolvedPermissions, error) {
+	resolved := &resolvedPermissions{
+		Grants:       make(map[int][]permissionSource),
+		Denies:       make(map[int][]permissionSource),
+		RoleVersions: make(map[string]int64),
+	}
+	if len(membership.RoleIds) == 0 {
+		return resolved, nil
+	}
+
+	roles, err := r.roleRepository.FindByIds(membership.RoleIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Aislamiento entre organizaciones: solo cuentan los roles de la propia organización
+	roles = slices.DeleteFunc(roles, func(role *model.Role) bool {
+		return role.OrganizationId != membership.OrganizationId
+	})
+	resolved.addRoles(roles)
+
+	// Un rol de organización no puede conceder permisos globales de este servicio
+	for permissionId := range resolved.Grants {
+		if !model.IsOrganizationScoped(permissionId) {
+			delete(resolved.Grants, permissionId)
+		}
+	}
+
+	return resolved, nil
+}
+
+// addRoles registra las concesiones y denegaciones de cada rol
+func (p *resolvedPermissions) addRoles(roles []*model.Role) {
+	for _, role := range roles {
+		p.RoleCodes = append(p.RoleCodes, role.Code)
+		p.RoleVersions[role.Id] = role.AuthzVersion
+		roleSource := permissionSource{Type: permissionSourceRole, Id: role.Id, Code: role.Code}
+		for _, permissionId := range grantedPermissionIds(role) {
+			p.Grants[permissionId] = append(p.Grants[permissionId], roleSource)
+		}
+		for _, permissionId := range role.DeniedPermissionIds {
+			p.Denies[permissionId] = append(p.Denies[permissionId], roleSource)
+		}
+	}
+}
+
+// addGroupRoles registra los roles heredados de un grupo; el origen es el grupo y el rol que concede o deniega
+func (p *resolvedPermissions) addGroupRoles(group *model.Group, roles []*model.Role) {
+	for _, role := range roles {
+		if !slices.Contains(p.RoleCodes, role.Code) {
+			p.RoleCodes = append(p.RoleCodes, role.Code)
+		}
+		p.RoleVersions[role.Id] = role.AuthzVersion
+		groupSource := permissionSource{Type: permissionSourceGroup, Id: group.Id, Code: group.Name + "/" + role.Code}
+		for _, permissionId := range grantedPermissionIds(role) {
+			p.Grants[permissionId] = append(p.Grants[permissionId], groupSource)
+		}
+		for _, permissionId := range role.DeniedPermissionIds {
+			p.Denies[permissionId] = append(p.Denies[permissionId], groupSource)
+		}
+	}
+}
+
+// grantedPermissionIds devuelve los permisos que concede el rol, sin repetir, incluyendo los de sus
+// concesiones comodín expandidas con el catálogo vigente; un permiso nuevo del grupo se concede sin editar el rol
+func grantedPermissionIds(role *model.Role) []int {
+	var permissionIds []int
+	if role.Permissions != nil {
+		for _, permission := range *role.Permissions {
+			permissionIds = append(permissionIds, permission.Id)
+		}
+	}
+	permissionIds = append(permissionIds, model.ExpandPermissionWildcards(role.PermissionGroups)...)
+	slices.Sort(permissionIds)
+	return slices.Compact(permissionIds)
+}
+
+// merge añade las concesiones y denegaciones de otro resultado
+func (p *resolvedPermissions) merge(other *resolvedPermissions) {
+	p.RoleCodes = append(p.RoleCodes, other.RoleCodes...)
+	maps.Copy(p.RoleVersions, other.RoleVersions)
+	for permissionId, sources := range other.Grants {
+		p.Grants[permissionId] = append(p.Grants[permissionId], sources...)
+	}
+	for permissionId, sources := range other.Denies {
+		p.Denies[permissionId] = append(p.Denies[permissionId], sources...)
+	}
+}
+
+// Has indica si el permiso fue concedido por al menos un rol y no está denegado
+func (p *resolvedPermissions) Has(permissionId int) bool {
+	return len(p.Grants[permissionId]) > 0 && len(p.Denies[permissionId]) == 0
+}
+
+// PermissionIds devuelve los IDs efectivos (concedidos y no denegados) ordenados
+func (p *resolvedPermissions) PermissionIds() []int {
+	permissionIds := make([]int, 0, len(p.Grants))
+	for id := range p.Grants {
+		if p.Has(id) {
+			permissionIds = append(permissionIds, id)
+		}
+	}
+	sort.Ints(permissionIds)
+	return permissionIds
+}
+
+// DeniedPermissionIds devuelve los IDs denegados explícitamente, ordenados
+func (p *resolvedPermissions) DeniedPermissionIds() []int {
+	permissionIds := make([]int, 0, len(p.Denies))
+	for id := range p.Denies {
+		permissionIds = append(permissionIds, id)
+	}
+	sort.Ints(permissionIds)
+	return permissionIds
+}
+
+func (s permissionSource) String() string {
+	return s.Type + " " + s.Code
+}
@@ -0,0 +1,63 @@
+package impl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func TestResolveDenyBeatsAllow(t *testing.T) {
+	support := &model.Role{Id: "r-support", Code: "SUPPORT", Permissions: rolePermissions(model.GetUserById, model.DeleteUser, model.UpdateUser)}
+	restricted := &model.Role{Id: "r-restricted", Code: "RESTRICTED", DeniedPermissionIds: []int{model.UpdateUser}}
+	auditor := &model.Role{Id: "r-auditor", Code: "AUDITOR", Permissions: rolePermissions(model.GetUsersPaginated), DeniedPermissionIds: []int{model.GetUserById}}
+	resolver := &permissionResolver{
+		roleRepository: newFakeRoleRepository(support, restricted, auditor),
+		groupRepository: &fakeGroupRepository{groups: []*model.Group{
+			{Id: "g1", Name: "auditors", MemberIds: []string{"u1"}, RoleIds: []string{"r-auditor"}},
+		}},
+	}
+	user := &model.User{
+		Id:                  "u1",
+		Email:               "ana@example.com",
+		RoleIds:             []string{"r-support", "r-restricted"},
+		DeniedPermissionIds: []int{model.DeleteUser},
+	}
+
+	resolved, err := resolver.resolve(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		permissionId int
+		allowed      bool
+		reason       string
+	}{
+		{"granted by role", model.GetUsersPaginated, true, "granted by group auditors/AUDITOR"},
+		{"denied by user", model.DeleteUser, false, "denied by user ana@example.com"},
+		{"denied by another role", model.UpdateUser, false, "denied by role RESTRICTED"},
+		{"denied by group role", model.GetUserById, false, "denied by group auditors/AUDITOR"},
+		{"not granted", model.CreateRole, false, "permission not granted by any role"},
+		{"not in catalog", 999999, false, "permission is not registered in the catalog"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := resolved.Has(test.permissionId); got != test.allowed {
+				t.Fatalf("Has() = %v, want %v", got, test.allowed)
+			}
+			decision := decide(resolved, test.permissionId)
+			if decision.Allowed != test.allowed || !strings.HasPrefix(decision.Reason, test.reason) {
+				t.Fatalf("decide() = %v %q, want %v %q", decision.Allowed, decision.Reason, test.allowed, test.reason)
+			}
+		})
+	}
+
+	for _, permissionId := range resolved.PermissionIds() {
+		if len(resolved.Denies[permissionId]) > 0 {
+			t.Fatalf("PermissionIds() includes denied permission %d", permissionId)
+		}
+	}
+}
@@ -1,18 +1,29 @@
 package impl
 
 import (
+	"fmt"
+	"log"
+	"regexp"
+
 	"github.com/ruiborda/ecommerce-user-service/src/dto/permission"
 	"github.com/ruiborda/ecommerce-user-service/src/mapper"
 	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
 )
 
+var serviceNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{1,62}$`)
+
 type PermissionServiceImpl struct {
-	permissionMapper *mapper.PermissionMapper
+	permissionRepository repository.PermissionRepository
+	permissionMapper     *mapper.PermissionMapper
 }
 
 func NewPermissionServiceImpl() *PermissionServiceImpl {
 	return &PermissionServiceImpl{
-		permissionMapper: &mapper.PermissionMapper{},
+		permissionRepository: impl.NewPermissionRepositoryImpl(),
+		permissionMapper:     &mapper.PermissionMapper{},
 	}
 }
 
@@ -48,3 +59,88 @@ func (s *PermissionServiceImpl) GetPermissionsByIdsAsArray(request *permission.G
 	permissions := model.FindPermissionsByIds(request.Ids)
 	return s.permissionMapper.PermissionsToArray(permissions)
 }
+
+// GetPermissionGroups obtiene los grupos de permisos y los IDs que incluye cada uno en el catálogo actual
+func (s *PermissionServiceImpl) GetPermissionGroups() []*permission.PermissionGroupResponse {
+	responses := make([]*permission.PermissionGroupResponse, 0, len(model.PermissionGroups))
+	for i := range model.PermissionGroups {
+		responses = append(responses, s.permissionMapper.PermissionGroupToResponse(&model.PermissionGroups[i]))
+	}
+	return responses
+}
+
+// RegisterServiceCatalog registra el catálogo de permisos de un servicio externo
+func (s *PermissionServiceImpl) RegisterServiceCatalog(serviceName string, request *permission.RegisterCatalogRequest) (*permission.RegisterCatalogResponse, error) {
+	if !serviceNamePattern.MatchString(serviceName) || serviceName == model.UserServiceNamespace {
+		return nil, fmt.Errorf("%w: invalid service name %q", service.ErrInvalidCatalog, serviceName)
+	}
+
+	seen := make(map[int]bool)
+	var permissions []*model.Permission
+	for i := range request.Permissions {
+		catalogPermission := &request.Permissions[i]
+		if catalogPermission.Id <= 0 || catalogPermission.Name == "" {
+			return nil, fmt.Errorf("%w: permission at index %d requires a positive id and a name", service.ErrInvalidCatalog, i)
+		}
+		if seen[catalogPermission.Id] {
+			return nil, fmt.Errorf("%w: duplicated permission id %d", service.ErrInvalidCatalog, catalogPermission.Id)
+		}
+		seen[catalogPermission.Id] = true
+
+		// Los IDs propios de este servicio están reservados
+		if model.IsBuiltinPermission(catalogPermission.Id) {
+			return nil, &repository.PermissionCollisionError{Id: catalogPermission.Id, OwnerService: model.UserServiceNamespace}
+		}
+
+		permissions = append(permissions, s.permissionMapper.CatalogPermissionRequestToPermission(catalogPermission))
+	}
+
+	deprecated, err := s.permissionRepository.RegisterServiceCatalog(serviceName, permissions)
+	if err != nil {
+		log.Printf("Error registering permission catalog for %s: %v", serviceName, err)
+		return nil, err
+	}
+
+	// La siguiente consulta debe reflejar el catálogo actualizado
+	model.InvalidatePermissionCache()
+
+	response := &permission.RegisterCatalogResponse{
+		Service:       serviceName,
+		Permissions:   make([]permission.GetPermissionByIdResponse, 0, len(permissions)),
+		DeprecatedIds: make([]int, 0, len(deprecated)),
+	}
+	for _, registered := range permissions {
+		response.Permissions = append(response.Permissions, *s.permissionMapper.PermissionToGetPermissionByIdResponse(registered))
+	}
+	for _, deprecatedPermission := range deprecated {
+		response.DeprecatedIds = append(response.DeprecatedIds, deprecatedPermission.Id)
+	}
+
+	return response, nil
+}
+
+// EnsureSeedPermissions siembra los permisos que otros servicios heredaron de este, sin tocar los ya registrados
+func (s *PermissionServiceImpl) EnsureSeedPermissions() error {
+	seeded := false
+	for serviceName, seedPermissions := range model.SeedPermissions {
+		permissions := make([]*model.Permission, 0, len(seedPermissions))
+		for i := range seedPermissions {
+			seedPermission := seedPermissions[i]
+			permissions = append(permissions, &seedPermission)
+		}
+
+		created, err := s.permissionRepository.SeedServiceCatalog(serviceName, permissions)
+		if err != nil {
+			return fmt.Errorf("failed to seed permission catalog for %s: %w", serviceName, err)
+		}
+		if len(created) > 0 {
+			log.Printf("Seeded %d permissions for %s", len(created), serviceName)
+			seeded = true
+		}
+	}
+
+	if seeded {
+		model.InvalidatePermissionCache()
+	}
+	return nil
+}
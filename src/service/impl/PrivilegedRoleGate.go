@@ -0,0 +1,74 @@
+package impl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// privilegedRoleGate separa los roles privilegiados de una asignación para que no se apliquen directamente:
+// quedan en una solicitud de cambio pendiente hasta que un segundo usuario la apruebe.
+type privilegedRoleGate struct {
+	roleRepository              repository.RoleRepository
+	roleChangeRequestRepository repository.RoleChangeRequestRepository
+}
+
+func newPrivilegedRoleGate(roleRepository repository.RoleRepository, roleChangeRequestRepository repository.RoleChangeRequestRepository) *privilegedRoleGate {
+	return &privilegedRoleGate{
+		roleRepository:              roleRepository,
+		roleChangeRequestRepository: roleChangeRequestRepository,
+	}
+}
+
+// split devuelve, en el orden original, los roles que se pueden asignar directamente y los que requieren aprobación.
+// Los roles de organizaciones no se pueden asignar como roles globales.
+func (g *privilegedRoleGate) split(roleIds []string) (regular []string, privileged []string, err error) {
+	if len(roleIds) == 0 {
+		return roleIds, nil, nil
+	}
+
+	roles, err := g.roleRepository.FindByIds(roleIds)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch roles: %v", err)
+	}
+	privilegedIds := make(map[string]bool)
+	for _, role := range roles {
+		if role.OrganizationId != "" {
+			return nil, nil, fmt.Errorf("%w: role %s belongs to an organization and is assigned through its membership", service.ErrInvalidRoleAssignment, role.Code)
+		}
+		if role.Privileged {
+			privilegedIds[role.Id] = true
+		}
+	}
+
+	regular = make([]string, 0, len(roleIds))
+	for _, roleId := range roleIds {
+		if privilegedIds[roleId] {
+			privileged = append(privileged, roleId)
+		} else {
+			regular = append(regular, roleId)
+		}
+	}
+	return regular, privileged, nil
+}
+
+// submit registra una solicitud pendiente para asignar roles privilegiados.
+// Con validUntil la asignación aprobada será temporal.
+func (g *privilegedRoleGate) submit(userId string, roleIds []string, validFrom, validUntil *time.Time, requestedBy string) (*model.RoleChangeRequest, error) {
+	now := time.Now()
+	return g.roleChangeRequestRepository.Create(&model.RoleChangeRequest{
+		UserId:      userId,
+		RoleIds:     roleIds,
+		ValidFrom:   validFrom,
+		ValidUntil:  validUntil,
+		Status:      model.RoleChangePending,
+		RequestedBy: requestedBy,
+		RequestedAt: now,
+		History: []model.RoleChangeEvent{
+			{Status: model.RoleChangePending, ActorId: requestedBy, At: now},
+		},
+	})
+}
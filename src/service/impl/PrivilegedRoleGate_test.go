@@ -0,0 +1,110 @@
+package impl
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/rolechange"
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+func TestPrivilegedRoleGateSplit(t *testing.T) {
+	gate := newPrivilegedRoleGate(newFakeRoleRepository(
+		&model.Role{Id: "r-support", Code: "SUPPORT"},
+		&model.Role{Id: "r-admin", Code: "ADMIN", Privileged: true},
+		&model.Role{Id: "r-finance", Code: "FINANCE_ADMIN", Privileged: true},
+		&model.Role{Id: "r-org", Code: "ORG_ADMIN", OrganizationId: "o1"},
+	), nil)
+
+	tests := []struct {
+		name           string
+		roleIds        []string
+		wantRegular    []string
+		wantPrivileged []string
+		wantErr        error
+	}{
+		{"no roles", nil, nil, nil, nil},
+		{"regular only", []string{"r-support"}, []string{"r-support"}, nil, nil},
+		{"keeps order", []string{"r-finance", "r-support", "r-admin"}, []string{"r-support"}, []string{"r-finance", "r-admin"}, nil},
+		{"organization role", []string{"r-support", "r-org"}, nil, nil, service.ErrInvalidRoleAssignment},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			regular, privileged, err := gate.split(test.roleIds)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("split() error = %v, want %v", err, test.wantErr)
+			}
+			if !slices.Equal(regular, test.wantRegular) || !slices.Equal(privileged, test.wantPrivileged) {
+				t.Fatalf("split() = %v, %v, want %v, %v", regular, privileged, test.wantRegular, test.wantPrivileged)
+			}
+		})
+	}
+}
+
+func TestDecideRoleChangeRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		approverId string
+		approve    bool
+		wantErr    error
+		wantRoles  []string
+	}{
+		{"requester cannot approve", "requester", true, service.ErrSelfApproval, []string{"r-support"}},
+		{"affected user cannot approve", "u1", true, service.ErrSelfApproval, []string{"r-support"}},
+		{"affected user cannot reject", "u1", false, service.ErrSelfApproval, []string{"r-support"}},
+		{"missing approver", "", true, service.ErrSelfApproval, []string{"r-support"}},
+		{"second user approves", "approver", true, nil, []string{"r-support", "r-admin"}},
+		{"second user rejects", "approver", false, nil, []string{"r-support"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			roleRepository := newFakeRoleRepository(
+				&model.Role{Id: "r-support", Code: "SUPPORT"},
+				&model.Role{Id: "r-admin", Code: "ADMIN", Privileged: true},
+			)
+			userRepository := newFakeUserRepository(&model.User{Id: "u1", RoleIds: []string{"r-support"}, AuthzVersion: 3})
+			requestRepository := &fakeRoleChangeRequestRepository{requests: make(map[string]*model.RoleChangeRequest), users: userRepository}
+			gate := newPrivilegedRoleGate(roleRepository, requestRepository)
+			pending, err := gate.submit("u1", []string{"r-admin"}, nil, nil, "requester")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			requestService := &RoleChangeRequestServiceImpl{
+				roleChangeRequestRepository: requestRepository,
+				roleRepository:              roleRepository,
+				sodChecker: &sodChecker{
+					sodConstraintRepository: &fakeSodConstraintRepository{},
+					roleRepository:          roleRepository,
+					groupRepository:         &fakeGroupRepository{},
+				},
+				roleChangeRequestMapper: &mapper.RoleChangeRequestMapper{},
+			}
+			decision := &rolechange.DecideRoleChangeRequest{}
+			if test.approve {
+				_, err = requestService.ApproveRoleChangeRequest(pending.Id, test.approverId, decision)
+			} else {
+				_, err = requestService.RejectRoleChangeRequest(pending.Id, test.approverId, decision)
+			}
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("decide() error = %v, want %v", err, test.wantErr)
+			}
+
+			user := userRepository.users["u1"]
+			if !slices.Equal(user.RoleIds, test.wantRoles) {
+				t.Fatalf("RoleIds = %v, want %v", user.RoleIds, test.wantRoles)
+			}
+			if test.wantErr == nil && test.approve && user.AuthzVersion != 4 {
+				t.Fatalf("AuthzVersion = %d, want 4 after approval", user.AuthzVersion)
+			}
+			if test.wantErr != nil && requestRepository.requests[pending.Id].Status != model.RoleChangePending {
+				t.Fatalf("Status = %s, want the request to stay pending", requestRepository.requests[pending.Id].Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package impl
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// Usuarios procesados por cada consulta del proceso de limpieza
+const roleAssignmentSweepBatchSize = 100
+
+type RoleAssignmentServiceImpl struct {
+	roleAssignmentRepository repository.RoleAssignmentRepository
+	userRepository           repository.UserRepository
+	roleRepository           repository.RoleRepository
+	privilegedRoleGate       *privilegedRoleGate
+	sodChecker               *sodChecker
+	roleAssignmentMapper     *mapper.RoleAssignmentMapper
+}
+
+func NewRoleAssignmentServiceImpl() *RoleAssignmentServiceImpl {
+	roleRepository := impl.NewRoleRepositoryImpl()
+	return &RoleAssignmentServiceImpl{
+		roleAssignmentRepository: impl.NewRoleAssignmentRepositoryImpl(),
+		userRepository:           impl.NewUserRepositoryImpl(),
+		roleRepository:           roleRepository,
+		privilegedRoleGate:       newPrivilegedRoleGate(roleRepository, impl.NewRoleChangeRequestRepositoryImpl()),
+		sodChecker:               newSodChecker(roleRepository),
+		roleAssignmentMapper:     &mapper.RoleAssignmentMapper{},
+	}
+}
+
+// GrantTemporaryRole asigna un rol durante una ventana de tiempo
+func (s *RoleAssignmentServiceImpl) GrantTemporaryRole(userId string, request *user.GrantTemporaryRoleRequest, grantedBy string) (*user.RoleAssignmentsResponse, error) {
+	now := time.Now()
+	assignment := s.roleAssignmentMapper.GrantTemporaryRoleRequestToRoleAssignment(request, grantedBy, now)
+
+	if assignment.RoleId == "" {
+		return nil, fmt.Errorf("%w: roleId is required", service.ErrInvalidRoleAssignment)
+	}
+	if !assignment.ValidUntil.After(assignment.ValidFrom) || !assignment.ValidUntil.After(now) {
+		return nil, fmt.Errorf("%w: validUntil must be in the future and after validFrom", service.ErrInvalidRoleAssignment)
+	}
+
+	roleModel, err := s.roleRepository.FindById(assignment.RoleId)
+	if err != nil {
+		log.Printf("Error finding role for temporary assignment: %v", err)
+		return nil, err
+	}
+	if roleModel == nil {
+		return nil, service.ErrRoleNotFound
+	}
+	if roleModel.OrganizationId != "" {
+		return nil, fmt.Errorf("%w: role %s belongs to an organization", service.ErrInvalidRoleAssignment, roleModel.Code)
+	}
+
+	// Un rol privilegiado queda pendiente de aprobación, también cuando es temporal
+	if roleModel.Privileged {
+		return s.submitPrivilegedGrant(userId, assignment, grantedBy)
+	}
+
+	updatedUser, err := s.roleAssignmentRepository.UpdateAssignments(userId, func(userModel *model.User) ([]*model.RoleAssignmentHistory, error) {
+		if slices.Contains(userModel.RoleIds, assignment.RoleId) {
+			return nil, fmt.Errorf("%w: the user already has role %s permanently", service.ErrInvalidRoleAssignment, roleModel.Code)
+		}
+		heldRoleIds, err := s.sodChecker.heldRoleIds(userModel, now, assignment.RoleId)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.sodChecker.check(heldRoleIds); err != nil {
+			return nil, err
+		}
+
+		// Una nueva concesión del mismo rol reemplaza la anterior
+		userModel.RoleAssignments = slices.DeleteFunc(userModel.RoleAssignments, func(existing model.RoleAssignment) bool {
+			return existing.RoleId == assignment.RoleId
+		})
+		userModel.RoleAssignments = append(userModel.RoleAssignments, assignment)
+
+		return []*model.RoleAssignmentHistory{
+			newRoleAssignmentHistory(assignment, model.RoleAssignmentGranted, grantedBy, now),
+		}, nil
+	})
+	if err != nil {
+		log.Printf("Error granting temporary role: %v", err)
+		return nil, err
+	}
+	if updatedUser == nil {
+		return nil, service.ErrUserNotFound
+	}
+
+	return s.buildResponse(updatedUser)
+}
+
+// GetRoleAssignments lista las asignaciones temporales de un usuario y su historial
+func (s *RoleAssignmentServiceImpl) GetRoleAssignments(userId string) (*user.RoleAssignmentsResponse, error) {
+	userModel, err := s.userRepository.FindById(userId)
+	if err != nil {
+		log.Printf("Error getting user for role assignments: %v", err)
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+
+	return s.buildResponse(userModel)
+}
+
+// RevokeTemporaryRole elimina una asignación temporal antes de su vencimiento
+func (s *RoleAssignmentServiceImpl) RevokeTemporaryRole(userId, roleId, revokedBy string) (*user.RoleAssignmentsResponse, error) {
+	now := time.Now()
+
+	updatedUser, err := s.roleAssignmentRepository.UpdateAssignments(userId, func(userModel *model.User) ([]*model.RoleAssignmentHistory, error) {
+		index := slices.IndexFunc(userModel.RoleAssignments, func(existing model.RoleAssignment) bool {
+			return existing.RoleId == roleId
+		})
+		if index < 0 {
+			return nil, service.ErrRoleAssignmentNotFound
+		}
+
+		revoked := userModel.RoleAssignments[index]
+		userModel.RoleAssignments = slices.Delete(userModel.RoleAssignments, index, index+1)
+
+		return []*model.RoleAssignmentHistory{
+			newRoleAssignmentHistory(revoked, model.RoleAssignmentRevoked, revokedBy, now),
+		}, nil
+	})
+	if err != nil {
+		log.Printf("Error revoking temporary role: %v", err)
+		return nil, err
+	}
+	if updatedUser == nil {
+		return nil, service.ErrUserNotFound
+	}
+
+	return s.buildResponse(updatedUser)
+}
+
+// SweepExpiredRoleAssignments elimina las asignaciones vencidas y registra su expiración
+func (s *RoleAssignmentServiceImpl) SweepExpiredRoleAssignments() (int, error) {
+	now := time.Now()
+	removed := 0
+
+	for {
+		userIds, err := s.roleAssignmentRepository.FindUserIdsWithExpiredAssignments(now, roleAssignmentSweepBatchSize)
+		if err != nil {
+			return removed, err
+		}
+
+		for _, userId := range userIds {
+			var expired int
+			_, err := s.roleAssignmentRepository.UpdateAssignments(userId, func(userModel *model.User) ([]*model.RoleAssignmentHistory, error) {
+				var history []*model.RoleAssignmentHistory
+				userModel.RoleAssignments = slices.DeleteFunc(userModel.RoleAssignments, func(existing model.RoleAssignment) bool {
+					if !existing.IsExpired(now) {
+						return false
+					}
+					history = append(history, newRoleAssignmentHistory(existing, model.RoleAssignmentExpired, "", now))
+					return true
+				})
+				expired = len(history)
+				return history, nil
+			})
+			if err != nil {
+				return removed, fmt.Errorf("failed to expire role assignments of user %s: %w", userId, err)
+			}
+			removed += expired
+		}
+
+		// Cada usuario procesado sale de la consulta, así que una página incompleta indica el final
+		if len(userIds) < roleAssignmentSweepBatchSize {
+			return removed, nil
+		}
+	}
+}
+
+func (s *RoleAssignmentServiceImpl) submitPrivilegedGrant(userId string, assignment model.RoleAssignment, grantedBy string) (*user.RoleAssignmentsResponse, error) {
+	userModel, err := s.userRepository.FindById(userId)
+	if err != nil {
+		log.Printf("Error getting user for temporary assignment: %v", err)
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+	if slices.Contains(userModel.RoleIds, assignment.RoleId) {
+		return nil, fmt.Errorf("%w: the user already has the role permanently", service.ErrInvalidRoleAssignment)
+	}
+	heldRoleIds, err := s.sodChecker.heldRoleIds(userModel, time.Now(), assignment.RoleId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sodChecker.check(heldRoleIds); err != nil {
+		return nil, err
+	}
+
+	pendingRequest, err := s.privilegedRoleGate.submit(userId, []string{assignment.RoleId}, &assignment.ValidFrom, &assignment.ValidUntil, grantedBy)
+	if err != nil {
+		log.Printf("Error creating role change request: %v", err)
+		return nil, err
+	}
+
+	response, err := s.buildResponse(userModel)
+	if err != nil {
+		return nil, err
+	}
+	response.PendingRoleChangeRequestId = pendingRequest.Id
+	return response, nil
+}
+
+func (s *RoleAssignmentServiceImpl) buildResponse(userModel *model.User) (*user.RoleAssignmentsResponse, error) {
+	rolesById := make(map[string]*model.Role)
+	if len(userModel.RoleAssignments) > 0 {
+		roleIds := make([]string, 0, len(userModel.RoleAssignments))
+		for _, assignment := range userModel.RoleAssignments {
+			roleIds = append(roleIds, assignment.RoleId)
+		}
+		roles, err := s.roleRepository.FindByIds(roleIds)
+		if err != nil {
+			log.Printf("Error fetching roles of temporary assignments: %v", err)
+			return nil, err
+		}
+		for _, roleModel := range roles {
+			rolesById[roleModel.Id] = roleModel
+		}
+	}
+
+	history, err := s.roleAssignmentRepository.FindHistoryByUserId(userModel.Id)
+	if err != nil {
+		log.Printf("Error fetching role assignment history: %v", err)
+		return nil, err
+	}
+
+	return s.roleAssignmentMapper.ToRoleAssignmentsResponse(userModel, rolesById, history, time.Now()), nil
+}
+
+func newRoleAssignmentHistory(assignment model.RoleAssignment, action, actorId string, now time.Time) *model.RoleAssignmentHistory {
+	return &model.RoleAssignmentHistory{
+		RoleId:     assignment.RoleId,
+		Action:     action,
+		ValidFrom:  assignment.ValidFrom,
+		ValidUntil: assignment.ValidUntil,
+		ActorId:    actorId,
+		RecordedAt: now,
+	}
+}
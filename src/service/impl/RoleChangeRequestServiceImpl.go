@@ -0,0 +1,190 @@
+package impl
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/rolechange"
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+type RoleChangeRequestServiceImpl struct {
+	roleChangeRequestRepository repository.RoleChangeRequestRepository
+	roleRepository              repository.RoleRepository
+	sodChecker                  *sodChecker
+	roleChangeRequestMapper     *mapper.RoleChangeRequestMapper
+}
+
+func NewRoleChangeRequestServiceImpl() *RoleChangeRequestServiceImpl {
+	roleRepository := impl.NewRoleRepositoryImpl()
+	return &RoleChangeRequestServiceImpl{
+		roleChangeRequestRepository: impl.NewRoleChangeRequestRepositoryImpl(),
+		roleRepository:              roleRepository,
+		sodChecker:                  newSodChecker(roleRepository),
+		roleChangeRequestMapper:     &mapper.RoleChangeRequestMapper{},
+	}
+}
+
+// GetRoleChangeRequests lista las solicitudes, opcionalmente filtradas por estado y usuario
+func (s *RoleChangeRequestServiceImpl) GetRoleChangeRequests(status, userId string) ([]*rolechange.RoleChangeRequestResponse, error) {
+	requests, err := s.roleChangeRequestRepository.FindAll(status, userId)
+	if err != nil {
+		log.Printf("Error fetching role change requests: %v", err)
+		return nil, err
+	}
+
+	return s.toResponses(requests)
+}
+
+// GetRoleChangeRequestById obtiene una solicitud por su ID
+func (s *RoleChangeRequestServiceImpl) GetRoleChangeRequestById(id string) (*rolechange.RoleChangeRequestResponse, error) {
+	request, err := s.roleChangeRequestRepository.FindById(id)
+	if err != nil {
+		log.Printf("Error fetching role change request: %v", err)
+		return nil, err
+	}
+	if request == nil {
+		return nil, service.ErrRoleChangeRequestNotFound
+	}
+
+	responses, err := s.toResponses([]*model.RoleChangeRequest{request})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0], nil
+}
+
+// ApproveRoleChangeRequest asigna los roles de la solicitud al usuario
+func (s *RoleChangeRequestServiceImpl) ApproveRoleChangeRequest(id, approverId string, decision *rolechange.DecideRoleChangeRequest) (*rolechange.RoleChangeRequestResponse, error) {
+	return s.decide(id, approverId, model.RoleChangeApproved, decision.Comment)
+}
+
+// RejectRoleChangeRequest cierra la solicitud sin modificar al usuario
+func (s *RoleChangeRequestServiceImpl) RejectRoleChangeRequest(id, approverId string, decision *rolechange.DecideRoleChangeRequest) (*rolechange.RoleChangeRequestResponse, error) {
+	return s.decide(id, approverId, model.RoleChangeRejected, decision.Comment)
+}
+
+func (s *RoleChangeRequestServiceImpl) decide(id, approverId, status, comment string) (*rolechange.RoleChangeRequestResponse, error) {
+	now := time.Now()
+
+	decided, err := s.roleChangeRequestRepository.Decide(id, func(request *model.RoleChangeRequest, user *model.User) ([]*model.RoleAssignmentHistory, error) {
+		if request.Status != model.RoleChangePending {
+			return nil, service.ErrRoleChangeRequestClosed
+		}
+		// Nadie decide sobre sus propias solicitudes ni sobre sus propios roles
+		if approverId == "" || approverId == request.RequestedBy || approverId == request.UserId {
+			return nil, service.ErrSelfApproval
+		}
+
+		request.Status = status
+		request.DecidedBy = approverId
+		request.DecidedAt = &now
+		request.History = append(request.History, model.RoleChangeEvent{
+			Status:  status,
+			ActorId: approverId,
+			Comment: comment,
+			At:      now,
+		})
+
+		if status != model.RoleChangeApproved {
+			return nil, nil
+		}
+		if user == nil {
+			return nil, service.ErrUserNotFound
+		}
+		// Los roles del usuario pueden haber cambiado desde que se creó la solicitud
+		heldRoleIds, err := s.sodChecker.heldRoleIds(user, now, request.RoleIds...)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.sodChecker.check(heldRoleIds); err != nil {
+			return nil, err
+		}
+		// Se guarda en la misma transacción que los nuevos roles
+		user.AuthzVersion++
+		return applyRoleChange(request, user, approverId, now), nil
+	})
+	if err != nil {
+		log.Printf("Error deciding role change request: %v", err)
+		return nil, err
+	}
+	if decided == nil {
+		return nil, service.ErrRoleChangeRequestNotFound
+	}
+
+	responses, err := s.toResponses([]*model.RoleChangeRequest{decided})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0], nil
+}
+
+// applyRoleChange añade los roles aprobados al usuario, como permanentes o como asignaciones temporales
+func applyRoleChange(request *model.RoleChangeRequest, user *model.User, approverId string, now time.Time) []*model.RoleAssignmentHistory {
+	user.UpdatedAt = now
+
+	if request.ValidUntil == nil {
+		for _, roleId := range request.RoleIds {
+			if !slices.Contains(user.RoleIds, roleId) {
+				user.RoleIds = append(user.RoleIds, roleId)
+			}
+		}
+		return nil
+	}
+
+	validFrom := now
+	if request.ValidFrom != nil {
+		validFrom = *request.ValidFrom
+	}
+	var history []*model.RoleAssignmentHistory
+	for _, roleId := range request.RoleIds {
+		assignment := model.RoleAssignment{
+			RoleId:     roleId,
+			ValidFrom:  validFrom,
+			ValidUntil: *request.ValidUntil,
+			GrantedBy:  approverId,
+			GrantedAt:  now,
+		}
+		user.RoleAssignments = slices.DeleteFunc(user.RoleAssignments, func(existing model.RoleAssignment) bool {
+			return existing.RoleId == roleId
+		})
+		user.RoleAssignments = append(user.RoleAssignments, assignment)
+		history = append(history, newRoleAssignmentHistory(assignment, model.RoleAssignmentGranted, approverId, now))
+	}
+	user.RefreshRoleAssignmentsExpireAt()
+	return history
+}
+
+func (s *RoleChangeRequestServiceImpl) toResponses(requests []*model.RoleChangeRequest) ([]*rolechange.RoleChangeRequestResponse, error) {
+	var roleIds []string
+	for _, request := range requests {
+		for _, roleId := range request.RoleIds {
+			if !slices.Contains(roleIds, roleId) {
+				roleIds = append(roleIds, roleId)
+			}
+		}
+	}
+
+	rolesById := make(map[string]*model.Role)
+	if len(roleIds) > 0 {
+		roles, err := s.roleRepository.FindByIds(roleIds)
+		if err != nil {
+			log.Printf("Error fetching roles of role change requests: %v", err)
+			return nil, err
+		}
+		for _, role := range roles {
+			rolesById[role.Id] = role
+		}
+	}
+
+	responses := make([]*rolechange.RoleChangeRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		responses = append(responses, s.roleChangeRequestMapper.RoleChangeRequestToResponse(request, rolesById))
+	}
+	return responses, nil
+}
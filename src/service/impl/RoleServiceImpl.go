@@ -2,7 +2,11 @@
 package impl
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"slices"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
@@ -11,43 +15,67 @@ import (
 	"github.com/ruiborda/ecommerce-user-service/src/model"
 	"github.com/ruiborda/ecommerce-user-service/src/repository"
 	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
 )
 
+// roleMigrationBatchSize es el número de usuarios que se actualizan en cada transacción al eliminar un rol en uso
+const roleMigrationBatchSize = 200
+
 type RoleServiceImpl struct {
-	roleRepository repository.RoleRepository
-	roleMapper     *mapper.RoleMapper
+	roleRepository              repository.RoleRepository
+	userRepository              repository.UserRepository
+	groupRepository             repository.GroupRepository
+	sodConstraintRepository     repository.SodConstraintRepository
+	roleChangeRequestRepository repository.RoleChangeRequestRepository
+	roleMapper                  *mapper.RoleMapper
+	sodChecker                  *sodChecker
 }
 
 func NewRoleServiceImpl() *RoleServiceImpl {
+	roleRepository := impl.NewRoleRepositoryImpl()
 	return &RoleServiceImpl{
-		roleRepository: impl.NewRoleRepositoryImpl(),
-		roleMapper:     &mapper.RoleMapper{},
+		roleRepository:              roleRepository,
+		userRepository:              impl.NewUserRepositoryImpl(),
+		groupRepository:             impl.NewGroupRepositoryImpl(),
+		sodConstraintRepository:     impl.NewSodConstraintRepositoryImpl(),
+		roleChangeRequestRepository: impl.NewRoleChangeRequestRepositoryImpl(),
+		roleMapper:                  &mapper.RoleMapper{},
+		sodChecker:                  newSodChecker(roleRepository),
 	}
 }
 
-// CreateRole crea un nuevo rol
-func (s *RoleServiceImpl) CreateRole(request *role.CreateRoleRequest) *role.CreateRoleResponse {
+// CreateRole crea un nuevo rol con un código único
+func (s *RoleServiceImpl) CreateRole(request *role.CreateRoleRequest) (*role.CreateRoleResponse, error) {
+	if !model.IsValidRoleCode(request.Code) {
+		return nil, service.ErrInvalidRoleCode
+	}
 	// Validar permisos si se proporcionan
 	if len(request.Permissions) > 0 {
 		permissions := model.FindPermissionsByIds(request.Permissions)
 		if len(*permissions) != len(request.Permissions) {
-			log.Printf("Uno o más IDs de permisos no son válidos")
-			return nil
+			return nil, service.ErrInvalidRolePermissions
 		}
 	}
+	if err := validatePermissionGroups(request.PermissionGroups); err != nil {
+		return nil, err
+	}
 
 	// Mapear request a modelo
 	roleModel := s.roleMapper.CreateRoleRequestToRole(request)
+	roleModel.PermissionGroups = distinct(roleModel.PermissionGroups)
 
-	// Guardar en el repositorio
+	// Guardar en el repositorio; el código se reserva en la misma transacción
 	createdRole, err := s.roleRepository.Create(roleModel)
+	if errors.Is(err, repository.ErrRoleCodeTaken) {
+		return nil, service.ErrRoleCodeTaken
+	}
 	if err != nil {
 		log.Printf("Error creating role: %v", err)
-		return nil
+		return nil, err
 	}
 
 	// Mapear modelo a response
-	return s.roleMapper.RoleToCreateRoleResponse(createdRole)
+	return s.roleMapper.RoleToCreateRoleResponse(createdRole), nil
 }
 
 // GetRoleById obtiene un rol por su ID
@@ -58,7 +86,8 @@ func (s *RoleServiceImpl) GetRoleById(id string) *role.GetRoleByIdResponse {
 		return nil
 	}
 
-	if roleModel == nil {
+	// Los roles de organizaciones se consultan desde su organización
+	if roleModel == nil || roleModel.OrganizationId != "" {
 		return nil
 	}
 
@@ -77,50 +106,337 @@ func (s *RoleServiceImpl) GetAllRoles() []*role.GetRoleByIdResponse {
 }
 
 // UpdateRoleById actualiza un rol existente
-func (s *RoleServiceImpl) UpdateRoleById(request *role.UpdateRoleRequest) *role.UpdateRoleResponse {
+func (s *RoleServiceImpl) UpdateRoleById(request *role.UpdateRoleRequest, canChangePrivileged bool) (*role.UpdateRoleResponse, error) {
 	// Primero obtener el rol existente
 	existingRole, err := s.roleRepository.FindById(request.Id)
 	if err != nil {
 		log.Printf("Error finding role to update: %v", err)
-		return nil
+		return nil, err
 	}
 
-	if existingRole == nil {
-		log.Printf("Role not found with ID: %s", request.Id)
-		return nil
+	if existingRole == nil || existingRole.OrganizationId != "" {
+		return nil, service.ErrRoleNotFound
+	}
+
+	// El formato solo se exige al cambiar el código, para no bloquear la edición de roles antiguos
+	if request.Code != existingRole.Code {
+		if existingRole.System {
+			return nil, service.ErrSystemRole
+		}
+		if !model.IsValidRoleCode(request.Code) {
+			return nil, service.ErrInvalidRoleCode
+		}
+	}
+
+	// Quitar la marca con solo UpdateRole permitiría asignar el rol sin pasar por la aprobación
+	if request.Privileged != nil && *request.Privileged != existingRole.Privileged && !canChangePrivileged {
+		return nil, service.ErrPrivilegedChangeNotAllowed
 	}
 
 	// Validar permisos si se proporcionan
 	if len(request.Permissions) > 0 {
 		permissions := model.FindPermissionsByIds(request.Permissions)
 		if len(*permissions) != len(request.Permissions) {
-			log.Printf("Uno o más IDs de permisos no son válidos")
-			return nil
+			return nil, service.ErrInvalidRolePermissions
 		}
 	}
+	if err := validatePermissionGroups(request.PermissionGroups); err != nil {
+		return nil, err
+	}
 
 	// Actualizar el modelo de rol con datos de la solicitud
 	updatedRoleModel := s.roleMapper.UpdateRoleRequestToRole(request, existingRole)
+	updatedRoleModel.PermissionGroups = distinct(updatedRoleModel.PermissionGroups)
 
 	// Guardar el rol actualizado
 	savedRole, err := s.roleRepository.Update(updatedRoleModel)
+	if errors.Is(err, repository.ErrRoleCodeTaken) {
+		return nil, service.ErrRoleCodeTaken
+	}
 	if err != nil {
 		log.Printf("Error updating role: %v", err)
-		return nil
+		return nil, err
 	}
 
-	return s.roleMapper.RoleToUpdateRoleResponse(savedRole)
+	return s.roleMapper.RoleToUpdateRoleResponse(savedRole), nil
+}
+
+// EnsureSystemRoles crea los roles del sistema que falten, sin permisos, y marca como tales a los existentes
+func (s *RoleServiceImpl) EnsureSystemRoles() error {
+	for _, code := range model.SystemRoleCodes {
+		existingRole, err := s.roleRepository.FindByCode(code)
+		if err != nil {
+			return fmt.Errorf("failed to get system role %s: %w", code, err)
+		}
+
+		if existingRole == nil {
+			emptyPermissions := make([]model.Permission, 0)
+			_, err = s.roleRepository.Create(&model.Role{Code: code, Permissions: &emptyPermissions, System: true})
+		} else if !existingRole.System {
+			existingRole.System = true
+			_, err = s.roleRepository.Update(existingRole)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to ensure system role %s: %w", code, err)
+		}
+	}
+	return nil
 }
 
-// DeleteRoleById elimina un rol por su ID
-func (s *RoleServiceImpl) DeleteRoleById(id string) *role.DeleteRoleByIdResponse {
-	err := s.roleRepository.Delete(id)
+// DeleteRoleById elimina un rol. Si usuarios, grupos o restricciones aún lo referencian, devuelve un
+// *service.RoleInUseError salvo que se indique reassignTo, que los migra a otro rol, o force, que les quita el rol.
+// La migración se hace por lotes; si falla, el rol no se elimina y la operación se puede repetir.
+func (s *RoleServiceImpl) DeleteRoleById(request *role.DeleteRoleByIdRequest) (*role.DeleteRoleByIdResponse, error) {
+	// Los roles de organizaciones se eliminan desde su organización
+	existingRole, err := s.roleRepository.FindById(request.Id)
+	if err != nil {
+		log.Printf("Error getting role to delete: %v", err)
+		return nil, err
+	}
+	if existingRole == nil || existingRole.OrganizationId != "" {
+		return nil, service.ErrRoleNotFound
+	}
+	if existingRole.System {
+		return nil, service.ErrSystemRole
+	}
+	if err := s.validateReassignment(request); err != nil {
+		return nil, err
+	}
+
+	permanentIds, temporaryIds, err := s.userRepository.FindIdsByRoleId(request.Id)
+	if err != nil {
+		log.Printf("Error getting users of role: %v", err)
+		return nil, err
+	}
+	usage, err := s.roleUsage(request.Id, permanentIds, temporaryIds)
 	if err != nil {
+		return nil, err
+	}
+
+	response := s.roleMapper.RoleToDeleteRoleByIdResponse(request.Id, true)
+	if usage.InUse() {
+		// Las solicitudes pendientes se deciden antes; aprobarlas después asignaría un rol inexistente
+		if usage.PendingRoleChangeRequests > 0 || (request.ReassignTo == "" && !request.Force) {
+			return nil, &service.RoleInUseError{Usage: usage}
+		}
+		if request.ReassignTo != "" {
+			if err := s.checkReassignmentSod(request, append(permanentIds, temporaryIds...)); err != nil {
+				return nil, err
+			}
+		}
+
+		updatedUsers, err := s.migrateRole(request, append(permanentIds, temporaryIds...))
+		if err != nil {
+			return nil, err
+		}
+		response.Usage = usage
+		response.ReassignedTo = request.ReassignTo
+		response.UpdatedUsers = updatedUsers
+	}
+
+	if err := s.roleRepository.Delete(request.Id); err != nil {
 		log.Printf("Error deleting role: %v", err)
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *RoleServiceImpl) validateReassignment(request *role.DeleteRoleByIdRequest) error {
+	if request.ReassignTo == "" {
+		return nil
+	}
+	if request.Force {
+		return fmt.Errorf("%w: reassignTo and force cannot be combined", service.ErrInvalidRoleReassignment)
+	}
+	if request.ReassignTo == request.Id {
+		return fmt.Errorf("%w: a role cannot be reassigned to itself", service.ErrInvalidRoleReassignment)
+	}
+
+	replacement, err := s.roleRepository.FindById(request.ReassignTo)
+	if err != nil {
+		log.Printf("Error getting replacement role: %v", err)
+		return err
+	}
+	switch {
+	case replacement == nil || replacement.OrganizationId != "":
+		return fmt.Errorf("%w: role %s does not exist", service.ErrInvalidRoleReassignment, request.ReassignTo)
+	case replacement.Privileged:
+		// Asignar un rol privilegiado requiere aprobación individual
+		return fmt.Errorf("%w: users cannot be reassigned to privileged role %s", service.ErrInvalidRoleReassignment, replacement.Code)
+	}
+	return nil
+}
+
+// roleUsage cuenta las referencias al rol
+func (s *RoleServiceImpl) roleUsage(roleId string, permanentIds, temporaryIds []string) (*role.RoleUsageResponse, error) {
+	usage := &role.RoleUsageResponse{
+		Users:                len(permanentIds),
+		TemporaryAssignments: len(temporaryIds),
+	}
+
+	groups, err := s.groupRepository.FindByRoleId(roleId)
+	if err != nil {
+		log.Printf("Error getting groups of role: %v", err)
+		return nil, err
+	}
+	usage.Groups = len(groups)
+
+	constraints, err := s.sodConstraintRepository.FindAll()
+	if err != nil {
+		log.Printf("Error getting separation of duty constraints: %v", err)
+		return nil, err
+	}
+	for _, constraint := range constraints {
+		if slices.Contains(constraint.RoleIds, roleId) {
+			usage.SodConstraints++
+		}
+	}
+
+	requests, err := s.roleChangeRequestRepository.FindAll(model.RoleChangePending, "")
+	if err != nil {
+		log.Printf("Error getting pending role change requests: %v", err)
+		return nil, err
+	}
+	for _, changeRequest := range requests {
+		if slices.Contains(changeRequest.RoleIds, roleId) {
+			usage.PendingRoleChangeRequests++
+		}
+	}
+
+	return usage, nil
+}
+
+// checkReassignmentSod verifica, antes de migrar, que ningún usuario ni grupo del rol quede con roles mutuamente
+// excluyentes al recibir reassignTo. Evalúa las restricciones como quedan después de la migración y devuelve la
+// primera violación con el usuario o grupo afectado.
+func (s *RoleServiceImpl) checkReassignmentSod(request *role.DeleteRoleByIdRequest, userIds []string) error {
+	constraints, err := s.sodConstraintRepository.FindAll()
+	if err != nil {
+		log.Printf("Error getting separation of duty constraints: %v", err)
+		return err
+	}
+	migratedConstraints := make([]*model.SodConstraint, 0, len(constraints))
+	for _, constraint := range constraints {
+		if slices.Contains(constraint.RoleIds, request.Id) {
+			migrated := *constraint
+			migrated.RoleIds = replaceRoleId(constraint.RoleIds, request.Id, request.ReassignTo)
+			constraint = &migrated
+		}
+		if len(constraint.RoleIds) >= 2 {
+			migratedConstraints = append(migratedConstraints, constraint)
+		}
+	}
+	if len(migratedConstraints) == 0 {
 		return nil
 	}
 
-	return s.roleMapper.RoleToDeleteRoleByIdResponse(id, true)
+	// Los miembros de los grupos del rol también lo reciben, aunque no lo tengan directamente
+	groups, err := s.groupRepository.FindByRoleId(request.Id)
+	if err != nil {
+		log.Printf("Error getting groups of role: %v", err)
+		return err
+	}
+	for _, group := range groups {
+		roleIds := replaceRoleId(group.RoleIds, request.Id, request.ReassignTo)
+		if err := s.sodChecker.checkConstraints(migratedConstraints, roleIds); err != nil {
+			return withSodSubject(err, "", group.Id)
+		}
+		userIds = append(userIds, group.MemberIds...)
+	}
+
+	userIds = slices.Compact(slices.Sorted(slices.Values(userIds)))
+	now := time.Now()
+	for batch := range slices.Chunk(userIds, roleMigrationBatchSize) {
+		users, err := s.userRepository.FindByIds(batch)
+		if err != nil {
+			log.Printf("Error getting users of role: %v", err)
+			return err
+		}
+		for _, user := range users {
+			roleIds, err := s.sodChecker.heldRoleIds(user, now)
+			if err != nil {
+				log.Printf("Error getting roles of user %s: %v", user.Id, err)
+				return err
+			}
+			roleIds = replaceRoleId(roleIds, request.Id, request.ReassignTo)
+			if err := s.sodChecker.checkConstraints(migratedConstraints, roleIds); err != nil {
+				return withSodSubject(err, user.Id, "")
+			}
+		}
+	}
+	return nil
+}
+
+// withSodSubject agrega a una violación de separación de funciones el usuario o grupo que la tendría
+func withSodSubject(err error, userId, groupId string) error {
+	var violation *service.SodViolationError
+	if errors.As(err, &violation) {
+		violation.UserId = userId
+		violation.GroupId = groupId
+	}
+	return err
+}
+
+// migrateRole reemplaza o quita el rol en usuarios, grupos y restricciones de separación de funciones
+func (s *RoleServiceImpl) migrateRole(request *role.DeleteRoleByIdRequest, userIds []string) (int, error) {
+	userIds = slices.Compact(slices.Sorted(slices.Values(userIds)))
+
+	updatedUsers := 0
+	for batch := range slices.Chunk(userIds, roleMigrationBatchSize) {
+		updated, err := s.userRepository.ReplaceRole(batch, request.Id, request.ReassignTo)
+		if err != nil {
+			log.Printf("Error migrating users of role %s: %v", request.Id, err)
+			return updatedUsers, err
+		}
+		updatedUsers += updated
+	}
+
+	groups, err := s.groupRepository.FindByRoleId(request.Id)
+	if err != nil {
+		log.Printf("Error getting groups of role: %v", err)
+		return updatedUsers, err
+	}
+	for _, group := range groups {
+		group.RoleIds = replaceRoleId(group.RoleIds, request.Id, request.ReassignTo)
+		if _, err := s.groupRepository.Update(group); err != nil {
+			log.Printf("Error migrating group %s: %v", group.Id, err)
+			return updatedUsers, err
+		}
+	}
+
+	constraints, err := s.sodConstraintRepository.FindAll()
+	if err != nil {
+		log.Printf("Error getting separation of duty constraints: %v", err)
+		return updatedUsers, err
+	}
+	for _, constraint := range constraints {
+		if !slices.Contains(constraint.RoleIds, request.Id) {
+			continue
+		}
+		constraint.RoleIds = replaceRoleId(constraint.RoleIds, request.Id, request.ReassignTo)
+		// Una restricción con un solo rol ya no restringe nada
+		if len(constraint.RoleIds) < 2 {
+			err = s.sodConstraintRepository.Delete(constraint.Id)
+		} else {
+			_, err = s.sodConstraintRepository.Update(constraint)
+		}
+		if err != nil {
+			log.Printf("Error migrating separation of duty constraint %s: %v", constraint.Id, err)
+			return updatedUsers, err
+		}
+	}
+
+	return updatedUsers, nil
+}
+
+// replaceRoleId quita roleId de roleIds y, si replacementId no está vacío, lo agrega sin duplicarlo
+func replaceRoleId(roleIds []string, roleId, replacementId string) []string {
+	result := slices.DeleteFunc(slices.Clone(roleIds), func(id string) bool { return id == roleId })
+	if replacementId != "" && !slices.Contains(result, replacementId) {
+		result = append(result, replacementId)
+	}
+	return result
 }
 
 // FindAllRolesByPageAndSize obtiene roles paginados
@@ -157,7 +473,18 @@ func (s *RoleServiceImpl) GetRolesByIds(ids []string) []*role.GetRoleByIdRespons
 }
 
 // FindAllRolesPaginated obtiene roles paginados y construye la respuesta paginada completa
-func (s *RoleServiceImpl) FindAllRolesPaginated(c *gin.Context, pageable *dto.Pageable) *dto.PaginationResponse[role.GetRoleByIdResponse] {
+func (s *RoleServiceImpl) FindAllRolesPaginated(c *gin.Context, pageable *dto.Pageable) (*dto.PaginationResponse[role.GetRoleByIdResponse], error) {
+	// Obtener el conteo total de roles
+	totalElements, err := s.roleRepository.Count()
+	if err != nil {
+		log.Printf("Error counting roles: %v", err)
+		return nil, err
+	}
+
+	if pageable.Cursor {
+		return s.findRolesAfterPageToken(c, pageable, int(totalElements))
+	}
+
 	// Convert from one-based (client) to zero-based (service) pagination
 	zeroBasedPage := pageable.Page - 1
 
@@ -165,20 +492,61 @@ func (s *RoleServiceImpl) FindAllRolesPaginated(c *gin.Context, pageable *dto.Pa
 	roles, err := s.roleRepository.FindAllByPageAndSize(zeroBasedPage, pageable.Size)
 	if err != nil {
 		log.Printf("Error fetching paginated roles: %v", err)
-		return nil
+		return nil, err
 	}
 
-	// Obtener el conteo total de roles
-	totalElements, err := s.roleRepository.Count()
+	// Convertir roles al formato de respuesta usando el mapper
+	roleMapper := &mapper.RoleMapper{}
+	rolesDTO := roleMapper.RolesToGetRolesResponse(roles)
+
+	// Crear la respuesta paginada
+	return dto.NewPaginationResponse(c, &rolesDTO, int(totalElements), pageable), nil
+}
+
+// rolePageToken es el contenido del token de página del listado de roles: el código y el ID del último rol entregado
+type rolePageToken struct {
+	Code string `json:"c"`
+	Id   string `json:"i"`
+}
+
+// findRolesAfterPageToken lee la página que sigue al token; pide un rol de más para saber si hay otra página
+func (s *RoleServiceImpl) findRolesAfterPageToken(c *gin.Context, pageable *dto.Pageable, totalElements int) (*dto.PaginationResponse[role.GetRoleByIdResponse], error) {
+	var after *repository.PageCursor
+	if pageable.PageToken != "" {
+		var token rolePageToken
+		if err := dto.DecodePageToken(pageable.PageToken, &token); err != nil {
+			return nil, err
+		}
+		if token.Id == "" {
+			return nil, dto.ErrInvalidPageToken
+		}
+		after = &repository.PageCursor{SortValue: token.Code, Id: token.Id}
+	}
+
+	roles, err := s.roleRepository.FindAllAfter(after, pageable.Size+1)
 	if err != nil {
-		log.Printf("Error counting roles: %v", err)
-		return nil
+		log.Printf("Error fetching paginated roles: %v", err)
+		return nil, err
+	}
+
+	nextPageToken := ""
+	if len(roles) > pageable.Size {
+		roles = roles[:pageable.Size]
+		last := roles[len(roles)-1]
+		nextPageToken = dto.EncodePageToken(rolePageToken{Code: last.Code, Id: last.Id})
 	}
 
-	// Convertir roles al formato de respuesta usando el mapper
 	roleMapper := &mapper.RoleMapper{}
 	rolesDTO := roleMapper.RolesToGetRolesResponse(roles)
+	return dto.NewCursorPaginationResponse(c, &rolesDTO, totalElements, pageable, nextPageToken), nil
+}
 
-	// Crear la respuesta paginada
-	return dto.NewPaginationResponse(c, &rolesDTO, int(totalElements), pageable)
+// validatePermissionGroups exige que cada concesión comodín sea de la forma "<grupo>:*" con un grupo conocido
+func validatePermissionGroups(wildcards []string) error {
+	for _, wildcard := range wildcards {
+		if _, ok := model.ParsePermissionWildcard(wildcard); !ok {
+			return fmt.Errorf("%w: unknown permission group %q", service.ErrInvalidRolePermissions, wildcard)
+		}
+	}
+	return nil
 }
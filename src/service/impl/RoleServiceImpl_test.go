@@ -0,0 +1,160 @@
+package impl
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/role"
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+func TestUpdateRoleByIdPrivileged(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name                string
+		existing            bool
+		requested           *bool
+		canChangePrivileged bool
+		want                bool
+		wantErr             error
+	}{
+		{"omitted keeps privileged", true, nil, false, true, nil},
+		{"omitted keeps regular", false, nil, false, false, nil},
+		{"same value without permission", true, &yes, false, true, nil},
+		{"unmark without permission", true, &no, false, true, service.ErrPrivilegedChangeNotAllowed},
+		{"mark without permission", false, &yes, false, false, service.ErrPrivilegedChangeNotAllowed},
+		{"unmark with permission", true, &no, true, false, nil},
+		{"mark with permission", false, &yes, true, true, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			roleRepository := newFakeRoleRepository(&model.Role{Id: "r1", Code: "FINANCE_ADMIN", Privileged: test.existing})
+			roleService := &RoleServiceImpl{roleRepository: roleRepository, roleMapper: &mapper.RoleMapper{}}
+
+			_, err := roleService.UpdateRoleById(&role.UpdateRoleRequest{Id: "r1", Code: "FINANCE_ADMIN", Privileged: test.requested}, test.canChangePrivileged)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("UpdateRoleById() error = %v, want %v", err, test.wantErr)
+			}
+			if got := roleRepository.roles["r1"].Privileged; got != test.want {
+				t.Fatalf("Privileged = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDeleteRoleByIdReassignmentSod(t *testing.T) {
+	tests := []struct {
+		name        string
+		users       []*model.User
+		groups      []*model.Group
+		constraint  []string
+		wantUserId  string
+		wantGroupId string
+	}{
+		{
+			name:       "user without conflict",
+			users:      []*model.User{{Id: "u1", RoleIds: []string{"r-old", "r-support"}}},
+			constraint: []string{"r-approver", "r-requester"},
+		},
+		{
+			name:       "user direct conflict",
+			users:      []*model.User{{Id: "u1", RoleIds: []string{"r-old", "r-requester"}}},
+			constraint: []string{"r-approver", "r-requester"},
+			wantUserId: "u1",
+		},
+		{
+			name: "user temporary conflict",
+			users: []*model.User{{Id: "u1", RoleIds: []string{"r-requester"}, RoleAssignments: []model.RoleAssignment{
+				{RoleId: "r-old", ValidFrom: time.Now().Add(-time.Hour), ValidUntil: time.Now().Add(time.Hour)},
+			}}},
+			constraint: []string{"r-approver", "r-requester"},
+			wantUserId: "u1",
+		},
+		{
+			name:       "group member conflict",
+			users:      []*model.User{{Id: "u2", RoleIds: []string{"r-requester"}}},
+			groups:     []*model.Group{{Id: "g1", MemberIds: []string{"u2"}, RoleIds: []string{"r-old"}}},
+			constraint: []string{"r-approver", "r-requester"},
+			wantUserId: "u2",
+		},
+		{
+			name:        "group roles conflict",
+			groups:      []*model.Group{{Id: "g1", RoleIds: []string{"r-old", "r-requester"}}},
+			constraint:  []string{"r-approver", "r-requester"},
+			wantGroupId: "g1",
+		},
+		{
+			name:       "constraint on the deleted role is migrated first",
+			users:      []*model.User{{Id: "u1", RoleIds: []string{"r-old", "r-approver"}}},
+			constraint: []string{"r-old", "r-approver"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			roleRepository := newFakeRoleRepository(
+				&model.Role{Id: "r-old", Code: "OLD_ROLE"},
+				&model.Role{Id: "r-approver", Code: "REFUND_APPROVER"},
+				&model.Role{Id: "r-requester", Code: "REFUND_REQUESTER"},
+				&model.Role{Id: "r-support", Code: "SUPPORT"},
+			)
+			userRepository := newFakeUserRepository(test.users...)
+			groupRepository := &fakeGroupRepository{groups: test.groups}
+			sodConstraintRepository := &fakeSodConstraintRepository{constraints: []*model.SodConstraint{
+				{Id: "c1", Name: "refunds", RoleIds: test.constraint},
+			}}
+			roleService := &RoleServiceImpl{
+				roleRepository:              roleRepository,
+				userRepository:              userRepository,
+				groupRepository:             groupRepository,
+				sodConstraintRepository:     sodConstraintRepository,
+				roleChangeRequestRepository: &fakeRoleChangeRequestRepository{},
+				roleMapper:                  &mapper.RoleMapper{},
+				sodChecker: &sodChecker{
+					sodConstraintRepository: sodConstraintRepository,
+					roleRepository:          roleRepository,
+					groupRepository:         groupRepository,
+				},
+			}
+
+			_, err := roleService.DeleteRoleById(&role.DeleteRoleByIdRequest{Id: "r-old", ReassignTo: "r-approver"})
+
+			conflict := test.wantUserId != "" || test.wantGroupId != ""
+			var violation *service.SodViolationError
+			if !conflict {
+				if err != nil {
+					t.Fatalf("DeleteRoleById() error = %v", err)
+				}
+				if _, ok := roleRepository.roles["r-old"]; ok {
+					t.Fatal("role was not deleted")
+				}
+				return
+			}
+			if !errors.As(err, &violation) {
+				t.Fatalf("DeleteRoleById() error = %v, want SodViolationError", err)
+			}
+			if violation.UserId != test.wantUserId || violation.GroupId != test.wantGroupId {
+				t.Fatalf("violation subject = user %q group %q, want user %q group %q", violation.UserId, violation.GroupId, test.wantUserId, test.wantGroupId)
+			}
+			// Nada se migra si la reasignación viola una restricción
+			if _, ok := roleRepository.roles["r-old"]; !ok {
+				t.Fatal("role was deleted")
+			}
+			for _, user := range userRepository.users {
+				if slices.Contains(user.RoleIds, "r-approver") && !slices.Contains(user.RoleIds, "r-old") {
+					t.Fatalf("user %s was migrated", user.Id)
+				}
+			}
+			for _, group := range groupRepository.groups {
+				if !slices.Contains(group.RoleIds, "r-old") {
+					t.Fatalf("group %s was migrated", group.Id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package impl
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// sodChecker verifica las restricciones de separación de funciones en cada camino que asigna roles
+type sodChecker struct {
+	sodConstraintRepository repository.SodConstraintRepository
+	roleRepository          repository.RoleRepository
+	groupRepository         repository.GroupRepository
+}
+
+func newSodChecker(roleRepository repository.RoleRepository) *sodChecker {
+	return &sodChecker{
+		sodConstraintRepository: impl.NewSodConstraintRepositoryImpl(),
+		roleRepository:          roleRepository,
+		groupRepository:         impl.NewGroupRepositoryImpl(),
+	}
+}
+
+// heldRoleIds devuelve los roles que el usuario tiene directamente, por asignaciones temporales
+// no vencidas o heredados de sus grupos, más los roles extra que se le quieren asignar
+func (c *sodChecker) heldRoleIds(user *model.User, now time.Time, extraRoleIds ...string) ([]string, error) {
+	roleIds := append(user.HeldRoleIds(now), extraRoleIds...)
+	if user.Id == "" {
+		return roleIds, nil
+	}
+
+	groups, err := c.groupRepository.FindByMemberId(user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch groups of user: %w", err)
+	}
+	for _, group := range groups {
+		for _, roleId := range group.RoleIds {
+			if !slices.Contains(roleIds, roleId) {
+				roleIds = append(roleIds, roleId)
+			}
+		}
+	}
+	return roleIds, nil
+}
+
+// check devuelve un *service.SodViolationError si roleIds incluye más de un rol de alguna restricción
+func (c *sodChecker) check(roleIds []string) error {
+	if len(roleIds) < 2 {
+		return nil
+	}
+
+	constraints, err := c.sodConstraintRepository.FindAll()
+	if err != nil {
+		return fmt.Errorf("failed to fetch separation of duty constraints: %w", err)
+	}
+	return c.checkConstraints(constraints, roleIds)
+}
+
+// checkConstraints es check con las restricciones ya leídas, para validar muchas asignaciones con una sola consulta
+func (c *sodChecker) checkConstraints(constraints []*model.SodConstraint, roleIds []string) error {
+	for _, constraint := range constraints {
+		conflicting := constraint.ConflictingRoleIds(roleIds)
+		if conflicting == nil {
+			continue
+		}
+		roleCodes, err := c.roleCodes(conflicting)
+		if err != nil {
+			return err
+		}
+		return &service.SodViolationError{
+			ConstraintId:   constraint.Id,
+			ConstraintName: constraint.Name,
+			RoleIds:        conflicting,
+			RoleCodes:      roleCodes,
+		}
+	}
+	return nil
+}
+
+func (c *sodChecker) roleCodes(roleIds []string) ([]string, error) {
+	roles, err := c.roleRepository.FindByIds(roleIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch roles: %w", err)
+	}
+	codes := make(map[string]string, len(roles))
+	for _, role := range roles {
+		codes[role.Id] = role.Code
+	}
+
+	roleCodes := make([]string, 0, len(roleIds))
+	for _, roleId := range roleIds {
+		if code, ok := codes[roleId]; ok {
+			roleCodes = append(roleCodes, code)
+		} else {
+			roleCodes = append(roleCodes, roleId)
+		}
+	}
+	return roleCodes, nil
+}
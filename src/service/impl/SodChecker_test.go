@@ -0,0 +1,111 @@
+package impl
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+func newTestSodChecker(groups ...*model.Group) *sodChecker {
+	return &sodChecker{
+		sodConstraintRepository: &fakeSodConstraintRepository{constraints: []*model.SodConstraint{
+			{Id: "c1", Name: "refunds", RoleIds: []string{"r-approver", "r-requester"}},
+		}},
+		roleRepository: newFakeRoleRepository(
+			&model.Role{Id: "r-approver", Code: "REFUND_APPROVER"},
+			&model.Role{Id: "r-requester", Code: "REFUND_REQUESTER"},
+			&model.Role{Id: "r-support", Code: "SUPPORT"},
+		),
+		groupRepository: &fakeGroupRepository{groups: groups},
+	}
+}
+
+func TestSodCheckerHeldRoleIds(t *testing.T) {
+	now := time.Now()
+	checker := newTestSodChecker(&model.Group{Id: "g1", MemberIds: []string{"u1"}, RoleIds: []string{"r-support", "r-approver"}})
+	user := &model.User{
+		Id:      "u1",
+		RoleIds: []string{"r-support"},
+		RoleAssignments: []model.RoleAssignment{
+			{RoleId: "r-future", ValidFrom: now.Add(time.Hour), ValidUntil: now.Add(2 * time.Hour)},
+			{RoleId: "r-expired", ValidFrom: now.Add(-2 * time.Hour), ValidUntil: now.Add(-time.Hour)},
+		},
+	}
+
+	got, err := checker.heldRoleIds(user, now, "r-requester")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"r-support", "r-future", "r-requester", "r-approver"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("heldRoleIds() = %v, want %v", got, want)
+	}
+}
+
+func TestSodCheckerCheck(t *testing.T) {
+	checker := newTestSodChecker()
+
+	tests := []struct {
+		name      string
+		roleIds   []string
+		wantCodes []string
+	}{
+		{"no roles", nil, nil},
+		{"single role", []string{"r-approver"}, nil},
+		{"unrelated roles", []string{"r-approver", "r-support"}, nil},
+		{"mutually exclusive roles", []string{"r-support", "r-requester", "r-approver"}, []string{"REFUND_APPROVER", "REFUND_REQUESTER"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checker.check(test.roleIds)
+			var violation *service.SodViolationError
+			if test.wantCodes == nil {
+				if err != nil {
+					t.Fatalf("check() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &violation) {
+				t.Fatalf("check() error = %v, want a SodViolationError", err)
+			}
+			if violation.ConstraintId != "c1" || !slices.Equal(violation.RoleCodes, test.wantCodes) {
+				t.Fatalf("violation = %+v, want constraint c1 with %v", violation, test.wantCodes)
+			}
+		})
+	}
+}
+
+func TestDecideRoleChangeRequestChecksSod(t *testing.T) {
+	roleRepository := newFakeRoleRepository(
+		&model.Role{Id: "r-approver", Code: "REFUND_APPROVER", Privileged: true},
+		&model.Role{Id: "r-requester", Code: "REFUND_REQUESTER"},
+	)
+	userRepository := newFakeUserRepository(&model.User{Id: "u1", RoleIds: []string{"r-requester"}})
+	requestRepository := &fakeRoleChangeRequestRepository{requests: make(map[string]*model.RoleChangeRequest), users: userRepository}
+	pending, err := newPrivilegedRoleGate(roleRepository, requestRepository).submit("u1", []string{"r-approver"}, nil, nil, "requester")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checker := newTestSodChecker()
+	checker.roleRepository = roleRepository
+	requestService := &RoleChangeRequestServiceImpl{
+		roleChangeRequestRepository: requestRepository,
+		roleRepository:              roleRepository,
+		sodChecker:                  checker,
+	}
+
+	// Approving would give the user both roles of the constraint
+	var violation *service.SodViolationError
+	if _, err := requestService.decide(pending.Id, "approver", model.RoleChangeApproved, ""); !errors.As(err, &violation) {
+		t.Fatalf("decide() error = %v, want a SodViolationError", err)
+	}
+	if got := userRepository.users["u1"].RoleIds; !slices.Equal(got, []string{"r-requester"}) {
+		t.Fatalf("RoleIds = %v, want the user unchanged", got)
+	}
+}
@@ -0,0 +1,240 @@
+package impl
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/sod"
+	"github.com/ruiborda/ecommerce-user-service/src/mapper"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+type SodConstraintServiceImpl struct {
+	sodConstraintRepository repository.SodConstraintRepository
+	roleRepository          repository.RoleRepository
+	userRepository          repository.UserRepository
+	groupRepository         repository.GroupRepository
+	sodConstraintMapper     *mapper.SodConstraintMapper
+}
+
+func NewSodConstraintServiceImpl() *SodConstraintServiceImpl {
+	return &SodConstraintServiceImpl{
+		sodConstraintRepository: impl.NewSodConstraintRepositoryImpl(),
+		roleRepository:          impl.NewRoleRepositoryImpl(),
+		userRepository:          impl.NewUserRepositoryImpl(),
+		groupRepository:         impl.NewGroupRepositoryImpl(),
+		sodConstraintMapper:     &mapper.SodConstraintMapper{},
+	}
+}
+
+// CreateSodConstraint crea una restricción de roles mutuamente excluyentes
+func (s *SodConstraintServiceImpl) CreateSodConstraint(request *sod.CreateSodConstraintRequest) (*sod.SodConstraintResponse, error) {
+	constraint := s.sodConstraintMapper.CreateSodConstraintRequestToSodConstraint(request)
+	rolesById, err := s.validate(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.sodConstraintRepository.Create(constraint)
+	if err != nil {
+		log.Printf("Error creating separation of duty constraint: %v", err)
+		return nil, err
+	}
+
+	return s.sodConstraintMapper.SodConstraintToResponse(created, rolesById), nil
+}
+
+// GetSodConstraintById obtiene una restricción por su ID
+func (s *SodConstraintServiceImpl) GetSodConstraintById(id string) (*sod.SodConstraintResponse, error) {
+	constraint, err := s.sodConstraintRepository.FindById(id)
+	if err != nil {
+		log.Printf("Error getting separation of duty constraint: %v", err)
+		return nil, err
+	}
+	if constraint == nil {
+		return nil, service.ErrSodConstraintNotFound
+	}
+
+	rolesById, err := s.rolesById(constraint.RoleIds)
+	if err != nil {
+		return nil, err
+	}
+	return s.sodConstraintMapper.SodConstraintToResponse(constraint, rolesById), nil
+}
+
+// GetAllSodConstraints obtiene todas las restricciones
+func (s *SodConstraintServiceImpl) GetAllSodConstraints() ([]*sod.SodConstraintResponse, error) {
+	constraints, err := s.sodConstraintRepository.FindAll()
+	if err != nil {
+		log.Printf("Error getting separation of duty constraints: %v", err)
+		return nil, err
+	}
+
+	rolesById, err := s.rolesById(constraintRoleIds(constraints))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*sod.SodConstraintResponse, 0, len(constraints))
+	for _, constraint := range constraints {
+		responses = append(responses, s.sodConstraintMapper.SodConstraintToResponse(constraint, rolesById))
+	}
+	return responses, nil
+}
+
+// UpdateSodConstraint actualiza una restricción existente; no modifica a los usuarios que ya la incumplen
+func (s *SodConstraintServiceImpl) UpdateSodConstraint(request *sod.UpdateSodConstraintRequest) (*sod.SodConstraintResponse, error) {
+	existing, err := s.sodConstraintRepository.FindById(request.Id)
+	if err != nil {
+		log.Printf("Error getting separation of duty constraint to update: %v", err)
+		return nil, err
+	}
+	if existing == nil {
+		return nil, service.ErrSodConstraintNotFound
+	}
+
+	constraint := s.sodConstraintMapper.UpdateSodConstraintRequestToSodConstraint(request, existing)
+	rolesById, err := s.validate(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.sodConstraintRepository.Update(constraint)
+	if err != nil {
+		log.Printf("Error updating separation of duty constraint: %v", err)
+		return nil, err
+	}
+
+	return s.sodConstraintMapper.SodConstraintToResponse(updated, rolesById), nil
+}
+
+// DeleteSodConstraintById elimina una restricción
+func (s *SodConstraintServiceImpl) DeleteSodConstraintById(id string) error {
+	existing, err := s.sodConstraintRepository.FindById(id)
+	if err != nil {
+		log.Printf("Error getting separation of duty constraint to delete: %v", err)
+		return err
+	}
+	if existing == nil {
+		return service.ErrSodConstraintNotFound
+	}
+
+	if err := s.sodConstraintRepository.Delete(id); err != nil {
+		log.Printf("Error deleting separation of duty constraint: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetSodViolations lista los usuarios que ya tienen roles mutuamente excluyentes,
+// por ejemplo porque la restricción se creó después de asignarlos
+func (s *SodConstraintServiceImpl) GetSodViolations() ([]*sod.SodViolationResponse, error) {
+	constraints, err := s.sodConstraintRepository.FindAll()
+	if err != nil {
+		log.Printf("Error getting separation of duty constraints: %v", err)
+		return nil, err
+	}
+
+	responses := make([]*sod.SodViolationResponse, 0)
+	if len(constraints) == 0 {
+		return responses, nil
+	}
+
+	users, err := s.userRepository.FindAll()
+	if err != nil {
+		log.Printf("Error getting users for separation of duty report: %v", err)
+		return nil, err
+	}
+
+	rolesById, err := s.rolesById(constraintRoleIds(constraints))
+	if err != nil {
+		return nil, err
+	}
+
+	// Los roles heredados de grupos también cuentan
+	groups, err := s.groupRepository.FindAll()
+	if err != nil {
+		log.Printf("Error getting groups for separation of duty report: %v", err)
+		return nil, err
+	}
+	groupRoleIds := make(map[string][]string)
+	for _, group := range groups {
+		for _, memberId := range group.MemberIds {
+			groupRoleIds[memberId] = append(groupRoleIds[memberId], group.RoleIds...)
+		}
+	}
+
+	now := time.Now()
+	for _, user := range users {
+		heldRoleIds := append(user.HeldRoleIds(now), groupRoleIds[user.Id]...)
+		for _, constraint := range constraints {
+			if conflicting := constraint.ConflictingRoleIds(heldRoleIds); conflicting != nil {
+				responses = append(responses, s.sodConstraintMapper.ToSodViolationResponse(user, constraint, conflicting, rolesById))
+			}
+		}
+	}
+	return responses, nil
+}
+
+func (s *SodConstraintServiceImpl) validate(constraint *model.SodConstraint) (map[string]*model.Role, error) {
+	if constraint.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", service.ErrInvalidSodConstraint)
+	}
+
+	// Se ignoran los roles repetidos
+	roleIds := make([]string, 0, len(constraint.RoleIds))
+	for _, roleId := range constraint.RoleIds {
+		if !slices.Contains(roleIds, roleId) {
+			roleIds = append(roleIds, roleId)
+		}
+	}
+	if len(roleIds) < 2 {
+		return nil, fmt.Errorf("%w: at least two different roles are required", service.ErrInvalidSodConstraint)
+	}
+	constraint.RoleIds = roleIds
+
+	rolesById, err := s.rolesById(roleIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, roleId := range roleIds {
+		if _, ok := rolesById[roleId]; !ok {
+			return nil, fmt.Errorf("%w: role %s does not exist", service.ErrInvalidSodConstraint, roleId)
+		}
+	}
+	return rolesById, nil
+}
+
+func (s *SodConstraintServiceImpl) rolesById(roleIds []string) (map[string]*model.Role, error) {
+	rolesById := make(map[string]*model.Role)
+	if len(roleIds) == 0 {
+		return rolesById, nil
+	}
+
+	roles, err := s.roleRepository.FindByIds(roleIds)
+	if err != nil {
+		log.Printf("Error fetching roles of separation of duty constraints: %v", err)
+		return nil, err
+	}
+	for _, role := range roles {
+		rolesById[role.Id] = role
+	}
+	return rolesById, nil
+}
+
+func constraintRoleIds(constraints []*model.SodConstraint) []string {
+	var roleIds []string
+	for _, constraint := range constraints {
+		for _, roleId := range constraint.RoleIds {
+			if !slices.Contains(roleIds, roleId) {
+				roleIds = append(roleIds, roleId)
+			}
+		}
+	}
+	return roleIds
+}
@@ -0,0 +1,177 @@
+package impl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// Formatos de archivo admitidos en la importación de usuarios
+const (
+	userImportCsv    = "csv"
+	userImportNdjson = "ndjson"
+)
+
+// userImportColumns son las columnas admitidas en la cabecera del CSV, en minúsculas
+var userImportColumns = []string{"email", "fullname", "roles", "password", "invite"}
+
+// parsedImportRow es una fila leída del archivo; err describe por qué no se pudo interpretar
+type parsedImportRow struct {
+	line int
+	row  user.UserImportRow
+	err  string
+}
+
+// userImportFormat devuelve el formato indicado o, si está vacío, el que corresponde a la extensión del archivo
+func userImportFormat(format, fileName string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(fileName)) {
+		case ".csv":
+			format = userImportCsv
+		case ".ndjson", ".jsonl":
+			format = userImportNdjson
+		}
+	}
+	if format != userImportCsv && format != userImportNdjson {
+		return "", fmt.Errorf("%w: format must be csv or ndjson", service.ErrInvalidUserImport)
+	}
+	return format, nil
+}
+
+// parseUserImport lee las filas del archivo. Un error de una fila se guarda en ella para informarlo junto con
+// los demás; solo un archivo que no se puede recorrer devuelve error.
+func parseUserImport(data []byte, format string) ([]parsedImportRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	var rows []parsedImportRow
+	var err error
+	if format == userImportCsv {
+		rows, err = parseUserImportCsv(data)
+	} else {
+		rows, err = parseUserImportNdjson(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", service.ErrInvalidUserImport)
+	}
+	return rows, nil
+}
+
+func parseUserImportCsv(data []byte) ([]parsedImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", service.ErrInvalidUserImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrInvalidUserImport, err)
+	}
+	columns := make(map[string]int, len(header))
+	for index, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(userImportColumns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q, expected email, fullName, roles, password and invite", service.ErrInvalidUserImport, header[index])
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", service.ErrInvalidUserImport, header[index])
+		}
+		columns[name] = index
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, fmt.Errorf("%w: the email column is required", service.ErrInvalidUserImport)
+	}
+
+	var rows []parsedImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", service.ErrInvalidUserImport, err)
+		}
+		if len(rows) == service.MaxUserImportRows {
+			return nil, fmt.Errorf("%w: the file has more than %d rows", service.ErrInvalidUserImport, service.MaxUserImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		parsed := parsedImportRow{line: line}
+		if len(record) != len(header) {
+			parsed.err = fmt.Sprintf("expected %d fields, got %d", len(header), len(record))
+			rows = append(rows, parsed)
+			continue
+		}
+		field := func(name string) string {
+			if index, ok := columns[name]; ok {
+				return record[index]
+			}
+			return ""
+		}
+		parsed.row = user.UserImportRow{
+			Email:    field("email"),
+			FullName: field("fullname"),
+			Password: field("password"),
+		}
+		for _, code := range strings.Split(field("roles"), ";") {
+			if code = strings.TrimSpace(code); code != "" {
+				parsed.row.Roles = append(parsed.row.Roles, code)
+			}
+		}
+		if invite := strings.TrimSpace(field("invite")); invite != "" {
+			value, err := strconv.ParseBool(strings.ToLower(invite))
+			if err != nil {
+				parsed.err = fmt.Sprintf("invite must be true or false, got %q", invite)
+			}
+			parsed.row.Invite = value
+		}
+		rows = append(rows, parsed)
+	}
+	return rows, nil
+}
+
+func parseUserImportNdjson(data []byte) ([]parsedImportRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+
+	var rows []parsedImportRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if len(rows) == service.MaxUserImportRows {
+			return nil, fmt.Errorf("%w: the file has more than %d rows", service.ErrInvalidUserImport, service.MaxUserImportRows)
+		}
+
+		parsed := parsedImportRow{line: line}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&parsed.row); err != nil {
+			parsed.err = fmt.Sprintf("invalid JSON: %v", err)
+		} else if decoder.More() {
+			parsed.err = "invalid JSON: one object per line expected"
+		}
+		rows = append(rows, parsed)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrInvalidUserImport, err)
+	}
+	return rows, nil
+}
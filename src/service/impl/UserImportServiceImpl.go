@@ -0,0 +1,469 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Estrategias para los emails del archivo que ya están registrados
+const (
+	userImportSkipDuplicates   = "skip"
+	userImportUpdateDuplicates = "update"
+	userImportFailDuplicates   = "fail"
+)
+
+const (
+	// Usuarios escritos en cada transacción de la importación
+	userImportBatchSize = 500
+	maxEmailLength      = 254
+)
+
+// errUserImportUserDeleted indica que el usuario se eliminó después de validar el archivo
+var errUserImportUserDeleted = errors.New("the registered user is deleted; restore it before updating")
+
+// plannedImportRow es una fila válida con los cambios que se escribirán
+type plannedImportRow struct {
+	result *user.UserImportRowResult
+	// Usuario nuevo; es nil si la fila actualiza el usuario userId
+	user   *model.User
+	userId string
+	// Cambios de la fila, que se aplican al usuario leído al escribir
+	fullName          string
+	roleIds           []string
+	privilegedRoleIds []string
+	password          string
+	passwordHash      string
+}
+
+type UserImportServiceImpl struct {
+	userRepository          repository.UserRepository
+	roleRepository          repository.RoleRepository
+	sodConstraintRepository repository.SodConstraintRepository
+	privilegedRoleGate      *privilegedRoleGate
+	sodChecker              *sodChecker
+}
+
+func NewUserImportServiceImpl() *UserImportServiceImpl {
+	roleRepository := impl.NewRoleRepositoryImpl()
+	return &UserImportServiceImpl{
+		userRepository:          impl.NewUserRepositoryImpl(),
+		roleRepository:          roleRepository,
+		sodConstraintRepository: impl.NewSodConstraintRepositoryImpl(),
+		privilegedRoleGate:      newPrivilegedRoleGate(roleRepository, impl.NewRoleChangeRequestRepositoryImpl()),
+		sodChecker:              newSodChecker(roleRepository),
+	}
+}
+
+// ImportUsers valida primero todas las filas contra los roles, las restricciones de separación de funciones y los
+// usuarios existentes. Si alguna no es válida, o es una simulación, devuelve el informe sin escribir nada.
+func (s *UserImportServiceImpl) ImportUsers(data []byte, fileName string, request *user.UserImportRequest, actorId string) (*user.UserImportReport, error) {
+	if len(data) > service.MaxUserImportBytes {
+		return nil, service.ErrUserImportTooLarge
+	}
+	format, err := userImportFormat(request.Format, fileName)
+	if err != nil {
+		return nil, err
+	}
+	onDuplicate := strings.ToLower(strings.TrimSpace(request.OnDuplicate))
+	if onDuplicate == "" {
+		onDuplicate = userImportSkipDuplicates
+	}
+	if !slices.Contains([]string{userImportSkipDuplicates, userImportUpdateDuplicates, userImportFailDuplicates}, onDuplicate) {
+		return nil, fmt.Errorf("%w: onDuplicate must be skip, update or fail", service.ErrInvalidUserImport)
+	}
+
+	rows, err := parseUserImport(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &user.UserImportReport{
+		DryRun:      request.DryRun,
+		Format:      format,
+		OnDuplicate: onDuplicate,
+		Total:       len(rows),
+		Rows:        make([]user.UserImportRowResult, len(rows)),
+	}
+	planned, constraints, err := s.planImport(rows, onDuplicate, report)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := !slices.ContainsFunc(report.Rows, func(row user.UserImportRowResult) bool {
+		return row.Result == user.UserImportInvalid
+	})
+	if valid && !request.DryRun {
+		s.applyImport(planned, constraints, actorId)
+		report.Applied = true
+	}
+	countUserImportResults(report)
+	return report, nil
+}
+
+// planImport valida cada fila y decide su resultado. Lee los roles, los usuarios existentes y las restricciones de
+// separación de funciones una sola vez para todo el archivo, y devuelve las restricciones para volver a comprobarlas al escribir.
+func (s *UserImportServiceImpl) planImport(rows []parsedImportRow, onDuplicate string, report *user.UserImportReport) ([]*plannedImportRow, []*model.SodConstraint, error) {
+	now := time.Now()
+	for index := range rows {
+		rows[index].row.Email = strings.TrimSpace(rows[index].row.Email)
+		rows[index].row.FullName = strings.TrimSpace(rows[index].row.FullName)
+	}
+
+	rolesByCode, err := s.findRolesByCode(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	existingByEmail, err := s.findExistingUsers(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	constraints, err := s.sodConstraintRepository.FindAll()
+	if err != nil {
+		log.Printf("Error fetching separation of duty constraints: %v", err)
+		return nil, nil, err
+	}
+
+	var planned []*plannedImportRow
+	firstLineByEmail := make(map[string]int)
+	for index, parsed := range rows {
+		result := &report.Rows[index]
+		result.Line = parsed.line
+		result.Email = parsed.row.Email
+
+		if parsed.err != "" {
+			result.Result = user.UserImportInvalid
+			result.Errors = []string{parsed.err}
+			continue
+		}
+		row := parsed.row
+		existing := existingByEmail[row.Email]
+		errs := validateUserImportRow(&row, existing != nil)
+
+		if firstLine, ok := firstLineByEmail[row.Email]; ok && row.Email != "" {
+			errs = append(errs, fmt.Sprintf("duplicate email, first used on line %d", firstLine))
+		} else {
+			firstLineByEmail[row.Email] = parsed.line
+		}
+
+		var regularRoleIds, privilegedRoleIds []string
+		for _, code := range row.Roles {
+			role, ok := rolesByCode[code]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("unknown role code %q", code))
+				continue
+			}
+			if slices.Contains(regularRoleIds, role.Id) || slices.Contains(privilegedRoleIds, role.Id) {
+				continue
+			}
+			if role.Privileged {
+				privilegedRoleIds = append(privilegedRoleIds, role.Id)
+			} else {
+				regularRoleIds = append(regularRoleIds, role.Id)
+			}
+		}
+
+		plan := &plannedImportRow{result: result, roleIds: regularRoleIds, password: row.Password}
+		switch {
+		case existing == nil:
+			result.Result = user.UserImportCreated
+			result.Invited = row.Invite
+			plan.user = &model.User{
+				Email:                  row.Email,
+				FullName:               row.FullName,
+				RoleIds:                regularRoleIds,
+				CreatedAt:              now,
+				UpdatedAt:              now,
+				FavoriteNewsArticleIds: []string{},
+			}
+		case onDuplicate == userImportSkipDuplicates:
+			result.Result = user.UserImportSkipped
+			result.UserId = existing.Id
+		case onDuplicate == userImportFailDuplicates:
+			errs = append(errs, "email already registered")
+		case existing.Status == model.UserStatusDeleted || existing.Status == model.UserStatusErased:
+			errs = append(errs, "the registered user is deleted; restore it before updating")
+		default:
+			result.Result = user.UserImportUpdated
+			result.UserId = existing.Id
+			plan.userId = existing.Id
+			plan.fullName = row.FullName
+			privilegedRoleIds = slices.DeleteFunc(privilegedRoleIds, func(roleId string) bool {
+				return slices.Contains(existing.RoleIds, roleId)
+			})
+		}
+
+		// Las restricciones incluyen los roles que quedan pendientes de aprobación, como en el alta individual
+		writes := plan.user != nil || plan.userId != ""
+		if writes && len(errs) == 0 {
+			roleIds := append(slices.Clone(regularRoleIds), privilegedRoleIds...)
+			if existing != nil {
+				roleIds, err = s.sodChecker.heldRoleIds(existing, now, roleIds...)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			if err := s.sodChecker.checkConstraints(constraints, roleIds); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+
+		if len(errs) > 0 {
+			result.Result = user.UserImportInvalid
+			result.UserId = ""
+			result.Invited = false
+			result.Errors = errs
+			continue
+		}
+		if writes {
+			plan.privilegedRoleIds = privilegedRoleIds
+			planned = append(planned, plan)
+		}
+	}
+	return planned, constraints, nil
+}
+
+// validateUserImportRow comprueba los campos de la fila; los roles y los duplicados se comprueban aparte
+func validateUserImportRow(row *user.UserImportRow, registered bool) []string {
+	var errs []string
+	if row.Email == "" {
+		errs = append(errs, "email is required")
+	} else if address, err := mail.ParseAddress(row.Email); err != nil || address.Address != row.Email || len(row.Email) > maxEmailLength {
+		errs = append(errs, "email is not a valid address")
+	}
+	if utf8.RuneCountInString(row.FullName) > maxFullNameLength {
+		errs = append(errs, fmt.Sprintf("fullName must have at most %d characters", maxFullNameLength))
+	}
+	if row.Password != "" && row.Invite {
+		errs = append(errs, "password and invite cannot be used together")
+	}
+	if row.Password == "" && !row.Invite && !registered {
+		errs = append(errs, "password or invite is required")
+	}
+	if row.Password != "" && (len(row.Password) < minPasswordLength || len(row.Password) > maxPasswordBytes) {
+		errs = append(errs, fmt.Sprintf("password must have between %d and %d bytes", minPasswordLength, maxPasswordBytes))
+	}
+	return errs
+}
+
+// findRolesByCode resuelve los códigos de rol del archivo; solo se admiten roles globales
+func (s *UserImportServiceImpl) findRolesByCode(rows []parsedImportRow) (map[string]*model.Role, error) {
+	rolesByCode := make(map[string]*model.Role)
+	for _, parsed := range rows {
+		for _, code := range parsed.row.Roles {
+			if _, ok := rolesByCode[code]; ok {
+				continue
+			}
+			role, err := s.roleRepository.FindByCode(code)
+			if err != nil {
+				log.Printf("Error fetching role by code: %v", err)
+				return nil, err
+			}
+			if role != nil {
+				rolesByCode[code] = role
+			}
+		}
+	}
+	return rolesByCode, nil
+}
+
+func (s *UserImportServiceImpl) findExistingUsers(rows []parsedImportRow) (map[string]*model.User, error) {
+	emails := make([]string, 0, len(rows))
+	for _, parsed := range rows {
+		if parsed.row.Email != "" && !slices.Contains(emails, parsed.row.Email) {
+			emails = append(emails, parsed.row.Email)
+		}
+	}
+	users, err := s.userRepository.FindByEmails(emails)
+	if err != nil {
+		log.Printf("Error fetching users by email: %v", err)
+		return nil, err
+	}
+	existingByEmail := make(map[string]*model.User, len(users))
+	for _, userModel := range users {
+		existingByEmail[userModel.Email] = userModel
+	}
+	return existingByEmail, nil
+}
+
+// applyImport escribe los usuarios nuevos por lotes y aplica cada actualización en su propia transacción. Una escritura
+// que falla marca sus filas como FAILED y la importación sigue; los roles privilegiados se solicitan después de
+// escribir cada usuario.
+func (s *UserImportServiceImpl) applyImport(planned []*plannedImportRow, constraints []*model.SodConstraint, actorId string) {
+	hashUserImportPasswords(planned)
+
+	var created []*plannedImportRow
+	for _, plan := range planned {
+		if plan.result.Result == user.UserImportFailed {
+			continue
+		}
+		if plan.user == nil {
+			s.applyImportUpdate(plan, constraints, actorId)
+			continue
+		}
+		plan.user.PasswordHash = plan.passwordHash
+		created = append(created, plan)
+	}
+
+	for batch := range slices.Chunk(created, userImportBatchSize) {
+		users := make([]*model.User, 0, len(batch))
+		for _, plan := range batch {
+			users = append(users, plan.user)
+		}
+
+		// Otro registro puede haber usado el email después de validar el archivo
+		taken, err := s.userRepository.CreateAll(users)
+		if err != nil {
+			log.Printf("Error saving imported users: %v", err)
+			for _, plan := range batch {
+				failImportRow(plan, "failed to save the user")
+			}
+			continue
+		}
+
+		for _, plan := range batch {
+			if slices.Contains(taken, plan.user.Email) {
+				failImportRow(plan, "email already registered")
+				continue
+			}
+			plan.result.UserId = plan.user.Id
+			s.requestImportPrivilegedRoles(plan, plan.privilegedRoleIds, actorId)
+		}
+	}
+}
+
+// applyImportUpdate aplica la fila sobre el usuario leído en la transacción, así que no pisa los cambios hechos
+// después de validar el archivo. El estado y las restricciones se vuelven a comprobar con los roles actuales.
+func (s *UserImportServiceImpl) applyImportUpdate(plan *plannedImportRow, constraints []*model.SodConstraint, actorId string) {
+	var privilegedRoleIds []string
+	updated, err := s.userRepository.Update(plan.userId, func(userModel *model.User) error {
+		if userModel.Status == model.UserStatusDeleted || userModel.Status == model.UserStatusErased {
+			return errUserImportUserDeleted
+		}
+		previousUser := *userModel
+		if plan.fullName != "" {
+			userModel.FullName = plan.fullName
+		}
+		// Los roles del archivo se añaden a los del usuario, nunca se le quitan
+		for _, roleId := range plan.roleIds {
+			if !slices.Contains(userModel.RoleIds, roleId) {
+				userModel.RoleIds = append(userModel.RoleIds, roleId)
+			}
+		}
+		privilegedRoleIds = slices.DeleteFunc(slices.Clone(plan.privilegedRoleIds), func(roleId string) bool {
+			return slices.Contains(userModel.RoleIds, roleId)
+		})
+
+		roleIds, err := s.sodChecker.heldRoleIds(userModel, time.Now(), privilegedRoleIds...)
+		if err != nil {
+			return err
+		}
+		if err := s.sodChecker.checkConstraints(constraints, roleIds); err != nil {
+			return err
+		}
+
+		if plan.passwordHash != "" {
+			userModel.PasswordHash = plan.passwordHash
+		}
+		// Los tokens emitidos antes dejan de valer si cambian los roles o la contraseña
+		if authzChanged(&previousUser, userModel) || plan.passwordHash != "" {
+			userModel.AuthzVersion++
+		}
+		return nil
+	})
+
+	var sodViolation *service.SodViolationError
+	switch {
+	case errors.Is(err, errUserImportUserDeleted) || errors.As(err, &sodViolation):
+		failImportRow(plan, err.Error())
+	case err != nil:
+		log.Printf("Error updating imported user: %v", err)
+		failImportRow(plan, "failed to save the user")
+	case updated == nil:
+		failImportRow(plan, "the registered user no longer exists")
+	default:
+		s.requestImportPrivilegedRoles(plan, privilegedRoleIds, actorId)
+	}
+}
+
+func (s *UserImportServiceImpl) requestImportPrivilegedRoles(plan *plannedImportRow, privilegedRoleIds []string, actorId string) {
+	if len(privilegedRoleIds) == 0 {
+		return
+	}
+	pendingRequest, err := s.privilegedRoleGate.submit(plan.result.UserId, privilegedRoleIds, nil, nil, actorId)
+	if err != nil {
+		log.Printf("Error creating role change request: %v", err)
+		plan.result.Errors = append(plan.result.Errors, "failed to request the privileged roles")
+		return
+	}
+	plan.result.PendingRoleChangeRequestId = pendingRequest.Id
+}
+
+// failImportRow marca como FAILED una fila que no se pudo escribir
+func failImportRow(plan *plannedImportRow, reason string) {
+	plan.result.Result = user.UserImportFailed
+	plan.result.UserId = ""
+	plan.result.Invited = false
+	plan.result.Errors = append(plan.result.Errors, reason)
+}
+
+// hashUserImportPasswords calcula los hashes en paralelo: bcrypt tarda decenas de milisegundos por contraseña.
+// Una fila cuyo hash falla queda como FAILED.
+func hashUserImportPasswords(planned []*plannedImportRow) {
+	var wg sync.WaitGroup
+	work := make(chan *plannedImportRow)
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for plan := range work {
+				passwordHash, err := bcrypt.GenerateFromPassword([]byte(plan.password), bcrypt.DefaultCost)
+				if err != nil {
+					log.Printf("Error hashing password: %v", err)
+					failImportRow(plan, "failed to hash the password")
+					continue
+				}
+				plan.passwordHash = string(passwordHash)
+			}
+		}()
+	}
+	for _, plan := range planned {
+		if plan.password != "" {
+			work <- plan
+		}
+	}
+	close(work)
+	wg.Wait()
+}
+
+func countUserImportResults(report *user.UserImportReport) {
+	for _, row := range report.Rows {
+		switch row.Result {
+		case user.UserImportCreated:
+			report.Created++
+		case user.UserImportUpdated:
+			report.Updated++
+		case user.UserImportSkipped:
+			report.Skipped++
+		case user.UserImportInvalid:
+			report.Invalid++
+		case user.UserImportFailed:
+			report.Failed++
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package impl
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+func newTestUserImportService(users ...*model.User) *UserImportServiceImpl {
+	roleRepository := newFakeRoleRepository(
+		&model.Role{Id: "r-approver", Code: "REFUND_APPROVER"},
+		&model.Role{Id: "r-requester", Code: "REFUND_REQUESTER"},
+		&model.Role{Id: "r-support", Code: "SUPPORT"},
+		&model.Role{Id: "r-admin", Code: "ADMIN", Privileged: true},
+	)
+	userRepository := newFakeUserRepository(users...)
+	checker := newTestSodChecker()
+	checker.roleRepository = roleRepository
+	return &UserImportServiceImpl{
+		userRepository:          userRepository,
+		roleRepository:          roleRepository,
+		sodConstraintRepository: checker.sodConstraintRepository,
+		privilegedRoleGate: newPrivilegedRoleGate(roleRepository, &fakeRoleChangeRequestRepository{
+			requests: make(map[string]*model.RoleChangeRequest),
+			users:    userRepository,
+		}),
+		sodChecker: checker,
+	}
+}
+
+func parsedImportRows(rows ...user.UserImportRow) []parsedImportRow {
+	parsed := make([]parsedImportRow, len(rows))
+	for i, row := range rows {
+		parsed[i] = parsedImportRow{line: i + 2, row: row}
+	}
+	return parsed
+}
+
+func TestValidateUserImportRow(t *testing.T) {
+	tests := []struct {
+		name       string
+		row        user.UserImportRow
+		registered bool
+		wantErrs   int
+	}{
+		{"new user with password", user.UserImportRow{Email: "ana@example.com", Password: "secret-pass"}, false, 0},
+		{"invited user", user.UserImportRow{Email: "ana@example.com", Invite: true}, false, 0},
+		{"registered user without credentials", user.UserImportRow{Email: "ana@example.com"}, true, 0},
+		{"missing email", user.UserImportRow{Invite: true}, false, 1},
+		{"email with display name", user.UserImportRow{Email: "Ana <ana@example.com>", Invite: true}, false, 1},
+		{"email too long", user.UserImportRow{Email: strings.Repeat("a", 250) + "@example.com", Invite: true}, false, 1},
+		{"full name too long", user.UserImportRow{Email: "ana@example.com", FullName: strings.Repeat("á", maxFullNameLength+1), Invite: true}, false, 1},
+		{"password and invite", user.UserImportRow{Email: "ana@example.com", Password: "secret-pass", Invite: true}, false, 1},
+		{"new user without credentials", user.UserImportRow{Email: "ana@example.com"}, false, 1},
+		{"short password", user.UserImportRow{Email: "ana@example.com", Password: "short"}, false, 1},
+		{"password over the bcrypt limit", user.UserImportRow{Email: "ana@example.com", Password: strings.Repeat("p", maxPasswordBytes+1)}, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := validateUserImportRow(&tt.row, tt.registered); len(errs) != tt.wantErrs {
+				t.Fatalf("validateUserImportRow() = %v, want %d errors", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestPlanImportResults(t *testing.T) {
+	tests := []struct {
+		name        string
+		onDuplicate string
+		row         user.UserImportRow
+		want        string
+	}{
+		{"new user", userImportUpdateDuplicates, user.UserImportRow{Email: "new@example.com", Roles: []string{"SUPPORT"}, Invite: true}, user.UserImportCreated},
+		{"registered user skipped", userImportSkipDuplicates, user.UserImportRow{Email: "ana@example.com"}, user.UserImportSkipped},
+		{"registered user fails", userImportFailDuplicates, user.UserImportRow{Email: "ana@example.com"}, user.UserImportInvalid},
+		{"registered user updated", userImportUpdateDuplicates, user.UserImportRow{Email: "ana@example.com", Roles: []string{"SUPPORT"}}, user.UserImportUpdated},
+		{"deleted user", userImportUpdateDuplicates, user.UserImportRow{Email: "gone@example.com"}, user.UserImportInvalid},
+		{"unknown role", userImportUpdateDuplicates, user.UserImportRow{Email: "new@example.com", Roles: []string{"OWNER"}, Invite: true}, user.UserImportInvalid},
+		{"conflicting roles", userImportUpdateDuplicates, user.UserImportRow{Email: "ana@example.com", Roles: []string{"REFUND_APPROVER"}}, user.UserImportInvalid},
+		{"invalid row", userImportUpdateDuplicates, user.UserImportRow{Email: "new@example.com"}, user.UserImportInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			importService := newTestUserImportService(
+				&model.User{Id: "u-ana", Email: "ana@example.com", Status: model.UserStatusActive, RoleIds: []string{"r-requester"}},
+				&model.User{Id: "u-gone", Email: "gone@example.com", Status: model.UserStatusDeleted},
+			)
+			report := &user.UserImportReport{Rows: make([]user.UserImportRowResult, 1)}
+
+			planned, _, err := importService.planImport(parsedImportRows(tt.row), tt.onDuplicate, report)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := report.Rows[0].Result; got != tt.want {
+				t.Fatalf("result = %s (%v), want %s", got, report.Rows[0].Errors, tt.want)
+			}
+			// Solo se escriben las filas creadas o actualizadas
+			wantPlanned := tt.want == user.UserImportCreated || tt.want == user.UserImportUpdated
+			if (len(planned) == 1) != wantPlanned {
+				t.Fatalf("planned %d rows, want written = %v", len(planned), wantPlanned)
+			}
+		})
+	}
+}
+
+func TestPlanImportRejectsDuplicateLines(t *testing.T) {
+	importService := newTestUserImportService()
+	report := &user.UserImportReport{Rows: make([]user.UserImportRowResult, 2)}
+	rows := parsedImportRows(
+		user.UserImportRow{Email: "ana@example.com", Invite: true},
+		user.UserImportRow{Email: "ana@example.com", Invite: true},
+	)
+
+	if _, _, err := importService.planImport(rows, userImportSkipDuplicates, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Rows[0].Result != user.UserImportCreated || report.Rows[1].Result != user.UserImportInvalid {
+		t.Fatalf("results = %s, %s, want CREATED, INVALID", report.Rows[0].Result, report.Rows[1].Result)
+	}
+}
+
+func TestApplyImportKeepsChangesMadeAfterPlanning(t *testing.T) {
+	importService := newTestUserImportService(
+		&model.User{Id: "u-ana", Email: "ana@example.com", Status: model.UserStatusActive, RoleIds: []string{"r-approver"}, PasswordHash: "old"},
+		&model.User{Id: "u-bob", Email: "bob@example.com", Status: model.UserStatusActive},
+		&model.User{Id: "u-dan", Email: "dan@example.com", Status: model.UserStatusActive},
+	)
+	users := importService.userRepository.(*fakeUserRepository)
+	report := &user.UserImportReport{Rows: make([]user.UserImportRowResult, 4)}
+	rows := parsedImportRows(
+		user.UserImportRow{Email: "ana@example.com", FullName: "Ana", Roles: []string{"SUPPORT", "ADMIN"}},
+		user.UserImportRow{Email: "bob@example.com", Roles: []string{"SUPPORT"}},
+		user.UserImportRow{Email: "cam@example.com", Invite: true},
+		user.UserImportRow{Email: "dan@example.com", Roles: []string{"REFUND_APPROVER"}},
+	)
+
+	planned, constraints, err := importService.planImport(rows, userImportUpdateDuplicates, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mientras se calculan los hashes: se le quita un rol a ana y cambia su contraseña, se elimina a bob,
+	// cam se registra por su cuenta y dan recibe un rol incompatible con el del archivo
+	ana := *users.users["u-ana"]
+	ana.RoleIds = nil
+	ana.PasswordHash = "changed"
+	users.users["u-ana"] = &ana
+	bob := *users.users["u-bob"]
+	bob.Status = model.UserStatusDeleted
+	users.users["u-bob"] = &bob
+	users.users["u-cam"] = &model.User{Id: "u-cam", Email: "cam@example.com", Status: model.UserStatusActive}
+	dan := *users.users["u-dan"]
+	dan.RoleIds = []string{"r-requester"}
+	users.users["u-dan"] = &dan
+
+	importService.applyImport(planned, constraints, "admin")
+
+	wantResults := []string{user.UserImportUpdated, user.UserImportFailed, user.UserImportFailed, user.UserImportFailed}
+	for i, want := range wantResults {
+		if got := report.Rows[i].Result; got != want {
+			t.Fatalf("row %d result = %s (%v), want %s", i, got, report.Rows[i].Errors, want)
+		}
+	}
+
+	updated := users.users["u-ana"]
+	if !slices.Equal(updated.RoleIds, []string{"r-support"}) || updated.PasswordHash != "changed" || updated.FullName != "Ana" {
+		t.Fatalf("ana = %+v, want the file changes on top of the revoked role and the new password", updated)
+	}
+	if report.Rows[0].PendingRoleChangeRequestId == "" {
+		t.Fatal("the privileged role should be requested")
+	}
+	if users.users["u-bob"].Status != model.UserStatusDeleted || len(users.users["u-bob"].RoleIds) != 0 {
+		t.Fatalf("bob = %+v, want the deleted user untouched", users.users["u-bob"])
+	}
+	if !slices.Equal(users.users["u-dan"].RoleIds, []string{"r-requester"}) {
+		t.Fatalf("dan roles = %v, want [r-requester]", users.users["u-dan"].RoleIds)
+	}
+	if registered, _ := users.FindByEmails([]string{"cam@example.com"}); len(registered) != 1 {
+		t.Fatalf("cam@example.com registered %d times, want 1", len(registered))
+	}
+}
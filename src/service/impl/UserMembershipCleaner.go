@@ -0,0 +1,60 @@
+package impl
+
+import (
+	"fmt"
+
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+)
+
+// userMembershipCleaner quita las referencias a un usuario que deja de existir o de tener datos personales:
+// sus grupos, sus membresías de organizaciones y la propiedad de sus organizaciones
+type userMembershipCleaner struct {
+	groupRepository                  repository.GroupRepository
+	organizationRepository           repository.OrganizationRepository
+	organizationMembershipRepository repository.OrganizationMembershipRepository
+}
+
+func newUserMembershipCleaner() *userMembershipCleaner {
+	return &userMembershipCleaner{
+		groupRepository:                  impl.NewGroupRepositoryImpl(),
+		organizationRepository:           impl.NewOrganizationRepositoryImpl(),
+		organizationMembershipRepository: impl.NewOrganizationMembershipRepositoryImpl(),
+	}
+}
+
+// removeUser es idempotente: si falla a medias se puede repetir. Las organizaciones del usuario quedan sin
+// propietario hasta que un administrador global asigne otro.
+func (c *userMembershipCleaner) removeUser(userId string) error {
+	groups, err := c.groupRepository.FindByMemberId(userId)
+	if err != nil {
+		return fmt.Errorf("failed to fetch groups of user: %w", err)
+	}
+	for _, group := range groups {
+		if err := c.groupRepository.RemoveMember(group.Id, userId); err != nil {
+			return fmt.Errorf("failed to remove user from group %s: %w", group.Id, err)
+		}
+	}
+
+	memberships, err := c.organizationMembershipRepository.FindByUserId(userId)
+	if err != nil {
+		return fmt.Errorf("failed to fetch organization memberships of user: %w", err)
+	}
+	for _, membership := range memberships {
+		if err := c.organizationMembershipRepository.Delete(membership.OrganizationId, userId); err != nil {
+			return fmt.Errorf("failed to remove user from organization %s: %w", membership.OrganizationId, err)
+		}
+	}
+
+	organizations, err := c.organizationRepository.FindByOwnerId(userId)
+	if err != nil {
+		return fmt.Errorf("failed to fetch organizations owned by user: %w", err)
+	}
+	for _, organizationModel := range organizations {
+		organizationModel.OwnerId = ""
+		if _, err := c.organizationRepository.Update(organizationModel); err != nil {
+			return fmt.Errorf("failed to clear owner of organization %s: %w", organizationModel.Id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package impl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+// dateOnlyLayout es el formato corto que se admite en los filtros de fecha, además de RFC 3339
+const dateOnlyLayout = "2006-01-02"
+
+// userFilterFromSearch valida los parámetros de búsqueda y los convierte en el filtro del repositorio.
+// Sin orden explícito se ordena por email si hay prefijo de email y por fecha de creación, de la más
+// reciente a la más antigua, en otro caso.
+func userFilterFromSearch(search *user.UserSearchRequest) (*repository.UserFilter, error) {
+	filter := &repository.UserFilter{
+		Query:        strings.TrimSpace(search.Query),
+		EmailPrefix:  strings.TrimSpace(search.EmailPrefix),
+		NameContains: strings.TrimSpace(search.Name),
+		RoleId:       strings.TrimSpace(search.RoleId),
+		SortField:    search.Sort,
+	}
+
+	if filter.RoleId != "" {
+		if _, err := uuid.Parse(filter.RoleId); err != nil {
+			return nil, fmt.Errorf("%w: roleId must be a UUID", service.ErrInvalidUserFilter)
+		}
+	}
+
+	if search.Status == "" {
+		filter.Statuses = []string{model.UserStatusActive, model.UserStatusSuspended, model.UserStatusDeactivated}
+	} else {
+		for _, status := range strings.Split(search.Status, ",") {
+			status = strings.ToUpper(strings.TrimSpace(status))
+			if !model.IsValidUserStatus(status) {
+				return nil, fmt.Errorf("%w: status must be a comma-separated list of %s", service.ErrInvalidUserFilter, strings.Join(model.UserStatuses, ", "))
+			}
+			if !slices.Contains(filter.Statuses, status) {
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		}
+		slices.Sort(filter.Statuses)
+	}
+
+	if filter.SortField == "" {
+		filter.SortField = repository.UserSortCreatedAt
+		if filter.EmailPrefix != "" {
+			filter.SortField = repository.UserSortEmail
+		}
+	}
+	if !slices.Contains(repository.UserSortFields, filter.SortField) {
+		return nil, fmt.Errorf("%w: sort must be one of %s", service.ErrInvalidUserFilter, strings.Join(repository.UserSortFields, ", "))
+	}
+	// Firestore solo admite rangos sobre el primer campo de ordenación
+	if filter.EmailPrefix != "" && filter.SortField != repository.UserSortEmail {
+		return nil, fmt.Errorf("%w: emailPrefix can only be combined with sort=email", service.ErrInvalidUserFilter)
+	}
+
+	switch strings.ToLower(search.Direction) {
+	case "":
+		filter.SortDesc = filter.SortField == repository.UserSortCreatedAt || filter.SortField == repository.UserSortUpdatedAt
+	case "asc":
+		filter.SortDesc = false
+	case "desc":
+		filter.SortDesc = true
+	default:
+		return nil, fmt.Errorf("%w: direction must be asc or desc", service.ErrInvalidUserFilter)
+	}
+
+	if search.CreatedFrom != "" {
+		createdFrom, _, err := parseSearchDate(search.CreatedFrom)
+		if err != nil {
+			return nil, fmt.Errorf("%w: createdFrom %v", service.ErrInvalidUserFilter, err)
+		}
+		filter.CreatedFrom = &createdFrom
+	}
+	if search.CreatedTo != "" {
+		createdTo, dateOnly, err := parseSearchDate(search.CreatedTo)
+		if err != nil {
+			return nil, fmt.Errorf("%w: createdTo %v", service.ErrInvalidUserFilter, err)
+		}
+		// Una fecha sin hora incluye el día completo; con hora, el instante indicado
+		if dateOnly {
+			createdTo = createdTo.AddDate(0, 0, 1)
+		} else {
+			createdTo = createdTo.Add(time.Nanosecond)
+		}
+		filter.CreatedBefore = &createdTo
+	}
+	if filter.CreatedFrom != nil && filter.CreatedBefore != nil && !filter.CreatedFrom.Before(*filter.CreatedBefore) {
+		return nil, fmt.Errorf("%w: createdFrom must not be after createdTo", service.ErrInvalidUserFilter)
+	}
+
+	return filter, nil
+}
+
+// parseSearchDate admite RFC 3339 o YYYY-MM-DD (en UTC) e indica si el valor era solo una fecha
+func parseSearchDate(value string) (time.Time, bool, error) {
+	if date, err := time.Parse(dateOnlyLayout, value); err == nil {
+		return date, true, nil
+	}
+	instant, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("must be RFC 3339 or YYYY-MM-DD")
+	}
+	return instant, false, nil
+}
+
+// userPageToken es el contenido del token de página del listado de usuarios: el último usuario entregado
+// y la huella del filtro con que se generó, para rechazar el token si la consulta cambia
+type userPageToken struct {
+	Filter string `json:"f"`
+	Value  string `json:"v"`
+	Id     string `json:"i"`
+}
+
+func encodeUserPageToken(last *model.User, filter *repository.UserFilter) string {
+	token := userPageToken{Filter: userFilterFingerprint(filter), Id: last.Id}
+	switch filter.SortField {
+	case repository.UserSortCreatedAt:
+		token.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	case repository.UserSortUpdatedAt:
+		token.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	case repository.UserSortEmail:
+		token.Value = last.Email
+	case repository.UserSortFullName:
+		token.Value = last.FullName
+	}
+	return dto.EncodePageToken(token)
+}
+
+// userPageCursor devuelve el cursor que sigue a last en el orden del filtro
+func userPageCursor(last *model.User, filter *repository.UserFilter) *repository.PageCursor {
+	cursor := &repository.PageCursor{Id: last.Id}
+	switch filter.SortField {
+	case repository.UserSortCreatedAt:
+		cursor.SortValue = last.CreatedAt
+	case repository.UserSortUpdatedAt:
+		cursor.SortValue = last.UpdatedAt
+	case repository.UserSortEmail:
+		cursor.SortValue = last.Email
+	case repository.UserSortFullName:
+		cursor.SortValue = last.FullName
+	}
+	return cursor
+}
+
+// decodeUserPageToken devuelve el cursor del token, o nil si está vacío (primera página)
+func decodeUserPageToken(pageToken string, filter *repository.UserFilter) (*repository.PageCursor, error) {
+	if pageToken == "" {
+		return nil, nil
+	}
+
+	var token userPageToken
+	if err := dto.DecodePageToken(pageToken, &token); err != nil {
+		return nil, err
+	}
+	if token.Filter != userFilterFingerprint(filter) || token.Id == "" {
+		return nil, fmt.Errorf("%w: it belongs to a different filter or sort", dto.ErrInvalidPageToken)
+	}
+
+	cursor := &repository.PageCursor{SortValue: token.Value, Id: token.Id}
+	if filter.SortField == repository.UserSortCreatedAt || filter.SortField == repository.UserSortUpdatedAt {
+		value, err := time.Parse(time.RFC3339Nano, token.Value)
+		if err != nil {
+			return nil, dto.ErrInvalidPageToken
+		}
+		cursor.SortValue = value
+	}
+	return cursor, nil
+}
+
+// userFilterFingerprint resume los criterios y el orden del filtro; el tamaño de página puede cambiar entre páginas
+func userFilterFingerprint(filter *repository.UserFilter) string {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.RFC3339Nano)
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		filter.Query, filter.EmailPrefix, filter.NameContains, filter.RoleId, strings.Join(filter.Statuses, ","),
+		formatTime(filter.CreatedFrom), formatTime(filter.CreatedBefore),
+		filter.SortField, strconv.FormatBool(filter.SortDesc),
+	}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,168 @@
+package impl
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+func TestUserFilterFromSearch(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	instant := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+	afterInstant := instant.Add(time.Nanosecond)
+	visibleStatuses := []string{model.UserStatusActive, model.UserStatusSuspended, model.UserStatusDeactivated}
+
+	tests := []struct {
+		name    string
+		search  user.UserSearchRequest
+		want    *repository.UserFilter
+		wantErr error
+	}{
+		{
+			name:   "defaults",
+			search: user.UserSearchRequest{},
+			want:   &repository.UserFilter{Statuses: visibleStatuses, SortField: repository.UserSortCreatedAt, SortDesc: true},
+		},
+		{
+			name:   "trimmed text filters",
+			search: user.UserSearchRequest{Query: " ana ", Name: " Pérez "},
+			want:   &repository.UserFilter{Query: "ana", NameContains: "Pérez", Statuses: visibleStatuses, SortField: repository.UserSortCreatedAt, SortDesc: true},
+		},
+		{
+			name:   "email prefix sorts by email ascending",
+			search: user.UserSearchRequest{EmailPrefix: "ana@"},
+			want:   &repository.UserFilter{EmailPrefix: "ana@", Statuses: visibleStatuses, SortField: repository.UserSortEmail},
+		},
+		{
+			name:   "statuses are normalized, deduplicated and sorted",
+			search: user.UserSearchRequest{Status: "suspended, active,SUSPENDED"},
+			want:   &repository.UserFilter{Statuses: []string{model.UserStatusActive, model.UserStatusSuspended}, SortField: repository.UserSortCreatedAt, SortDesc: true},
+		},
+		{
+			name:   "explicit direction",
+			search: user.UserSearchRequest{Sort: repository.UserSortFullName, Direction: "DESC"},
+			want:   &repository.UserFilter{Statuses: visibleStatuses, SortField: repository.UserSortFullName, SortDesc: true},
+		},
+		{
+			name:   "date only includes the whole day",
+			search: user.UserSearchRequest{CreatedFrom: "2026-03-01", CreatedTo: "2026-03-01"},
+			want:   &repository.UserFilter{Statuses: visibleStatuses, CreatedFrom: &day, CreatedBefore: &nextDay, SortField: repository.UserSortCreatedAt, SortDesc: true},
+		},
+		{
+			name:   "instant includes the instant",
+			search: user.UserSearchRequest{CreatedFrom: "2026-03-01", CreatedTo: "2026-03-01T10:30:00Z"},
+			want:   &repository.UserFilter{Statuses: visibleStatuses, CreatedFrom: &day, CreatedBefore: &afterInstant, SortField: repository.UserSortCreatedAt, SortDesc: true},
+		},
+		{name: "role id must be a UUID", search: user.UserSearchRequest{RoleId: "admin"}, wantErr: service.ErrInvalidUserFilter},
+		{name: "unknown status", search: user.UserSearchRequest{Status: "ACTIVE,BANNED"}, wantErr: service.ErrInvalidUserFilter},
+		{name: "unknown sort", search: user.UserSearchRequest{Sort: "password"}, wantErr: service.ErrInvalidUserFilter},
+		{name: "email prefix with another sort", search: user.UserSearchRequest{EmailPrefix: "ana", Sort: repository.UserSortFullName}, wantErr: service.ErrInvalidUserFilter},
+		{name: "unknown direction", search: user.UserSearchRequest{Direction: "up"}, wantErr: service.ErrInvalidUserFilter},
+		{name: "invalid date", search: user.UserSearchRequest{CreatedFrom: "01/03/2026"}, wantErr: service.ErrInvalidUserFilter},
+		{name: "inverted range", search: user.UserSearchRequest{CreatedFrom: "2026-03-02", CreatedTo: "2026-03-01"}, wantErr: service.ErrInvalidUserFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := userFilterFromSearch(&tt.search)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("userFilterFromSearch() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !equalUserFilters(got, tt.want) {
+				t.Fatalf("userFilterFromSearch() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func equalUserFilters(a, b *repository.UserFilter) bool {
+	equalTime := func(x, y *time.Time) bool {
+		return (x == nil && y == nil) || (x != nil && y != nil && x.Equal(*y))
+	}
+	return a.Query == b.Query && a.EmailPrefix == b.EmailPrefix && a.NameContains == b.NameContains &&
+		a.RoleId == b.RoleId && slices.Equal(a.Statuses, b.Statuses) &&
+		equalTime(a.CreatedFrom, b.CreatedFrom) && equalTime(a.CreatedBefore, b.CreatedBefore) &&
+		a.SortField == b.SortField && a.SortDesc == b.SortDesc
+}
+
+func TestUserPageTokenRoundTrip(t *testing.T) {
+	last := &model.User{
+		Id:        "u1",
+		Email:     "ana@example.com",
+		FullName:  "Ana Pérez",
+		CreatedAt: time.Date(2026, 3, 1, 10, 30, 0, 123456789, time.UTC),
+		UpdatedAt: time.Date(2026, 3, 2, 8, 0, 0, 1, time.UTC),
+	}
+
+	wantValues := map[string]any{
+		repository.UserSortCreatedAt: last.CreatedAt,
+		repository.UserSortUpdatedAt: last.UpdatedAt,
+		repository.UserSortEmail:     last.Email,
+		repository.UserSortFullName:  last.FullName,
+	}
+
+	for _, sortField := range repository.UserSortFields {
+		t.Run(sortField, func(t *testing.T) {
+			filter := &repository.UserFilter{Statuses: []string{model.UserStatusActive}, SortField: sortField}
+
+			cursor, err := decodeUserPageToken(encodeUserPageToken(last, filter), filter)
+			if err != nil {
+				t.Fatalf("decodeUserPageToken() error = %v", err)
+			}
+			if cursor.Id != last.Id {
+				t.Fatalf("cursor id = %s, want %s", cursor.Id, last.Id)
+			}
+			// Las fechas conservan los nanosegundos para no repetir ni saltar usuarios
+			want := wantValues[sortField]
+			if wantTime, ok := want.(time.Time); ok {
+				if gotTime, ok := cursor.SortValue.(time.Time); !ok || !gotTime.Equal(wantTime) {
+					t.Fatalf("cursor value = %v, want %v", cursor.SortValue, wantTime)
+				}
+			} else if cursor.SortValue != want {
+				t.Fatalf("cursor value = %v, want %v", cursor.SortValue, want)
+			}
+		})
+	}
+}
+
+func TestDecodeUserPageTokenRejectsOtherQueries(t *testing.T) {
+	last := &model.User{Id: "u1", Email: "ana@example.com", CreatedAt: time.Now()}
+	filter := &repository.UserFilter{Statuses: []string{model.UserStatusActive}, SortField: repository.UserSortCreatedAt, SortDesc: true}
+	token := encodeUserPageToken(last, filter)
+
+	tests := []struct {
+		name   string
+		token  string
+		filter repository.UserFilter
+	}{
+		{"other query", token, repository.UserFilter{Query: "ana", Statuses: filter.Statuses, SortField: filter.SortField, SortDesc: true}},
+		{"other direction", token, repository.UserFilter{Statuses: filter.Statuses, SortField: filter.SortField}},
+		{"other sort", token, repository.UserFilter{Statuses: filter.Statuses, SortField: repository.UserSortEmail, SortDesc: true}},
+		{"not base64", "%%%", *filter},
+		{"without id", dto.EncodePageToken(userPageToken{Filter: userFilterFingerprint(filter), Value: "2026-03-01T00:00:00Z"}), *filter},
+		{"invalid date", dto.EncodePageToken(userPageToken{Filter: userFilterFingerprint(filter), Value: "yesterday", Id: "u1"}), *filter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeUserPageToken(tt.token, &tt.filter); !errors.Is(err, dto.ErrInvalidPageToken) {
+				t.Fatalf("decodeUserPageToken() error = %v, want %v", err, dto.ErrInvalidPageToken)
+			}
+		})
+	}
+
+	if cursor, err := decodeUserPageToken("", filter); cursor != nil || err != nil {
+		t.Fatalf("empty token = %v, %v, want the first page", cursor, err)
+	}
+}
@@ -1,8 +1,10 @@
 package impl
 
 import (
+	"cmp"
 	dto "github.com/ruiborda/ecommerce-user-service/src/dto/common"
 	"log"
+	"slices"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,44 +13,74 @@ import (
 	"github.com/ruiborda/ecommerce-user-service/src/model"
 	"github.com/ruiborda/ecommerce-user-service/src/repository"
 	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserServiceImpl struct {
-	userRepository repository.UserRepository
-	roleRepository repository.RoleRepository
-	userMapper     *mapper.UserMapper
-	roleMapper     *mapper.RoleMapper
+	userRepository     repository.UserRepository
+	roleRepository     repository.RoleRepository
+	groupRepository    repository.GroupRepository
+	privilegedRoleGate *privilegedRoleGate
+	sodChecker         *sodChecker
+	userMapper         *mapper.UserMapper
+	roleMapper         *mapper.RoleMapper
 }
 
 func NewUserServiceImpl() *UserServiceImpl {
+	roleRepository := impl.NewRoleRepositoryImpl()
 	return &UserServiceImpl{
-		userRepository: impl.NewUserRepositoryImpl(),
-		roleRepository: impl.NewRoleRepositoryImpl(),
-		userMapper:     &mapper.UserMapper{},
-		roleMapper:     &mapper.RoleMapper{},
+		userRepository:     impl.NewUserRepositoryImpl(),
+		roleRepository:     roleRepository,
+		groupRepository:    impl.NewGroupRepositoryImpl(),
+		privilegedRoleGate: newPrivilegedRoleGate(roleRepository, impl.NewRoleChangeRequestRepositoryImpl()),
+		sodChecker:         newSodChecker(roleRepository),
+		userMapper:         &mapper.UserMapper{},
+		roleMapper:         &mapper.RoleMapper{},
 	}
 }
 
-// CreateUser crea un nuevo usuario
-func (s *UserServiceImpl) CreateUser(request *user.CreateUserRequest) *user.CreateUserResponse {
+// CreateUser crea un nuevo usuario; los roles privilegiados quedan pendientes de aprobación
+func (s *UserServiceImpl) CreateUser(request *user.CreateUserRequest, actorId string) (*user.CreateUserResponse, error) {
+	// Mutually exclusive roles are rejected, including those that would wait for approval
+	if err := s.sodChecker.check(request.RoleIds); err != nil {
+		return nil, err
+	}
+
 	// Hash the password
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
 		log.Printf("Error hashing password: %v", err)
-		return nil
+		return nil, err
 	}
 
 	// Map request to model
 	userModel := s.userMapper.CreateUserRequestToUser(request)
 	userModel.PasswordHash = string(passwordHash)
 
+	// Privileged roles are only assigned once a second user approves them
+	regularRoleIds, privilegedRoleIds, err := s.privilegedRoleGate.split(userModel.RoleIds)
+	if err != nil {
+		log.Printf("Error checking privileged roles: %v", err)
+		return nil, err
+	}
+	userModel.RoleIds = regularRoleIds
+
 	// Save to database
 	createdUser, err := s.userRepository.Create(userModel)
 	if err != nil {
 		log.Printf("Error creating user: %v", err)
-		return nil
+		return nil, err
+	}
+
+	var pendingRequest *model.RoleChangeRequest
+	if len(privilegedRoleIds) > 0 {
+		pendingRequest, err = s.privilegedRoleGate.submit(createdUser.Id, privilegedRoleIds, nil, nil, actorId)
+		if err != nil {
+			log.Printf("Error creating role change request: %v", err)
+			return nil, err
+		}
 	}
 
 	// Get roles for response
@@ -71,7 +103,11 @@ func (s *UserServiceImpl) CreateUser(request *user.CreateUserRequest) *user.Crea
 	}
 
 	// Map model to response
-	return s.userMapper.UserToCreateUserResponse(createdUser, &roles)
+	response := s.userMapper.UserToCreateUserResponse(createdUser, &roles)
+	if pendingRequest != nil {
+		response.PendingRoleChangeRequestId = pendingRequest.Id
+	}
+	return response, nil
 }
 
 // GetUserById obtiene un usuario por su ID
@@ -105,7 +141,38 @@ func (s *UserServiceImpl) GetUserById(id string) *user.GetUserByIdResponse {
 		}
 	}
 
-	return s.userMapper.UserToGetUserByIdResponse(userModel, &roles)
+	response := s.userMapper.UserToGetUserByIdResponse(userModel, &roles)
+	response.InheritedRoles = s.inheritedRoles(userModel.Id)
+	return response
+}
+
+// inheritedRoles obtiene los roles que el usuario hereda de sus grupos
+func (s *UserServiceImpl) inheritedRoles(userId string) []user.InheritedRoleResponse {
+	groups, err := s.groupRepository.FindByMemberId(userId)
+	if err != nil {
+		log.Printf("Error fetching groups of user: %v", err)
+		return nil
+	}
+
+	var roleIds []string
+	for _, group := range groups {
+		roleIds = append(roleIds, group.RoleIds...)
+	}
+	if len(roleIds) == 0 {
+		return nil
+	}
+
+	roleModels, err := s.roleRepository.FindByIds(roleIds)
+	if err != nil {
+		log.Printf("Error fetching group roles: %v", err)
+		return nil
+	}
+	rolesById := make(map[string]*model.Role, len(roleModels))
+	for _, roleModel := range roleModels {
+		rolesById[roleModel.Id] = roleModel
+	}
+
+	return s.userMapper.ToInheritedRoles(groups, rolesById)
 }
 
 // GetUserByEmail obtiene un usuario por su email
@@ -172,6 +239,7 @@ func (s *UserServiceImpl) GetAllUsers() []*user.GetUserByIdResponse {
 	// Fetch all needed roles in one go
 	var allRolesPtr []*model.Role
 	if len(uniqueRoleIdsSlice) > 0 {
+		var err error
 		allRolesPtr, err = s.roleRepository.FindByIds(uniqueRoleIdsSlice)
 		if err != nil {
 			log.Printf("Error fetching roles: %v", err)
@@ -218,17 +286,17 @@ func (s *UserServiceImpl) GetAllUsers() []*user.GetUserByIdResponse {
 	return userResponses
 }
 
-// UpdateUserById actualiza un usuario existente
-func (s *UserServiceImpl) UpdateUserById(request *user.UpdateUserRequest) *user.UpdateUserResponse {
+// UpdateUserById actualiza un usuario existente; los roles privilegiados añadidos quedan pendientes de aprobación
+func (s *UserServiceImpl) UpdateUserById(request *user.UpdateUserRequest, actorId string) (*user.UpdateUserResponse, error) {
 	// First get existing user
 	existingUser, err := s.userRepository.FindById(request.Id)
 	if err != nil {
 		log.Printf("Error fetching user to update: %v", err)
-		return nil
+		return nil, err
 	}
 
 	if existingUser == nil {
-		return nil
+		return nil, service.ErrUserNotFound
 	}
 
 	// Check if password needs to be updated
@@ -236,19 +304,65 @@ func (s *UserServiceImpl) UpdateUserById(request *user.UpdateUserRequest) *user.
 		passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 		if err != nil {
 			log.Printf("Error hashing password: %v", err)
-			return nil
+			return nil, err
 		}
 		request.Password = string(passwordHash)
 	}
 
-	// Map request to model
-	updatedUserModel := s.userMapper.UpdateUserRequestToUser(request, existingUser)
+	// Mutually exclusive roles are rejected, counting temporary assignments that have not expired and group roles
+	candidate := *existingUser
+	candidate.RoleIds = request.RoleIds
+	heldRoleIds, err := s.sodChecker.heldRoleIds(&candidate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sodChecker.check(heldRoleIds); err != nil {
+		return nil, err
+	}
 
-	// Save to database
-	updatedUser, err := s.userRepository.Update(updatedUserModel)
+	// Only newly added privileged roles need approval; removing roles is applied directly
+	var addedRoleIds []string
+	for _, roleId := range request.RoleIds {
+		if !slices.Contains(existingUser.RoleIds, roleId) {
+			addedRoleIds = append(addedRoleIds, roleId)
+		}
+	}
+	_, privilegedRoleIds, err := s.privilegedRoleGate.split(addedRoleIds)
 	if err != nil {
-		log.Printf("Error updating user: %v", err)
+		log.Printf("Error checking privileged roles: %v", err)
+		return nil, err
+	}
+	if len(privilegedRoleIds) > 0 {
+		request.RoleIds = slices.DeleteFunc(request.RoleIds, func(roleId string) bool {
+			return slices.Contains(privilegedRoleIds, roleId)
+		})
+	}
+
+	// Map request to model on the user read inside the transaction, so concurrent changes are not overwritten
+	updatedUser, err := s.userRepository.Update(request.Id, func(userModel *model.User) error {
+		previousUser := *userModel
+		s.userMapper.UpdateUserRequestToUser(request, userModel)
+		if authzChanged(&previousUser, userModel) {
+			// Los tokens emitidos con los roles anteriores dejan de ser válidos
+			userModel.AuthzVersion++
+		}
 		return nil
+	})
+	if err != nil {
+		log.Printf("Error updating user: %v", err)
+		return nil, err
+	}
+	if updatedUser == nil {
+		return nil, service.ErrUserNotFound
+	}
+
+	var pendingRequest *model.RoleChangeRequest
+	if len(privilegedRoleIds) > 0 {
+		pendingRequest, err = s.privilegedRoleGate.submit(updatedUser.Id, privilegedRoleIds, nil, nil, actorId)
+		if err != nil {
+			log.Printf("Error creating role change request: %v", err)
+			return nil, err
+		}
 	}
 
 	// Get roles for response
@@ -271,15 +385,29 @@ func (s *UserServiceImpl) UpdateUserById(request *user.UpdateUserRequest) *user.
 	}
 
 	// Map model to response
-	return s.userMapper.UserToUpdateUserResponse(updatedUser, &roles)
+	response := s.userMapper.UserToUpdateUserResponse(updatedUser, &roles)
+	if pendingRequest != nil {
+		response.PendingRoleChangeRequestId = pendingRequest.Id
+	}
+	return response, nil
 }
 
 // DeleteUserById elimina un usuario por su ID
-func (s *UserServiceImpl) DeleteUserById(id string) *user.DeleteUserByIdResponse {
-	err := s.userRepository.Delete(id)
-	success := err == nil
+func (s *UserServiceImpl) DeleteUserById(id string, actorId string) (*user.DeleteUserByIdResponse, error) {
+	// Otros servicios guardan referencias al usuario, así que el documento se conserva hasta la purga
+	userModel, err := s.userRepository.Update(id, func(userModel *model.User) error {
+		markUserDeleted(userModel, actorId, time.Now())
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error deleting user: %v", err)
+		return s.userMapper.UserToDeleteUserByIdResponse(id, false), nil
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
 
-	return s.userMapper.UserToDeleteUserByIdResponse(id, success)
+	return s.userMapper.UserToDeleteUserByIdResponse(id, true), nil
 }
 
 // FindAllUsersByPageAndSize obtiene usuarios paginados
@@ -359,24 +487,64 @@ func (s *UserServiceImpl) FindAllUsersByPageAndSize(page, size int) []*user.GetU
 }
 
 // FindAllUsersPaginated obtiene usuarios paginados y construye la respuesta paginada completa
-func (s *UserServiceImpl) FindAllUsersPaginated(c *gin.Context, pageable *dto.Pageable) *dto.PaginationResponse[user.GetUserByIdResponse] {
+func (s *UserServiceImpl) FindAllUsersPaginated(c *gin.Context, pageable *dto.Pageable, search *user.UserSearchRequest) (*dto.PaginationResponse[user.GetUserByIdResponse], error) {
+	filter, err := userFilterFromSearch(search)
+	if err != nil {
+		return nil, err
+	}
+
+	// Obtener el conteo total de usuarios que cumplen el filtro
+	totalElements, err := s.userRepository.CountByFilter(filter)
+	if err != nil {
+		log.Printf("Error counting users: %v", err)
+		return nil, err
+	}
+
+	if pageable.Cursor {
+		return s.findUsersAfterPageToken(c, pageable, filter, int(totalElements))
+	}
+
 	// Convert from one-based (client) to zero-based (service) pagination
 	zeroBasedPage := pageable.Page - 1
 
 	// Obtener datos de usuarios paginados
-	users, err := s.userRepository.FindAllByPageAndSize(zeroBasedPage, pageable.Size)
+	users, err := s.userRepository.FindByFilter(filter, zeroBasedPage, pageable.Size)
 	if err != nil {
 		log.Printf("Error fetching paginated users: %v", err)
-		return nil
+		return nil, err
 	}
 
-	// Obtener el conteo total de usuarios
-	totalElements, err := s.userRepository.Count()
+	userResponses := s.usersToResponses(users)
+
+	// Crear la respuesta paginada; los enlaces conservan los filtros de la URL
+	return dto.NewPaginationResponse(c, &userResponses, int(totalElements), pageable), nil
+}
+
+// findUsersAfterPageToken lee la página que sigue al token; pide un usuario de más para saber si hay otra página
+func (s *UserServiceImpl) findUsersAfterPageToken(c *gin.Context, pageable *dto.Pageable, filter *repository.UserFilter, totalElements int) (*dto.PaginationResponse[user.GetUserByIdResponse], error) {
+	after, err := decodeUserPageToken(pageable.PageToken, filter)
 	if err != nil {
-		log.Printf("Error counting users: %v", err)
-		return nil
+		return nil, err
 	}
 
+	users, err := s.userRepository.FindByFilterAfter(filter, after, pageable.Size+1)
+	if err != nil {
+		log.Printf("Error fetching paginated users: %v", err)
+		return nil, err
+	}
+
+	nextPageToken := ""
+	if len(users) > pageable.Size {
+		users = users[:pageable.Size]
+		nextPageToken = encodeUserPageToken(users[len(users)-1], filter)
+	}
+
+	userResponses := s.usersToResponses(users)
+	return dto.NewCursorPaginationResponse(c, &userResponses, totalElements, pageable, nextPageToken), nil
+}
+
+// usersToResponses convierte los usuarios con sus roles, leyendo todos los roles en una sola consulta
+func (s *UserServiceImpl) usersToResponses(users []*model.User) []*user.GetUserByIdResponse {
 	// Create map to store roles for each user
 	rolesMap := make(map[string]*[]model.Role)
 
@@ -399,6 +567,7 @@ func (s *UserServiceImpl) FindAllUsersPaginated(c *gin.Context, pageable *dto.Pa
 	// Fetch all needed roles in one go
 	var allRolesPtr []*model.Role
 	if len(uniqueRoleIdsSlice) > 0 {
+		var err error
 		allRolesPtr, err = s.roleRepository.FindByIds(uniqueRoleIdsSlice)
 		if err != nil {
 			log.Printf("Error fetching roles: %v", err)
@@ -442,8 +611,7 @@ func (s *UserServiceImpl) FindAllUsersPaginated(c *gin.Context, pageable *dto.Pa
 		userResponses = append(userResponses, userDto)
 	}
 
-	// Crear la respuesta paginada
-	return dto.NewPaginationResponse(c, &userResponses, int(totalElements), pageable)
+	return userResponses
 }
 
 // CountAllUsers cuenta el número total de usuarios
@@ -613,3 +781,15 @@ func (s *UserServiceImpl) CreateUserWithRoleAndAuth(email, fullName, password st
 
 	return createdUser, nil
 }
+
+// authzChanged indica si cambió algún dato del usuario que se incluye en sus tokens
+func authzChanged(before, after *model.User) bool {
+	return before.Email != after.Email ||
+		!sameElements(before.RoleIds, after.RoleIds) ||
+		!sameElements(before.DeniedPermissionIds, after.DeniedPermissionIds)
+}
+
+// sameElements compara dos listas sin tener en cuenta el orden
+func sameElements[T cmp.Ordered](a, b []T) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
@@ -0,0 +1,210 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ruiborda/ecommerce-user-service/src/dto/user"
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+	"github.com/ruiborda/ecommerce-user-service/src/repository"
+	"github.com/ruiborda/ecommerce-user-service/src/repository/impl"
+	"github.com/ruiborda/ecommerce-user-service/src/service"
+)
+
+const (
+	// DefaultUserDeletionRetention es el tiempo que se conserva una cuenta eliminada si no se configura otro
+	DefaultUserDeletionRetention = 30 * 24 * time.Hour
+	// Usuarios procesados por cada consulta del proceso de estados
+	userStatusSweepBatchSize = 100
+	maxStatusReasonLength    = 500
+)
+
+// userDeletionRetention se configura una sola vez al arrancar, antes de atender peticiones
+var userDeletionRetention = DefaultUserDeletionRetention
+
+// SetUserDeletionRetention cambia el tiempo que se conservan las cuentas eliminadas antes de purgarlas
+func SetUserDeletionRetention(retention time.Duration) {
+	userDeletionRetention = retention
+}
+
+type UserStatusServiceImpl struct {
+	userRepository    repository.UserRepository
+	userService       service.UserService
+	membershipCleaner *userMembershipCleaner
+}
+
+func NewUserStatusServiceImpl() *UserStatusServiceImpl {
+	return &UserStatusServiceImpl{
+		userRepository:    impl.NewUserRepositoryImpl(),
+		userService:       NewUserServiceImpl(),
+		membershipCleaner: newUserMembershipCleaner(),
+	}
+}
+
+// ChangeStatus aplica la transición si el estado actual la permite. Salir del estado activo invalida los tokens.
+func (s *UserStatusServiceImpl) ChangeStatus(userId string, request *user.ChangeUserStatusRequest, actorId string) (*user.GetUserByIdResponse, error) {
+	now := time.Now()
+	status := strings.ToUpper(strings.TrimSpace(request.Status))
+	reason := strings.TrimSpace(request.Reason)
+
+	if !slices.Contains([]string{model.UserStatusActive, model.UserStatusSuspended, model.UserStatusDeactivated}, status) {
+		return nil, fmt.Errorf("%w: status must be ACTIVE, SUSPENDED or DEACTIVATED; use the delete and restore endpoints for DELETED", service.ErrInvalidUserStatus)
+	}
+	if utf8.RuneCountInString(reason) > maxStatusReasonLength {
+		return nil, fmt.Errorf("%w: reason must have at most %d characters", service.ErrInvalidUserStatus, maxStatusReasonLength)
+	}
+	if status == model.UserStatusSuspended {
+		if reason == "" {
+			return nil, fmt.Errorf("%w: reason is required to suspend a user", service.ErrInvalidUserStatus)
+		}
+		if request.SuspendedUntil != nil && !request.SuspendedUntil.After(now) {
+			return nil, fmt.Errorf("%w: suspendedUntil must be in the future", service.ErrInvalidUserStatus)
+		}
+	} else if request.SuspendedUntil != nil {
+		return nil, fmt.Errorf("%w: suspendedUntil is only valid when suspending", service.ErrInvalidUserStatus)
+	}
+	if userId == actorId {
+		return nil, fmt.Errorf("%w: users cannot change their own status", service.ErrUserStatusTransition)
+	}
+
+	userModel, err := s.userRepository.Update(userId, func(userModel *model.User) error {
+		// Repetir el estado actual no cambia nada, salvo en una suspensión, que actualiza el motivo y el vencimiento
+		current := userModel.CurrentStatus(now)
+		if current == status && status != model.UserStatusSuspended {
+			return nil
+		}
+		if current != status && !model.CanTransitionUserStatus(current, status) {
+			return fmt.Errorf("%w: from %s to %s", service.ErrUserStatusTransition, current, status)
+		}
+
+		userModel.SetStatus(status, reason, actorId, now)
+		if status == model.UserStatusSuspended {
+			userModel.SuspendedUntil = request.SuspendedUntil
+		}
+		if current == model.UserStatusActive {
+			// Los tokens emitidos mientras la cuenta estaba activa dejan de ser válidos
+			userModel.AuthzVersion++
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, service.ErrUserStatusTransition) {
+			log.Printf("Error updating user status: %v", err)
+		}
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+	return s.userService.GetUserById(userId), nil
+}
+
+// RestoreUser devuelve al estado activo una cuenta eliminada; una vez purgada ya no existe
+func (s *UserStatusServiceImpl) RestoreUser(userId, actorId string) (*user.GetUserByIdResponse, error) {
+	userModel, err := s.userRepository.Update(userId, func(userModel *model.User) error {
+		if userModel.Status != model.UserStatusDeleted {
+			return fmt.Errorf("%w: only deleted users can be restored", service.ErrUserStatusTransition)
+		}
+		userModel.SetStatus(model.UserStatusActive, "", actorId, time.Now())
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, service.ErrUserStatusTransition) {
+			log.Printf("Error restoring user: %v", err)
+		}
+		return nil, err
+	}
+	if userModel == nil {
+		return nil, service.ErrUserNotFound
+	}
+	return s.userService.GetUserById(userId), nil
+}
+
+// SweepUserStatuses procesa los usuarios por lotes; cada usuario procesado sale de su consulta,
+// así que un lote incompleto indica el final
+func (s *UserStatusServiceImpl) SweepUserStatuses() (int, int, error) {
+	now := time.Now()
+	reactivated, purged := 0, 0
+
+	for {
+		users, err := s.userRepository.FindExpiredSuspensions(now, userStatusSweepBatchSize)
+		if err != nil {
+			return reactivated, purged, err
+		}
+		for _, userModel := range users {
+			_, err := s.userRepository.Update(userModel.Id, func(userModel *model.User) error {
+				// La suspensión pudo cambiar desde la consulta
+				if userModel.Status == model.UserStatusSuspended && userModel.CurrentStatus(now) == model.UserStatusActive {
+					userModel.SetStatus(model.UserStatusActive, "", "", now)
+				}
+				return nil
+			})
+			if err != nil {
+				return reactivated, purged, fmt.Errorf("failed to reactivate user %s: %w", userModel.Id, err)
+			}
+			reactivated++
+		}
+		if len(users) < userStatusSweepBatchSize {
+			break
+		}
+	}
+
+	for {
+		users, err := s.userRepository.FindPurgeable(now, userStatusSweepBatchSize)
+		if err != nil {
+			return reactivated, purged, err
+		}
+		for _, userModel := range users {
+			// La cuenta pudo restaurarse desde la consulta; solo se borra si sigue eliminada y vencida
+			purgedUser, err := s.userRepository.DeleteIf(userModel.Id, func(userModel *model.User) bool {
+				return isPurgeable(userModel, now)
+			})
+			if err != nil {
+				return reactivated, purged, fmt.Errorf("failed to purge user %s: %w", userModel.Id, err)
+			}
+			if purgedUser == nil {
+				continue
+			}
+			// Las referencias se quitan después de borrar para no dejar sin grupos a una cuenta restaurada;
+			// si falla, quedan referencias a un usuario que ya no existe, que los listados ignoran
+			if err := s.membershipCleaner.removeUser(purgedUser.Id); err != nil {
+				return reactivated, purged, fmt.Errorf("failed to remove memberships of purged user %s: %w", purgedUser.Id, err)
+			}
+			deleteAvatarBlobs(purgedUser.ImageFileKey)
+			purged++
+		}
+		if len(users) < userStatusSweepBatchSize {
+			break
+		}
+	}
+
+	return reactivated, purged, nil
+}
+
+func (s *UserStatusServiceImpl) EnsureUserStatuses() (int, error) {
+	return s.userRepository.BackfillStatus()
+}
+
+// isPurgeable indica si la cuenta sigue eliminada y su retención ya venció
+func isPurgeable(userModel *model.User, now time.Time) bool {
+	return userModel.Status == model.UserStatusDeleted && userModel.PurgeAt != nil && !userModel.PurgeAt.After(now)
+}
+
+// markUserDeleted elimina la cuenta de forma lógica e invalida sus tokens. Devuelve false si ya estaba eliminada,
+// para no alargar su retención, o suprimida.
+func markUserDeleted(userModel *model.User, actorId string, now time.Time) bool {
+	// Una cuenta suprimida ya no tiene datos personales y se conserva solo por las referencias
+	if userModel.Status == model.UserStatusDeleted || userModel.Status == model.UserStatusErased {
+		return false
+	}
+	userModel.SetStatus(model.UserStatusDeleted, "", actorId, now)
+	purgeAt := now.Add(userDeletionRetention)
+	userModel.PurgeAt = &purgeAt
+	userModel.AuthzVersion++
+	return true
+}
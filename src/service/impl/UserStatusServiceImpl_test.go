@@ -0,0 +1,132 @@
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ruiborda/ecommerce-user-service/src/model"
+)
+
+// restoringUserRepository simula una restauración entre la consulta de usuarios purgables y su borrado
+type restoringUserRepository struct {
+	*fakeUserRepository
+	restoreId string
+}
+
+func (r *restoringUserRepository) FindPurgeable(now time.Time, limit int) ([]*model.User, error) {
+	users, err := r.fakeUserRepository.FindPurgeable(now, limit)
+	if restored, ok := r.users[r.restoreId]; ok {
+		userCopy := *restored
+		userCopy.SetStatus(model.UserStatusActive, "", "admin", now)
+		r.users[r.restoreId] = &userCopy
+	}
+	return users, err
+}
+
+func TestSweepUserStatusesPurge(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	users := newFakeUserRepository(
+		&model.User{Id: "u-purge", Status: model.UserStatusDeleted, PurgeAt: &past},
+		&model.User{Id: "u-restored", Status: model.UserStatusDeleted, PurgeAt: &past},
+		&model.User{Id: "u-retained", Status: model.UserStatusDeleted, PurgeAt: &future},
+	)
+	groups := &fakeGroupRepository{groups: []*model.Group{
+		{Id: "g1", MemberIds: []string{"u-purge", "u-restored"}},
+	}}
+	organizations := &fakeOrganizationRepository{organizations: map[string]*model.Organization{
+		"o1": {Id: "o1", OwnerId: "u-purge"},
+		"o2": {Id: "o2", OwnerId: "u-restored"},
+	}}
+	memberships := &fakeOrganizationMembershipRepository{memberships: []*model.OrganizationMembership{
+		{OrganizationId: "o1", UserId: "u-purge"},
+		{OrganizationId: "o2", UserId: "u-restored"},
+	}}
+	statusService := &UserStatusServiceImpl{
+		userRepository: &restoringUserRepository{fakeUserRepository: users, restoreId: "u-restored"},
+		membershipCleaner: &userMembershipCleaner{
+			groupRepository:                  groups,
+			organizationRepository:           organizations,
+			organizationMembershipRepository: memberships,
+		},
+	}
+
+	reactivated, purged, err := statusService.SweepUserStatuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reactivated != 0 || purged != 1 {
+		t.Fatalf("SweepUserStatuses() = %d reactivated, %d purged, want 0 and 1", reactivated, purged)
+	}
+
+	if _, ok := users.users["u-purge"]; ok {
+		t.Fatal("u-purge should be deleted")
+	}
+	if restored := users.users["u-restored"]; restored == nil || restored.Status != model.UserStatusActive {
+		t.Fatalf("u-restored = %+v, want it restored and kept", restored)
+	}
+	if _, ok := users.users["u-retained"]; !ok {
+		t.Fatal("u-retained should be kept until its purge date")
+	}
+
+	// Solo se quitan las referencias del usuario purgado
+	if memberIds := groups.groups[0].MemberIds; len(memberIds) != 1 || memberIds[0] != "u-restored" {
+		t.Fatalf("group members = %v, want [u-restored]", memberIds)
+	}
+	if len(memberships.memberships) != 1 || memberships.memberships[0].UserId != "u-restored" {
+		t.Fatalf("memberships = %+v, want only u-restored", memberships.memberships)
+	}
+	if organizations.organizations["o1"].OwnerId != "" || organizations.organizations["o2"].OwnerId != "u-restored" {
+		t.Fatalf("owners = %q, %q, want the purged owner cleared", organizations.organizations["o1"].OwnerId, organizations.organizations["o2"].OwnerId)
+	}
+}
+
+func TestIsPurgeable(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		user model.User
+		want bool
+	}{
+		{"deleted past retention", model.User{Status: model.UserStatusDeleted, PurgeAt: &past}, true},
+		{"deleted at retention end", model.User{Status: model.UserStatusDeleted, PurgeAt: &now}, true},
+		{"deleted within retention", model.User{Status: model.UserStatusDeleted, PurgeAt: &future}, false},
+		{"deleted without purge date", model.User{Status: model.UserStatusDeleted}, false},
+		{"restored", model.User{Status: model.UserStatusActive}, false},
+		{"erased", model.User{Status: model.UserStatusErased, PurgeAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPurgeable(&tt.user, now); got != tt.want {
+				t.Fatalf("isPurgeable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkUserDeleted(t *testing.T) {
+	now := time.Now()
+
+	active := &model.User{Status: model.UserStatusActive, AuthzVersion: 3}
+	if !markUserDeleted(active, "admin", now) {
+		t.Fatal("an active user should be deleted")
+	}
+	if active.Status != model.UserStatusDeleted || active.PurgeAt == nil || !active.PurgeAt.Equal(now.Add(userDeletionRetention)) || active.AuthzVersion != 4 {
+		t.Fatalf("deleted user = %+v", active)
+	}
+
+	// Eliminar de nuevo no alarga la retención
+	purgeAt := *active.PurgeAt
+	if markUserDeleted(active, "admin", now.Add(time.Hour)) || !active.PurgeAt.Equal(purgeAt) {
+		t.Fatal("deleting a deleted user should not change it")
+	}
+	if markUserDeleted(&model.User{Status: model.UserStatusErased}, "admin", now) {
+		t.Fatal("an erased user should not be deleted")
+	}
+}